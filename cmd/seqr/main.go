@@ -9,19 +9,25 @@ import (
 	"syscall"
 
 	"github.com/seqr-cli/seqr/internal/cli"
+	"github.com/seqr-cli/seqr/internal/config"
 )
 
-var version = "dev"
+// Set at build time with -ldflags "-X main.version=... -X main.commit=... -X main.date=..."
+var (
+	version = "dev"
+	commit  = "none"
+	date    = "unknown"
+)
 
 func main() {
+	config.ToolVersion = version
 	cliApp := cli.NewCLI(os.Args[1:])
 
 	if err := cliApp.Parse(); err != nil {
-		if isFlagError(err) {
-			os.Exit(2)
+		if !isFlagError(err) {
+			cliApp.ReportError(err)
 		}
-		os.Stderr.WriteString("Error: " + err.Error() + "\n")
-		os.Exit(1)
+		os.Exit(cli.ExitUsage)
 	}
 
 	if cliApp.ShouldShowHelp() {
@@ -30,30 +36,94 @@ func main() {
 	}
 
 	if cliApp.ShouldShowVersion() {
-		cliApp.ShowVersion(version)
+		cliApp.ShowVersion(cli.BuildInfo{Version: version, Commit: commit, Date: date})
 		os.Exit(0)
 	}
 
 	if cliApp.ShouldRunInit() {
 		if err := cliApp.RunInit(); err != nil {
-			os.Stderr.WriteString("Error: " + err.Error() + "\n")
-			os.Exit(1)
+			cliApp.ReportError(err)
+			os.Exit(cli.ExitCode(err))
 		}
 		os.Exit(0)
 	}
 
 	if cliApp.ShouldRunKill() {
 		if err := cliApp.RunKill(); err != nil {
-			os.Stderr.WriteString("Error: " + err.Error() + "\n")
-			os.Exit(1)
+			cliApp.ReportError(err)
+			os.Exit(cli.ExitCode(err))
 		}
 		os.Exit(0)
 	}
 
 	if cliApp.ShouldRunStatus() {
 		if err := cliApp.RunStatus(); err != nil {
-			os.Stderr.WriteString("Error: " + err.Error() + "\n")
-			os.Exit(1)
+			cliApp.ReportError(err)
+			os.Exit(cli.ExitCode(err))
+		}
+		os.Exit(0)
+	}
+
+	if cliApp.ShouldRunLast() {
+		if err := cliApp.RunLast(); err != nil {
+			cliApp.ReportError(err)
+			os.Exit(cli.ExitCode(err))
+		}
+		os.Exit(0)
+	}
+
+	if cliApp.ShouldRunDumpEnv() {
+		if err := cliApp.RunDumpEnv(); err != nil {
+			cliApp.ReportError(err)
+			os.Exit(cli.ExitCode(err))
+		}
+		os.Exit(0)
+	}
+
+	if cliApp.ShouldRunDoctor() {
+		if err := cliApp.RunDoctor(context.Background()); err != nil {
+			cliApp.ReportError(err)
+			os.Exit(cli.ExitCode(err))
+		}
+		os.Exit(0)
+	}
+
+	if cliApp.ShouldRunGraph() {
+		if err := cliApp.RunGraph(); err != nil {
+			cliApp.ReportError(err)
+			os.Exit(cli.ExitCode(err))
+		}
+		os.Exit(0)
+	}
+
+	if cliApp.ShouldRunLint() {
+		if err := cliApp.RunLint(); err != nil {
+			cliApp.ReportError(err)
+			os.Exit(cli.ExitCode(err))
+		}
+		os.Exit(0)
+	}
+
+	if cliApp.ShouldRunExplain() {
+		if err := cliApp.RunExplain(); err != nil {
+			cliApp.ReportError(err)
+			os.Exit(cli.ExitCode(err))
+		}
+		os.Exit(0)
+	}
+
+	if cliApp.ShouldRunDiff() {
+		if err := cliApp.RunDiff(); err != nil {
+			cliApp.ReportError(err)
+			os.Exit(cli.ExitCode(err))
+		}
+		os.Exit(0)
+	}
+
+	if cliApp.ShouldRunCompletion() {
+		if err := cliApp.RunCompletion(); err != nil {
+			cliApp.ReportError(err)
+			os.Exit(cli.ExitCode(err))
 		}
 		os.Exit(0)
 	}
@@ -71,8 +141,27 @@ func main() {
 		}()
 
 		if err := cliApp.RunWatch(ctx); err != nil {
-			os.Stderr.WriteString("Error: " + err.Error() + "\n")
-			os.Exit(1)
+			cliApp.ReportError(err)
+			os.Exit(cli.ExitCode(err))
+		}
+		os.Exit(0)
+	}
+
+	if cliApp.ShouldRunLogs() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+		go func() {
+			<-sigChan
+			cancel()
+		}()
+
+		if err := cliApp.RunLogs(ctx); err != nil {
+			cliApp.ReportError(err)
+			os.Exit(cli.ExitCode(err))
 		}
 		os.Exit(0)
 	}
@@ -83,34 +172,17 @@ func main() {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
-	go func() {
-		signalCount := 0
-		for {
-			<-sigChan
-			signalCount++
-
-			if signalCount == 1 {
-				// First signal: try to detach from streaming if active
-				if cliApp.TryDetachFromStreaming() {
-					// Successfully detached from streaming, continue running
-					continue
-				} else {
-					// No active streaming or detachment failed, proceed with normal shutdown
-					cancel()
-					cliApp.Stop()
-					return
-				}
-			} else {
-				// Second signal: force shutdown
-				cancel()
-				cliApp.Stop()
-				return
-			}
-		}
-	}()
+	go cliApp.HandleInterrupts(sigChan, cancel)
+
+	// Relay configured signals (e.g. SIGUSR1 for log rotation) to keepAlive processes
+	go cliApp.HandleForwardedSignals()
+
+	// Pause keepAlive processes along with seqr on Ctrl+Z, and resume them on fg or bg
+	go cliApp.HandleSuspend()
+
 	if err := cliApp.Run(ctx); err != nil {
-		os.Stderr.WriteString("Error: " + err.Error() + "\n")
-		os.Exit(1)
+		cliApp.ReportError(err)
+		os.Exit(cli.ExitCode(err))
 	}
 }
 
@@ -1,3 +1,5 @@
 module github.com/seqr-cli/seqr
 
-go 1.25
+go 1.25.0
+
+require golang.org/x/text v0.41.0
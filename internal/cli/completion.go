@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+// CompletionShells lists the shells a completion script can be generated for
+var CompletionShells = []string{"bash", "zsh"}
+
+// completionSafeName matches command names that can be embedded in a completion script without quoting
+var completionSafeName = regexp.MustCompile(`^[A-Za-z0-9._:@+-]+$`)
+
+const bashCompletionTemplate = `# bash completion for seqr
+# Generated by: seqr --completion bash
+# Load with: source <(seqr --completion bash)
+
+_seqr_completion() {
+    local cur prev
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    prev="${COMP_WORDS[COMP_CWORD-1]}"
+
+    case "$prev" in
+        -f)
+            COMPREPLY=( $(compgen -f -- "$cur") )
+            return 0
+            ;;
+        --completion)
+            COMPREPLY=( $(compgen -W "%s" -- "$cur") )
+            return 0
+            ;;
+    esac
+
+    if [[ "$cur" == -* ]]; then
+        COMPREPLY=( $(compgen -W "%s" -- "$cur") )
+        return 0
+    fi
+
+    # Command names from the queue configuration
+    COMPREPLY=( $(compgen -W "%s" -- "$cur") )
+}
+
+complete -F _seqr_completion seqr
+`
+
+const zshCompletionTemplate = `#compdef seqr
+# zsh completion for seqr
+# Generated by: seqr --completion zsh
+# Load with: source <(seqr --completion zsh)
+
+_seqr() {
+    local -a flags commands
+    flags=(%s)
+    commands=(%s)
+
+    case "$words[CURRENT-1]" in
+        -f)
+            _files
+            return
+            ;;
+        --completion)
+            compadd %s
+            return
+            ;;
+    esac
+
+    if [[ "$PREFIX" == -* ]]; then
+        compadd -a flags
+    else
+        # Command names from the queue configuration
+        compadd -a commands
+    fi
+}
+
+compdef _seqr seqr
+`
+
+// RunCompletion prints a shell completion script for the requested shell
+func (c *CLI) RunCompletion() error {
+	script, err := c.completionScript(c.options.Completion)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(os.Stdout, script)
+	return nil
+}
+
+// completionScript generates the completion script for a shell, completing command names
+// from the configuration file when it can be loaded
+func (c *CLI) completionScript(shell string) (string, error) {
+	flags := completionFlags(c.flagSet)
+	commands := completionCommandNames(c.options.ConfigFile)
+	shells := strings.Join(CompletionShells, " ")
+
+	switch shell {
+	case "bash":
+		return fmt.Sprintf(bashCompletionTemplate, shells, strings.Join(flags, " "), strings.Join(commands, " ")), nil
+	case "zsh":
+		return fmt.Sprintf(zshCompletionTemplate, strings.Join(flags, " "), strings.Join(commands, " "), shells), nil
+	default:
+		return "", fmt.Errorf("unsupported completion shell '%s', supported shells are: %s", shell, strings.Join(CompletionShells, ", "))
+	}
+}
+
+// completionFlags returns the flag names as typed on the command line, e.g. -f and --verbose
+func completionFlags(flagSet *flag.FlagSet) []string {
+	var flags []string
+	flagSet.VisitAll(func(f *flag.Flag) {
+		if len(f.Name) == 1 {
+			flags = append(flags, "-"+f.Name)
+		} else {
+			flags = append(flags, "--"+f.Name)
+		}
+	})
+	sort.Strings(flags)
+	return flags
+}
+
+// completionCommandNames returns the command names from the configuration file, or nil
+// when the file is absent or cannot be loaded
+func completionCommandNames(configFile string) []string {
+	cfg, err := config.LoadFromFile(configFile)
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, cmd := range cfg.Commands {
+		// Skip names that would need shell quoting rather than risk a broken script
+		if completionSafeName.MatchString(cmd.Name) {
+			names = append(names, cmd.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCLI_CompletionScript(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "queue.json")
+	configJSON := `{
+		"version": "1.0",
+		"commands": [
+			{"name": "build", "command": "go", "args": ["build", "./..."]},
+			{"name": "api-server", "command": "node", "args": ["server.js"], "mode": "keepAlive"}
+		]
+	}`
+	if err := os.WriteFile(configFile, []byte(configJSON), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	for _, shell := range CompletionShells {
+		t.Run(shell, func(t *testing.T) {
+			cli := NewCLI([]string{"-f", configFile, "--completion", shell})
+			if err := cli.Parse(); err != nil {
+				t.Fatalf("Unexpected parse error: %v", err)
+			}
+			if !cli.ShouldRunCompletion() {
+				t.Fatal("Expected ShouldRunCompletion to be true")
+			}
+
+			output := captureStdout(t, func() {
+				if err := cli.RunCompletion(); err != nil {
+					t.Errorf("RunCompletion failed: %v", err)
+				}
+			})
+
+			if strings.TrimSpace(output) == "" {
+				t.Fatal("Expected a non-empty completion script")
+			}
+
+			for _, expected := range []string{"-f", "--verbose", "--kill", "--status", "--watch", "--completion", "build", "api-server"} {
+				if !strings.Contains(output, expected) {
+					t.Errorf("Expected %s completion script to contain %q", shell, expected)
+				}
+			}
+
+			// The script must at least parse in its target shell when available
+			if shellPath, err := exec.LookPath(shell); err == nil {
+				check := exec.Command(shellPath, "-n")
+				check.Stdin = strings.NewReader(output)
+				if out, err := check.CombinedOutput(); err != nil {
+					t.Errorf("Generated %s script has syntax errors: %v\n%s", shell, err, out)
+				}
+			}
+		})
+	}
+}
+
+func TestCLI_CompletionWithoutConfig(t *testing.T) {
+	cli := NewCLI([]string{"-f", filepath.Join(t.TempDir(), "missing.json"), "--completion", "bash"})
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+
+	script, err := cli.completionScript("bash")
+	if err != nil {
+		t.Fatalf("Expected completion without a config file to succeed, got: %v", err)
+	}
+	if !strings.Contains(script, "--help") {
+		t.Error("Expected flag completion even without a config file")
+	}
+}
+
+func TestCLI_CompletionUnsupportedShell(t *testing.T) {
+	cli := NewCLI([]string{"--completion", "fish"})
+	if err := cli.Parse(); err == nil {
+		t.Error("Expected error for unsupported completion shell")
+	}
+}
@@ -0,0 +1,235 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/seqr-cli/seqr/internal/config"
+	"github.com/seqr-cli/seqr/internal/executor"
+)
+
+// commandDiff is how one command differs between two queues
+type commandDiff struct {
+	name    string
+	change  string   // "added", "removed" or "changed"
+	details []string // For a changed command, one line per changed field
+}
+
+// queueDiff is how two queues differ
+type queueDiff struct {
+	commands []commandDiff
+	order    []string // The old and new order of the commands in both queues, when it changed
+	settings []string // Top-level settings that changed, by their JSON name
+}
+
+// RunDiff loads the two queue files given as arguments and prints the commands added, removed and
+// changed between them, by name. Both go through the normalizer, so formatting, field order and
+// shorthand forms make no difference. Nothing is run.
+func (c *CLI) RunDiff() error {
+	oldFile, newFile := c.flagSet.Arg(0), c.flagSet.Arg(1)
+
+	oldCfg, err := c.loadQueueFile(oldFile)
+	if err != nil {
+		return fmt.Errorf("failed to load '%s': %w", oldFile, err)
+	}
+	newCfg, err := c.loadQueueFile(newFile)
+	if err != nil {
+		return fmt.Errorf("failed to load '%s': %w", newFile, err)
+	}
+
+	writeDiff(os.Stdout, oldFile, newFile, diffConfigs(oldCfg, newCfg, c.options.ShowSecrets))
+	return nil
+}
+
+// loadQueueFile loads and normalizes one queue file or URL, without expanding variables
+func (c *CLI) loadQueueFile(path string) (*config.Config, error) {
+	var cfg *config.Config
+	var err error
+	if config.IsURL(path) {
+		cfg, err = config.LoadFromURL(path, c.options.Insecure)
+	} else {
+		cfg, err = config.LoadFromFile(path)
+	}
+	if err != nil {
+		return nil, &ConfigError{Err: err}
+	}
+	return cfg, nil
+}
+
+// diffConfigs compares two queues command by command, matching commands by name. Removed and
+// changed commands come in the old queue's order, followed by added ones in the new queue's.
+// Secret-looking env values are masked unless showSecrets is set.
+func diffConfigs(oldCfg, newCfg *config.Config, showSecrets bool) queueDiff {
+	var diff queueDiff
+
+	newByName := make(map[string]config.Command, len(newCfg.Commands))
+	for _, cmd := range newCfg.Commands {
+		newByName[cmd.Name] = cmd
+	}
+	oldByName := make(map[string]config.Command, len(oldCfg.Commands))
+	for _, cmd := range oldCfg.Commands {
+		oldByName[cmd.Name] = cmd
+	}
+
+	var oldOrder, newOrder []string
+	for _, oldCmd := range oldCfg.Commands {
+		newCmd, found := newByName[oldCmd.Name]
+		if !found {
+			diff.commands = append(diff.commands, commandDiff{name: oldCmd.Name, change: "removed"})
+			continue
+		}
+		oldOrder = append(oldOrder, oldCmd.Name)
+		if details := diffCommand(oldCmd, newCmd, showSecrets); len(details) > 0 {
+			diff.commands = append(diff.commands, commandDiff{name: oldCmd.Name, change: "changed", details: details})
+		}
+	}
+	for _, newCmd := range newCfg.Commands {
+		if _, found := oldByName[newCmd.Name]; !found {
+			diff.commands = append(diff.commands, commandDiff{name: newCmd.Name, change: "added"})
+			continue
+		}
+		newOrder = append(newOrder, newCmd.Name)
+	}
+
+	// Only a change in the relative order of the commands both queues have is a reordering
+	if !slices.Equal(oldOrder, newOrder) {
+		diff.order = []string{strings.Join(oldOrder, ", "), strings.Join(newOrder, ", ")}
+	}
+
+	// Everything but the commands is compared as a whole
+	oldSettings, newSettings := *oldCfg, *newCfg
+	oldSettings.Commands, newSettings.Commands = nil, nil
+	diff.settings = changedJSONFields(oldSettings, newSettings)
+
+	return diff
+}
+
+// diffCommand describes the changes between two versions of a command: its command line, mode,
+// workDir and each env value on lines of their own, then the names of any other changed fields
+func diffCommand(oldCmd, newCmd config.Command, showSecrets bool) []string {
+	var details []string
+
+	if oldLine, newLine := executor.CommandLine(oldCmd), executor.CommandLine(newCmd); oldLine != newLine {
+		details = append(details, fmt.Sprintf("command line: %s -> %s", oldLine, newLine))
+	}
+	if oldCmd.Mode != newCmd.Mode {
+		details = append(details, fmt.Sprintf("mode: %s -> %s", oldCmd.Mode, newCmd.Mode))
+	}
+	if oldCmd.WorkDir != newCmd.WorkDir {
+		details = append(details, fmt.Sprintf("workDir: %s -> %s", diffValue(oldCmd.WorkDir, oldCmd.WorkDir != ""), diffValue(newCmd.WorkDir, newCmd.WorkDir != "")))
+	}
+
+	keys := make([]string, 0, len(oldCmd.Env)+len(newCmd.Env))
+	for key := range oldCmd.Env {
+		keys = append(keys, key)
+	}
+	for key := range newCmd.Env {
+		if _, found := oldCmd.Env[key]; !found {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		oldValue, oldSet := oldCmd.Env[key]
+		newValue, newSet := newCmd.Env[key]
+		if oldSet == newSet && oldValue == newValue {
+			continue
+		}
+		if !showSecrets && executor.IsSecretEnvKey(key) {
+			oldValue, newValue = maskedDiffValue(oldValue), maskedDiffValue(newValue)
+		}
+		details = append(details, fmt.Sprintf("env %s: %s -> %s", key, diffValue(oldValue, oldSet), diffValue(newValue, newSet)))
+	}
+
+	// The fields shown above are left out of the comparison of the rest
+	oldRest, newRest := oldCmd, newCmd
+	for _, cmd := range []*config.Command{&oldRest, &newRest} {
+		cmd.Command, cmd.Args, cmd.Filter, cmd.Mode, cmd.WorkDir, cmd.Env = "", nil, nil, "", "", nil
+	}
+	if fields := changedJSONFields(oldRest, newRest); len(fields) > 0 {
+		details = append(details, "other settings: "+strings.Join(fields, ", "))
+	}
+
+	return details
+}
+
+// diffValue shows a value in a diff line, or (unset) for one that is not set
+func diffValue(value string, set bool) string {
+	if !set {
+		return "(unset)"
+	}
+	return fmt.Sprintf("%q", value)
+}
+
+// maskedDiffValue hides a secret value the way --dump-env does, keeping an empty one visible
+func maskedDiffValue(value string) string {
+	masked := executor.MaskEnvEntry("SECRET=" + value)
+	return strings.TrimPrefix(masked, "SECRET=")
+}
+
+// changedJSONFields returns the sorted JSON names of the fields whose values differ between a
+// and b
+func changedJSONFields(a, b any) []string {
+	oldFields, newFields := jsonFields(a), jsonFields(b)
+
+	var changed []string
+	for name, oldValue := range oldFields {
+		if newValue, found := newFields[name]; !found || !bytes.Equal(oldValue, newValue) {
+			changed = append(changed, name)
+		}
+	}
+	for name := range newFields {
+		if _, found := oldFields[name]; !found {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// jsonFields returns the JSON encoding of each field of v. Maps encode with sorted keys, so equal
+// values encode the same.
+func jsonFields(v any) map[string]json.RawMessage {
+	fields := map[string]json.RawMessage{}
+	if data, err := json.Marshal(v); err == nil {
+		json.Unmarshal(data, &fields)
+	}
+	return fields
+}
+
+// writeDiff prints the differences between two queues and a count of each kind of change
+func writeDiff(w io.Writer, oldFile, newFile string, diff queueDiff) {
+	fmt.Fprintf(w, "seqr Diff\n")
+	fmt.Fprintf(w, "=========\n\n")
+	fmt.Fprintf(w, "--- %s\n", oldFile)
+	fmt.Fprintf(w, "+++ %s\n\n", newFile)
+
+	if len(diff.commands) == 0 && diff.order == nil && len(diff.settings) == 0 {
+		fmt.Fprintf(w, "No differences\n")
+		return
+	}
+
+	counts := map[string]int{}
+	marks := map[string]string{"added": "+", "removed": "-", "changed": "~"}
+	for _, command := range diff.commands {
+		counts[command.change]++
+		fmt.Fprintf(w, "  %s %s: %s\n", marks[command.change], command.name, command.change)
+		for _, detail := range command.details {
+			fmt.Fprintf(w, "      %s\n", detail)
+		}
+	}
+	if diff.order != nil {
+		fmt.Fprintf(w, "  order: %s -> %s\n", diff.order[0], diff.order[1])
+	}
+	if len(diff.settings) > 0 {
+		fmt.Fprintf(w, "  queue settings: %s\n", strings.Join(diff.settings, ", "))
+	}
+
+	fmt.Fprintf(w, "\n%d added, %d removed, %d changed\n", counts["added"], counts["removed"], counts["changed"])
+}
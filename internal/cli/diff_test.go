@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeQueueFile writes a queue configuration into dir and returns its path
+func writeQueueFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+	return path
+}
+
+func TestCLI_RunDiffReportsChangedCommands(t *testing.T) {
+	tempDir := t.TempDir()
+	oldFile := writeQueueFile(t, tempDir, "old.json", `{
+		"version": "1.0",
+		"commands": [
+			{"name": "build", "command": "go", "args": ["build", "./..."], "mode": "once"},
+			{"name": "api", "command": "go", "args": ["run", "./cmd/api"], "mode": "once", "env": {"PORT": "8080", "API_TOKEN": "old-token"}},
+			{"name": "lint", "command": "golangci-lint", "args": ["run"], "mode": "once"}
+		]
+	}`)
+	newFile := writeQueueFile(t, tempDir, "new.json", `{
+		"version": "1.0",
+		"commands": [
+			{"name": "build", "command": "go", "args": ["build", "./..."], "mode": "once"},
+			{"name": "api", "command": "go", "args": ["run", "-race", "./cmd/api"], "mode": "keepAlive", "env": {"PORT": "9090", "API_TOKEN": "new-token"}, "workDir": "services"},
+			{"name": "test", "command": "go", "args": ["test", "./..."], "mode": "once", "timeout": "5m"}
+		]
+	}`)
+
+	cli := NewCLI([]string{"--diff", oldFile, newFile})
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Failed to parse CLI args: %v", err)
+	}
+	if !cli.ShouldRunDiff() {
+		t.Fatal("Expected --diff to be requested")
+	}
+
+	var runErr error
+	output := captureStdout(t, func() { runErr = cli.RunDiff() })
+	if runErr != nil {
+		t.Fatalf("Expected the diff to be printed, got %v", runErr)
+	}
+
+	for _, expected := range []string{
+		"~ api: changed",
+		"command line: go run ./cmd/api -> go run -race ./cmd/api",
+		"mode: once -> keepAlive",
+		`workDir: (unset) -> "services"`,
+		`env PORT: "8080" -> "9090"`,
+		"- lint: removed",
+		"+ test: added",
+		"1 added, 1 removed, 1 changed",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in diff, got:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "build") {
+		t.Errorf("Expected the unchanged command to be left out, got:\n%s", output)
+	}
+	if strings.Contains(output, "old-token") || strings.Contains(output, "new-token") {
+		t.Errorf("Expected secret-looking env values to be masked, got:\n%s", output)
+	}
+}
+
+func TestCLI_RunDiffIgnoresFormatting(t *testing.T) {
+	tempDir := t.TempDir()
+	oldFile := writeQueueFile(t, tempDir, "old.json", `{"version": "1.0", "commands": [{"name": "test", "command": "go test ./...", "mode": "once"}]}`)
+	newFile := writeQueueFile(t, tempDir, "new.json", `{
+		"version": "1.0",
+		"commands": [
+			{
+				"mode": "once",
+				"args": ["test", "./..."],
+				"command": "go",
+				"name": "test"
+			}
+		]
+	}`)
+
+	cli := NewCLI([]string{"--diff", oldFile, newFile})
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Failed to parse CLI args: %v", err)
+	}
+
+	var runErr error
+	output := captureStdout(t, func() { runErr = cli.RunDiff() })
+	if runErr != nil {
+		t.Fatalf("Expected the diff to be printed, got %v", runErr)
+	}
+	if !strings.Contains(output, "No differences") {
+		t.Errorf("Expected equivalent queues to have no differences, got:\n%s", output)
+	}
+}
+
+func TestCLI_RunDiffReportsOtherChanges(t *testing.T) {
+	tempDir := t.TempDir()
+	oldFile := writeQueueFile(t, tempDir, "old.json", `{
+		"version": "1.0",
+		"commands": [
+			{"name": "a", "command": "true", "mode": "once"},
+			{"name": "b", "command": "true", "mode": "once"}
+		]
+	}`)
+	newFile := writeQueueFile(t, tempDir, "new.json", `{
+		"version": "1.0",
+		"maxConcurrency": 2,
+		"commands": [
+			{"name": "b", "command": "true", "mode": "once", "allowFailure": true},
+			{"name": "a", "command": "true", "mode": "once"}
+		]
+	}`)
+
+	queueDiff := captureStdout(t, func() {
+		cli := NewCLI([]string{"--diff", oldFile, newFile})
+		if err := cli.Parse(); err != nil {
+			t.Fatalf("Failed to parse CLI args: %v", err)
+		}
+		if err := cli.RunDiff(); err != nil {
+			t.Fatalf("Expected the diff to be printed, got %v", err)
+		}
+	})
+
+	for _, expected := range []string{
+		"~ b: changed",
+		"other settings: allowFailure",
+		"order: a, b -> b, a",
+		"queue settings: maxConcurrency",
+	} {
+		if !strings.Contains(queueDiff, expected) {
+			t.Errorf("Expected %q in diff, got:\n%s", expected, queueDiff)
+		}
+	}
+}
+
+func TestCLI_DiffRequiresTwoFiles(t *testing.T) {
+	cli := NewCLI([]string{"--diff", "old.json"})
+	err := cli.Parse()
+	if err == nil || !strings.Contains(err.Error(), "--diff takes two queue files") {
+		t.Errorf("Expected --diff with one file to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+// dockerPingTimeout bounds how long the doctor waits for the Docker daemon to answer
+const dockerPingTimeout = 10 * time.Second
+
+// dockerPing checks that the Docker daemon is reachable. It is a variable so tests can run the
+// doctor without a Docker installation.
+var dockerPing = func(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, dockerPingTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, "docker", "info", "--format", "{{.ServerVersion}}").CombinedOutput()
+	if err != nil {
+		// The first line of docker's output says why, e.g. it cannot connect to the daemon
+		if line, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n"); line != "" {
+			return fmt.Errorf("%s", line)
+		}
+		return err
+	}
+	return nil
+}
+
+// doctorCheck is one prerequisite the queue's commands rely on
+type doctorCheck struct {
+	description string   // What is checked, e.g. "executable 'node'"
+	usedBy      []string // Names of the commands that need it
+	detail      string   // Where it was found when the check passed
+	err         error    // Why the check failed, nil when it passed
+}
+
+// RunDoctor checks that the prerequisites of the queue's commands are available: every
+// executable can be found, and the Docker daemon is running if any command uses docker.
+// Nothing is run; the report lists every check, and an error is returned if any failed.
+func (c *CLI) RunDoctor(ctx context.Context) error {
+	cfg, err := c.loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	checks := doctorChecks(ctx, cfg)
+	return writeDoctorReport(os.Stdout, checks)
+}
+
+// doctorChecks derives the prerequisite checks from the queue's commands and runs them
+func doctorChecks(ctx context.Context, cfg *config.Config) []*doctorCheck {
+	var checks []*doctorCheck
+	executables := make(map[string]*doctorCheck)
+	var dockerUsers []string
+
+	for _, cmd := range cfg.Commands {
+		path := executablePath(cmd)
+		if check, seen := executables[path]; seen {
+			check.usedBy = append(check.usedBy, cmd.Name)
+		} else {
+			check := &doctorCheck{
+				description: fmt.Sprintf("executable '%s'", cmd.Command),
+				usedBy:      []string{cmd.Name},
+			}
+			if resolved, err := exec.LookPath(path); err != nil {
+				check.err = err
+			} else {
+				check.detail = resolved
+			}
+			executables[path] = check
+			checks = append(checks, check)
+		}
+
+		if usesDocker(cmd) {
+			dockerUsers = append(dockerUsers, cmd.Name)
+		}
+	}
+
+	if len(dockerUsers) > 0 {
+		check := &doctorCheck{description: "Docker daemon", usedBy: dockerUsers}
+		// Without the docker executable the missing binary is already reported
+		if _, err := exec.LookPath("docker"); err != nil {
+			check.err = fmt.Errorf("docker is not installed")
+		} else if err := dockerPing(ctx); err != nil {
+			check.err = err
+		} else {
+			check.detail = "running"
+		}
+		checks = append(checks, check)
+	}
+
+	return checks
+}
+
+// executablePath returns the path to look a command's executable up with. A relative path such
+// as ./script.sh is resolved against the command's working directory, as it is when run.
+func executablePath(cmd config.Command) string {
+	path := cmd.Command
+	if cmd.WorkDir != "" && strings.ContainsRune(path, filepath.Separator) && !filepath.IsAbs(path) {
+		path = filepath.Join(cmd.WorkDir, path)
+	}
+	return path
+}
+
+// usesDocker reports whether a command runs the docker or docker-compose executable
+func usesDocker(cmd config.Command) bool {
+	name := strings.TrimSuffix(filepath.Base(cmd.Command), ".exe")
+	return name == "docker" || name == "docker-compose"
+}
+
+// writeDoctorReport prints the result of each check and returns an error if any failed
+func writeDoctorReport(w io.Writer, checks []*doctorCheck) error {
+	fmt.Fprintf(w, "seqr Doctor\n")
+	fmt.Fprintf(w, "===========\n\n")
+
+	failed := 0
+	for _, check := range checks {
+		usedBy := strings.Join(check.usedBy, ", ")
+		if check.err != nil {
+			failed++
+			fmt.Fprintf(w, "  ✗ %s (used by %s): %v\n", check.description, usedBy, check.err)
+		} else {
+			fmt.Fprintf(w, "  ✓ %s (used by %s): %s\n", check.description, usedBy, check.detail)
+		}
+	}
+
+	fmt.Fprintf(w, "\n%d of %d checks passed\n", len(checks)-failed, len(checks))
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestCLI_RunDoctorFlagsMissingExecutable(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sh")
+	}
+
+	tempDir := t.TempDir()
+	configFile := filepath.Join(tempDir, "test.queue.json")
+	markerFile := filepath.Join(tempDir, "ran.txt")
+
+	configContent := `{
+		"version": "1.0",
+		"commands": [
+			{"name": "setup", "command": "sh", "args": ["-c", "touch ` + markerFile + `"], "mode": "once"},
+			{"name": "build", "command": "seqr-definitely-not-a-command", "mode": "once"},
+			{"name": "test", "command": "sh", "args": ["-c", "true"], "mode": "once"}
+		]
+	}`
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	cli := NewCLI([]string{"-f", configFile, "--doctor"})
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Failed to parse CLI args: %v", err)
+	}
+	if !cli.ShouldRunDoctor() {
+		t.Fatal("Expected --doctor to be requested")
+	}
+
+	var runErr error
+	output := captureStdout(t, func() { runErr = cli.RunDoctor(context.Background()) })
+	if runErr == nil || !strings.Contains(runErr.Error(), "1 of 2 checks failed") {
+		t.Errorf("Expected one failed check, got %v", runErr)
+	}
+
+	// Commands sharing an executable are checked once
+	for _, expected := range []string{
+		"✗ executable 'seqr-definitely-not-a-command' (used by build)",
+		"✓ executable 'sh' (used by setup, test)",
+		"1 of 2 checks passed",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in report, got:\n%s", expected, output)
+		}
+	}
+	if _, err := os.Stat(markerFile); err == nil {
+		t.Error("Expected --doctor not to run any command")
+	}
+}
+
+func TestDoctorChecksDockerDaemon(t *testing.T) {
+	original := dockerPing
+	defer func() { dockerPing = original }()
+
+	pinged := false
+	dockerPing = func(ctx context.Context) error {
+		pinged = true
+		return nil
+	}
+
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "db", Command: "docker", Args: []string{"run", "postgres"}, Mode: config.ModeKeepAlive},
+			{Name: "api", Command: "/usr/local/bin/docker-compose", Args: []string{"up"}, Mode: config.ModeKeepAlive},
+		},
+	}
+
+	checks := doctorChecks(context.Background(), cfg)
+	daemon := checks[len(checks)-1]
+	if daemon.description != "Docker daemon" || strings.Join(daemon.usedBy, ",") != "db,api" {
+		t.Fatalf("Expected a Docker daemon check used by db and api, got %+v", daemon)
+	}
+
+	// The daemon is only pinged when the docker executable is installed
+	if daemon.err == nil && !pinged {
+		t.Error("Expected the Docker daemon to be pinged")
+	}
+	if daemon.err != nil && !strings.Contains(daemon.err.Error(), "not installed") {
+		t.Errorf("Expected docker to be reported as not installed, got %v", daemon.err)
+	}
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/seqr-cli/seqr/internal/executor"
+)
+
+// ErrorFormats lists the formats fatal errors can be written in
+var ErrorFormats = []string{"text", "json"}
+
+// errorOutput is the JSON form of a fatal error, for CI systems to parse
+type errorOutput struct {
+	Error    string `json:"error"`
+	Type     string `json:"type,omitempty"`    // Why the command failed, from its ErrorDetail
+	Command  string `json:"command,omitempty"` // Name of the command that failed
+	ExitCode int    `json:"exitCode"`          // The failed command's exit code, or seqr's own exit status when no command failed
+}
+
+// ReportError writes a fatal error to stderr in the format chosen with --error-format
+func (c *CLI) ReportError(err error) {
+	c.writeError(os.Stderr, err)
+}
+
+// writeError writes err as an "Error: " line, or as a JSON object with the details of the
+// command that failed when the run got that far
+func (c *CLI) writeError(w io.Writer, err error) {
+	if c.options.ErrorFormat != "json" {
+		fmt.Fprintf(w, "Error: %v\n", err)
+		return
+	}
+
+	output := errorOutput{Error: err.Error(), ExitCode: ExitCode(err)}
+	if result := c.failedResult(); result != nil {
+		output.Type = string(result.ErrorDetail.Type)
+		output.Command = result.Command.Name
+		output.ExitCode = result.ErrorDetail.ExitCode
+	}
+
+	data, marshalErr := json.Marshal(output)
+	if marshalErr != nil {
+		fmt.Fprintf(w, "Error: %v\n", err)
+		return
+	}
+	fmt.Fprintf(w, "%s\n", data)
+}
+
+// failedResult returns the first failed command of the run, or nil if the run did not start or
+// no command failed other than those allowFailure let through
+func (c *CLI) failedResult() *executor.ExecutionResult {
+	if c.executor == nil {
+		return nil
+	}
+
+	results := c.executor.GetStatus().Results
+	for i := range results {
+		if !results[i].Success && !results[i].AllowedFailure && results[i].ErrorDetail != nil {
+			return &results[i]
+		}
+	}
+	return nil
+}
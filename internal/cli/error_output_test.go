@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCLI_WriteErrorAsJSON(t *testing.T) {
+	tempDir := t.TempDir()
+	configFile := filepath.Join(tempDir, "test.queue.json")
+
+	configContent := `{
+		"version": "1.0",
+		"commands": [
+			{"name": "build", "command": "seqr-definitely-not-a-command", "mode": "once"}
+		]
+	}`
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	cli := NewCLI([]string{"-f", configFile, "--error-format", "json"})
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Failed to parse CLI args: %v", err)
+	}
+
+	var runErr error
+	captureStdout(t, func() { runErr = cli.Run(context.Background()) })
+	if runErr == nil {
+		t.Fatal("Expected the run to fail")
+	}
+
+	var buf bytes.Buffer
+	cli.writeError(&buf, runErr)
+
+	var output map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &output); err != nil {
+		t.Fatalf("Expected a JSON error, got %q: %v", buf.String(), err)
+	}
+
+	expected := map[string]interface{}{
+		"error":    runErr.Error(),
+		"type":     "command_not_found",
+		"command":  "build",
+		"exitCode": float64(-1),
+	}
+	for key, value := range expected {
+		if output[key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, output[key])
+		}
+	}
+	if len(output) != len(expected) {
+		t.Errorf("Expected exactly the fields %v, got %v", expected, output)
+	}
+}
+
+func TestCLI_WriteErrorSkipsAllowedFailures(t *testing.T) {
+	tempDir := t.TempDir()
+	configFile := filepath.Join(tempDir, "test.queue.json")
+
+	configContent := `{
+		"version": "1.0",
+		"commands": [
+			{"name": "lint", "command": "seqr-definitely-not-a-linter", "mode": "once", "allowFailure": true},
+			{"name": "build", "command": "sh", "args": ["-c", "exit 4"], "mode": "once"}
+		]
+	}`
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	cli := NewCLI([]string{"-f", configFile, "--error-format", "json"})
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Failed to parse CLI args: %v", err)
+	}
+
+	var runErr error
+	captureStdout(t, func() { runErr = cli.Run(context.Background()) })
+	if runErr == nil {
+		t.Fatal("Expected the run to fail")
+	}
+
+	var buf bytes.Buffer
+	cli.writeError(&buf, runErr)
+
+	var output map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &output); err != nil {
+		t.Fatalf("Expected a JSON error, got %q: %v", buf.String(), err)
+	}
+	if output["command"] != "build" || output["exitCode"] != float64(4) {
+		t.Errorf("Expected the error to name the failure that failed the run, got %v", output)
+	}
+}
+
+func TestCLI_WriteErrorWithoutFailedCommand(t *testing.T) {
+	cli := NewCLI([]string{"--error-format", "json"})
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Failed to parse CLI args: %v", err)
+	}
+
+	var buf bytes.Buffer
+	cli.writeError(&buf, errors.New("failed to load configuration"))
+	if got := strings.TrimSpace(buf.String()); got != `{"error":"failed to load configuration","exitCode":1}` {
+		t.Errorf("Unexpected JSON error: %s", got)
+	}
+
+	// Text stays the default
+	cli = NewCLI([]string{})
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Failed to parse CLI args: %v", err)
+	}
+	buf.Reset()
+	cli.writeError(&buf, errors.New("boom"))
+	if buf.String() != "Error: boom\n" {
+		t.Errorf("Expected a plain error line, got %q", buf.String())
+	}
+
+	if err := NewCLI([]string{"--error-format", "xml"}).Parse(); err == nil || !strings.Contains(err.Error(), "unsupported error format 'xml'") {
+		t.Errorf("Expected an unsupported format error, got %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+package cli
+
+import "errors"
+
+// Exit codes seqr ends with, so CI can tell a bad queue from a failed command
+const (
+	ExitFailure = 1 // A command failed, or seqr could not complete what was asked
+	ExitUsage   = 2 // Invalid command-line flags or arguments
+	ExitConfig  = 3 // The queue configuration could not be loaded or is invalid
+)
+
+// ConfigError reports a queue configuration that could not be loaded or failed validation
+type ConfigError struct {
+	Err error
+}
+
+// Error implements the error interface
+func (e *ConfigError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying load or validation error
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit code for an error returned by Run or one of the other commands:
+// ExitConfig for configuration errors, ExitFailure for everything else
+func ExitCode(err error) int {
+	var configErr *ConfigError
+	if errors.As(err, &configErr) {
+		return ExitConfig
+	}
+	return ExitFailure
+}
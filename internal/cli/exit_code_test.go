@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	tempDir := t.TempDir()
+
+	tests := []struct {
+		name          string
+		configContent string
+		args          []string
+		expected      int
+	}{
+		{
+			name:          "invalid config",
+			configContent: `{"version": "1.0", "commands": [{"name": "build", "command": "true", "mode": "sometimes"}]}`,
+			expected:      ExitConfig,
+		},
+		{
+			name:          "malformed config",
+			configContent: `{"version": "1.0", "commands": [`,
+			expected:      ExitConfig,
+		},
+		{
+			name:          "missing workDir with --check-paths",
+			configContent: `{"version": "1.0", "commands": [{"name": "build", "command": "true", "mode": "once", "workDir": "missing"}]}`,
+			args:          []string{"--check-paths"},
+			expected:      ExitConfig,
+		},
+		{
+			name:          "failing command",
+			configContent: `{"version": "1.0", "commands": [{"name": "build", "command": "sh", "args": ["-c", "exit 4"], "mode": "once"}]}`,
+			expected:      ExitFailure,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configFile := filepath.Join(tempDir, "test"+string(rune('a'+i))+".queue.json")
+			if err := os.WriteFile(configFile, []byte(tt.configContent), 0644); err != nil {
+				t.Fatalf("Failed to create config: %v", err)
+			}
+
+			cli := NewCLI(append([]string{"-f", configFile}, tt.args...))
+			if err := cli.Parse(); err != nil {
+				t.Fatalf("Failed to parse CLI args: %v", err)
+			}
+
+			var runErr error
+			captureStdout(t, func() { runErr = cli.Run(context.Background()) })
+			if runErr == nil {
+				t.Fatal("Expected the run to fail")
+			}
+			if got := ExitCode(runErr); got != tt.expected {
+				t.Errorf("Expected exit code %d for %v, got %d", tt.expected, runErr, got)
+			}
+		})
+	}
+}
+
+func TestExitCodeOfMissingConfigFile(t *testing.T) {
+	cli := NewCLI([]string{"-f", filepath.Join(t.TempDir(), "missing.queue.json")})
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Failed to parse CLI args: %v", err)
+	}
+
+	err := cli.Run(context.Background())
+	var configErr *ConfigError
+	if !errors.As(err, &configErr) || ExitCode(err) != ExitConfig {
+		t.Errorf("Expected a configuration error with exit code %d, got %v", ExitConfig, err)
+	}
+}
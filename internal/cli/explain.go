@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/seqr-cli/seqr/internal/config"
+	"github.com/seqr-cli/seqr/internal/executor"
+)
+
+// commandExplanation is whether a command will run and why
+type commandExplanation struct {
+	name    string
+	runs    bool
+	reasons []string
+}
+
+// RunExplain prints, for each command in the queue, whether a run with the same flags would
+// run it and why: --retry-failed selection, an unchanged cacheKey, and afterReady waits.
+// Nothing is run.
+func (c *CLI) RunExplain() error {
+	cfg, err := c.loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	stateDir := executor.NewProcessManager().GetStateDir()
+	var lastRun *executor.ExecutionStatus
+	if c.options.RetryFailed {
+		if lastRun, err = c.loadLastRun(stateDir, os.Stderr); err != nil {
+			return err
+		}
+	}
+
+	writeExplanation(os.Stdout, explainCommands(cfg.Commands, lastRun, stateDir))
+	return nil
+}
+
+// explainCommands decides whether each command will run. With lastRun, only the commands
+// retryCommands selects from it run, as with --retry-failed.
+func explainCommands(commands []config.Command, lastRun *executor.ExecutionStatus, stateDir string) []commandExplanation {
+	var retried map[string]bool
+	if lastRun != nil {
+		retried = make(map[string]bool)
+		for _, cmd := range retryCommands(commands, lastRun, io.Discard) {
+			retried[cmd.Name] = true
+		}
+	}
+
+	explanations := make([]commandExplanation, 0, len(commands))
+	for _, cmd := range commands {
+		explanation := commandExplanation{name: cmd.Name, runs: true}
+
+		if lastRun != nil {
+			explanation.reasons = append(explanation.reasons, retryReason(cmd, commands, lastRun, retried))
+			if !retried[cmd.Name] {
+				explanation.runs = false
+				explanations = append(explanations, explanation)
+				continue
+			}
+		}
+
+		cached, err := executor.IsCached(cmd, stateDir)
+		switch {
+		case err != nil:
+			explanation.reasons = append(explanation.reasons, fmt.Sprintf("cacheKey cannot be checked (%v), so it runs uncached", err))
+		case cached:
+			explanation.runs = false
+			explanation.reasons = append(explanation.reasons, "cacheKey inputs unchanged since its last successful run")
+			explanations = append(explanations, explanation)
+			continue
+		case len(cmd.CacheKey) > 0 && cmd.Mode == config.ModeOnce:
+			explanation.reasons = append(explanation.reasons, "cacheKey inputs changed since its last successful run, or it has not succeeded yet")
+		}
+
+		for _, name := range cmd.AfterReady {
+			explanation.reasons = append(explanation.reasons, fmt.Sprintf("waits for '%s' to be ready (afterReady)", name))
+		}
+		if cmd.Mode == config.ModeKeepAlive {
+			explanation.reasons = append(explanation.reasons, "keepAlive, left running once started")
+		}
+		if cmd.AllowFailure {
+			explanation.reasons = append(explanation.reasons, "its failure does not stop the queue (allowFailure)")
+		}
+		explanations = append(explanations, explanation)
+	}
+	return explanations
+}
+
+// retryReason returns why --retry-failed, whose selection is retried, selects a command or
+// leaves it out
+func retryReason(cmd config.Command, commands []config.Command, lastRun *executor.ExecutionStatus, retried map[string]bool) string {
+	succeeded, allowed := false, false
+	for _, result := range lastRun.Results {
+		if result.Command.Name != cmd.Name {
+			continue
+		}
+		switch {
+		case result.AllowedFailure:
+			allowed = true
+		case !result.Success:
+			return "selected by --retry-failed, it failed in the last run"
+		default:
+			succeeded = true
+		}
+	}
+	if slices.Contains(lastRun.Skipped, cmd.Name) {
+		return "selected by --retry-failed, it never started in the last run"
+	}
+
+	if retried[cmd.Name] {
+		for _, name := range cmd.AfterReady {
+			if retried[name] {
+				return fmt.Sprintf("selected by --retry-failed, it waits for '%s', which is retried (afterReady)", name)
+			}
+		}
+		for _, other := range commands {
+			if retried[other.Name] && slices.Contains(other.AfterReady, cmd.Name) {
+				return fmt.Sprintf("selected by --retry-failed, '%s' is retried and waits for it (afterReady)", other.Name)
+			}
+		}
+	}
+	if allowed {
+		return "not selected by --retry-failed, allowFailure let its failure in the last run through"
+	}
+	if succeeded {
+		return "not selected by --retry-failed, it succeeded in the last run"
+	}
+	return "not selected by --retry-failed, it was not part of the last run"
+}
+
+// writeExplanation prints one line per command and how many of them will run
+func writeExplanation(w io.Writer, explanations []commandExplanation) {
+	fmt.Fprintf(w, "seqr Explain\n")
+	fmt.Fprintf(w, "============\n\n")
+
+	runs := 0
+	for _, explanation := range explanations {
+		mark, verdict := "✗", "skipped"
+		if explanation.runs {
+			runs++
+			mark, verdict = "✓", "runs"
+		}
+		line := fmt.Sprintf("  %s %s: %s", mark, explanation.name, verdict)
+		if len(explanation.reasons) > 0 {
+			line += "; " + strings.Join(explanation.reasons, "; ")
+		}
+		fmt.Fprintf(w, "%s\n", line)
+	}
+
+	fmt.Fprintf(w, "\n%d of %d commands will run\n", runs, len(explanations))
+}
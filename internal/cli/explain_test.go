@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/seqr-cli/seqr/internal/config"
+	"github.com/seqr-cli/seqr/internal/executor"
+)
+
+// explain runs seqr --explain with args and returns what it printed
+func explain(t *testing.T, args ...string) string {
+	t.Helper()
+
+	cli := NewCLI(append(args, "--explain"))
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Failed to parse CLI args: %v", err)
+	}
+	if !cli.ShouldRunExplain() {
+		t.Fatal("Expected --explain to be requested")
+	}
+
+	var runErr error
+	output := captureStdout(t, func() { runErr = cli.RunExplain() })
+	if runErr != nil {
+		t.Fatalf("Expected the explanation to be printed, got %v", runErr)
+	}
+	return output
+}
+
+func TestCLI_RunExplainRetryFailed(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("TMPDIR", stateDir)
+
+	configFile := filepath.Join(t.TempDir(), "test.queue.json")
+	configContent := `{
+		"version": "1.0",
+		"commands": [
+			{"name": "build", "command": "make"},
+			{"name": "db", "command": "postgres", "mode": "keepAlive", "concurrent": true},
+			{"name": "test", "command": "make", "args": ["test"], "concurrent": true, "afterReady": ["db"]},
+			{"name": "deploy", "command": "make", "args": ["deploy"]}
+		]
+	}`
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	// A run where test failed, so deploy never started
+	seeded := executor.ExecutionStatus{
+		State: executor.StateFailed,
+		Results: []executor.ExecutionResult{
+			{Command: config.Command{Name: "build", Command: "make", Mode: config.ModeOnce}, Success: true},
+			{Command: config.Command{Name: "db", Command: "postgres", Mode: config.ModeKeepAlive}, Success: true},
+			{Command: config.Command{Name: "test", Command: "make", Mode: config.ModeOnce}, Success: false},
+		},
+		Skipped: []string{"deploy"},
+	}
+	if err := executor.SaveLastRun(stateDir, executor.LastRun{ExecutionStatus: seeded, ConfigPath: configFile}); err != nil {
+		t.Fatalf("Failed to save last run: %v", err)
+	}
+
+	output := explain(t, "-f", configFile)
+	for _, expected := range []string{
+		"  ✓ build: runs\n",
+		"  ✓ db: runs; keepAlive, left running once started\n",
+		"  ✓ test: runs; waits for 'db' to be ready (afterReady)\n",
+		"4 of 4 commands will run\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in explanation, got:\n%s", expected, output)
+		}
+	}
+
+	output = explain(t, "-f", configFile, "--retry-failed")
+	for _, expected := range []string{
+		"  ✗ build: skipped; not selected by --retry-failed, it succeeded in the last run\n",
+		"  ✓ db: runs; selected by --retry-failed, 'test' is retried and waits for it (afterReady); keepAlive, left running once started\n",
+		"  ✓ test: runs; selected by --retry-failed, it failed in the last run; waits for 'db' to be ready (afterReady)\n",
+		"  ✓ deploy: runs; selected by --retry-failed, it never started in the last run\n",
+		"3 of 4 commands will run\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in explanation, got:\n%s", expected, output)
+		}
+	}
+}
+
+func TestCLI_RunExplainCacheKey(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires true")
+	}
+	t.Setenv("TMPDIR", t.TempDir())
+
+	tempDir := t.TempDir()
+	input := filepath.Join(tempDir, "input.txt")
+	if err := os.WriteFile(input, []byte("v1"), 0644); err != nil {
+		t.Fatalf("Failed to create cacheKey input: %v", err)
+	}
+
+	configFile := filepath.Join(tempDir, "test.queue.json")
+	configContent := `{
+		"version": "1.0",
+		"commands": [
+			{"name": "generate", "command": "true", "cacheKey": ["` + filepath.ToSlash(input) + `"]}
+		]
+	}`
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	want := "  ✓ generate: runs; cacheKey inputs changed since its last successful run, or it has not succeeded yet\n"
+	if output := explain(t, "-f", configFile); !strings.Contains(output, want) {
+		t.Errorf("Expected %q before the first run, got:\n%s", want, output)
+	}
+
+	cli := NewCLI([]string{"-f", configFile})
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Failed to parse CLI args: %v", err)
+	}
+	captureStdout(t, func() {
+		if err := cli.Run(context.Background()); err != nil {
+			t.Errorf("Run failed: %v", err)
+		}
+	})
+
+	want = "  ✗ generate: skipped; cacheKey inputs unchanged since its last successful run\n"
+	if output := explain(t, "-f", configFile); !strings.Contains(output, want) || !strings.Contains(output, "0 of 1 commands will run") {
+		t.Errorf("Expected %q after a successful run, got:\n%s", want, output)
+	}
+
+	if err := os.WriteFile(input, []byte("v2"), 0644); err != nil {
+		t.Fatalf("Failed to change cacheKey input: %v", err)
+	}
+	if output := explain(t, "-f", configFile); !strings.Contains(output, "  ✓ generate: runs;") {
+		t.Errorf("Expected the changed input to make the command run, got:\n%s", output)
+	}
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+// RunGraph prints the queue's command graph in Graphviz DOT, for piping to dot. Nothing is run.
+func (c *CLI) RunGraph() error {
+	cfg, err := c.loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	writeGraph(os.Stdout, cfg)
+	return nil
+}
+
+// writeGraph writes the queue as a DOT digraph. Each command is a node and an edge points
+// from a command to those that start after it: the commands of the next group in the queue,
+// where a run of concurrent commands forms one group drawn as a cluster. afterReady waits
+// are drawn as dashed edges from the keepAlive command waited for.
+func writeGraph(w io.Writer, cfg *config.Config) {
+	fmt.Fprintf(w, "digraph seqr {\n")
+	fmt.Fprintf(w, "  rankdir=LR;\n")
+	fmt.Fprintf(w, "  node [shape=box];\n")
+
+	groups := commandGroups(cfg.Commands)
+	for i, group := range groups {
+		indent := "  "
+		if len(group) > 1 {
+			fmt.Fprintf(w, "  subgraph cluster_%d {\n", i)
+			fmt.Fprintf(w, "    label=\"concurrent\";\n")
+			fmt.Fprintf(w, "    style=dashed;\n")
+			indent = "    "
+		}
+		for _, cmd := range group {
+			attributes := fmt.Sprintf("label=%s", dotQuote(fmt.Sprintf("%s\n%s", cmd.Name, cmd.Mode)))
+			if cmd.Mode == config.ModeKeepAlive {
+				attributes += ", style=rounded"
+			}
+			fmt.Fprintf(w, "%s%s [%s];\n", indent, dotQuote(cmd.Name), attributes)
+		}
+		if len(group) > 1 {
+			fmt.Fprintf(w, "  }\n")
+		}
+	}
+
+	for i := 1; i < len(groups); i++ {
+		for _, from := range groups[i-1] {
+			for _, to := range groups[i] {
+				fmt.Fprintf(w, "  %s -> %s;\n", dotQuote(from.Name), dotQuote(to.Name))
+			}
+		}
+	}
+
+	for _, cmd := range cfg.Commands {
+		for _, name := range cmd.AfterReady {
+			fmt.Fprintf(w, "  %s -> %s [style=dashed, label=\"afterReady\"];\n", dotQuote(name), dotQuote(cmd.Name))
+		}
+	}
+
+	fmt.Fprintf(w, "}\n")
+}
+
+// commandGroups splits the queue into the groups the executor runs one after another: each
+// run of consecutive concurrent commands, and each other command on its own
+func commandGroups(commands []config.Command) [][]config.Command {
+	var groups [][]config.Command
+	var concurrent []config.Command
+
+	for _, cmd := range commands {
+		if cmd.Concurrent {
+			if len(concurrent) > 0 && !config.ConcurrentWith(concurrent[len(concurrent)-1], cmd) {
+				groups = append(groups, concurrent)
+				concurrent = nil
+			}
+			concurrent = append(concurrent, cmd)
+			continue
+		}
+		if len(concurrent) > 0 {
+			groups = append(groups, concurrent)
+			concurrent = nil
+		}
+		groups = append(groups, []config.Command{cmd})
+	}
+	if len(concurrent) > 0 {
+		groups = append(groups, concurrent)
+	}
+
+	return groups
+}
+
+// dotQuote quotes s as a DOT string, escaping backslashes and double quotes and turning
+// newlines into DOT's centered line breaks
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
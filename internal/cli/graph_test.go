@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCLI_RunGraphPrintsDOT(t *testing.T) {
+	tempDir := t.TempDir()
+	configFile := filepath.Join(tempDir, "test.queue.json")
+	markerFile := filepath.Join(tempDir, "ran.txt")
+
+	configContent := `{
+		"version": "1.0",
+		"commands": [
+			{"name": "setup", "command": "touch", "args": ["` + filepath.ToSlash(markerFile) + `"], "mode": "once"},
+			{"name": "db", "command": "sleep", "args": ["60"], "mode": "keepAlive", "concurrent": true},
+			{"name": "cache", "command": "sleep", "args": ["60"], "mode": "keepAlive", "concurrent": true},
+			{"name": "migrate", "command": "true", "mode": "once", "afterReady": ["db"]}
+		]
+	}`
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	cli := NewCLI([]string{"-f", configFile, "--graph"})
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Failed to parse CLI args: %v", err)
+	}
+	if !cli.ShouldRunGraph() {
+		t.Fatal("Expected --graph to be requested")
+	}
+
+	var runErr error
+	output := captureStdout(t, func() { runErr = cli.RunGraph() })
+	if runErr != nil {
+		t.Fatalf("Expected the graph to be printed, got %v", runErr)
+	}
+
+	for _, expected := range []string{
+		"digraph seqr {",
+		`"setup" [label="setup\nonce"];`,
+		`"db" [label="db\nkeepAlive", style=rounded];`,
+		"subgraph cluster_1 {",
+		`"setup" -> "db";`,
+		`"setup" -> "cache";`,
+		`"db" -> "migrate";`,
+		`"cache" -> "migrate";`,
+		`"db" -> "migrate" [style=dashed, label="afterReady"];`,
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in graph, got:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, `"setup" -> "migrate";`) {
+		t.Errorf("Expected edges only between consecutive groups, got:\n%s", output)
+	}
+	if _, err := os.Stat(markerFile); err == nil {
+		t.Error("Expected --graph not to run any command")
+	}
+}
+
+func TestDotQuoteEscapesSpecialCharacters(t *testing.T) {
+	if got := dotQuote(`say "hi" \ bye`); got != `"say \"hi\" \\ bye"` {
+		t.Errorf("Unexpected quoting: %s", got)
+	}
+}
@@ -1,6 +1,9 @@
 package cli
 
-import "context"
+import (
+	"context"
+	"os"
+)
 
 // Interface defines the contract for CLI implementations
 type Interface interface {
@@ -17,7 +20,7 @@ type Interface interface {
 	ShouldShowVersion() bool
 
 	// ShowVersion displays version information
-	ShowVersion(version string)
+	ShowVersion(build BuildInfo)
 
 	// ShouldRunInit returns true if init should be executed
 	ShouldRunInit() bool
@@ -37,6 +40,60 @@ type Interface interface {
 	// RunStatus shows the status of running seqr processes
 	RunStatus() error
 
+	// ShouldRunLast returns true if the last run summary should be shown
+	ShouldRunLast() bool
+
+	// RunLast shows the summary of the most recent completed run
+	RunLast() error
+
+	// ShouldRunDumpEnv returns true if a command's environment should be printed
+	ShouldRunDumpEnv() bool
+
+	// RunDumpEnv prints the environment a command would run with, without running it
+	RunDumpEnv() error
+
+	// ShouldRunDoctor returns true if the queue's prerequisites should be checked
+	ShouldRunDoctor() bool
+
+	// RunDoctor checks that the queue's executables and services are available
+	RunDoctor(ctx context.Context) error
+
+	// ShouldRunGraph returns true if the queue's command graph should be printed
+	ShouldRunGraph() bool
+
+	// RunGraph prints the queue's command graph in Graphviz DOT
+	RunGraph() error
+
+	// ShouldRunLint returns true if the queue should be checked for anti-patterns
+	ShouldRunLint() bool
+
+	// RunLint prints warnings about anti-patterns in the queue
+	RunLint() error
+
+	// ShouldRunExplain returns true if the commands that would run should be explained
+	ShouldRunExplain() bool
+
+	// RunExplain prints whether each command would run and why
+	RunExplain() error
+
+	// ShouldRunDiff returns true if two queue files should be compared
+	ShouldRunDiff() bool
+
+	// RunDiff prints the commands added, removed and changed between two queue files
+	RunDiff() error
+
+	// ShouldRunLogs returns true if logs should be followed
+	ShouldRunLogs() bool
+
+	// RunLogs follows the live logs of running keepAlive processes
+	RunLogs(ctx context.Context) error
+
+	// ShouldRunCompletion returns true if a shell completion script should be printed
+	ShouldRunCompletion() bool
+
+	// RunCompletion prints a shell completion script
+	RunCompletion() error
+
 	// ShouldRunWatch returns true if watch should be executed
 	ShouldRunWatch() bool
 
@@ -53,6 +110,23 @@ type Interface interface {
 	// Returns true if detachment was successful, false if no streaming was active
 	TryDetachFromStreaming() bool
 
+	// HandleInterrupts stops the run on SIGINT or SIGTERM, first detaching from streamed output
+	// unless NoDetach is set. It returns once the run is stopped.
+	HandleInterrupts(signals <-chan os.Signal, cancel context.CancelFunc)
+
+	// ForwardSignal relays a parent signal to the keepAlive processes configured to receive it
+	ForwardSignal(sig os.Signal) error
+
+	// HandleForwardedSignals relays the signals the loaded config forwards once Run has loaded
+	// it. Signals the config does not forward keep their default action.
+	HandleForwardedSignals()
+
+	// HandleSuspend pauses and resumes the keepAlive processes along with seqr on Ctrl+Z
+	HandleSuspend()
+
+	// ReportError writes a fatal error to stderr in the format chosen with --error-format
+	ReportError(err error)
+
 	// GetOptions returns the parsed CLI options
 	GetOptions() CLIOptions
 }
@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"context"
+	"os"
+)
+
+// HandleInterrupts stops the run when a signal arrives. By default the first signal only
+// detaches from the output of streaming keepAlive processes, leaving them running, and the
+// second stops everything; with NoDetach, or when nothing is streaming, the first one does.
+func (c *CLI) HandleInterrupts(signals <-chan os.Signal, cancel context.CancelFunc) {
+	detached := false
+	for range signals {
+		if !c.options.NoDetach && !detached && c.TryDetachFromStreaming() {
+			detached = true
+			continue
+		}
+
+		cancel()
+		c.Stop()
+		return
+	}
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+	"github.com/seqr-cli/seqr/internal/executor"
+)
+
+// startStreaming runs a keepAlive command with verbose output, so its output is being streamed
+func startStreaming(t *testing.T, cli *CLI) {
+	t.Helper()
+	t.Setenv("TMPDIR", t.TempDir())
+
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "server", Command: "sleep", Args: []string{"30"}, Mode: config.ModeKeepAlive},
+		},
+	}
+
+	cli.executor = executor.NewExecutor(true)
+	captureStdout(t, func() {
+		if err := cli.executor.Execute(context.Background(), cfg); err != nil {
+			t.Fatalf("Execute returned error: %v", err)
+		}
+	})
+	t.Cleanup(func() {
+		captureStdout(t, cli.Stop)
+		// The stopped process is untracked in the background; let that finish writing into
+		// the temporary directory before it is removed
+		deadline := time.Now().Add(5 * time.Second)
+		for executor.NewProcessTracker().GetRunningProcessCount() > 0 && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+
+	if !cli.executor.HasActiveStreaming() {
+		t.Fatal("Expected the keepAlive output to be streaming")
+	}
+}
+
+func TestCLI_HandleInterrupts(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sleep")
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		stopsAfter int // Signals needed before the run is cancelled
+	}{
+		{name: "detaches on the first signal by default", args: nil, stopsAfter: 2},
+		{name: "stops on the first signal with --no-detach", args: []string{"--no-detach"}, stopsAfter: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := NewCLI(tt.args)
+			if err := cli.Parse(); err != nil {
+				t.Fatalf("Failed to parse CLI args: %v", err)
+			}
+			startStreaming(t, cli)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			signals := make(chan os.Signal)
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				cli.HandleInterrupts(signals, cancel)
+			}()
+
+			captureStdout(t, func() {
+				for i := 1; i < tt.stopsAfter; i++ {
+					signals <- syscall.SIGINT
+				}
+			})
+
+			if tt.stopsAfter > 1 {
+				if ctx.Err() != nil {
+					t.Fatal("Expected the first signal not to cancel the run")
+				}
+				if cli.executor.HasActiveStreaming() {
+					t.Error("Expected the first signal to detach from streaming")
+				}
+				if !cli.executor.HasActiveKeepAliveProcesses() {
+					t.Error("Expected the keepAlive process to keep running after detaching")
+				}
+			}
+
+			captureStdout(t, func() {
+				signals <- syscall.SIGINT
+				select {
+				case <-done:
+				case <-time.After(5 * time.Second):
+					t.Fatal("Expected HandleInterrupts to return after stopping the run")
+				}
+			})
+
+			if ctx.Err() == nil {
+				t.Error("Expected the run to be cancelled")
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+// RunLint prints warnings about anti-patterns in the queue, such as a sleep standing in for a
+// readiness check. Nothing is run. Warnings are advisory unless --strict is set, which turns
+// them into a configuration error.
+func (c *CLI) RunLint() error {
+	cfg, err := c.loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	warnings := config.Lint(cfg)
+	writeLintReport(os.Stdout, warnings)
+	if c.options.Strict && len(warnings) > 0 {
+		return &ConfigError{Err: fmt.Errorf("%d lint warnings with --strict", len(warnings))}
+	}
+	return nil
+}
+
+// writeLintReport prints each warning with its suggestion indented below it
+func writeLintReport(w io.Writer, warnings []config.LintWarning) {
+	fmt.Fprintf(w, "seqr Lint\n")
+	fmt.Fprintf(w, "=========\n\n")
+
+	for _, warning := range warnings {
+		message, suggestion, _ := strings.Cut(warning.String(), "\n")
+		fmt.Fprintf(w, "  ! %s\n", message)
+		if suggestion != "" {
+			fmt.Fprintf(w, "    %s\n", suggestion)
+		}
+	}
+
+	if len(warnings) > 0 {
+		fmt.Fprintf(w, "\n")
+	}
+	if len(warnings) == 1 {
+		fmt.Fprintf(w, "1 warning\n")
+	} else {
+		fmt.Fprintf(w, "%d warnings\n", len(warnings))
+	}
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCLI_RunLint(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "test.queue.json")
+	configContent := `{
+		"version": "1.0",
+		"commands": [
+			{"name": "db", "command": "postgres", "mode": "keepAlive"},
+			{"name": "wait", "command": "sleep", "args": ["5"]},
+			{"name": "migrate", "command": "migrate"}
+		]
+	}`
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantExit int
+	}{
+		{name: "warnings are advisory", args: []string{"-f", configFile, "--lint"}, wantExit: 0},
+		{name: "strict makes warnings fatal", args: []string{"-f", configFile, "--lint", "--strict"}, wantExit: ExitConfig},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := NewCLI(tt.args)
+			if err := cli.Parse(); err != nil {
+				t.Fatalf("Failed to parse CLI args: %v", err)
+			}
+			if !cli.ShouldRunLint() {
+				t.Fatal("Expected --lint to be requested")
+			}
+
+			var runErr error
+			output := captureStdout(t, func() { runErr = cli.RunLint() })
+
+			exit := 0
+			if runErr != nil {
+				exit = ExitCode(runErr)
+			}
+			if exit != tt.wantExit {
+				t.Errorf("Expected exit status %d, got %d (%v)", tt.wantExit, exit, runErr)
+			}
+			for _, expected := range []string{
+				"  ! command 'wait': sleeps, likely to wait for keepAlive command 'db' to come up\n",
+				"    Suggestion: Have 'db' create a readyFile",
+				"1 warning\n",
+			} {
+				if !strings.Contains(output, expected) {
+					t.Errorf("Expected %q in lint report, got:\n%s", expected, output)
+				}
+			}
+		})
+	}
+}
+
+func TestCLI_StrictRequiresLint(t *testing.T) {
+	cli := NewCLI([]string{"--strict"})
+	if err := cli.Parse(); err == nil || !strings.Contains(err.Error(), "--strict can only be used with --lint") {
+		t.Errorf("Expected --strict without --lint to be rejected, got %v", err)
+	}
+}
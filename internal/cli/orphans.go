@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/seqr-cli/seqr/internal/executor"
+)
+
+// orphanAction is what to do with the processes an earlier seqr run left running
+type orphanAction string
+
+const (
+	orphanTerminate orphanAction = "terminate"
+	orphanAdopt     orphanAction = "adopt"
+	orphanSkip      orphanAction = "skip"
+)
+
+// reapOrphans lists the processes still running from seqr runs that have exited, such as one
+// that crashed, and asks whether to terminate them, adopt them or leave them alone
+func (c *CLI) reapOrphans(in io.Reader, out io.Writer) error {
+	processManager := executor.NewProcessManager()
+
+	orphans, err := processManager.FindOrphans()
+	if err != nil {
+		return fmt.Errorf("failed to check for orphaned processes: %w", err)
+	}
+	if len(orphans) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(out, "Found %d process(es) left running by an earlier seqr run:\n", len(orphans))
+	for _, info := range orphans {
+		fmt.Fprintf(out, "  PID %d: %s (%s %v) - started %s\n",
+			info.PID, info.Name, info.Command, info.Args, info.StartTime.Format("2006-01-02 15:04:05"))
+	}
+
+	action, err := promptOrphanAction(in, out)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, info := range orphans {
+		switch action {
+		case orphanTerminate:
+			if err := processManager.TerminateOrphan(info.PID); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			fmt.Fprintf(out, "Terminated %s (PID %d)\n", info.Name, info.PID)
+		case orphanAdopt:
+			if err := processManager.AdoptProcess(info.PID); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			fmt.Fprintf(out, "Adopted %s (PID %d)\n", info.Name, info.PID)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// promptOrphanAction asks what to do with orphaned processes, leaving them alone without an
+// answer, as when input is not interactive
+func promptOrphanAction(in io.Reader, out io.Writer) (orphanAction, error) {
+	fmt.Fprintf(out, "What would you like to do?\n")
+	fmt.Fprintf(out, "  [t] Terminate them\n")
+	fmt.Fprintf(out, "  [a] Adopt them, so they are not reported while this run lasts (seqr --kill still stops them)\n")
+	fmt.Fprintf(out, "  [s] Skip, leaving them as they are\n")
+	fmt.Fprintf(out, "Choice (t/a/s): ")
+
+	reader := bufio.NewReader(in)
+	for {
+		input, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", fmt.Errorf("failed to read input: %w", err)
+		}
+
+		choice := strings.ToLower(strings.TrimSpace(input))
+		switch choice {
+		case "t", "terminate":
+			return orphanTerminate, nil
+		case "a", "adopt":
+			return orphanAdopt, nil
+		case "s", "skip":
+			return orphanSkip, nil
+		}
+
+		// Handle EOF (non-interactive mode) and empty input by defaulting to skip
+		if err == io.EOF {
+			fmt.Fprintf(out, "s (defaulting to skip in non-interactive mode)\n")
+			return orphanSkip, nil
+		}
+		if choice == "" {
+			return orphanSkip, nil
+		}
+		fmt.Fprintf(out, "Invalid choice '%s'. Please enter 't' (terminate), 'a' (adopt), or 's' (skip): ", choice)
+	}
+}
@@ -0,0 +1,88 @@
+//go:build !windows
+
+package cli
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/executor"
+)
+
+func TestCLI_ReapOrphans(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantOutput string
+		terminated bool
+		tracked    bool
+	}{
+		{name: "terminate", input: "t\n", wantOutput: "Terminated server", terminated: true},
+		{name: "adopt", input: "a\n", wantOutput: "Adopted server", tracked: true},
+		{name: "non-interactive", input: "", wantOutput: "defaulting to skip", tracked: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TMPDIR", t.TempDir())
+
+			cmd := exec.Command("sleep", "30")
+			if err := cmd.Start(); err != nil {
+				t.Fatalf("Failed to start process: %v", err)
+			}
+			exited := make(chan struct{})
+			go func() {
+				cmd.Wait()
+				close(exited)
+			}()
+			defer func() {
+				cmd.Process.Kill()
+				<-exited
+			}()
+
+			// Track the process as started by a seqr process that has exited
+			owner := exec.Command("true")
+			if err := owner.Run(); err != nil {
+				t.Fatalf("Failed to run owner process: %v", err)
+			}
+			tracker := executor.NewProcessTracker()
+			pid := cmd.Process.Pid
+			if err := tracker.AddProcess(pid, "server", "sleep", []string{"30"}, "", "keepAlive"); err != nil {
+				t.Fatalf("AddProcess failed: %v", err)
+			}
+			if err := tracker.SetOwner(pid, owner.Process.Pid); err != nil {
+				t.Fatalf("SetOwner failed: %v", err)
+			}
+
+			var out bytes.Buffer
+			if err := NewCLI(nil).reapOrphans(strings.NewReader(tt.input), &out); err != nil {
+				t.Fatalf("reapOrphans failed: %v", err)
+			}
+			if !strings.Contains(out.String(), "server (sleep [30])") || !strings.Contains(out.String(), tt.wantOutput) {
+				t.Errorf("Expected the orphan to be listed and %q, got:\n%s", tt.wantOutput, out.String())
+			}
+
+			if tt.terminated {
+				select {
+				case <-exited:
+				case <-time.After(2 * time.Second):
+					t.Error("Expected the orphan to be terminated")
+				}
+			} else {
+				select {
+				case <-exited:
+					t.Error("Expected the orphan to keep running")
+				default:
+				}
+			}
+
+			_, tracked := executor.NewProcessTracker().GetProcess(pid)
+			if tracked != tt.tracked {
+				t.Errorf("Expected tracked=%v, got %v", tt.tracked, tracked)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+// newRunID returns an ID for a run started at start. IDs sort by start time, and the random
+// suffix tells apart runs started in the same second.
+func newRunID(start time.Time) string {
+	suffix := make([]byte, 3)
+	rand.Read(suffix)
+	return start.Format("20060102-150405") + "-" + hex.EncodeToString(suffix)
+}
+
+// resolveReportPath expands the run's built-in variables in the path of a report, such as
+// reports/${RUN_ID}/junit.xml, and creates the directories it names
+func (c *CLI) resolveReportPath(path string) (string, error) {
+	resolved := config.ExpandVariables(path, config.RunVariables(c.runID, c.runStart))
+	if err := os.MkdirAll(filepath.Dir(resolved), 0755); err != nil {
+		return "", fmt.Errorf("failed to create the directory of '%s': %w", resolved, err)
+	}
+	return resolved, nil
+}
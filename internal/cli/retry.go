@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/seqr-cli/seqr/internal/config"
+	"github.com/seqr-cli/seqr/internal/executor"
+)
+
+// retryConfig narrows the queue to the commands that failed or never started in the last run,
+// taken from the current configuration so fixes to them apply
+func (c *CLI) retryConfig(cfg *config.Config, warnings io.Writer) (*config.Config, error) {
+	status, err := c.loadLastRun(executor.NewProcessManager().GetStateDir(), warnings)
+	if err != nil {
+		return nil, err
+	}
+
+	retry := *cfg
+	retry.Commands = retryCommands(cfg.Commands, status, warnings)
+	return &retry, nil
+}
+
+// loadLastRun loads the last run to retry from. The state directory holds a single last run
+// whatever queue it came from, so one of another configuration is refused rather than
+// matched against this queue by command name.
+func (c *CLI) loadLastRun(stateDir string, warnings io.Writer) (*executor.ExecutionStatus, error) {
+	lastRun, err := executor.LoadLastRun(stateDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load last run: %w", err)
+	}
+
+	source := c.configSource()
+	switch lastRun.ConfigPath {
+	case source:
+	case "":
+		fmt.Fprintf(warnings, "Warning: the last run did not record its configuration, assuming it was %s\n", source)
+	default:
+		return nil, fmt.Errorf("the last run used %s, not %s; run this configuration before retrying its failures", lastRun.ConfigPath, source)
+	}
+
+	return &lastRun.ExecutionStatus, nil
+}
+
+// retryCommands returns the commands that failed or were skipped in the last run, in queue
+// order, together with the commands that wait for them with afterReady and the commands they
+// wait for in turn. Failures allowFailure let through are not retried. Commands of the last run that are no longer in the queue are reported and
+// left out. --explain uses the same selection.
+func retryCommands(commands []config.Command, status *executor.ExecutionStatus, warnings io.Writer) []config.Command {
+	var names []string
+	for _, result := range status.Results {
+		if !result.Success && !result.AllowedFailure {
+			names = append(names, result.Command.Name)
+		}
+	}
+	names = append(names, status.Skipped...)
+
+	selected := make(map[string]bool, len(names))
+	for _, name := range names {
+		selected[name] = true
+	}
+	addAfterReadyDependents(commands, selected)
+	addAfterReadyDependencies(commands, selected)
+
+	var retry []config.Command
+	for _, cmd := range commands {
+		if selected[cmd.Name] {
+			retry = append(retry, cmd)
+		}
+	}
+
+	for _, name := range names {
+		if !slices.ContainsFunc(commands, func(cmd config.Command) bool { return cmd.Name == name }) {
+			fmt.Fprintf(warnings, "Warning: command '%s' from the last run is no longer in the configuration, skipping it\n", name)
+		}
+	}
+
+	return retry
+}
+
+// addAfterReadyDependents adds to selected every command that waits for a selected command with
+// afterReady, transitively, so that commands relying on a retried one run against it again
+func addAfterReadyDependents(commands []config.Command, selected map[string]bool) {
+	for added := true; added; {
+		added = false
+		for _, cmd := range commands {
+			if selected[cmd.Name] {
+				continue
+			}
+			if slices.ContainsFunc(cmd.AfterReady, func(name string) bool { return selected[name] }) {
+				selected[cmd.Name] = true
+				added = true
+			}
+		}
+	}
+}
+
+// addAfterReadyDependencies adds to selected every command a selected command waits for with
+// afterReady, transitively, so that a retried command does not wait for one left out of the run
+func addAfterReadyDependencies(commands []config.Command, selected map[string]bool) {
+	byName := make(map[string]config.Command, len(commands))
+	for _, cmd := range commands {
+		byName[cmd.Name] = cmd
+	}
+
+	var pending []string
+	for name := range selected {
+		pending = append(pending, name)
+	}
+	for len(pending) > 0 {
+		name := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		for _, dependency := range byName[name].AfterReady {
+			if !selected[dependency] {
+				selected[dependency] = true
+				pending = append(pending, dependency)
+			}
+		}
+	}
+}
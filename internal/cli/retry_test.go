@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/seqr-cli/seqr/internal/config"
+	"github.com/seqr-cli/seqr/internal/executor"
+)
+
+func TestRetryCommands(t *testing.T) {
+	commands := []config.Command{
+		{Name: "build"},
+		{Name: "test"},
+		{Name: "lint"},
+		{Name: "deploy"},
+	}
+	status := &executor.ExecutionStatus{
+		State: executor.StateFailed,
+		Results: []executor.ExecutionResult{
+			{Command: config.Command{Name: "build"}, Success: true},
+			{Command: config.Command{Name: "test"}, Success: false},
+			{Command: config.Command{Name: "lint"}, Success: false, AllowedFailure: true},
+			{Command: config.Command{Name: "e2e"}, Success: false},
+		},
+		Skipped: []string{"deploy"},
+	}
+
+	var warnings bytes.Buffer
+	retry := retryCommands(commands, status, &warnings)
+
+	var names []string
+	for _, cmd := range retry {
+		names = append(names, cmd.Name)
+	}
+	if strings.Join(names, ",") != "test,deploy" {
+		t.Errorf("Expected the failed and skipped commands in queue order, without the allowed failure, got %v", names)
+	}
+	if !strings.Contains(warnings.String(), "command 'e2e' from the last run is no longer in the configuration") {
+		t.Errorf("Expected a warning about the removed command, got: %q", warnings.String())
+	}
+}
+
+func TestRetryCommandsIncludesAfterReadyDependencies(t *testing.T) {
+	commands := []config.Command{
+		{Name: "db", Mode: config.ModeKeepAlive},
+		{Name: "cache", Mode: config.ModeKeepAlive},
+		{Name: "api", Mode: config.ModeKeepAlive, AfterReady: []string{"db"}},
+		{Name: "e2e", AfterReady: []string{"api"}},
+		{Name: "lint"},
+	}
+	status := &executor.ExecutionStatus{
+		State: executor.StateFailed,
+		Results: []executor.ExecutionResult{
+			{Command: config.Command{Name: "db"}, Success: true},
+			{Command: config.Command{Name: "cache"}, Success: true},
+			{Command: config.Command{Name: "api"}, Success: true},
+			{Command: config.Command{Name: "e2e"}, Success: false},
+			{Command: config.Command{Name: "lint"}, Success: true},
+		},
+	}
+
+	var names []string
+	for _, cmd := range retryCommands(commands, status, io.Discard) {
+		names = append(names, cmd.Name)
+	}
+	if strings.Join(names, ",") != "db,api,e2e" {
+		t.Errorf("Expected the failed command and the commands it transitively waits for, got %v", names)
+	}
+}
+
+func TestRetryCommandsIncludesAfterReadyDependents(t *testing.T) {
+	commands := []config.Command{
+		{Name: "db", Mode: config.ModeKeepAlive},
+		{Name: "api", Mode: config.ModeKeepAlive, AfterReady: []string{"db", "cache"}},
+		{Name: "cache", Mode: config.ModeKeepAlive},
+		{Name: "e2e", AfterReady: []string{"api"}},
+		{Name: "lint"},
+	}
+	status := &executor.ExecutionStatus{
+		State: executor.StateFailed,
+		Results: []executor.ExecutionResult{
+			{Command: config.Command{Name: "db"}, Success: false},
+			{Command: config.Command{Name: "api"}, Success: true},
+			{Command: config.Command{Name: "cache"}, Success: true},
+			{Command: config.Command{Name: "e2e"}, Success: true},
+			{Command: config.Command{Name: "lint"}, Success: true},
+		},
+	}
+
+	var names []string
+	for _, cmd := range retryCommands(commands, status, io.Discard) {
+		names = append(names, cmd.Name)
+	}
+	if strings.Join(names, ",") != "db,api,cache,e2e" {
+		t.Errorf("Expected the failed command, its dependents and their dependencies, got %v", names)
+	}
+}
+
+func TestCLI_RetryFailedRunsOnlyFailedCommands(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("TMPDIR", stateDir)
+
+	tempDir := t.TempDir()
+	configFile := filepath.Join(tempDir, "test.queue.json")
+	marker := filepath.Join(tempDir, "ran")
+	configContent := `{
+		"version": "1.0",
+		"commands": [
+			{"name": "build", "command": "sh", "args": ["-c", "echo build >> ` + marker + `"], "mode": "once"},
+			{"name": "test", "command": "sh", "args": ["-c", "echo test >> ` + marker + `"], "mode": "once"},
+			{"name": "deploy", "command": "sh", "args": ["-c", "echo deploy >> ` + marker + `"], "mode": "once"}
+		]
+	}`
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	retry := func() (string, error) {
+		cli := NewCLI([]string{"-f", configFile, "--retry-failed"})
+		if err := cli.Parse(); err != nil {
+			t.Fatalf("Failed to parse CLI args: %v", err)
+		}
+		var runErr error
+		output := captureStdout(t, func() { runErr = cli.Run(context.Background()) })
+		return output, runErr
+	}
+
+	// Without a recorded run there is nothing to retry from
+	if _, err := retry(); err == nil || !strings.Contains(err.Error(), "failed to load last run") {
+		t.Fatalf("Expected an error without a last run, got %v", err)
+	}
+
+	// A run where test failed, so deploy never started
+	seeded := executor.ExecutionStatus{
+		State:          executor.StateFailed,
+		CompletedCount: 1,
+		TotalCount:     3,
+		LastError:      "exit status 1",
+		Results: []executor.ExecutionResult{
+			{Command: config.Command{Name: "build", Command: "sh", Mode: config.ModeOnce}, Success: true},
+			{Command: config.Command{Name: "test", Command: "sh", Mode: config.ModeOnce}, Success: false, ExitCode: 1, Error: "exit status 1"},
+		},
+		Skipped: []string{"deploy"},
+	}
+
+	// A last run of another queue is not retried against this one
+	if err := executor.SaveLastRun(stateDir, executor.LastRun{ExecutionStatus: seeded, ConfigPath: filepath.Join(tempDir, "other.queue.json")}); err != nil {
+		t.Fatalf("Failed to save last run: %v", err)
+	}
+	if _, err := retry(); err == nil || !strings.Contains(err.Error(), "other.queue.json") {
+		t.Fatalf("Expected a last run of another configuration to be refused, got %v", err)
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Fatalf("Expected nothing to run when the last run is refused, got %v", err)
+	}
+
+	if err := executor.SaveLastRun(stateDir, executor.LastRun{ExecutionStatus: seeded, ConfigPath: configFile}); err != nil {
+		t.Fatalf("Failed to save last run: %v", err)
+	}
+
+	if _, err := retry(); err != nil {
+		t.Fatalf("Retry failed: %v", err)
+	}
+	ran, err := os.ReadFile(marker)
+	if err != nil {
+		t.Fatalf("Failed to read marker file: %v", err)
+	}
+	if string(ran) != "test\ndeploy\n" {
+		t.Errorf("Expected only test and deploy to re-run, got %q", ran)
+	}
+
+	// The retry succeeded, so a second retry has nothing left to run
+	output, err := retry()
+	if err != nil {
+		t.Fatalf("Second retry failed: %v", err)
+	}
+	if !strings.Contains(output, "Nothing to retry") {
+		t.Errorf("Expected nothing to retry after a successful retry, got:\n%s", output)
+	}
+}
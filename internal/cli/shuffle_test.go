@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestCLI_RunShuffled(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires true")
+	}
+	t.Setenv("TMPDIR", t.TempDir())
+
+	configFile := filepath.Join(t.TempDir(), ".queue.json")
+	content := `{"version": "1.0", "commands": [
+		{"name": "a", "command": "true"},
+		{"name": "b", "command": "true"},
+		{"name": "c", "command": "true"},
+		{"name": "d", "command": "true"}
+	]}`
+	if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := config.LoadFromFile(configFile)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	var want []string
+	for _, cmd := range config.Shuffle(cfg.Commands, 7) {
+		want = append(want, cmd.Name)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cli := NewCLI([]string{"-f", configFile, "--shuffle", "--shuffle-seed", "7"})
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Failed to parse CLI args: %v", err)
+	}
+	var runErr error
+	var out string
+	errOut := captureStderr(t, func() {
+		out = captureStdout(t, func() { runErr = cli.Run(ctx) })
+	})
+	if runErr != nil {
+		t.Fatalf("CLI Run failed: %v", runErr)
+	}
+
+	if !strings.Contains(errOut, "Shuffled the queue with seed 7, rerun with --shuffle --shuffle-seed 7") {
+		t.Errorf("Expected the seed to be reported on stderr, got %q", errOut)
+	}
+	if strings.Contains(out, "seed") {
+		t.Errorf("Expected stdout to leave out the seed, got %q", out)
+	}
+
+	var got []string
+	for _, result := range cli.executor.GetStatus().Results {
+		got = append(got, result.Command.Name)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected the order of seed 7, %v, got %v", want, got)
+	}
+}
+
+func TestCLI_ShuffleSeedRequiresShuffle(t *testing.T) {
+	cli := NewCLI([]string{"--shuffle-seed", "7"})
+	if err := cli.Parse(); err == nil || !strings.Contains(err.Error(), "--shuffle-seed can only be used with --shuffle") {
+		t.Errorf("Expected --shuffle-seed without --shuffle to be rejected, got %v", err)
+	}
+}
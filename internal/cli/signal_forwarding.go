@@ -0,0 +1,24 @@
+package cli
+
+import (
+	"os"
+	"os/signal"
+)
+
+// HandleForwardedSignals relays the signals the loaded config forwards to its keepAlive
+// processes. It waits for Run to load the config and only takes over the signals it maps to a
+// command, so any other SIGUSR1 or SIGUSR2 still has its default action.
+func (c *CLI) HandleForwardedSignals() {
+	signals := <-c.forwardedSignals
+	if len(signals) == 0 {
+		return
+	}
+
+	forward := make(chan os.Signal, 1)
+	signal.Notify(forward, signals...)
+	for sig := range forward {
+		if err := c.ForwardSignal(sig); err != nil {
+			os.Stderr.WriteString("Warning: " + err.Error() + "\n")
+		}
+	}
+}
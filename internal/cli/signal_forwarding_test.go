@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCLI_HandleForwardedSignalsWithoutForwarding(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	configFile := filepath.Join(t.TempDir(), "test.queue.json")
+	if err := os.WriteFile(configFile, []byte(`{"version": "1.0", "commands": [{"name": "ok", "command": "echo", "args": ["hello"], "mode": "once"}]}`), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	cli := NewCLI([]string{"-f", configFile})
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Failed to parse CLI args: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		cli.HandleForwardedSignals()
+		close(done)
+	}()
+
+	captureStdout(t, func() {
+		if err := cli.Run(context.Background()); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+	})
+
+	// Nothing is forwarded, so no signal is taken over and the handler returns
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected HandleForwardedSignals to return for a config without signalForwarding")
+	}
+}
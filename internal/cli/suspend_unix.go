@@ -0,0 +1,37 @@
+//go:build !windows
+
+package cli
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// HandleSuspend makes Ctrl+Z suspend the keepAlive processes together with seqr. On SIGTSTP it
+// stops their process groups, then stops seqr as SIGTSTP would have without a handler; on the
+// SIGCONT of fg or bg it continues them. Without this, they would keep running in their own
+// process groups, detached from the suspended terminal session.
+func (c *CLI) HandleSuspend() {
+	suspend := make(chan os.Signal, 1)
+	resume := make(chan os.Signal, 1)
+	signal.Notify(suspend, syscall.SIGTSTP)
+	signal.Notify(resume, syscall.SIGCONT)
+
+	for {
+		select {
+		case <-suspend:
+			if err := c.SuspendProcesses(); err != nil {
+				os.Stderr.WriteString("Warning: " + err.Error() + "\n")
+			}
+			// The handler is removed so the signal stops seqr itself; it is restored on resume
+			signal.Reset(syscall.SIGTSTP)
+			syscall.Kill(os.Getpid(), syscall.SIGTSTP)
+		case <-resume:
+			signal.Notify(suspend, syscall.SIGTSTP)
+			if err := c.ResumeProcesses(); err != nil {
+				os.Stderr.WriteString("Warning: " + err.Error() + "\n")
+			}
+		}
+	}
+}
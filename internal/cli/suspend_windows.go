@@ -0,0 +1,6 @@
+//go:build windows
+
+package cli
+
+// HandleSuspend does nothing on Windows, which has no Ctrl+Z job control
+func (c *CLI) HandleSuspend() {}
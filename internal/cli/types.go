@@ -5,6 +5,10 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/seqr-cli/seqr/internal/config"
@@ -13,7 +17,8 @@ import (
 
 // CLIOptions holds all command-line configuration options
 type CLIOptions struct {
-	ConfigFile string // Path to queue configuration file
+	ConfigFile string // Path or http(s):// URL of the queue configuration file
+	ConfigDir  string // Directory whose *.queue.json files are merged into one queue, instead of ConfigFile
 	Verbose    bool   // Enable verbose output
 	Help       bool   // Show help message
 	Version    bool   // Show version information
@@ -21,6 +26,51 @@ type CLIOptions struct {
 	Kill       bool   // Kill running seqr processes
 	Status     bool   // Show status of running seqr processes
 	Watch      bool   // Watch live processes and their output
+	Last       bool   // Show the summary of the last completed run
+	Logs       bool   // Follow the logs of running keepAlive processes
+	Doctor     bool   // Check that the queue's executables and services are available
+	Graph      bool   // Print the queue's command graph in Graphviz DOT
+	Lint       bool   // Warn about anti-patterns in the queue
+	Strict     bool   // With Lint, fail if there are any warnings
+	Explain    bool   // Print whether each command would run and why, without running anything
+	Diff       bool   // Compare the two queue files given as arguments instead of running anything
+
+	MaxConcurrency int    // Bound on commands running at once, overrides the config's maxConcurrency when set
+	Completion     string // Shell to print a completion script for (bash or zsh)
+	JUnitFile      string // Path to write a JUnit XML report of the run to
+	ProfileFile    string // Path to write the run's timeline to, as Chrome trace event JSON
+	LogFile        string // Path of each command's background log, instead of ~/.seqr/logs/<name>.log
+	KeepGoing      bool   // Continue running remaining commands after a failure
+	MaxFailures    int    // With KeepGoing, stop launching commands after this many failures (0 means unlimited)
+	CheckPaths     bool   // Verify every command's workDir exists before running anything
+	Echo           bool   // Print each command line before running it, like set -x
+	EchoEnv        bool   // Like Echo, with each command's env overrides included
+	DumpEnv        string // Name of a command whose environment should be printed instead of running the queue
+	ShowSecrets    bool   // With DumpEnv, print secret-looking values instead of masking them
+	ErrorFormat    string // Format of fatal errors on stderr: text or json
+	Syslog         string // Syslog facility to send keepAlive output to, e.g. daemon (empty disables it)
+	SyslogTag      string // With Syslog, the tag each command's name is appended to
+	ShuffleSeed    int64  // With Shuffle, the seed of the order, to repeat one; a new seed each run when not set
+
+	ShowOutputOnFailure bool // Without verbose output, print a failed command's captured output
+	RetryFailed         bool // Run only the commands that failed or never started in the last run
+	Compact             bool // Without verbose output, show progress as one status line updated in place
+	NoDetach            bool // Stop on the first interrupt instead of detaching from streamed output
+	MaxTotalOutput      int  // Bound in bytes on the output kept in the run's results (0 means unlimited)
+	NoTimestamps        bool // Leave the timestamp out of streamed output lines
+	JSON                bool // With Version, print the version and build metadata as JSON
+	ExpandHostEnv       bool // Expand ${NAME} references to seqr's own environment variables after the positional arguments
+	UpdateGolden        bool // Write once commands' output to their goldenFile instead of comparing it
+	ReapOrphans         bool // Before running, offer to terminate or adopt processes left running by seqr runs that exited
+	NoSummary           bool // Leave out the final result line and the verbose timing summary
+	Insecure            bool // Allow fetching the configuration from a plain http:// URL
+	Shuffle             bool // Run the queue in a random order that keeps concurrent groups and afterReady dependencies
+
+	Heartbeat  time.Duration // With verbose output, log when a keepAlive process has been silent this long (0 disables)
+	Timeout    time.Duration // Bound on each once command and on keepAlive startup, never on a started keepAlive (0 disables)
+	MaxHostMem float64       // Stop every command once host memory use exceeds this percentage (0 disables)
+
+	QueueArgs []string // Arguments after `--`, expanded into ${ARGS}, ${ARG_1}, ${1}, ... in the queue; nil without `--`
 }
 
 // CLI represents the command-line interface
@@ -29,6 +79,11 @@ type CLI struct {
 	flagSet  *flag.FlagSet
 	executor *executor.Executor
 	args     []string
+
+	runID    string    // Identifies the current run in report paths, as ${RUN_ID}
+	runStart time.Time // When the current run started, for ${DATE} and ${TIME} in report paths
+
+	forwardedSignals chan []os.Signal // The signals the loaded config forwards, sent once Run has its executor
 }
 
 // NewCLI creates a new CLI instance with default options
@@ -48,9 +103,15 @@ func NewCLI(args []string) *CLI {
 			Kill:       false,
 			Status:     false,
 			Watch:      false,
+			Last:       false,
+			Logs:       false,
+
+			ErrorFormat: "text",
 		},
 		flagSet: flagSet,
 		args:    args,
+
+		forwardedSignals: make(chan []os.Signal, 1),
 	}
 
 	cli.setupFlags()
@@ -60,7 +121,9 @@ func NewCLI(args []string) *CLI {
 // setupFlags configures all command-line flags
 func (c *CLI) setupFlags() {
 	c.flagSet.StringVar(&c.options.ConfigFile, "f", c.options.ConfigFile,
-		"Path to queue configuration file")
+		"Path to queue configuration file, or an https:// URL to fetch it from")
+	c.flagSet.StringVar(&c.options.ConfigDir, "d", c.options.ConfigDir,
+		"Load and merge all *.queue.json files in a directory, in name order")
 	c.flagSet.BoolVar(&c.options.Verbose, "v", c.options.Verbose,
 		"Enable verbose output with execution details")
 	c.flagSet.BoolVar(&c.options.Verbose, "verbose", c.options.Verbose,
@@ -79,6 +142,86 @@ func (c *CLI) setupFlags() {
 		"Show status of running seqr processes")
 	c.flagSet.BoolVar(&c.options.Watch, "watch", c.options.Watch,
 		"Watch live processes and their real-time output")
+	c.flagSet.BoolVar(&c.options.Last, "last", c.options.Last,
+		"Show the summary of the last completed run")
+	c.flagSet.BoolVar(&c.options.Logs, "logs", c.options.Logs,
+		"Follow the live logs of running keepAlive processes (optionally: --logs <name>)")
+	c.flagSet.IntVar(&c.options.MaxConcurrency, "max-concurrency", c.options.MaxConcurrency,
+		"Maximum number of concurrent commands running at once (overrides config maxConcurrency)")
+	c.flagSet.StringVar(&c.options.Completion, "completion", c.options.Completion,
+		"Print a shell completion script (bash or zsh)")
+	c.flagSet.StringVar(&c.options.JUnitFile, "junit", c.options.JUnitFile,
+		"Write a JUnit XML report of the run to the given file, which may use ${RUN_ID}, ${DATE} and ${TIME}")
+	c.flagSet.StringVar(&c.options.ProfileFile, "profile", c.options.ProfileFile,
+		"Write the timeline of the run's commands to the given file as Chrome trace JSON, which may use ${RUN_ID}, ${DATE} and ${TIME}")
+	c.flagSet.StringVar(&c.options.LogFile, "log-file", c.options.LogFile,
+		"Keep each command's background log at the given path instead of ~/.seqr/logs/<name>.log, which may use ${NAME}, ${RUN_ID}, ${DATE} and ${TIME}")
+	c.flagSet.BoolVar(&c.options.KeepGoing, "keep-going", c.options.KeepGoing,
+		"Continue running remaining commands after a failure and report all failures at the end")
+	c.flagSet.IntVar(&c.options.MaxFailures, "max-failures", c.options.MaxFailures,
+		"With --keep-going, stop launching commands after this many failures (0 means unlimited)")
+	c.flagSet.BoolVar(&c.options.CheckPaths, "check-paths", c.options.CheckPaths,
+		"Verify that every command's workDir exists before running anything")
+	c.flagSet.BoolVar(&c.options.Echo, "echo", c.options.Echo,
+		"Print each command line before running it, like set -x")
+	c.flagSet.BoolVar(&c.options.EchoEnv, "echo-env", c.options.EchoEnv,
+		"Like --echo, including each command's env overrides")
+	c.flagSet.StringVar(&c.options.DumpEnv, "dump-env", c.options.DumpEnv,
+		"Print the environment the named command would run with, without running anything")
+	c.flagSet.BoolVar(&c.options.ShowSecrets, "show-secrets", c.options.ShowSecrets,
+		"With --dump-env or --diff, print secret-looking values instead of masking them")
+	c.flagSet.StringVar(&c.options.ErrorFormat, "error-format", c.options.ErrorFormat,
+		"Format of fatal errors on stderr: text or json")
+	c.flagSet.BoolVar(&c.options.ShowOutputOnFailure, "show-output-on-failure", c.options.ShowOutputOnFailure,
+		"Without -v, print the captured output of a command that fails")
+	c.flagSet.BoolVar(&c.options.RetryFailed, "retry-failed", c.options.RetryFailed,
+		"Run only the commands that failed or never started in the last run")
+	c.flagSet.BoolVar(&c.options.Compact, "compact", c.options.Compact,
+		"Without -v, show progress as a single status line updated in place when writing to a terminal")
+	c.flagSet.BoolVar(&c.options.NoDetach, "no-detach", c.options.NoDetach,
+		"Stop on the first Ctrl+C instead of detaching from the output of running keepAlive processes")
+	c.flagSet.IntVar(&c.options.MaxTotalOutput, "max-total-output", c.options.MaxTotalOutput,
+		"Bound in bytes on the output kept in the run's results, dropping that of the earliest successful commands first (0 means unlimited)")
+	c.flagSet.BoolVar(&c.options.NoTimestamps, "no-timestamps", c.options.NoTimestamps,
+		"Leave the timestamp out of streamed output lines, for consumers such as journald that add their own")
+	c.flagSet.BoolVar(&c.options.JSON, "json", c.options.JSON,
+		"With --version, print the version and build metadata as JSON")
+	c.flagSet.BoolVar(&c.options.ExpandHostEnv, "expand-host-env", c.options.ExpandHostEnv,
+		"Expand ${NAME} in command, args, workDir and env values to seqr's own environment variables, e.g. ${HOME}")
+	c.flagSet.BoolVar(&c.options.UpdateGolden, "update-golden", c.options.UpdateGolden,
+		"Rewrite each command's goldenFile with its output instead of comparing the two")
+	c.flagSet.BoolVar(&c.options.ReapOrphans, "reap-orphans", c.options.ReapOrphans,
+		"Before running, offer to terminate or adopt processes left running by an earlier seqr run that exited, e.g. crashed")
+	c.flagSet.BoolVar(&c.options.NoSummary, "no-summary", c.options.NoSummary,
+		"Leave out the final \"All commands completed successfully\" or \"Execution failed\" line and the verbose timing summary")
+	c.flagSet.StringVar(&c.options.Syslog, "syslog", c.options.Syslog,
+		"Send keepAlive output to the system logger under this facility, e.g. daemon or local0; shown on the console too only with -v")
+	c.flagSet.StringVar(&c.options.SyslogTag, "syslog-tag", executor.DefaultSyslogTag,
+		"With --syslog, the tag to log under, as <tag>/<command name>")
+	c.flagSet.BoolVar(&c.options.Insecure, "insecure", c.options.Insecure,
+		"Allow -f to fetch the queue configuration from a plain http:// URL")
+	c.flagSet.BoolVar(&c.options.Shuffle, "shuffle", c.options.Shuffle,
+		"Run the queue in a random order to expose hidden ordering dependencies, keeping concurrent groups together and commands after what they wait for")
+	c.flagSet.Int64Var(&c.options.ShuffleSeed, "shuffle-seed", c.options.ShuffleSeed,
+		"With --shuffle, the seed of the order, to repeat the order of an earlier run")
+	c.flagSet.Float64Var(&c.options.MaxHostMem, "max-host-mem", c.options.MaxHostMem,
+		"Stop every command, keepAlive processes included, once the host's memory use exceeds this percentage, e.g. 90 (0 disables, Linux only)")
+	c.flagSet.BoolVar(&c.options.Doctor, "doctor", c.options.Doctor,
+		"Check that every command's executable is installed, and Docker is running if used, without running anything")
+	c.flagSet.BoolVar(&c.options.Graph, "graph", c.options.Graph,
+		"Print the queue's command graph in Graphviz DOT, e.g. to pipe to dot, without running anything")
+	c.flagSet.BoolVar(&c.options.Lint, "lint", c.options.Lint,
+		"Warn about anti-patterns in the queue, e.g. a sleep where a readiness check belongs, without running anything")
+	c.flagSet.BoolVar(&c.options.Explain, "explain", c.options.Explain,
+		"Print whether each command would run and why, e.g. with --retry-failed or an unchanged cacheKey, without running anything")
+	c.flagSet.BoolVar(&c.options.Diff, "diff", c.options.Diff,
+		"Compare two queue files after normalizing them, e.g. --diff old.json new.json, and print the commands added, removed and changed")
+	c.flagSet.BoolVar(&c.options.Strict, "strict", c.options.Strict,
+		"With --lint, exit with the configuration error status if there are any warnings")
+	c.flagSet.DurationVar(&c.options.Heartbeat, "heartbeat", c.options.Heartbeat,
+		"With -v, log that a keepAlive process is still running after this long without output, e.g. 30s (0 disables)")
+	c.flagSet.DurationVar(&c.options.Timeout, "timeout", c.options.Timeout,
+		"Kill a once command that runs longer than this, e.g. 10m; keepAlive processes only have to start within it (0 disables)")
 }
 
 // Parse parses command-line arguments and validates options
@@ -87,13 +230,88 @@ func (c *CLI) Parse() error {
 		return fmt.Errorf("failed to parse command-line arguments: %w", err)
 	}
 
+	// Everything after a `--` terminator is passed to the queue as positional arguments
+	rest := c.flagSet.Args()
+	if consumed := len(c.args) - len(rest); consumed > 0 && c.args[consumed-1] == "--" {
+		// Non-nil even when empty, since it records that `--` was given
+		c.options.QueueArgs = append([]string{}, rest...)
+	}
+
 	return c.validateOptions()
 }
 
 // validateOptions validates the parsed command-line options
 func (c *CLI) validateOptions() error {
-	// If help, version, init, kill, status, or watch is requested, no validation needed
-	if c.options.Help || c.options.Version || c.options.Init || c.options.Kill || c.options.Status || c.options.Watch {
+	if c.options.ConfigDir != "" {
+		fileSet := false
+		c.flagSet.Visit(func(f *flag.Flag) {
+			if f.Name == "f" {
+				fileSet = true
+			}
+		})
+		if fileSet {
+			return fmt.Errorf("-d and -f cannot be used together")
+		}
+	}
+
+	if c.options.MaxConcurrency < 0 {
+		return fmt.Errorf("--max-concurrency cannot be negative, got %d", c.options.MaxConcurrency)
+	}
+
+	if c.options.Heartbeat < 0 {
+		return fmt.Errorf("--heartbeat cannot be negative, got %v", c.options.Heartbeat)
+	}
+
+	if c.options.Timeout < 0 {
+		return fmt.Errorf("--timeout cannot be negative, got %v", c.options.Timeout)
+	}
+
+	if c.options.MaxTotalOutput < 0 {
+		return fmt.Errorf("--max-total-output cannot be negative, got %d", c.options.MaxTotalOutput)
+	}
+
+	if c.options.MaxHostMem < 0 || c.options.MaxHostMem > 100 {
+		return fmt.Errorf("--max-host-mem must be a percentage between 0 and 100, got %g", c.options.MaxHostMem)
+	}
+
+	if c.options.MaxFailures < 0 {
+		return fmt.Errorf("--max-failures cannot be negative, got %d", c.options.MaxFailures)
+	}
+
+	if c.options.MaxFailures > 0 && !c.options.KeepGoing {
+		return fmt.Errorf("--max-failures requires --keep-going")
+	}
+
+	if !slices.Contains(ErrorFormats, c.options.ErrorFormat) {
+		return fmt.Errorf("unsupported error format '%s', supported formats are: %s", c.options.ErrorFormat, strings.Join(ErrorFormats, ", "))
+	}
+
+	if c.options.Completion != "" && !slices.Contains(CompletionShells, c.options.Completion) {
+		return fmt.Errorf("unsupported completion shell '%s', supported shells are: %s", c.options.Completion, strings.Join(CompletionShells, ", "))
+	}
+
+	if c.options.JSON && !c.options.Version {
+		return fmt.Errorf("--json can only be used with --version")
+	}
+
+	if c.options.Syslog != "" && !slices.Contains(executor.SyslogFacilities(), c.options.Syslog) {
+		return fmt.Errorf("unsupported syslog facility '%s', supported facilities are: %s", c.options.Syslog, strings.Join(executor.SyslogFacilities(), ", "))
+	}
+
+	if c.options.Strict && !c.options.Lint {
+		return fmt.Errorf("--strict can only be used with --lint")
+	}
+
+	if c.isFlagSet("shuffle-seed") && !c.options.Shuffle {
+		return fmt.Errorf("--shuffle-seed can only be used with --shuffle")
+	}
+
+	if c.options.Diff && c.flagSet.NArg() != 2 {
+		return fmt.Errorf("--diff takes two queue files, e.g. --diff old.json new.json, got %d", c.flagSet.NArg())
+	}
+
+	// If help, version, init, kill, status, watch, last, logs, completion, dump-env, doctor, graph, lint, explain, or diff is requested, no validation needed
+	if c.options.Help || c.options.Version || c.options.Init || c.options.Kill || c.options.Status || c.options.Watch || c.options.Last || c.options.Logs || c.options.Completion != "" || c.options.DumpEnv != "" || c.options.Doctor || c.options.Graph || c.options.Lint || c.options.Explain || c.options.Diff {
 		return nil
 	}
 
@@ -104,6 +322,17 @@ func (c *CLI) validateOptions() error {
 	return nil
 }
 
+// isFlagSet reports whether the named flag was given on the command line
+func (c *CLI) isFlagSet(name string) bool {
+	set := false
+	c.flagSet.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
 // GetOptions returns the parsed CLI options
 func (c *CLI) GetOptions() CLIOptions {
 	return c.options
@@ -139,9 +368,54 @@ func (c *CLI) ShouldRunWatch() bool {
 	return c.options.Watch
 }
 
+// ShouldRunLast returns true if the last run summary should be shown
+func (c *CLI) ShouldRunLast() bool {
+	return c.options.Last
+}
+
+// ShouldRunDumpEnv returns true if a command's environment should be printed
+func (c *CLI) ShouldRunDumpEnv() bool {
+	return c.options.DumpEnv != ""
+}
+
+// ShouldRunDoctor returns true if the queue's prerequisites should be checked
+func (c *CLI) ShouldRunDoctor() bool {
+	return c.options.Doctor
+}
+
+// ShouldRunGraph returns true if the queue's command graph should be printed
+func (c *CLI) ShouldRunGraph() bool {
+	return c.options.Graph
+}
+
+// ShouldRunLint returns true if the queue should be checked for anti-patterns
+func (c *CLI) ShouldRunLint() bool {
+	return c.options.Lint
+}
+
+// ShouldRunExplain returns true if the commands that would run should be explained
+func (c *CLI) ShouldRunExplain() bool {
+	return c.options.Explain
+}
+
+// ShouldRunDiff returns true if two queue files should be compared
+func (c *CLI) ShouldRunDiff() bool {
+	return c.options.Diff
+}
+
+// ShouldRunLogs returns true if logs should be followed
+func (c *CLI) ShouldRunLogs() bool {
+	return c.options.Logs
+}
+
+// ShouldRunCompletion returns true if a shell completion script should be printed
+func (c *CLI) ShouldRunCompletion() bool {
+	return c.options.Completion != ""
+}
+
 // ShowVersion displays version information
-func (c *CLI) ShowVersion(version string) {
-	fmt.Fprintf(os.Stdout, "seqr version %s\n", version)
+func (c *CLI) ShowVersion(build BuildInfo) {
+	c.writeVersion(os.Stdout, build)
 }
 
 // ShowHelp displays the help message
@@ -151,19 +425,55 @@ func (c *CLI) ShowHelp() {
 	fmt.Fprintf(os.Stdout, "  Execute commands sequentially from a JSON configuration file.\n")
 	fmt.Fprintf(os.Stdout, "  Supports both one-time commands and long-running background processes.\n\n")
 	fmt.Fprintf(os.Stdout, "USAGE:\n")
-	fmt.Fprintf(os.Stdout, "  seqr [options]\n\n")
+	fmt.Fprintf(os.Stdout, "  seqr [options] [-- args...]\n\n")
 	fmt.Fprintf(os.Stdout, "OPTIONS:\n")
 	c.flagSet.PrintDefaults()
 	fmt.Fprintf(os.Stdout, "\nEXAMPLES:\n")
 	fmt.Fprintf(os.Stdout, "  seqr                      # Run commands from .queue.json\n")
 	fmt.Fprintf(os.Stdout, "  seqr -f my-queue.json     # Run commands from custom file\n")
+	fmt.Fprintf(os.Stdout, "  seqr -d queues/           # Run the merged *.queue.json files in queues/\n")
 	fmt.Fprintf(os.Stdout, "  seqr -v                   # Run with verbose output\n")
 	fmt.Fprintf(os.Stdout, "  seqr --verbose            # Run with verbose output (long form)\n")
 	fmt.Fprintf(os.Stdout, "  seqr -f queue.json -v     # Custom file with verbose output\n")
+	fmt.Fprintf(os.Stdout, "  seqr -f build.json -- --env staging  # Expand ${ARGS}, ${1}, ${2} in the queue\n")
 	fmt.Fprintf(os.Stdout, "  seqr --init               # Generate example configuration files\n")
 	fmt.Fprintf(os.Stdout, "  seqr --kill               # Kill running seqr processes\n")
 	fmt.Fprintf(os.Stdout, "  seqr --status             # Show status of running seqr processes\n")
-	fmt.Fprintf(os.Stdout, "  seqr --watch              # Watch live processes and their output\n\n")
+	fmt.Fprintf(os.Stdout, "  seqr --watch              # Watch live processes and their output\n")
+	fmt.Fprintf(os.Stdout, "  seqr --last               # Show the summary of the last completed run\n")
+	fmt.Fprintf(os.Stdout, "  seqr --logs               # Follow logs of all running keepAlive processes\n")
+	fmt.Fprintf(os.Stdout, "  seqr --logs api           # Follow logs of the 'api' process only\n")
+	fmt.Fprintf(os.Stdout, "  seqr --max-concurrency 2  # Run at most 2 concurrent commands at once\n")
+	fmt.Fprintf(os.Stdout, "  seqr --completion bash    # Print a bash completion script\n")
+	fmt.Fprintf(os.Stdout, "  seqr --junit report.xml   # Write a JUnit XML report of the run\n")
+	fmt.Fprintf(os.Stdout, "  seqr --profile trace.json # Write the run's timeline for chrome://tracing\n")
+	fmt.Fprintf(os.Stdout, "  seqr --keep-going --max-failures 3  # Keep going past failures, stop after 3\n")
+	fmt.Fprintf(os.Stdout, "  seqr --check-paths        # Fail early if any workDir is missing\n")
+	fmt.Fprintf(os.Stdout, "  seqr --echo               # Print each command line before running it\n")
+	fmt.Fprintf(os.Stdout, "  seqr --dump-env api       # Print the environment 'api' would run with\n")
+	fmt.Fprintf(os.Stdout, "  seqr --doctor             # Check the queue's executables and Docker are available\n")
+	fmt.Fprintf(os.Stdout, "  seqr --graph | dot -Tsvg > queue.svg  # Render the queue's command graph\n")
+	fmt.Fprintf(os.Stdout, "  seqr --explain --retry-failed  # Show which commands a retry would run, and why\n")
+	fmt.Fprintf(os.Stdout, "  seqr --diff old.json new.json  # Show the commands a change to a queue adds, removes or changes\n")
+	fmt.Fprintf(os.Stdout, "  seqr --lint --strict      # Fail CI on anti-patterns such as sleeping for readiness\n")
+	fmt.Fprintf(os.Stdout, "  seqr --max-host-mem 90    # Stop everything if the host runs short of memory\n")
+	fmt.Fprintf(os.Stdout, "  seqr -v --heartbeat 30s   # Note keepAlive processes silent for 30s\n")
+	fmt.Fprintf(os.Stdout, "  seqr --show-output-on-failure  # Print a failed command's output without -v\n")
+	fmt.Fprintf(os.Stdout, "  seqr --error-format json  # Write a fatal error as JSON for CI to parse\n")
+	fmt.Fprintf(os.Stdout, "  seqr --retry-failed       # Re-run only what failed or was skipped last time\n")
+	fmt.Fprintf(os.Stdout, "  seqr --compact            # Show progress on a single status line\n")
+	fmt.Fprintf(os.Stdout, "  seqr -v --no-detach       # Stop on the first Ctrl+C, even while streaming\n")
+	fmt.Fprintf(os.Stdout, "  seqr --max-total-output 1048576  # Keep at most 1 MiB of output in the results\n")
+	fmt.Fprintf(os.Stdout, "  seqr -v --no-timestamps   # Stream output without timestamps, e.g. under journald\n")
+	fmt.Fprintf(os.Stdout, "  seqr --version --json     # Print the version and build metadata as JSON\n")
+	fmt.Fprintf(os.Stdout, "  seqr --expand-host-env    # Expand ${HOME} and other host variables in the queue\n")
+	fmt.Fprintf(os.Stdout, "  seqr --update-golden      # Record the output of commands with a goldenFile\n")
+	fmt.Fprintf(os.Stdout, "  seqr --reap-orphans       # Deal with processes a crashed run left behind first\n")
+	fmt.Fprintf(os.Stdout, "  seqr --no-summary         # End with the last command's output, for scripts\n")
+	fmt.Fprintf(os.Stdout, "  seqr -f https://example.com/ci.queue.json  # Fetch and run a shared queue\n")
+	fmt.Fprintf(os.Stdout, "  seqr --syslog daemon      # Send keepAlive output to syslog as seqr/<name>\n")
+	fmt.Fprintf(os.Stdout, "  seqr --shuffle            # Run in a random order to expose hidden ordering dependencies\n")
+	fmt.Fprintf(os.Stdout, "  seqr --timeout 10m        # Kill once commands running longer than 10 minutes\n\n")
 	fmt.Fprintf(os.Stdout, "CONFIGURATION:\n")
 	fmt.Fprintf(os.Stdout, "  The queue file should be a JSON file with the following structure:\n")
 	fmt.Fprintf(os.Stdout, "  {\n")
@@ -182,15 +492,20 @@ func (c *CLI) ShowHelp() {
 	fmt.Fprintf(os.Stdout, "  }\n\n")
 	fmt.Fprintf(os.Stdout, "EXECUTION MODES:\n")
 	fmt.Fprintf(os.Stdout, "  once      - Run command once and wait for completion\n")
-	fmt.Fprintf(os.Stdout, "  keepAlive - Start command and keep running in background\n\n")
+	fmt.Fprintf(os.Stdout, "  keepAlive - Start command and keep running in background\n")
+	fmt.Fprintf(os.Stdout, "  Commands without a mode run once, unless the queue sets a top-level\n")
+	fmt.Fprintf(os.Stdout, "  \"defaultMode\".\n\n")
 	fmt.Fprintf(os.Stdout, "CONCURRENT EXECUTION:\n")
 	fmt.Fprintf(os.Stdout, "  Commands with \"concurrent\": true will run in parallel with other\n")
 	fmt.Fprintf(os.Stdout, "  concurrent commands. Sequential commands (concurrent: false or omitted)\n")
-	fmt.Fprintf(os.Stdout, "  will wait for all previous commands to complete before starting.\n\n")
+	fmt.Fprintf(os.Stdout, "  will wait for all previous commands to complete before starting.\n")
+	fmt.Fprintf(os.Stdout, "  Set a top-level \"maxConcurrency\" to bound how many run at once;\n")
+	fmt.Fprintf(os.Stdout, "  --max-concurrency overrides it.\n\n")
 	fmt.Fprintf(os.Stdout, "EXIT CODES:\n")
 	fmt.Fprintf(os.Stdout, "  0 - All commands executed successfully\n")
-	fmt.Fprintf(os.Stdout, "  1 - Command execution failed or configuration error\n")
+	fmt.Fprintf(os.Stdout, "  1 - Command execution failed\n")
 	fmt.Fprintf(os.Stdout, "  2 - Invalid command-line arguments\n")
+	fmt.Fprintf(os.Stdout, "  3 - Configuration error: the queue could not be loaded or is invalid\n")
 }
 
 // Run executes the CLI application with the parsed options
@@ -202,19 +517,144 @@ func (c *CLI) Run(ctx context.Context) error {
 	}
 
 	// Load configuration
-	cfg, err := config.LoadFromFile(c.options.ConfigFile)
+	cfg, err := c.loadConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
+	// Directories may be created by earlier commands, so checking them up front is opt-in
+	if c.options.CheckPaths {
+		validator := &config.Validator{
+			ValidateWorkDirs: true,
+			BaseDir:          c.configBaseDir(),
+		}
+		if err := validator.ValidateConfig(cfg); err != nil {
+			return fmt.Errorf("path check failed: %w", &ConfigError{Err: err})
+		}
+	}
+
+	// Processes a crashed run left behind may hold ports or files this run needs
+	if c.options.ReapOrphans {
+		if err := c.reapOrphans(os.Stdin, os.Stdout); err != nil {
+			return err
+		}
+	}
+
+	if c.options.RetryFailed {
+		cfg, err = c.retryConfig(cfg, os.Stderr)
+		if err != nil {
+			return err
+		}
+		if len(cfg.Commands) == 0 {
+			fmt.Fprintf(os.Stdout, "Nothing to retry: no command failed or was skipped in the last run\n")
+			return nil
+		}
+	}
+
+	if c.options.Shuffle {
+		seed := c.options.ShuffleSeed
+		if !c.isFlagSet("shuffle-seed") {
+			seed = time.Now().UnixNano()
+		}
+		shuffled := *cfg
+		shuffled.Commands = config.Shuffle(cfg.Commands, seed)
+		cfg = &shuffled
+		// On stderr, so the seed does not mix into output that is compared or piped on
+		fmt.Fprintf(os.Stderr, "Shuffled the queue with seed %d, rerun with --shuffle --shuffle-seed %d to repeat the order\n", seed, seed)
+	}
+
 	// Create executor with CLI options
-	c.executor = executor.NewExecutor(c.options.Verbose)
+	reporter := executor.NewConsoleReporterWithOptions(executor.ConsoleReporterOptions{
+		Writer:              os.Stdout,
+		Verbose:             c.options.Verbose,
+		SummaryOutputLines:  executor.DefaultSummaryOutputLines,
+		ShowOutputOnFailure: c.options.ShowOutputOnFailure,
+		Compact:             c.options.Compact,
+		Spinner:             true,
+		NoSummary:           c.options.NoSummary,
+	})
+
+	var syslog *executor.SyslogOptions
+	if c.options.Syslog != "" {
+		syslog = &executor.SyslogOptions{Facility: c.options.Syslog, Tag: c.options.SyslogTag}
+	}
+
+	c.runStart = time.Now()
+	c.runID = newRunID(c.runStart)
+	c.executor = executor.NewExecutorWithOptions(executor.ExecutorOptions{
+		Verbose:         c.options.Verbose,
+		Reporter:        reporter,
+		MaxConcurrency:  c.options.MaxConcurrency,
+		ContinueOnError: c.options.KeepGoing,
+		MaxFailures:     c.options.MaxFailures,
+		EchoCommands:    c.options.Echo || c.options.EchoEnv,
+		EchoEnv:         c.options.EchoEnv,
+
+		HeartbeatInterval: c.options.Heartbeat,
+		Timeout:           c.options.Timeout,
+
+		MaxTotalOutputBytes: c.options.MaxTotalOutput,
+		NoTimestamps:        c.options.NoTimestamps,
+		UpdateGolden:        c.options.UpdateGolden,
+		Syslog:              syslog,
+		MaxHostMemPercent:   c.options.MaxHostMem,
+		RunID:               c.runID,
+		LogFile:             c.options.LogFile,
+		RunStart:            c.runStart,
+	})
+
+	// Hand the signals the config forwards to HandleForwardedSignals, if it is waiting for them
+	select {
+	case c.forwardedSignals <- executor.ForwardedSignals(cfg.SignalForwarding):
+	default:
+	}
+
+	// The ID matches a run to the reports it leaves, and to --last
+	if c.options.Verbose {
+		timestamp := time.Now().Format("15:04:05.000")
+		fmt.Printf("[%s] [seqr] [system] Run ID %s\n", timestamp, c.runID)
+	} else if c.options.JUnitFile != "" || c.options.ProfileFile != "" {
+		fmt.Fprintf(os.Stdout, "Run ID: %s\n", c.runID)
+	}
 
 	// Execute the command queue
-	if err := c.executor.Execute(ctx, cfg); err != nil {
-		return fmt.Errorf("execution failed: %w", err)
+	execErr := c.executor.Execute(ctx, cfg)
+
+	// Record the run for --last and --retry-failed
+	lastRun := executor.LastRun{ExecutionStatus: c.executor.GetStatus(), ConfigPath: c.configSource()}
+	if err := executor.SaveLastRun(c.executor.GetStateDir(), lastRun); err != nil && c.options.Verbose {
+		timestamp := time.Now().Format("15:04:05.000")
+		fmt.Printf("[%s] [seqr] [system] Warning: Failed to persist run status: %v\n", timestamp, err)
+	}
+
+	// Write the JUnit report for failed runs too, that is when it matters most
+	if c.options.JUnitFile != "" {
+		if err := c.writeJUnitReport(cfg); err != nil {
+			if execErr == nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+
+	// Slow failing runs are worth profiling too
+	if c.options.ProfileFile != "" {
+		if err := c.writeProfile(cfg); err != nil {
+			if execErr == nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
 	}
 
+	if execErr != nil {
+		return fmt.Errorf("execution failed: %w", execErr)
+	}
+
+	// KeepAlive processes with a maxLifetime are stopped by this process, so stay until they are.
+	// Cancellation means seqr is shutting down and stops them itself.
+	_ = c.executor.WaitForAutoStops(ctx)
+
 	// Check if there are any active keepAlive processes running
 	if c.executor.HasActiveKeepAliveProcesses() {
 		if c.options.Verbose {
@@ -231,6 +671,119 @@ func (c *CLI) Run(ctx context.Context) error {
 	return nil
 }
 
+// loadConfig loads the queue from the config directory if one was given, otherwise from the config file
+// or URL, and expands the positional arguments passed after `--`, and host variables with
+// --expand-host-env, into it. A queue run without either is left as written.
+func (c *CLI) loadConfig() (*config.Config, error) {
+	var cfg *config.Config
+	var err error
+	if c.options.ConfigDir != "" {
+		cfg, err = config.LoadFromDir(c.options.ConfigDir)
+	} else if config.IsURL(c.options.ConfigFile) {
+		cfg, err = config.LoadFromURL(c.options.ConfigFile, c.options.Insecure)
+	} else {
+		cfg, err = config.LoadFromFile(c.options.ConfigFile)
+	}
+	if err != nil {
+		return nil, &ConfigError{Err: err}
+	}
+
+	// Positional arguments take precedence over host variables of the same name
+	var lookups []config.VariableLookup
+	if c.options.QueueArgs != nil {
+		lookups = append(lookups, config.PositionalArgs(c.options.QueueArgs))
+	}
+	if c.options.ExpandHostEnv {
+		lookups = append(lookups, config.HostEnv())
+	}
+	if len(lookups) == 0 {
+		return cfg, nil
+	}
+
+	// The queue was validated as written, so what it expands to is checked again
+	cfg.ExpandVariables(config.ChainLookups(lookups...))
+	if err := cfg.Validate(); err != nil {
+		return nil, &ConfigError{Err: fmt.Errorf("after expanding variables: %w", err)}
+	}
+	return cfg, nil
+}
+
+// configSource identifies the loaded configuration, an absolute path or a URL, so a later run
+// can tell whether it loads the same one
+func (c *CLI) configSource() string {
+	source := c.options.ConfigFile
+	if c.options.ConfigDir != "" {
+		source = c.options.ConfigDir
+	}
+	if config.IsURL(source) {
+		return source
+	}
+	if abs, err := filepath.Abs(source); err == nil {
+		return abs
+	}
+	return source
+}
+
+// configBaseDir returns the directory relative paths in the loaded queue resolve against
+func (c *CLI) configBaseDir() string {
+	if c.options.ConfigDir != "" {
+		return c.options.ConfigDir
+	}
+	if config.IsURL(c.options.ConfigFile) {
+		// A fetched queue has no directory of its own
+		return "."
+	}
+	return filepath.Dir(c.options.ConfigFile)
+}
+
+// writeJUnitReport writes the executor's final status to the configured JUnit report file
+func (c *CLI) writeJUnitReport(cfg *config.Config) error {
+	path, err := c.resolveReportPath(c.options.JUnitFile)
+	if err != nil {
+		return fmt.Errorf("failed to create JUnit report: %w", err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create JUnit report '%s': %w", path, err)
+	}
+
+	suiteName := filepath.Base(c.options.ConfigFile)
+	if c.options.ConfigDir != "" {
+		suiteName = filepath.Base(filepath.Clean(c.options.ConfigDir))
+	}
+	if err := executor.WriteJUnitReport(file, suiteName, cfg.Commands, c.executor.GetStatus()); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write JUnit report '%s': %w", path, err)
+	}
+	return nil
+}
+
+// writeProfile writes the timeline of the executor's commands to the configured profile file
+func (c *CLI) writeProfile(cfg *config.Config) error {
+	path, err := c.resolveReportPath(c.options.ProfileFile)
+	if err != nil {
+		return fmt.Errorf("failed to create profile: %w", err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create profile '%s': %w", path, err)
+	}
+
+	if err := executor.WriteProfile(file, cfg.Commands, c.executor.GetStatus()); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write profile '%s': %w", path, err)
+	}
+	return nil
+}
+
 // RunInit generates example configuration files
 func (c *CLI) RunInit() error {
 	generator := config.NewTemplateGenerator()
@@ -307,6 +860,78 @@ func (c *CLI) RunStatus() error {
 	return nil
 }
 
+// RunLast shows the summary of the most recent completed run
+func (c *CLI) RunLast() error {
+	processManager := executor.NewProcessManager()
+
+	status, err := executor.LoadLastRun(processManager.GetStateDir())
+	if err != nil {
+		return fmt.Errorf("failed to load last run: %w", err)
+	}
+
+	fmt.Fprintf(os.Stdout, "seqr Last Run\n")
+	fmt.Fprintf(os.Stdout, "=============\n\n")
+	if status.RunID != "" {
+		fmt.Fprintf(os.Stdout, "Run ID: %s\n", status.RunID)
+	}
+	fmt.Fprintf(os.Stdout, "State: %s\n", status.State)
+	fmt.Fprintf(os.Stdout, "Completed: %d/%d commands\n", status.CompletedCount, status.TotalCount)
+	if len(status.Results) > 0 {
+		finished := status.Results[len(status.Results)-1].EndTime
+		fmt.Fprintf(os.Stdout, "Finished: %s\n", finished.Format("2006-01-02 15:04:05"))
+	}
+	fmt.Fprintf(os.Stdout, "\n")
+
+	for _, result := range status.Results {
+		if result.Success && result.Command.Warmup {
+			fmt.Fprintf(os.Stdout, "  ✓ %s (warmup, %v)\n", result.Command.Name, result.Duration.Round(time.Millisecond))
+		} else if result.Success {
+			fmt.Fprintf(os.Stdout, "  ✓ %s (%v)\n", result.Command.Name, result.Duration.Round(time.Millisecond))
+		} else if result.AllowedFailure {
+			fmt.Fprintf(os.Stdout, "  ✗ %s failed (exit code %d, allowed): %s\n", result.Command.Name, result.ExitCode, result.Error)
+		} else {
+			fmt.Fprintf(os.Stdout, "  ✗ %s failed (exit code %d): %s\n", result.Command.Name, result.ExitCode, result.Error)
+		}
+	}
+
+	if status.LastError != "" {
+		fmt.Fprintf(os.Stdout, "\nLast error: %s\n", status.LastError)
+	}
+
+	return nil
+}
+
+// RunDumpEnv prints the environment the named command would run with, one sorted KEY=value
+// per line, without running anything. Secret-looking values are masked unless ShowSecrets is set.
+func (c *CLI) RunDumpEnv() error {
+	cfg, err := c.loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	index := slices.IndexFunc(cfg.Commands, func(cmd config.Command) bool {
+		return cmd.Name == c.options.DumpEnv
+	})
+	if index < 0 {
+		return fmt.Errorf("command '%s' not found in configuration", c.options.DumpEnv)
+	}
+
+	env, err := executor.BuildCommandEnv(cfg.Commands[index])
+	if err != nil {
+		return err
+	}
+	sort.Strings(env)
+
+	for _, entry := range env {
+		if !c.options.ShowSecrets {
+			entry = executor.MaskEnvEntry(entry)
+		}
+		fmt.Fprintln(os.Stdout, entry)
+	}
+
+	return nil
+}
+
 // RunWatch shows live output from running seqr processes
 func (c *CLI) RunWatch(ctx context.Context) error {
 	processManager := executor.NewProcessManager()
@@ -412,6 +1037,46 @@ func (c *CLI) RunWatch(ctx context.Context) error {
 	return nil
 }
 
+// RunLogs follows the background logs of running keepAlive processes, multiplexed into one stream
+func (c *CLI) RunLogs(ctx context.Context) error {
+	processManager := executor.NewProcessManager()
+	logger := executor.NewBackgroundLogger()
+
+	processes, err := processManager.GetAllRunningProcesses()
+	if err != nil {
+		return fmt.Errorf("failed to get running processes: %w", err)
+	}
+
+	var names []string
+	for _, info := range processes {
+		if info.Mode == string(config.ModeKeepAlive) && !slices.Contains(names, info.Name) {
+			names = append(names, info.Name)
+		}
+	}
+	sort.Strings(names)
+
+	// A single process can be selected by name, including one that has stopped but left a log
+	if name := c.flagSet.Arg(0); name != "" {
+		if !slices.Contains(names, name) {
+			if _, err := logger.GetLogInfo(name); err != nil {
+				return fmt.Errorf("no running keepAlive process or log named '%s'", name)
+			}
+		}
+		names = []string{name}
+	}
+
+	if len(names) == 0 {
+		fmt.Fprintf(os.Stdout, "No seqr keepAlive processes are currently running\n")
+		fmt.Fprintf(os.Stdout, "📁 Log files are preserved in: %s\n", logger.GetLogDir())
+		return nil
+	}
+
+	fmt.Fprintf(os.Stdout, "📜 Following logs for: %s\n", strings.Join(names, ", "))
+	fmt.Fprintf(os.Stdout, "💡 Press Ctrl+C to stop following\n\n")
+
+	return logger.FollowLogs(ctx, os.Stdout, names, 10, executor.DefaultLogPollInterval)
+}
+
 // formatFileSize formats a file size in human-readable format
 func formatFileSize(bytes int64) string {
 	const unit = 1024
@@ -433,6 +1098,30 @@ func (c *CLI) Stop() {
 	}
 }
 
+// ForwardSignal relays a parent signal to the keepAlive processes configured to receive it
+func (c *CLI) ForwardSignal(sig os.Signal) error {
+	if c.executor == nil {
+		return nil
+	}
+	return c.executor.ForwardSignal(sig)
+}
+
+// SuspendProcesses pauses the running keepAlive processes while seqr is suspended
+func (c *CLI) SuspendProcesses() error {
+	if c.executor == nil {
+		return nil
+	}
+	return c.executor.SuspendProcesses()
+}
+
+// ResumeProcesses continues the keepAlive processes paused by SuspendProcesses
+func (c *CLI) ResumeProcesses() error {
+	if c.executor == nil {
+		return nil
+	}
+	return c.executor.ResumeProcesses()
+}
+
 // TryDetachFromStreaming attempts to detach from active streaming sessions
 // Returns true if detachment was successful, false if no streaming was active
 func (c *CLI) TryDetachFromStreaming() bool {
@@ -1,15 +1,64 @@
 package cli
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/seqr-cli/seqr/internal/config"
+	"github.com/seqr-cli/seqr/internal/executor"
 )
 
+// captureStdout captures stdout written while f runs
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+	return captureFile(t, &os.Stdout, f)
+}
+
+// captureStderr captures stderr written while f runs
+func captureStderr(t *testing.T, f func()) string {
+	t.Helper()
+	return captureFile(t, &os.Stderr, f)
+}
+
+// captureFile captures what is written to *file while f runs
+func captureFile(t *testing.T, file **os.File, f func()) string {
+	t.Helper()
+
+	old := *file
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	*file = w
+
+	done := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		done <- buf.String()
+	}()
+
+	f()
+
+	w.Close()
+	*file = old
+	output := <-done
+	r.Close()
+	return output
+}
+
 func TestNewCLI(t *testing.T) {
 	args := []string{"-f", "test.json", "-v"}
 	cli := NewCLI(args)
@@ -271,6 +320,81 @@ func TestCLI_ParseInvalidArgs(t *testing.T) {
 		args        []string
 		expectError bool
 	}{
+		{
+			name:        "max failures without keep going",
+			args:        []string{"--max-failures", "2"},
+			expectError: true,
+		},
+		{
+			name:        "negative max failures",
+			args:        []string{"--keep-going", "--max-failures", "-1"},
+			expectError: true,
+		},
+		{
+			name:        "max failures with keep going",
+			args:        []string{"--keep-going", "--max-failures", "2"},
+			expectError: false,
+		},
+		{
+			name:        "unknown syslog facility",
+			args:        []string{"--syslog", "local9"},
+			expectError: true,
+		},
+		{
+			name:        "syslog facility",
+			args:        []string{"--syslog", "daemon", "--syslog-tag", "ci"},
+			expectError: false,
+		},
+		{
+			name:        "negative heartbeat",
+			args:        []string{"--heartbeat", "-5s"},
+			expectError: true,
+		},
+		{
+			name:        "heartbeat interval",
+			args:        []string{"-v", "--heartbeat", "30s"},
+			expectError: false,
+		},
+		{
+			name:        "negative timeout",
+			args:        []string{"--timeout", "-1m"},
+			expectError: true,
+		},
+		{
+			name:        "timeout",
+			args:        []string{"--timeout", "10m"},
+			expectError: false,
+		},
+		{
+			name:        "host memory limit above 100 percent",
+			args:        []string{"--max-host-mem", "150"},
+			expectError: true,
+		},
+		{
+			name:        "host memory limit",
+			args:        []string{"--max-host-mem", "90"},
+			expectError: false,
+		},
+		{
+			name:        "config dir with config file",
+			args:        []string{"-d", "queues", "-f", "queue.json"},
+			expectError: true,
+		},
+		{
+			name:        "config dir alone",
+			args:        []string{"-d", "queues"},
+			expectError: false,
+		},
+		{
+			name:        "negative max concurrency",
+			args:        []string{"--max-concurrency", "-1"},
+			expectError: true,
+		},
+		{
+			name:        "non-numeric max concurrency",
+			args:        []string{"--max-concurrency", "many"},
+			expectError: true,
+		},
 		{
 			name:        "unknown flag",
 			args:        []string{"-unknown"},
@@ -615,3 +739,637 @@ func TestCLI_MultipleHelpFlags(t *testing.T) {
 		})
 	}
 }
+
+func TestCLI_RunLast(t *testing.T) {
+	// Point the tracker's state directory at an isolated temp dir
+	stateDir := t.TempDir()
+	t.Setenv("TMPDIR", stateDir)
+
+	cli := NewCLI([]string{"--last"})
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Failed to parse CLI args: %v", err)
+	}
+	if !cli.ShouldRunLast() {
+		t.Fatal("Expected --last to be requested")
+	}
+
+	// Without a recorded run, RunLast should report an error
+	if err := cli.RunLast(); err == nil {
+		t.Error("Expected error when no previous run is recorded")
+	}
+
+	now := time.Now()
+	status := executor.ExecutionStatus{
+		State:          executor.StateFailed,
+		CompletedCount: 1,
+		TotalCount:     2,
+		LastError:      "exit status 1",
+		Results: []executor.ExecutionResult{
+			{
+				Command:   config.Command{Name: "build", Command: "make", Mode: config.ModeOnce},
+				Success:   true,
+				StartTime: now,
+				EndTime:   now.Add(time.Second),
+				Duration:  time.Second,
+			},
+			{
+				Command:   config.Command{Name: "test", Command: "make", Mode: config.ModeOnce},
+				Success:   false,
+				ExitCode:  1,
+				Error:     "exit status 1",
+				StartTime: now.Add(time.Second),
+				EndTime:   now.Add(2 * time.Second),
+			},
+			{
+				Command:        config.Command{Name: "lint", Command: "make", Mode: config.ModeOnce},
+				Success:        false,
+				ExitCode:       2,
+				Error:          "exit status 2",
+				AllowedFailure: true,
+			},
+		},
+	}
+	if err := executor.SaveLastRun(stateDir, executor.LastRun{ExecutionStatus: status}); err != nil {
+		t.Fatalf("Failed to save last run: %v", err)
+	}
+
+	var runErr error
+	output := captureStdout(t, func() {
+		runErr = cli.RunLast()
+	})
+	if runErr != nil {
+		t.Fatalf("RunLast failed: %v", runErr)
+	}
+
+	expected := []string{
+		"State: failed",
+		"Completed: 1/2 commands",
+		"✓ build",
+		"✗ test failed (exit code 1): exit status 1",
+		"✗ lint failed (exit code 2, allowed): exit status 2",
+		"Last error: exit status 1",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestCLI_MaxConcurrencyFlag(t *testing.T) {
+	cli := NewCLI([]string{"--max-concurrency", "3"})
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := cli.GetOptions().MaxConcurrency; got != 3 {
+		t.Errorf("Expected MaxConcurrency 3, got %d", got)
+	}
+
+	// Unset flag leaves the config value in charge
+	cli = NewCLI([]string{})
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := cli.GetOptions().MaxConcurrency; got != 0 {
+		t.Errorf("Expected MaxConcurrency 0 when flag is unset, got %d", got)
+	}
+}
+
+func TestCLI_RunWritesJUnitReportOnFailure(t *testing.T) {
+	tempDir := t.TempDir()
+	configFile := filepath.Join(tempDir, "test.queue.json")
+	reportFile := filepath.Join(tempDir, "report.xml")
+
+	configContent := `{
+		"version": "1.0",
+		"commands": [
+			{"name": "ok", "command": "echo", "args": ["hello"], "mode": "once"},
+			{"name": "broken", "command": "seqr-definitely-not-a-command", "mode": "once"}
+		]
+	}`
+
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	cli := NewCLI([]string{"-f", configFile, "--junit", reportFile})
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Failed to parse CLI args: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := cli.Run(ctx); err == nil {
+		t.Fatal("Expected Run to report the failing command")
+	}
+
+	data, err := os.ReadFile(reportFile)
+	if err != nil {
+		t.Fatalf("Expected JUnit report to be written: %v", err)
+	}
+
+	report := string(data)
+	for _, expected := range []string{`<testsuite name="test.queue.json"`, `<testcase name="ok"`, `<testcase name="broken"`, "<failure"} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("Expected report to contain %q, got:\n%s", expected, report)
+		}
+	}
+}
+
+func TestCLI_RunExpandsReportPaths(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	tempDir := t.TempDir()
+	configFile := filepath.Join(tempDir, "test.queue.json")
+	if err := os.WriteFile(configFile, []byte(`{"version": "1.0", "commands": [{"name": "ok", "command": "echo", "args": ["hello"], "mode": "once"}]}`), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	reportDir := filepath.Join(tempDir, "reports")
+	cli := NewCLI([]string{"-f", configFile,
+		"--junit", filepath.Join(reportDir, "${RUN_ID}", "${DATE}", "junit.xml"),
+		"--profile", filepath.Join(reportDir, "${RUN_ID}", "profile-${TIME}.json"),
+	})
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Failed to parse CLI args: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	output := captureStdout(t, func() {
+		if err := cli.Run(ctx); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+	})
+
+	if !regexp.MustCompile(`^\d{8}-\d{6}-[0-9a-f]{6}$`).MatchString(cli.runID) {
+		t.Fatalf("Expected a run ID of the start time and a random suffix, got %q", cli.runID)
+	}
+	if !strings.Contains(output, "Run ID: "+cli.runID) {
+		t.Errorf("Expected the run ID to be printed, got:\n%s", output)
+	}
+	lastRun, err := executor.LoadLastRun(executor.NewProcessManager().GetStateDir())
+	if err != nil || lastRun.RunID != cli.runID {
+		t.Errorf("Expected the last run to record run %s, got %+v (%v)", cli.runID, lastRun, err)
+	} else if lastRun.ConfigPath != configFile {
+		t.Errorf("Expected the last run to record config %s, got %s", configFile, lastRun.ConfigPath)
+	}
+	entries, err := os.ReadDir(reportDir)
+	if err != nil || len(entries) != 1 || entries[0].Name() != cli.runID {
+		t.Fatalf("Expected the reports in one directory named after run %s, got %v (%v)", cli.runID, entries, err)
+	}
+
+	runDir := filepath.Join(reportDir, cli.runID)
+	for _, path := range []string{
+		filepath.Join(runDir, cli.runStart.Format("2006-01-02"), "junit.xml"),
+		filepath.Join(runDir, "profile-"+cli.runStart.Format("150405")+".json"),
+	} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Expected a report at %s: %v", path, err)
+		}
+	}
+
+	report, err := os.ReadFile(filepath.Join(runDir, cli.runStart.Format("2006-01-02"), "junit.xml"))
+	if err != nil || !strings.Contains(string(report), `<property name="runId" value="`+cli.runID+`">`) {
+		t.Errorf("Expected the JUnit report to record run %s, got:\n%s", cli.runID, report)
+	}
+}
+
+func TestCLI_RunExpandsLogFilePath(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	tempDir := t.TempDir()
+	configFile := filepath.Join(tempDir, "test.queue.json")
+	if err := os.WriteFile(configFile, []byte(`{"version": "1.0", "commands": [{"name": "ok", "command": "echo", "args": ["hello"], "mode": "once"}]}`), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	logDir := filepath.Join(tempDir, "logs")
+	cli := NewCLI([]string{"-f", configFile, "-v", "--log-file", filepath.Join(logDir, "${RUN_ID}", "${NAME}-${DATE}.log")})
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Failed to parse CLI args: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	captureStdout(t, func() {
+		if err := cli.Run(ctx); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+	})
+
+	logFile := filepath.Join(logDir, cli.runID, "ok-"+cli.runStart.Format("2006-01-02")+".log")
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("Expected the background log at %s: %v", logFile, err)
+	}
+	if !strings.Contains(string(data), "hello") {
+		t.Errorf("Expected the command's output in its log, got:\n%s", data)
+	}
+}
+
+func TestCLI_RunLogsUnknownProcess(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("TMPDIR", t.TempDir())
+
+	cli := NewCLI([]string{"--logs", "no-such-process"})
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+	if !cli.ShouldRunLogs() {
+		t.Fatal("Expected ShouldRunLogs to be true")
+	}
+
+	err := cli.RunLogs(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "no-such-process") {
+		t.Errorf("Expected error naming the unknown process, got %v", err)
+	}
+}
+
+func TestCLI_RunCheckPathsReportsAllMissingWorkDirs(t *testing.T) {
+	tempDir := t.TempDir()
+	configFile := filepath.Join(tempDir, "test.queue.json")
+	markerFile := filepath.Join(tempDir, "ran.txt")
+
+	if err := os.Mkdir(filepath.Join(tempDir, "app"), 0755); err != nil {
+		t.Fatalf("Failed to create workDir: %v", err)
+	}
+
+	configContent := `{
+		"version": "1.0",
+		"commands": [
+			{"name": "marker", "command": "touch", "args": ["` + markerFile + `"], "mode": "once", "workDir": "app"},
+			{"name": "build", "command": "echo", "mode": "once", "workDir": "missing-build"},
+			{"name": "deploy", "command": "echo", "mode": "once", "workDir": "missing-deploy"}
+		]
+	}`
+
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	cli := NewCLI([]string{"-f", configFile, "--check-paths"})
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Failed to parse CLI args: %v", err)
+	}
+
+	err := cli.Run(context.Background())
+	if err == nil {
+		t.Fatal("Expected Run to fail on missing workDirs")
+	}
+
+	for _, missing := range []string{"missing-build", "missing-deploy"} {
+		if !strings.Contains(err.Error(), filepath.Join(tempDir, missing)) {
+			t.Errorf("Expected error to name %s resolved against the config directory, got: %v", missing, err)
+		}
+	}
+	if strings.Contains(err.Error(), filepath.Join(tempDir, "app")+"'") {
+		t.Errorf("Expected the existing workDir not to be reported, got: %v", err)
+	}
+
+	if _, statErr := os.Stat(markerFile); statErr == nil {
+		t.Error("Expected no command to run when the path check fails")
+	}
+}
+
+func TestCLI_RunWithConfigDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sh")
+	}
+
+	queueDir := t.TempDir()
+	outputFile := filepath.Join(t.TempDir(), "order.txt")
+
+	if err := os.Mkdir(filepath.Join(queueDir, "web"), 0755); err != nil {
+		t.Fatalf("Failed to create workDir: %v", err)
+	}
+
+	// Files are merged in name order, and relative workDirs resolve against the queue directory
+	queues := map[string]string{
+		"b-web.queue.json": `{
+			"version": "1.0",
+			"commands": [
+				{"name": "web", "command": "sh", "args": ["-c", "basename \"$PWD\" >> \"$OUT\""], "workDir": "web", "env": {"OUT": "` + filepath.ToSlash(outputFile) + `"}}
+			]
+		}`,
+		"a-db.queue.json": `{
+			"version": "1.0",
+			"commands": [
+				{"name": "db", "command": "sh", "args": ["-c", "echo db >> \"$OUT\""], "env": {"OUT": "` + filepath.ToSlash(outputFile) + `"}}
+			]
+		}`,
+	}
+	for name, content := range queues {
+		if err := os.WriteFile(filepath.Join(queueDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	cli := NewCLI([]string{"-d", queueDir})
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Failed to parse CLI args: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Run from elsewhere so the workDir can only resolve against the queue directory
+	t.Chdir(t.TempDir())
+	if err := cli.Run(ctx); err != nil {
+		t.Fatalf("CLI Run failed: %v", err)
+	}
+
+	data, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	if got := string(data); got != "db\nweb\n" {
+		t.Errorf("Expected commands from both files to run in name order, got %q", got)
+	}
+}
+
+func TestCLI_RunDumpEnv(t *testing.T) {
+	tempDir := t.TempDir()
+	configFile := filepath.Join(tempDir, "test.queue.json")
+	markerFile := filepath.Join(tempDir, "ran.txt")
+
+	configContent := `{
+		"version": "1.0",
+		"commands": [
+			{"name": "api", "command": "touch", "args": ["` + markerFile + `"], "mode": "once",
+			 "env": {"SEQR_TEST_PORT": "4000", "SEQR_TEST_API_TOKEN": "s3cr3t"}}
+		]
+	}`
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	t.Setenv("SEQR_TEST_PORT", "3000")
+	t.Setenv("SEQR_TEST_INHERITED", "yes")
+
+	cli := NewCLI([]string{"-f", configFile, "--dump-env", "api"})
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Failed to parse CLI args: %v", err)
+	}
+	if !cli.ShouldRunDumpEnv() {
+		t.Fatal("Expected --dump-env to be requested")
+	}
+
+	var runErr error
+	output := captureStdout(t, func() { runErr = cli.RunDumpEnv() })
+	if runErr != nil {
+		t.Fatalf("RunDumpEnv failed: %v", runErr)
+	}
+
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	for _, expected := range []string{"SEQR_TEST_PORT=4000", "SEQR_TEST_INHERITED=yes", "SEQR_TEST_API_TOKEN=********"} {
+		if !slices.Contains(lines, expected) {
+			t.Errorf("Expected %q in dump, got:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "SEQR_TEST_PORT=3000") || strings.Contains(output, "s3cr3t") {
+		t.Errorf("Expected overridden and secret values to be absent, got:\n%s", output)
+	}
+	if !slices.IsSorted(lines) {
+		t.Errorf("Expected sorted output, got:\n%s", output)
+	}
+	if _, err := os.Stat(markerFile); err == nil {
+		t.Error("Expected --dump-env not to run the command")
+	}
+
+	// --show-secrets reveals masked values, an unknown command is an error
+	cli = NewCLI([]string{"-f", configFile, "--dump-env", "api", "--show-secrets"})
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Failed to parse CLI args: %v", err)
+	}
+	output = captureStdout(t, func() { runErr = cli.RunDumpEnv() })
+	if runErr != nil || !strings.Contains(output, "SEQR_TEST_API_TOKEN=s3cr3t") {
+		t.Errorf("Expected secret to be shown with --show-secrets, got err %v and:\n%s", runErr, output)
+	}
+
+	cli = NewCLI([]string{"-f", configFile, "--dump-env", "missing"})
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Failed to parse CLI args: %v", err)
+	}
+	if err := cli.RunDumpEnv(); err == nil || !strings.Contains(err.Error(), "'missing' not found") {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
+
+func TestCLI_RunExpandsPositionalArgs(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sh")
+	}
+
+	tempDir := t.TempDir()
+	configFile := filepath.Join(tempDir, "build.queue.json")
+	outputFile := filepath.Join(tempDir, "args.txt")
+
+	configContent := `{
+		"version": "1.0",
+		"commands": [
+			{"name": "build", "command": "sh", "args": ["-c", "printf '%s|' \"$@\" > ` + outputFile + `", "sh", "${1}", "${ARG_2}", "${ARGS}"], "mode": "once"}
+		]
+	}`
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	cli := NewCLI([]string{"-f", configFile, "--", "--env", "staging"})
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Failed to parse CLI args: %v", err)
+	}
+	if got := cli.GetOptions().QueueArgs; !slices.Equal(got, []string{"--env", "staging"}) {
+		t.Fatalf("Expected queue args after --, got %v", got)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := cli.Run(ctx); err != nil {
+		t.Fatalf("CLI Run failed: %v", err)
+	}
+
+	data, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	if got := string(data); got != "--env|staging|--env staging|" {
+		t.Errorf("Expected positional args to be expanded, got %q", got)
+	}
+}
+
+func TestCLI_LoadConfigPositionalArgsNeedTerminator(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "build.queue.json")
+	configContent := `{
+		"version": "1.0",
+		"commands": [
+			{"name": "build", "command": "${1}", "args": ["-c", "echo ${1} ${ARGS}", "${2}"], "mode": "once"}
+		]
+	}`
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		args      []string
+		wantArgs  []string
+		errSubstr string
+	}{
+		{name: "without --", wantArgs: []string{"-c", "echo ${1} ${ARGS}", "${2}"}},
+		{name: "one argument", args: []string{"--", "sh"}, wantArgs: []string{"-c", "echo sh sh", "${2}"}},
+		{name: "empty command", args: []string{"--", ""}, errSubstr: "after expanding variables"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := NewCLI(append([]string{"-f", configFile}, tt.args...))
+			if err := cli.Parse(); err != nil {
+				t.Fatalf("Failed to parse CLI args: %v", err)
+			}
+
+			cfg, err := cli.loadConfig()
+			if tt.errSubstr != "" {
+				var configErr *ConfigError
+				if !errors.As(err, &configErr) || !strings.Contains(err.Error(), tt.errSubstr) {
+					t.Fatalf("Expected a configuration error containing %q, got %v", tt.errSubstr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadConfig failed: %v", err)
+			}
+			if got := cfg.Commands[0].Args; !slices.Equal(got, tt.wantArgs) {
+				t.Errorf("Expected args %q, got %q", tt.wantArgs, got)
+			}
+		})
+	}
+}
+
+func TestCLI_RunShowsOutputOnFailure(t *testing.T) {
+	tempDir := t.TempDir()
+	configFile := filepath.Join(tempDir, "test.queue.json")
+
+	configContent := `{
+		"version": "1.0",
+		"commands": [
+			{"name": "build", "command": "sh", "args": ["-c", "echo 'main.go:3: undefined: foo'; exit 2"], "mode": "once"}
+		]
+	}`
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	for _, enabled := range []bool{false, true} {
+		args := []string{"-f", configFile}
+		if enabled {
+			args = append(args, "--show-output-on-failure")
+		}
+		cli := NewCLI(args)
+		if err := cli.Parse(); err != nil {
+			t.Fatalf("Failed to parse CLI args: %v", err)
+		}
+
+		var runErr error
+		output := captureStdout(t, func() { runErr = cli.Run(context.Background()) })
+		if runErr == nil {
+			t.Fatal("Expected the run to fail")
+		}
+		if got := strings.Contains(output, "--- output of build ---\nmain.go:3: undefined: foo\n"); got != enabled {
+			t.Errorf("With --show-output-on-failure=%v, expected output replayed=%v, got:\n%s", enabled, enabled, output)
+		}
+	}
+}
+
+func TestCLI_ExpandHostEnvInWorkDir(t *testing.T) {
+	tempDir := t.TempDir()
+	configFile := filepath.Join(tempDir, "test.queue.json")
+	t.Setenv("SEQR_TEST_PROJECT_DIR", tempDir)
+
+	configContent := `{
+		"version": "1.0",
+		"commands": [
+			{"name": "build", "command": "true", "mode": "once", "workDir": "${SEQR_TEST_PROJECT_DIR}/web"}
+		]
+	}`
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	for _, enabled := range []bool{false, true} {
+		args := []string{"-f", configFile}
+		if enabled {
+			args = append(args, "--expand-host-env")
+		}
+		cli := NewCLI(args)
+		if err := cli.Parse(); err != nil {
+			t.Fatalf("Failed to parse CLI args: %v", err)
+		}
+
+		cfg, err := cli.loadConfig()
+		if err != nil {
+			t.Fatalf("Failed to load config: %v", err)
+		}
+
+		expected := "${SEQR_TEST_PROJECT_DIR}/web"
+		if enabled {
+			expected = tempDir + "/web"
+		}
+		if got := cfg.Commands[0].WorkDir; got != expected {
+			t.Errorf("With --expand-host-env=%v, expected workDir %q, got %q", enabled, expected, got)
+		}
+	}
+}
+
+func TestCLI_RunWithConfigURL(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sh")
+	}
+
+	runDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(runDir, "web"), 0755); err != nil {
+		t.Fatalf("Failed to create workDir: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"version": "1.0",
+			"commands": [
+				{"name": "web", "command": "sh", "args": ["-c", "basename \"$PWD\" > ../order.txt"], "workDir": "web"}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// A fetched queue has no directory, so its relative workDir resolves against the current one
+	t.Chdir(runDir)
+
+	cli := NewCLI([]string{"-f", server.URL + "/ci.queue.json"})
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Failed to parse CLI args: %v", err)
+	}
+	if err := cli.Run(ctx); err == nil || !strings.Contains(err.Error(), "--insecure") {
+		t.Fatalf("Expected a plain http URL to be refused without --insecure, got %v", err)
+	}
+
+	cli = NewCLI([]string{"-f", server.URL + "/ci.queue.json", "--insecure", "--check-paths"})
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Failed to parse CLI args: %v", err)
+	}
+	if err := cli.Run(ctx); err != nil {
+		t.Fatalf("CLI Run failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(runDir, "order.txt"))
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	if got := string(data); got != "web\n" {
+		t.Errorf("Expected the served command to run in the web workDir, got %q", got)
+	}
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+)
+
+// BuildInfo describes the seqr binary, as set at build time through -ldflags
+type BuildInfo struct {
+	Version string
+	Commit  string
+	Date    string
+}
+
+// versionOutput is the JSON form of the version, for bug reports and tooling to parse
+type versionOutput struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	Date      string `json:"date"`      // When the binary was built
+	GoVersion string `json:"goVersion"` // Go release the binary was built with
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+}
+
+// writeVersion writes the version as a "seqr version" line, or with --json as a JSON object
+// that adds the build metadata and the platform the binary was built for
+func (c *CLI) writeVersion(w io.Writer, build BuildInfo) {
+	if !c.options.JSON {
+		fmt.Fprintf(w, "seqr version %s\n", build.Version)
+		return
+	}
+
+	output := versionOutput{
+		Version:   build.Version,
+		Commit:    build.Commit,
+		Date:      build.Date,
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+	}
+	data, err := json.Marshal(output)
+	if err != nil {
+		fmt.Fprintf(w, "seqr version %s\n", build.Version)
+		return
+	}
+	fmt.Fprintf(w, "%s\n", data)
+}
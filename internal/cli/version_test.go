@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestCLI_WriteVersionAsJSON(t *testing.T) {
+	cli := NewCLI([]string{"--version", "--json"})
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Failed to parse CLI args: %v", err)
+	}
+
+	var buf bytes.Buffer
+	cli.writeVersion(&buf, BuildInfo{Version: "1.4.0", Commit: "a45b34f", Date: "2026-10-01T12:00:00Z"})
+
+	var output map[string]string
+	if err := json.Unmarshal(buf.Bytes(), &output); err != nil {
+		t.Fatalf("Expected JSON output, got %q: %v", buf.String(), err)
+	}
+
+	expected := map[string]string{
+		"version":   "1.4.0",
+		"commit":    "a45b34f",
+		"date":      "2026-10-01T12:00:00Z",
+		"goVersion": runtime.Version(),
+		"os":        runtime.GOOS,
+		"arch":      runtime.GOARCH,
+	}
+	for field, value := range expected {
+		if output[field] != value {
+			t.Errorf("Expected %s %q, got %q", field, value, output[field])
+		}
+	}
+}
+
+func TestCLI_WriteVersionAsText(t *testing.T) {
+	cli := NewCLI([]string{"--version"})
+	if err := cli.Parse(); err != nil {
+		t.Fatalf("Failed to parse CLI args: %v", err)
+	}
+
+	var buf bytes.Buffer
+	cli.writeVersion(&buf, BuildInfo{Version: "1.4.0", Commit: "a45b34f", Date: "2026-10-01T12:00:00Z"})
+
+	if buf.String() != "seqr version 1.4.0\n" {
+		t.Errorf("Expected the plain version line, got %q", buf.String())
+	}
+}
+
+func TestCLI_JSONRequiresVersion(t *testing.T) {
+	cli := NewCLI([]string{"--json"})
+	err := cli.Parse()
+	if err == nil || !strings.Contains(err.Error(), "--json can only be used with --version") {
+		t.Errorf("Expected --json without --version to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// extractAliases parses the top-level aliases: a map from name to the command prefix it stands
+// for, given as a string split like a command string or as an array of words. An alias's prefix
+// may itself start with another @alias.
+func (n *Normalizer) extractAliases(aliasesInterface interface{}) (map[string][]string, error) {
+	aliasesMap, ok := aliasesInterface.(map[string]interface{})
+	if !ok {
+		return nil, ConfigNormalizationError{
+			Message:      fmt.Sprintf("aliases must be an object, got %T", aliasesInterface),
+			CommandIndex: -1,
+			Field:        "aliases",
+			Value:        aliasesInterface,
+			Suggestion:   "Map alias names to command prefixes: \"aliases\": {\"drun\": \"docker run --rm\"}",
+		}
+	}
+
+	aliases := make(map[string][]string, len(aliasesMap))
+	for name, prefixInterface := range aliasesMap {
+		if name == "" || strings.ContainsAny(name, " \t\n@") {
+			return nil, ConfigNormalizationError{
+				Message:      fmt.Sprintf("alias name '%s' must be non-empty without whitespace or @", name),
+				CommandIndex: -1,
+				Field:        "aliases",
+				Value:        name,
+				Suggestion:   "Name aliases with a single word, such as \"drun\", and refer to them as \"@drun\"",
+			}
+		}
+
+		var words []string
+		var err error
+		switch prefix := prefixInterface.(type) {
+		case string:
+			words, err = splitShellWords(prefix)
+		case []interface{}:
+			for i, word := range prefix {
+				wordStr, ok := word.(string)
+				if !ok {
+					err = fmt.Errorf("element %d must be a string, got %T", i, word)
+					break
+				}
+				words = append(words, wordStr)
+			}
+		default:
+			err = fmt.Errorf("must be a string or an array of strings, got %T", prefixInterface)
+		}
+		if err == nil && len(words) == 0 {
+			err = fmt.Errorf("cannot be empty")
+		}
+		if err != nil {
+			return nil, ConfigNormalizationError{
+				Message:      fmt.Sprintf("alias '%s' %v", name, err),
+				CommandIndex: -1,
+				Field:        fmt.Sprintf("aliases.%s", name),
+				Value:        prefixInterface,
+				Suggestion:   "Give the command prefix as a string, \"docker run --rm\", or an array, [\"docker\", \"run\", \"--rm\"]",
+			}
+		}
+		aliases[name] = words
+	}
+
+	return resolveAliases(aliases)
+}
+
+// resolveAliases expands aliases whose prefix starts with another alias, so each maps straight
+// to its executable and leading arguments. Aliases referring back to themselves are an error.
+func resolveAliases(aliases map[string][]string) (map[string][]string, error) {
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	// Sorted so the cycle reported does not depend on map order
+	sort.Strings(names)
+
+	resolved := make(map[string][]string, len(aliases))
+	var resolve func(name string, chain []string) ([]string, error)
+	resolve = func(name string, chain []string) ([]string, error) {
+		if words, done := resolved[name]; done {
+			return words, nil
+		}
+		for i, seen := range chain {
+			if seen == name {
+				cycle := append(append([]string{}, chain[i:]...), name)
+				return nil, ConfigNormalizationError{
+					Message:      fmt.Sprintf("aliases refer to each other in a cycle: @%s", strings.Join(cycle, " -> @")),
+					CommandIndex: -1,
+					Field:        "aliases",
+					Suggestion:   "Make one of the aliases in the cycle spell out its command instead of referring to another alias",
+				}
+			}
+		}
+
+		words := aliases[name]
+		if target, isAlias := strings.CutPrefix(words[0], "@"); isAlias {
+			if _, known := aliases[target]; !known {
+				return nil, ConfigNormalizationError{
+					Message:      fmt.Sprintf("alias '%s' refers to unknown alias '@%s'", name, target),
+					CommandIndex: -1,
+					Field:        fmt.Sprintf("aliases.%s", name),
+					Suggestion:   "Define the alias it refers to, or spell out the command",
+				}
+			}
+			prefix, err := resolve(target, append(chain, name))
+			if err != nil {
+				return nil, err
+			}
+			words = append(append([]string{}, prefix...), words[1:]...)
+		}
+
+		resolved[name] = words
+		return words, nil
+	}
+
+	for _, name := range names {
+		if _, err := resolve(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return resolved, nil
+}
+
+// expandAlias replaces a command's @alias executable with the alias's prefix, putting the alias's
+// arguments before the command's own. A generated name is derived again from the alias name, so
+// "@drun node" is named drun-node.
+func (n *Normalizer) expandAlias(cmd *Command, aliases map[string][]string, index int, generatedName bool) error {
+	name, isAlias := strings.CutPrefix(cmd.Command, "@")
+	if !isAlias {
+		return nil
+	}
+
+	prefix, known := aliases[name]
+	if !known {
+		return ConfigNormalizationError{
+			Message:      fmt.Sprintf("command refers to unknown alias '@%s'", name),
+			CommandIndex: index,
+			Field:        "command",
+			Value:        cmd.Command,
+			Suggestion:   fmt.Sprintf("Define it in the top-level aliases: \"aliases\": {\"%s\": \"...\"}", name),
+		}
+	}
+
+	if generatedName {
+		cmd.Name = n.generateCommandName(name, cmd.Args)
+	}
+	cmd.Command = prefix[0]
+	if args := append(append([]string{}, prefix[1:]...), cmd.Args...); len(args) > 0 {
+		cmd.Args = args
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package config
+
+import (
+	"fmt"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// LookupEncoding returns the character set an encoding field names, by a label browsers accept
+// such as "utf-16le", "windows-1252" or "shift_jis". UTF-8 returns nil, as output in it needs no
+// decoding.
+func LookupEncoding(name string) (encoding.Encoding, error) {
+	enc, err := htmlindex.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown encoding '%s'", name)
+	}
+	if enc == unicode.UTF8 {
+		return nil, nil
+	}
+	return enc, nil
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VariableLookup resolves the value of a ${NAME} reference; ok is false for names it does not define
+type VariableLookup func(name string) (value string, ok bool)
+
+// ExpandVariables replaces each ${NAME} reference in s that lookup defines. References to names
+// lookup does not define are left untouched so they can still be expanded by a shell later.
+func ExpandVariables(s string, lookup VariableLookup) string {
+	if !strings.Contains(s, "${") {
+		return s
+	}
+
+	var b strings.Builder
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(s[start+2:], '}')
+		if end < 0 {
+			break
+		}
+		end += start + 2
+
+		b.WriteString(s[:start])
+		if value, ok := lookup(s[start+2 : end]); ok {
+			b.WriteString(value)
+		} else {
+			b.WriteString(s[start : end+1])
+		}
+		s = s[end+1:]
+	}
+	b.WriteString(s)
+
+	return b.String()
+}
+
+// PositionalArgs returns a lookup for the arguments passed after `--` on the command line:
+// ${ARGS} is all of them joined with single spaces, and ${ARG_1} (or ${1}), ${ARG_2} (or ${2}),
+// and so on are the individual arguments. Positions past the last argument are not defined, so
+// they are left as they are.
+func PositionalArgs(args []string) VariableLookup {
+	return func(name string) (string, bool) {
+		if name == "ARGS" {
+			return strings.Join(args, " "), true
+		}
+
+		digits := strings.TrimPrefix(name, "ARG_")
+		if digits == "" || digits[0] < '0' || digits[0] > '9' {
+			return "", false
+		}
+		position, err := strconv.Atoi(digits)
+		if err != nil || position < 1 {
+			return "", false
+		}
+		if position > len(args) {
+			return "", false
+		}
+		return args[position-1], true
+	}
+}
+
+// HostEnv returns a lookup for the environment seqr itself runs in, so that ${HOME} expands to
+// the host's home directory. Unset variables are left untouched rather than expanding to "".
+func HostEnv() VariableLookup {
+	return os.LookupEnv
+}
+
+// RunVariables returns a lookup for the built-in variables that describe a run, for paths such as
+// a report's: ${RUN_ID}, and ${DATE} (2006-01-02) and ${TIME} (150405) of when the run started
+func RunVariables(runID string, start time.Time) VariableLookup {
+	return func(name string) (string, bool) {
+		switch name {
+		case "RUN_ID":
+			return runID, true
+		case "DATE":
+			return start.Format("2006-01-02"), true
+		case "TIME":
+			return start.Format("150405"), true
+		}
+		return "", false
+	}
+}
+
+// ChainLookups returns a lookup that tries each of lookups in turn, the first to define a
+// name giving its value
+func ChainLookups(lookups ...VariableLookup) VariableLookup {
+	return func(name string) (string, bool) {
+		for _, lookup := range lookups {
+			if value, ok := lookup(name); ok {
+				return value, true
+			}
+		}
+		return "", false
+	}
+}
+
+// ExpandVariables applies lookup to every command's command, args, args file, ready file, ready TCP address, output FIFO, workDir, env values, env file paths, filter and external readiness checks
+func (c *Config) ExpandVariables(lookup VariableLookup) {
+	for i := range c.Commands {
+		c.Commands[i].expandVariables(lookup)
+	}
+}
+
+// expandVariables applies lookup to the fields of one command that Config.ExpandVariables covers
+func (cmd *Command) expandVariables(lookup VariableLookup) {
+	cmd.Command = ExpandVariables(cmd.Command, lookup)
+	cmd.WorkDir = ExpandVariables(cmd.WorkDir, lookup)
+	for j, arg := range cmd.Args {
+		cmd.Args[j] = ExpandVariables(arg, lookup)
+	}
+	cmd.ArgsFile = ExpandVariables(cmd.ArgsFile, lookup)
+	cmd.ReadyFile = ExpandVariables(cmd.ReadyFile, lookup)
+	cmd.ReadyTCP = ExpandVariables(cmd.ReadyTCP, lookup)
+	cmd.OutputFifo = ExpandVariables(cmd.OutputFifo, lookup)
+	for key, value := range cmd.Env {
+		cmd.Env[key] = ExpandVariables(value, lookup)
+	}
+	for key, path := range cmd.EnvFromFile {
+		cmd.EnvFromFile[key] = ExpandVariables(path, lookup)
+	}
+	for j, word := range cmd.Filter {
+		cmd.Filter[j] = ExpandVariables(word, lookup)
+	}
+	if external := cmd.ExternalReady; external != nil {
+		external.ReadyTCP = ExpandVariables(external.ReadyTCP, lookup)
+		external.ReadyFile = ExpandVariables(external.ReadyFile, lookup)
+		for j, word := range external.Command {
+			external.Command[j] = ExpandVariables(word, lookup)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpandVariablesWithPositionalArgs(t *testing.T) {
+	lookup := PositionalArgs([]string{"--env", "staging eu"})
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"${ARGS}", "--env staging eu"},
+		{"${1}=${2}", "--env=staging eu"},
+		{"deploy-${ARG_2}.log", "deploy-staging eu.log"},
+		{"${ARG_3} ${3}", "${ARG_3} ${3}"},
+		{"${HOME}/bin", "${HOME}/bin"},
+		{"${+1} ${0} ${ARG_}", "${+1} ${0} ${ARG_}"},
+		{"no variables", "no variables"},
+		{"unterminated ${1", "unterminated ${1"},
+	}
+
+	for _, tt := range tests {
+		if got := ExpandVariables(tt.input, lookup); got != tt.expected {
+			t.Errorf("ExpandVariables(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestConfigExpandVariables(t *testing.T) {
+	cfg := &Config{
+		Version: "1.0",
+		Commands: []Command{
+			{
+				Name:    "deploy",
+				Command: "./deploy-${1}.sh",
+				Args:    []string{"--target", "${1}", "${ARGS}"},
+				Mode:    ModeOnce,
+				WorkDir: "envs/${1}",
+				Env:     map[string]string{"TARGET": "${1}"},
+			},
+		},
+	}
+
+	cfg.ExpandVariables(PositionalArgs([]string{"staging", "fast"}))
+
+	cmd := cfg.Commands[0]
+	if cmd.Command != "./deploy-staging.sh" || cmd.WorkDir != "envs/staging" || cmd.Env["TARGET"] != "staging" {
+		t.Errorf("Expected command, workDir and env to be expanded, got %+v", cmd)
+	}
+	expectedArgs := []string{"--target", "staging", "staging fast"}
+	if len(cmd.Args) != len(expectedArgs) {
+		t.Fatalf("Expected args %v, got %v", expectedArgs, cmd.Args)
+	}
+	for i := range expectedArgs {
+		if cmd.Args[i] != expectedArgs[i] {
+			t.Errorf("Expected args %v, got %v", expectedArgs, cmd.Args)
+			break
+		}
+	}
+}
+
+func TestExpandVariablesWithHostEnv(t *testing.T) {
+	t.Setenv("SEQR_TEST_PROJECT", "/srv/project")
+	t.Setenv("ARGS", "from-host")
+
+	lookup := ChainLookups(PositionalArgs([]string{"staging"}), HostEnv())
+
+	tests := map[string]string{
+		"${SEQR_TEST_PROJECT}/web": "/srv/project/web",
+		"${ARGS}":                  "staging",
+		"${SEQR_TEST_UNSET_VAR}":   "${SEQR_TEST_UNSET_VAR}",
+		"$SEQR_TEST_PROJECT":       "$SEQR_TEST_PROJECT",
+	}
+	for input, expected := range tests {
+		if got := ExpandVariables(input, lookup); got != expected {
+			t.Errorf("ExpandVariables(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
+
+func TestExpandVariablesWithRunVariables(t *testing.T) {
+	start := time.Date(2026, 3, 7, 9, 5, 30, 0, time.Local)
+	lookup := RunVariables("20260307-090530-a1b2c3", start)
+
+	tests := map[string]string{
+		"reports/${RUN_ID}/junit.xml": "reports/20260307-090530-a1b2c3/junit.xml",
+		"logs/${DATE}/${TIME}.json":   "logs/2026-03-07/090530.json",
+		"${HOME}/report.xml":          "${HOME}/report.xml",
+	}
+	for input, expected := range tests {
+		if got := ExpandVariables(input, lookup); got != expected {
+			t.Errorf("ExpandVariables(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
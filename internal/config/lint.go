@@ -0,0 +1,158 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// LintWarning is a pattern in a valid configuration that is likely a mistake or a fragile way
+// to get what it wants
+type LintWarning struct {
+	Command    string // Name of the command the warning is about, empty for the whole queue
+	Message    string
+	Suggestion string
+}
+
+// String formats the warning as one line, followed by its suggestion
+func (w LintWarning) String() string {
+	message := w.Message
+	if w.Command != "" {
+		message = fmt.Sprintf("command '%s': %s", w.Command, message)
+	}
+	if w.Suggestion == "" {
+		return message
+	}
+	return fmt.Sprintf("%s\nSuggestion: %s", message, w.Suggestion)
+}
+
+// Lint looks for anti-patterns in a configuration that validation accepts, such as a sleep
+// standing in for a readiness check. Warnings about commands come in queue order, then those about
+// signalForwarding.
+func Lint(config *Config) []LintWarning {
+	var warnings []LintWarning
+
+	groupSizes := concurrentGroupSizes(config.Commands)
+	generated := generatedNames(config.Commands)
+	keepAliveSeen := ""
+
+	for i, cmd := range config.Commands {
+		if isSleep(cmd) && keepAliveSeen != "" {
+			warnings = append(warnings, LintWarning{
+				Command:    cmd.Name,
+				Message:    fmt.Sprintf("sleeps, likely to wait for keepAlive command '%s' to come up", keepAliveSeen),
+				Suggestion: fmt.Sprintf("Have '%s' create a readyFile and make the commands that need it concurrent with \"afterReady\": [\"%s\"]", keepAliveSeen, keepAliveSeen),
+			})
+		}
+		if cmd.Mode == ModeKeepAlive {
+			keepAliveSeen = cmd.Name
+		}
+
+		if cmd.Concurrent && groupSizes[i] == 1 {
+			warnings = append(warnings, LintWarning{
+				Command:    cmd.Name,
+				Message:    "is concurrent, but no neighbouring command is, so it runs on its own",
+				Suggestion: "Mark the commands it should run alongside as concurrent too, or drop \"concurrent\"",
+			})
+		}
+		if cmd.Priority != 0 && groupSizes[i] < 2 {
+			warnings = append(warnings, LintWarning{
+				Command:    cmd.Name,
+				Message:    "sets a priority outside a concurrent group, where it has no effect",
+				Suggestion: "Priorities only order the commands of a concurrent group waiting for a maxConcurrency slot; drop \"priority\"",
+			})
+		}
+
+		for _, name := range cmd.AfterReady {
+			if dependency := config.findCommand(name); dependency != nil && dependency.ReadyFile == "" && dependency.ReadyTCP == "" {
+				warnings = append(warnings, LintWarning{
+					Command:    cmd.Name,
+					Message:    fmt.Sprintf("waits for '%s', which has no readyFile or readyTCP, so it only waits for the process to start", name),
+					Suggestion: fmt.Sprintf("Have '%s' create a readyFile once it accepts work, or set its readyTCP address", name),
+				})
+			}
+			if generated[name] {
+				warnings = append(warnings, generatedNameWarning(name, fmt.Sprintf("'%s' waits for it", cmd.Name)))
+			}
+		}
+	}
+
+	for _, signal := range slices.Sorted(maps.Keys(config.SignalForwarding)) {
+		for _, name := range config.SignalForwarding[signal] {
+			if generated[name] {
+				warnings = append(warnings, generatedNameWarning(name, fmt.Sprintf("signalForwarding relays %s to it", signal)))
+			}
+		}
+	}
+
+	return warnings
+}
+
+// generatedNameWarning warns that a command is referred to by the name generated for it
+func generatedNameWarning(name, reference string) LintWarning {
+	return LintWarning{
+		Command:    name,
+		Message:    fmt.Sprintf("has no name of its own, but %s by its generated name", reference),
+		Suggestion: "Give it a \"name\", since the generated one changes, or collides with another command's, when its command or first argument does",
+	}
+}
+
+// findCommand returns the command with the given name, or nil
+func (c *Config) findCommand(name string) *Command {
+	for i := range c.Commands {
+		if c.Commands[i].Name == name {
+			return &c.Commands[i]
+		}
+	}
+	return nil
+}
+
+// concurrentGroupSizes returns, for each command, the number of commands in its concurrent
+// group, a run of consecutive concurrent commands; sequential commands are groups of one
+func concurrentGroupSizes(commands []Command) []int {
+	sizes := make([]int, len(commands))
+	for start := 0; start < len(commands); {
+		end := start + 1
+		for end < len(commands) && ConcurrentWith(commands[end-1], commands[end]) {
+			end++
+		}
+		for i := start; i < end; i++ {
+			sizes[i] = end - start
+		}
+		start = end
+	}
+	return sizes
+}
+
+// generatedNames returns the names that match what the normalizer generates for an unnamed
+// command. A name given explicitly in that form is indistinguishable, and is treated alike.
+func generatedNames(commands []Command) map[string]bool {
+	normalizer := NewNormalizer()
+	names := make(map[string]bool)
+	for _, cmd := range commands {
+		if cmd.Name == normalizer.generateCommandName(cmd.Command, cmd.Args) {
+			names[cmd.Name] = true
+		}
+	}
+	return names
+}
+
+// isSleep reports whether a command runs sleep, directly or as a shell script such as
+// sh -c "sleep 5"
+func isSleep(cmd Command) bool {
+	executable := strings.TrimSuffix(filepath.Base(cmd.Command), ".exe")
+	switch executable {
+	case "sleep":
+		return true
+	case "sh", "bash", "zsh":
+		for i, arg := range cmd.Args {
+			if arg == "-c" && i+1 < len(cmd.Args) {
+				fields := strings.Fields(cmd.Args[i+1])
+				return len(fields) > 0 && fields[0] == "sleep"
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name         string
+		json         string
+		wantWarnings []string // Substrings of the expected warnings, in order
+	}{
+		{
+			name: "clean queue",
+			json: `{"version": "1.0", "commands": [
+				{"name": "db", "command": "postgres", "mode": "keepAlive", "concurrent": true, "readyFile": "/tmp/db.ready"},
+				{"name": "migrate", "command": "migrate", "concurrent": true, "afterReady": ["db"], "priority": 1}
+			]}`,
+		},
+		{
+			name: "sleep after a keepAlive command",
+			json: `{"version": "1.0", "commands": [
+				{"name": "db", "command": "postgres", "mode": "keepAlive"},
+				{"name": "wait", "command": "sleep", "args": ["5"]},
+				{"name": "migrate", "command": "migrate"}
+			]}`,
+			wantWarnings: []string{"command 'wait': sleeps, likely to wait for keepAlive command 'db'"},
+		},
+		{
+			name: "sleep in a shell script",
+			json: `{"version": "1.0", "commands": [
+				{"name": "db", "command": "postgres", "mode": "keepAlive"},
+				{"name": "migrate", "command": "sh", "args": ["-c", "sleep 5 && migrate"]}
+			]}`,
+			wantWarnings: []string{"command 'migrate': sleeps"},
+		},
+		{
+			name: "sleep before any keepAlive command",
+			json: `{"version": "1.0", "commands": [
+				{"name": "wait", "command": "sleep", "args": ["5"]},
+				{"name": "db", "command": "postgres", "mode": "keepAlive"}
+			]}`,
+		},
+		{
+			name: "afterReady without readyFile",
+			json: `{"version": "1.0", "commands": [
+				{"name": "db", "command": "postgres", "mode": "keepAlive", "concurrent": true},
+				{"name": "migrate", "command": "migrate", "concurrent": true, "afterReady": ["db"]}
+			]}`,
+			wantWarnings: []string{"command 'migrate': waits for 'db', which has no readyFile"},
+		},
+		{
+			name: "lone concurrent command with a priority",
+			json: `{"version": "1.0", "commands": [
+				{"name": "build", "command": "make", "concurrent": true, "priority": 5},
+				{"name": "test", "command": "make", "args": ["test"]}
+			]}`,
+			wantWarnings: []string{
+				"command 'build': is concurrent, but no neighbouring command is",
+				"command 'build': sets a priority outside a concurrent group",
+			},
+		},
+		{
+			name: "references to generated names",
+			json: `{"version": "1.0",
+				"signalForwarding": {"SIGUSR1": ["nginx-serve"]},
+				"commands": [
+					{"command": "nginx serve", "mode": "keepAlive", "concurrent": true, "readyFile": "/tmp/nginx.ready"},
+					{"name": "smoke", "command": "curl", "args": ["localhost"], "concurrent": true, "afterReady": ["nginx-serve"]}
+				]}`,
+			wantWarnings: []string{
+				"command 'nginx-serve': has no name of its own, but 'smoke' waits for it",
+				"command 'nginx-serve': has no name of its own, but signalForwarding relays SIGUSR1 to it",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ParseJSON([]byte(tt.json))
+			if err != nil {
+				t.Fatalf("Failed to parse config: %v", err)
+			}
+
+			warnings := Lint(cfg)
+			if len(warnings) != len(tt.wantWarnings) {
+				t.Fatalf("Expected %d warnings, got %d: %v", len(tt.wantWarnings), len(warnings), warnings)
+			}
+			for i, want := range tt.wantWarnings {
+				if got := warnings[i].String(); !strings.Contains(got, want) || !strings.Contains(got, "\nSuggestion: ") {
+					t.Errorf("Expected warning %d to contain %q and a suggestion, got %q", i, want, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ToolVersion is the version of the running seqr, which a config's minVersion is checked against.
+// main sets it from the build version. Builds that are not releases, such as "dev", run any config.
+var ToolVersion = "dev"
+
+// MinVersionError reports a config whose minVersion is newer than the running seqr
+type MinVersionError struct {
+	MinVersion  string
+	ToolVersion string
+}
+
+func (e *MinVersionError) Error() string {
+	return fmt.Sprintf("this configuration requires seqr %s or newer (minVersion), but this is seqr %s\nSuggestion: Upgrade seqr, or lower minVersion if the configuration does not use newer features",
+		e.MinVersion, e.ToolVersion)
+}
+
+// CheckMinVersion returns a *MinVersionError when toolVersion is older than minVersion. A tool
+// version that is not a release, such as a dev build, satisfies any minVersion.
+func CheckMinVersion(minVersion, toolVersion string) error {
+	required, ok := parseReleaseVersion(minVersion)
+	if !ok {
+		return fmt.Errorf("minVersion '%s' is not a valid seqr version (expected format: X.Y or X.Y.Z)", minVersion)
+	}
+	running, ok := parseReleaseVersion(toolVersion)
+	if !ok {
+		return nil
+	}
+	if slices.Compare(running[:], required[:]) < 0 {
+		return &MinVersionError{MinVersion: minVersion, ToolVersion: toolVersion}
+	}
+	return nil
+}
+
+// parseReleaseVersion parses a seqr version such as 1.4, 1.4.2 or v1.4.2-rc.1 into its major,
+// minor and patch numbers. Pre-release and build suffixes are ignored.
+func parseReleaseVersion(version string) ([3]int, bool) {
+	var parsed [3]int
+	core, _, _ := strings.Cut(strings.TrimPrefix(version, "v"), "+")
+	core, _, _ = strings.Cut(core, "-")
+
+	parts := strings.Split(core, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return parsed, false
+	}
+	for i, part := range parts {
+		if part == "" || strings.Trim(part, "0123456789") != "" {
+			return parsed, false
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return parsed, false
+		}
+		parsed[i] = n
+	}
+	return parsed, true
+}
+
+// newerVersion returns whichever of two minVersions is the later one, or b when a is empty
+func newerVersion(a, b string) string {
+	parsedA, _ := parseReleaseVersion(a)
+	parsedB, _ := parseReleaseVersion(b)
+	if slices.Compare(parsedA[:], parsedB[:]) < 0 {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckMinVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		minVersion  string
+		toolVersion string
+		tooOld      bool
+		errorSubstr string
+	}{
+		{name: "same release", minVersion: "1.4.0", toolVersion: "1.4.0"},
+		{name: "newer release", minVersion: "1.4", toolVersion: "v1.10.2"},
+		{name: "older patch", minVersion: "1.4.2", toolVersion: "1.4.1", tooOld: true},
+		{name: "older minor", minVersion: "2.0", toolVersion: "1.9.9", tooOld: true},
+		{name: "pre-release suffix ignored", minVersion: "1.4.0", toolVersion: "1.4.0-rc.1"},
+		{name: "dev build", minVersion: "99.0", toolVersion: "dev"},
+		{name: "invalid minVersion", minVersion: "latest", toolVersion: "1.4.0", errorSubstr: "not a valid seqr version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckMinVersion(tt.minVersion, tt.toolVersion)
+
+			var tooOld *MinVersionError
+			if errors.As(err, &tooOld) != tt.tooOld {
+				t.Fatalf("Expected too old %v, got %v", tt.tooOld, err)
+			}
+			if tt.errorSubstr != "" && (err == nil || !strings.Contains(err.Error(), tt.errorSubstr)) {
+				t.Fatalf("Expected error containing %q, got %v", tt.errorSubstr, err)
+			}
+			if !tt.tooOld && tt.errorSubstr == "" && err != nil {
+				t.Fatalf("Expected minVersion to be satisfied, got %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadFromFile_MinVersion(t *testing.T) {
+	original := ToolVersion
+	ToolVersion = "1.4.0"
+	t.Cleanup(func() { ToolVersion = original })
+
+	load := func(t *testing.T, minVersion, mode string) (*Config, error) {
+		t.Helper()
+		content := `{"version": "1.0", "minVersion": "` + minVersion + `", "commands": [{"name": "build", "command": "go", "mode": "` + mode + `"}]}`
+		configFile := filepath.Join(t.TempDir(), ".queue.json")
+		if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+		return LoadFromFile(configFile)
+	}
+
+	t.Run("satisfied", func(t *testing.T) {
+		cfg, err := load(t, "1.3", "once")
+		if err != nil {
+			t.Fatalf("Expected the config to load, got %v", err)
+		}
+		if cfg.MinVersion != "1.3" || cfg.Version != "1.0" {
+			t.Errorf("Expected version 1.0 and minVersion 1.3, got %q and %q", cfg.Version, cfg.MinVersion)
+		}
+	})
+
+	t.Run("unsatisfied", func(t *testing.T) {
+		// A mode this seqr rejects stands in for a value a newer release added, the version
+		// check comes first
+		_, err := load(t, "1.5.0", "restartAlways")
+		var tooOld *MinVersionError
+		if !errors.As(err, &tooOld) {
+			t.Fatalf("Expected a MinVersionError, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "requires seqr 1.5.0 or newer (minVersion), but this is seqr 1.4.0") {
+			t.Errorf("Expected the error to name both versions, got %v", err)
+		}
+	})
+}
@@ -3,14 +3,26 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
+	"runtime"
+	"slices"
 	"strings"
+	"time"
 )
 
 // Normalizer provides functionality to convert various command formats to a unified internal structure
 type Normalizer struct {
 	StrictMode bool
+
+	// GOOS is the operating system env values keyed by OS are resolved for; empty means the
+	// one seqr runs on
+	GOOS string
 }
 
+// envOSKeys are the keys an env value keyed by OS may use: the operating systems Go supports,
+// and "default" for the others
+var envOSKeys = []string{"aix", "android", "darwin", "dragonfly", "freebsd", "illumos", "ios", "js", "linux", "netbsd", "openbsd", "plan9", "solaris", "wasip1", "windows", "default"}
+
 // NewNormalizer creates a new normalizer with default settings
 func NewNormalizer() *Normalizer {
 	return &Normalizer{
@@ -120,7 +132,8 @@ func (n *Normalizer) enhanceNormalizationError(format string, originalErr error,
 	}
 }
 
-// normalizeStringCommand handles string format commands like "npm run build"
+// normalizeStringCommand handles string format commands like "npm run build". The string is split
+// into words the way a shell would, so `echo "hello world"` passes "hello world" as one argument.
 func (n *Normalizer) normalizeStringCommand(input string, cmd *Command) error {
 	if input == "" {
 		return fmt.Errorf("command string cannot be empty")
@@ -131,7 +144,10 @@ func (n *Normalizer) normalizeStringCommand(input string, cmd *Command) error {
 	}
 
 	// Split the string into command and arguments
-	parts := strings.Fields(input)
+	parts, err := splitShellWords(input)
+	if err != nil {
+		return err
+	}
 	if len(parts) == 0 {
 		return fmt.Errorf("command string cannot be empty after parsing")
 	}
@@ -144,6 +160,74 @@ func (n *Normalizer) normalizeStringCommand(input string, cmd *Command) error {
 	return nil
 }
 
+// splitShellWords splits a command string into words following POSIX shell quoting rules:
+// whitespace separates words, single quotes keep everything literal, double quotes keep
+// whitespace but allow \" \\ \$ and \` escapes, and a backslash outside quotes escapes the next
+// character. Quotes only group, so `--name="my app"` is the single word --name=my app. There is
+// no variable expansion, globbing, or operator handling; a command needing those should run
+// through a shell, e.g. "sh -c '...'".
+func splitShellWords(input string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+
+	runes := []rune(input)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+
+		case r == '\\':
+			if i+1 == len(runes) {
+				return nil, fmt.Errorf("command string ends with an unescaped backslash")
+			}
+			i++
+			word.WriteRune(runes[i])
+			inWord = true
+
+		case r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != '\'' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("command string has an unterminated single quote")
+			}
+			word.WriteString(string(runes[i+1 : end]))
+			i = end
+			inWord = true
+
+		case r == '"':
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) && strings.ContainsRune("\"\\$`", runes[i+1]) {
+					i++
+				}
+				word.WriteRune(runes[i])
+			}
+			if i == len(runes) {
+				return nil, fmt.Errorf("command string has an unterminated double quote")
+			}
+			inWord = true
+
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
 // normalizeArrayCommand handles array format commands like ["npm", "run", "build"]
 func (n *Normalizer) normalizeArrayCommand(input []interface{}, cmd *Command) error {
 	if len(input) == 0 {
@@ -289,6 +373,60 @@ func (n *Normalizer) NormalizeConfig(input interface{}) (*Config, error) {
 		})
 	}
 
+	// Checked before anything else, since a config for a newer seqr may use fields this one rejects
+	if minVersionInterface, hasMinVersion := configMap["minVersion"]; hasMinVersion {
+		minVersion, ok := minVersionInterface.(string)
+		if !ok {
+			errors = append(errors, ConfigNormalizationError{
+				Message:      fmt.Sprintf("minVersion must be a string, got %T", minVersionInterface),
+				CommandIndex: -1,
+				Field:        "minVersion",
+				Value:        minVersionInterface,
+				Suggestion:   "Set minVersion to the oldest seqr release the config works with, like \"1.4.0\"",
+			})
+		} else if err := CheckMinVersion(minVersion, ToolVersion); err != nil {
+			if _, tooOld := err.(*MinVersionError); tooOld {
+				return nil, err
+			}
+			errors = append(errors, ConfigNormalizationError{
+				Message:      err.Error(),
+				CommandIndex: -1,
+				Field:        "minVersion",
+				Value:        minVersion,
+				Suggestion:   "Set minVersion to a seqr release like \"1.4\" or \"1.4.0\"; version is the schema version, minVersion the seqr version",
+			})
+		} else {
+			config.MinVersion = minVersion
+		}
+	}
+
+	// Extract optional command aliases, which commands refer to as @name
+	var aliases map[string][]string
+	if aliasesInterface, hasAliases := configMap["aliases"]; hasAliases {
+		var err error
+		if aliases, err = n.extractAliases(aliasesInterface); err != nil {
+			errors = append(errors, err)
+		}
+	}
+
+	// Extract the optional mode of commands that do not set one
+	defaultMode := ModeOnce
+	if defaultModeInterface, hasDefaultMode := configMap["defaultMode"]; hasDefaultMode {
+		modeStr, ok := defaultModeInterface.(string)
+		if !ok || (Mode(modeStr) != ModeOnce && Mode(modeStr) != ModeKeepAlive) {
+			errors = append(errors, ConfigNormalizationError{
+				Message:      fmt.Sprintf("invalid defaultMode value: %v", defaultModeInterface),
+				CommandIndex: -1,
+				Field:        "defaultMode",
+				Value:        defaultModeInterface,
+				Suggestion:   "defaultMode must be either \"once\" or \"keepAlive\"",
+			})
+		} else {
+			defaultMode = Mode(modeStr)
+			config.DefaultMode = defaultMode
+		}
+	}
+
 	// Extract commands
 	commandsInterface, hasCommands := configMap["commands"]
 	if !hasCommands {
@@ -321,18 +459,117 @@ func (n *Normalizer) NormalizeConfig(input interface{}) (*Config, error) {
 			config.Commands = make([]Command, len(commandsList))
 
 			for i, cmdInterface := range commandsList {
-				if err := n.normalizeConfigCommand(cmdInterface, i, &config.Commands[i]); err != nil {
+				if err := n.normalizeConfigCommand(cmdInterface, i, defaultMode, &config.Commands[i]); err != nil {
 					errors = append(errors, err)
+					continue
+				}
+				if len(aliases) > 0 {
+					name, _ := cmdInterface.(map[string]interface{})["name"].(string)
+					if err := n.expandAlias(&config.Commands[i], aliases, i, name == ""); err != nil {
+						errors = append(errors, err)
+					}
 				}
 			}
 		}
 	}
 
+	// Extract optional concurrency limit
+	if maxConcurrencyInterface, hasMaxConcurrency := configMap["maxConcurrency"]; hasMaxConcurrency {
+		maxConcurrency, ok := maxConcurrencyInterface.(float64)
+		if !ok || maxConcurrency != float64(int(maxConcurrency)) {
+			errors = append(errors, ConfigNormalizationError{
+				Message:      fmt.Sprintf("maxConcurrency must be a whole number, got %v", maxConcurrencyInterface),
+				CommandIndex: -1,
+				Field:        "maxConcurrency",
+				Value:        maxConcurrencyInterface,
+				Suggestion:   "Set the number of concurrent commands allowed at once: \"maxConcurrency\": 4",
+			})
+		} else {
+			config.MaxConcurrency = int(maxConcurrency)
+		}
+	}
+
+	// Extract optional process group opt-out for every command
+	if noProcessGroupInterface, hasNoProcessGroup := configMap["noProcessGroup"]; hasNoProcessGroup {
+		noProcessGroup, ok := noProcessGroupInterface.(bool)
+		if !ok {
+			errors = append(errors, ConfigNormalizationError{
+				Message:      fmt.Sprintf("noProcessGroup must be a boolean, got %T", noProcessGroupInterface),
+				CommandIndex: -1,
+				Field:        "noProcessGroup",
+				Value:        noProcessGroupInterface,
+				Suggestion:   "Set noProcessGroup to true or false",
+			})
+		} else {
+			config.NoProcessGroup = noProcessGroup
+		}
+	}
+
+	// Extract optional output prefixing for every command
+	if prefixOutputInterface, hasPrefixOutput := configMap["prefixOutput"]; hasPrefixOutput {
+		prefixOutput, ok := prefixOutputInterface.(bool)
+		if !ok {
+			errors = append(errors, ConfigNormalizationError{
+				Message:      fmt.Sprintf("prefixOutput must be a boolean, got %T", prefixOutputInterface),
+				CommandIndex: -1,
+				Field:        "prefixOutput",
+				Value:        prefixOutputInterface,
+				Suggestion:   "Set prefixOutput to true or false",
+			})
+		} else {
+			config.PrefixOutput = prefixOutput
+		}
+	}
+
+	// Extract the optional message printed when the run starts
+	if bannerInterface, hasBanner := configMap["banner"]; hasBanner {
+		banner, ok := bannerInterface.(string)
+		if !ok {
+			errors = append(errors, ConfigNormalizationError{
+				Message:      fmt.Sprintf("banner must be a string, got %T", bannerInterface),
+				CommandIndex: -1,
+				Field:        "banner",
+				Value:        bannerInterface,
+				Suggestion:   "Set banner to the message to print, like \"Starting staging deploy\"",
+			})
+		} else {
+			config.Banner = banner
+		}
+	}
+
+	// Extract the optional message printed when the run succeeds
+	if footerInterface, hasFooter := configMap["footer"]; hasFooter {
+		footer, ok := footerInterface.(string)
+		if !ok {
+			errors = append(errors, ConfigNormalizationError{
+				Message:      fmt.Sprintf("footer must be a string, got %T", footerInterface),
+				CommandIndex: -1,
+				Field:        "footer",
+				Value:        footerInterface,
+				Suggestion:   "Set footer to the message to print, like \"Staging is up\"",
+			})
+		} else {
+			config.Footer = footer
+		}
+	}
+
+	// Extract optional signal forwarding
+	if forwardingInterface, hasForwarding := configMap["signalForwarding"]; hasForwarding {
+		forwarding, err := n.extractSignalForwarding(forwardingInterface)
+		if err != nil {
+			errors = append(errors, err)
+		} else {
+			config.SignalForwarding = forwarding
+		}
+	}
+
 	// Return aggregated errors if any
 	if len(errors) > 0 {
 		return nil, n.aggregateConfigErrors(errors)
 	}
 
+	n.expandMatrices(config)
+
 	// Validate the entire config
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("normalized config validation failed: %w\nSuggestion: Review the validation errors above and ensure all commands have valid names, executables, and modes", err)
@@ -341,8 +578,9 @@ func (n *Normalizer) NormalizeConfig(input interface{}) (*Config, error) {
 	return config, nil
 }
 
-// normalizeConfigCommand handles normalization of a single command within the config
-func (n *Normalizer) normalizeConfigCommand(cmdInterface interface{}, index int, result *Command) error {
+// normalizeConfigCommand handles normalization of a single command within the config, giving it
+// defaultMode if it does not set a mode
+func (n *Normalizer) normalizeConfigCommand(cmdInterface interface{}, index int, defaultMode Mode, result *Command) error {
 	cmdMap, ok := cmdInterface.(map[string]interface{})
 	if !ok {
 		return ConfigNormalizationError{
@@ -359,7 +597,7 @@ func (n *Normalizer) normalizeConfigCommand(cmdInterface interface{}, index int,
 		return err
 	}
 
-	mode, err := n.extractModeField(cmdMap, "mode", index)
+	mode, err := n.extractModeField(cmdMap, "mode", index, name, defaultMode)
 	if err != nil {
 		return err
 	}
@@ -369,7 +607,7 @@ func (n *Normalizer) normalizeConfigCommand(cmdInterface interface{}, index int,
 		return err
 	}
 
-	env, err := n.extractEnvField(cmdMap, "env", index)
+	env, envFromFile, err := n.extractEnvField(cmdMap, "env", index)
 	if err != nil {
 		return err
 	}
@@ -379,6 +617,166 @@ func (n *Normalizer) normalizeConfigCommand(cmdInterface interface{}, index int,
 		return err
 	}
 
+	stopSignals, err := n.extractStopSignalsField(cmdMap, "stopSignals", index)
+	if err != nil {
+		return err
+	}
+
+	maxLifetime, err := n.extractDurationField(cmdMap, "maxLifetime", index)
+	if err != nil {
+		return err
+	}
+
+	cacheKey, err := n.extractStringListField(cmdMap, "cacheKey", index)
+	if err != nil {
+		return err
+	}
+
+	filter, err := n.extractFilterField(cmdMap, "filter", index)
+	if err != nil {
+		return err
+	}
+
+	noProcessGroup, err := n.extractBoolField(cmdMap, "noProcessGroup", index)
+	if err != nil {
+		return err
+	}
+
+	prefixOutput, err := n.extractBoolField(cmdMap, "prefixOutput", index)
+	if err != nil {
+		return err
+	}
+
+	stage, err := n.extractStringField(cmdMap, "stage", index, true)
+	if err != nil {
+		return err
+	}
+
+	pty, err := n.extractBoolField(cmdMap, "pty", index)
+	if err != nil {
+		return err
+	}
+
+	ports, err := n.extractPortsField(cmdMap, "ports", index)
+	if err != nil {
+		return err
+	}
+
+	argsFile, err := n.extractStringField(cmdMap, "argsFile", index, true)
+	if err != nil {
+		return err
+	}
+
+	readyFile, err := n.extractStringField(cmdMap, "readyFile", index, true)
+	if err != nil {
+		return err
+	}
+
+	readyTCP, err := n.extractStringField(cmdMap, "readyTCP", index, true)
+	if err != nil {
+		return err
+	}
+
+	readyTimeout, err := n.extractDurationField(cmdMap, "readyTimeout", index)
+	if err != nil {
+		return err
+	}
+
+	logMaxSize, err := n.extractIntField(cmdMap, "logMaxSize", index)
+	if err != nil {
+		return err
+	}
+
+	logMaxFiles, err := n.extractIntField(cmdMap, "logMaxFiles", index)
+	if err != nil {
+		return err
+	}
+
+	encoding, err := n.extractStringField(cmdMap, "encoding", index, true)
+	if err != nil {
+		return err
+	}
+
+	afterReady, err := n.extractStringListField(cmdMap, "afterReady", index)
+	if err != nil {
+		return err
+	}
+
+	expectOutput, expectOutputRegex, err := n.extractExpectOutputField(cmdMap, "expectOutput", index)
+	if err != nil {
+		return err
+	}
+
+	memoryLimit, err := n.extractIntField(cmdMap, "memoryLimit", index)
+	if err != nil {
+		return err
+	}
+
+	goldenFile, err := n.extractStringField(cmdMap, "goldenFile", index, true)
+	if err != nil {
+		return err
+	}
+
+	priority, err := n.extractIntField(cmdMap, "priority", index)
+	if err != nil {
+		return err
+	}
+
+	matrix, err := n.extractMatrixField(cmdMap, "matrix", index)
+	if err != nil {
+		return err
+	}
+
+	allowFailure, err := n.extractBoolField(cmdMap, "allowFailure", index)
+	if err != nil {
+		return err
+	}
+
+	dedupeOutput, err := n.extractBoolField(cmdMap, "dedupeOutput", index)
+	if err != nil {
+		return err
+	}
+
+	createWorkDir, err := n.extractBoolField(cmdMap, "createWorkDir", index)
+	if err != nil {
+		return err
+	}
+
+	warmup, err := n.extractBoolField(cmdMap, "warmup", index)
+	if err != nil {
+		return err
+	}
+
+	outputFifo, err := n.extractStringField(cmdMap, "outputFifo", index, true)
+	if err != nil {
+		return err
+	}
+
+	showLines, err := n.extractStringField(cmdMap, "showLines", index, true)
+	if err != nil {
+		return err
+	}
+
+	hideLines, err := n.extractStringField(cmdMap, "hideLines", index, true)
+	if err != nil {
+		return err
+	}
+
+	filterCapturedLines, err := n.extractBoolField(cmdMap, "filterCapturedLines", index)
+	if err != nil {
+		return err
+	}
+
+	meta, err := n.extractStringMapField(cmdMap, "meta", index)
+	if err != nil {
+		return err
+	}
+
+	externalReady, err := n.extractExternalReadyField(cmdMap, "externalReady", index)
+	if err != nil {
+		return err
+	}
+
 	// Extract and normalize the command field
 	commandField, hasCommand := cmdMap["command"]
 	if !hasCommand {
@@ -441,6 +839,41 @@ func (n *Normalizer) normalizeConfigCommand(cmdInterface interface{}, index int,
 		}
 	}
 
+	normalizedCmd.StopSignals = stopSignals
+	normalizedCmd.MaxLifetime = maxLifetime
+	normalizedCmd.CacheKey = cacheKey
+	normalizedCmd.Filter = filter
+	normalizedCmd.NoProcessGroup = noProcessGroup
+	normalizedCmd.PrefixOutput = prefixOutput
+	normalizedCmd.EnvFromFile = envFromFile
+	normalizedCmd.Stage = stage
+	normalizedCmd.PTY = pty
+	normalizedCmd.Ports = ports
+	normalizedCmd.ArgsFile = argsFile
+	normalizedCmd.ReadyFile = readyFile
+	normalizedCmd.ReadyTCP = readyTCP
+	normalizedCmd.ReadyTimeout = readyTimeout
+	normalizedCmd.LogMaxSize = int64(logMaxSize)
+	normalizedCmd.LogMaxFiles = logMaxFiles
+	normalizedCmd.Encoding = encoding
+	normalizedCmd.AfterReady = afterReady
+	normalizedCmd.ExpectOutput = expectOutput
+	normalizedCmd.ExpectOutputRegex = expectOutputRegex
+	normalizedCmd.MemoryLimit = int64(memoryLimit)
+	normalizedCmd.GoldenFile = goldenFile
+	normalizedCmd.Priority = priority
+	normalizedCmd.Matrix = matrix
+	normalizedCmd.AllowFailure = allowFailure
+	normalizedCmd.DedupeOutput = dedupeOutput
+	normalizedCmd.CreateWorkDir = createWorkDir
+	normalizedCmd.Warmup = warmup
+	normalizedCmd.OutputFifo = outputFifo
+	normalizedCmd.ShowLines = showLines
+	normalizedCmd.HideLines = hideLines
+	normalizedCmd.FilterCapturedLines = filterCapturedLines
+	normalizedCmd.Meta = meta
+	normalizedCmd.ExternalReady = externalReady
+
 	*result = *normalizedCmd
 	return nil
 }
@@ -462,13 +895,13 @@ func (n *Normalizer) extractStringField(cmdMap map[string]interface{}, fieldName
 	return "", nil
 }
 
-func (n *Normalizer) extractModeField(cmdMap map[string]interface{}, fieldName string, index int) (Mode, error) {
+func (n *Normalizer) extractModeField(cmdMap map[string]interface{}, fieldName string, index int, commandName string, defaultMode Mode) (Mode, error) {
 	if modeInterface, hasMode := cmdMap[fieldName]; hasMode {
 		if modeStr, ok := modeInterface.(string); ok {
 			mode := Mode(modeStr)
 			if mode != ModeOnce && mode != ModeKeepAlive {
 				return "", ConfigNormalizationError{
-					Message:      fmt.Sprintf("invalid mode value: %s", modeStr),
+					Message:      fmt.Sprintf("invalid mode value: %s in command '%s'", modeStr, commandName),
 					CommandIndex: index,
 					Field:        fieldName,
 					Value:        modeInterface,
@@ -485,29 +918,50 @@ func (n *Normalizer) extractModeField(cmdMap map[string]interface{}, fieldName s
 			Suggestion:   "Set mode to \"once\" or \"keepAlive\"",
 		}
 	}
-	return ModeOnce, nil
+	return defaultMode, nil
 }
 
-func (n *Normalizer) extractEnvField(cmdMap map[string]interface{}, fieldName string, index int) (map[string]string, error) {
+// extractEnvField returns the literal env values, and separately the ones given as
+// {"fromFile": "path"} objects, which are read from the file when the command starts
+func (n *Normalizer) extractEnvField(cmdMap map[string]interface{}, fieldName string, index int) (map[string]string, map[string]string, error) {
 	if envInterface, hasEnv := cmdMap[fieldName]; hasEnv {
 		if envMap, ok := envInterface.(map[string]interface{}); ok {
 			env := make(map[string]string)
+			var envFromFile map[string]string
 			for key, value := range envMap {
 				if valueStr, ok := value.(string); ok {
 					env[key] = valueStr
-				} else {
-					return nil, ConfigNormalizationError{
-						Message:      fmt.Sprintf("env value for key '%s' must be a string, got %T", key, value),
-						CommandIndex: index,
-						Field:        fmt.Sprintf("env.%s", key),
-						Value:        value,
-						Suggestion:   "All environment variable values must be strings",
+					continue
+				}
+
+				if path, ok := extractFromFile(value); ok {
+					if envFromFile == nil {
+						envFromFile = make(map[string]string)
 					}
+					envFromFile[key] = path
+					continue
+				}
+
+				if valueMap, ok := value.(map[string]interface{}); ok && len(valueMap) > 0 && valueMap["fromFile"] == nil {
+					valueStr, err := n.resolveOSValue(valueMap, key, index)
+					if err != nil {
+						return nil, nil, err
+					}
+					env[key] = valueStr
+					continue
+				}
+
+				return nil, nil, ConfigNormalizationError{
+					Message:      fmt.Sprintf("env value for key '%s' must be a string or a {\"fromFile\": \"path\"} object, got %T", key, value),
+					CommandIndex: index,
+					Field:        fmt.Sprintf("env.%s", key),
+					Value:        value,
+					Suggestion:   "Use a string value, or read the value from a file: \"env\": {\"API_TOKEN\": {\"fromFile\": \"/run/secrets/token\"}}",
 				}
 			}
-			return env, nil
+			return env, envFromFile, nil
 		}
-		return nil, ConfigNormalizationError{
+		return nil, nil, ConfigNormalizationError{
 			Message:      fmt.Sprintf("env must be an object, got %T", envInterface),
 			CommandIndex: index,
 			Field:        fieldName,
@@ -515,7 +969,91 @@ func (n *Normalizer) extractEnvField(cmdMap map[string]interface{}, fieldName st
 			Suggestion:   "Environment variables should be an object: \"env\": {\"KEY\": \"value\"}",
 		}
 	}
-	return nil, nil
+	return nil, nil, nil
+}
+
+// resolveOSValue picks the value for the normalizer's OS from an env value keyed by OS, such as
+// {"linux": ":", "windows": ";"}, falling back to its "default" entry
+func (n *Normalizer) resolveOSValue(valueMap map[string]interface{}, key string, index int) (string, error) {
+	field := fmt.Sprintf("env.%s", key)
+	for osKey, osValue := range valueMap {
+		if !slices.Contains(envOSKeys, osKey) {
+			return "", ConfigNormalizationError{
+				Message:      fmt.Sprintf("env value for key '%s' has unknown OS '%s'", key, osKey),
+				CommandIndex: index,
+				Field:        field,
+				Value:        valueMap,
+				Suggestion:   fmt.Sprintf("Key values by OS, as runtime.GOOS names it: %s", strings.Join(envOSKeys, ", ")),
+			}
+		}
+		if _, ok := osValue.(string); !ok {
+			return "", ConfigNormalizationError{
+				Message:      fmt.Sprintf("env value for key '%s' on %s must be a string, got %T", key, osKey, osValue),
+				CommandIndex: index,
+				Field:        fmt.Sprintf("%s.%s", field, osKey),
+				Value:        osValue,
+				Suggestion:   "Give each OS a string value: {\"linux\": \":\", \"windows\": \";\"}",
+			}
+		}
+	}
+
+	goos := n.GOOS
+	if goos == "" {
+		goos = runtime.GOOS
+	}
+	if value, ok := valueMap[goos].(string); ok {
+		return value, nil
+	}
+	if value, ok := valueMap["default"].(string); ok {
+		return value, nil
+	}
+
+	return "", ConfigNormalizationError{
+		Message:      fmt.Sprintf("env value for key '%s' has no entry for %s and no default", key, goos),
+		CommandIndex: index,
+		Field:        field,
+		Value:        valueMap,
+		Suggestion:   fmt.Sprintf("Add a \"%s\" or \"default\" entry", goos),
+	}
+}
+
+// extractFromFile returns the path of a {"fromFile": "path"} env value, which must have no other keys
+func extractFromFile(value interface{}) (string, bool) {
+	valueMap, ok := value.(map[string]interface{})
+	if !ok || len(valueMap) != 1 {
+		return "", false
+	}
+	path, ok := valueMap["fromFile"].(string)
+	if !ok || path == "" {
+		return "", false
+	}
+	return path, true
+}
+
+// extractExpectOutputField extracts the output a command is expected to produce: a substring, or a
+// {"regex": "pattern"} object, reported by the returned bool
+func (n *Normalizer) extractExpectOutputField(cmdMap map[string]interface{}, fieldName string, index int) (string, bool, error) {
+	fieldInterface, hasField := cmdMap[fieldName]
+	if !hasField {
+		return "", false, nil
+	}
+
+	if substring, ok := fieldInterface.(string); ok {
+		return substring, false, nil
+	}
+	if fieldMap, ok := fieldInterface.(map[string]interface{}); ok && len(fieldMap) == 1 {
+		if pattern, ok := fieldMap["regex"].(string); ok && pattern != "" {
+			return pattern, true, nil
+		}
+	}
+
+	return "", false, ConfigNormalizationError{
+		Message:      fmt.Sprintf("%s must be a string or a {\"regex\": \"pattern\"} object, got %v", fieldName, fieldInterface),
+		CommandIndex: index,
+		Field:        fieldName,
+		Value:        fieldInterface,
+		Suggestion:   fmt.Sprintf("Expect a substring, \"%s\": \"ok\", or a pattern, \"%s\": {\"regex\": \"^v[0-9]+\"}", fieldName, fieldName),
+	}
 }
 
 func (n *Normalizer) extractArgsField(argsInterface interface{}, index int) ([]string, error) {
@@ -561,6 +1099,486 @@ func (n *Normalizer) extractBoolField(cmdMap map[string]interface{}, fieldName s
 	return false, nil
 }
 
+// extractStringListField parses a field holding either a single string or an array of strings
+func (n *Normalizer) extractStringListField(cmdMap map[string]interface{}, fieldName string, index int) ([]string, error) {
+	fieldInterface, hasField := cmdMap[fieldName]
+	if !hasField {
+		return nil, nil
+	}
+
+	if fieldStr, ok := fieldInterface.(string); ok {
+		return []string{fieldStr}, nil
+	}
+
+	fieldList, ok := fieldInterface.([]interface{})
+	if !ok {
+		return nil, ConfigNormalizationError{
+			Message:      fmt.Sprintf("%s must be a string or an array of strings, got %T", fieldName, fieldInterface),
+			CommandIndex: index,
+			Field:        fieldName,
+			Value:        fieldInterface,
+			Suggestion:   fmt.Sprintf("List entries in an array: \"%s\": [\"src/*.go\", \"go.mod\"]", fieldName),
+		}
+	}
+
+	values := make([]string, len(fieldList))
+	for i, item := range fieldList {
+		itemStr, ok := item.(string)
+		if !ok {
+			return nil, ConfigNormalizationError{
+				Message:      fmt.Sprintf("%s element %d must be a string, got %T", fieldName, i, item),
+				CommandIndex: index,
+				Field:        fmt.Sprintf("%s[%d]", fieldName, i),
+				Value:        item,
+				Suggestion:   fmt.Sprintf("All %s entries must be strings", fieldName),
+			}
+		}
+		values[i] = itemStr
+	}
+	return values, nil
+}
+
+// extractStringMapField extracts an optional object whose values must all be strings
+func (n *Normalizer) extractStringMapField(cmdMap map[string]interface{}, fieldName string, index int) (map[string]string, error) {
+	fieldInterface, hasField := cmdMap[fieldName]
+	if !hasField {
+		return nil, nil
+	}
+
+	fieldMap, ok := fieldInterface.(map[string]interface{})
+	if !ok {
+		return nil, ConfigNormalizationError{
+			Message:      fmt.Sprintf("%s must be an object, got %T", fieldName, fieldInterface),
+			CommandIndex: index,
+			Field:        fieldName,
+			Value:        fieldInterface,
+			Suggestion:   fmt.Sprintf("Map keys to string values: \"%s\": {\"owner\": \"platform-team\"}", fieldName),
+		}
+	}
+
+	values := make(map[string]string, len(fieldMap))
+	for _, key := range slices.Sorted(maps.Keys(fieldMap)) {
+		value, ok := fieldMap[key].(string)
+		if !ok {
+			return nil, ConfigNormalizationError{
+				Message:      fmt.Sprintf("%s value for '%s' must be a string, got %T", fieldName, key, fieldMap[key]),
+				CommandIndex: index,
+				Field:        fmt.Sprintf("%s.%s", fieldName, key),
+				Value:        fieldMap[key],
+				Suggestion:   fmt.Sprintf("Quote %s values, e.g. \"%s\": \"%v\"", fieldName, key, fieldMap[key]),
+			}
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// extractMatrixField extracts an optional list of matrix values, each of which must be
+// non-empty since it becomes part of a command name
+func (n *Normalizer) extractMatrixField(cmdMap map[string]interface{}, fieldName string, index int) ([]string, error) {
+	values, err := n.extractStringListField(cmdMap, fieldName, index)
+	if err != nil || values == nil {
+		return values, err
+	}
+
+	if len(values) == 0 {
+		return nil, ConfigNormalizationError{
+			Message:      fmt.Sprintf("%s must have at least one value", fieldName),
+			CommandIndex: index,
+			Field:        fieldName,
+			Value:        values,
+			Suggestion:   fmt.Sprintf("List the values to run the command with: \"%s\": [\"18\", \"20\", \"22\"]", fieldName),
+		}
+	}
+	for i, value := range values {
+		if value == "" {
+			return nil, ConfigNormalizationError{
+				Message:      fmt.Sprintf("%s element %d cannot be empty", fieldName, i),
+				CommandIndex: index,
+				Field:        fmt.Sprintf("%s[%d]", fieldName, i),
+				Value:        value,
+				Suggestion:   "Each value names a command instance, so give it a non-empty value",
+			}
+		}
+	}
+	return values, nil
+}
+
+// expandMatrices replaces each command with a matrix by one concurrent instance per value,
+// named name-<value>, with ${MATRIX_VALUE} replaced by the value. afterReady and signalForwarding
+// references to the matrix command's own name are replaced by the names of all its instances.
+func (n *Normalizer) expandMatrices(config *Config) {
+	expanded := make([]Command, 0, len(config.Commands))
+	instanceNames := make(map[string][]string)
+	for _, cmd := range config.Commands {
+		if len(cmd.Matrix) == 0 {
+			expanded = append(expanded, cmd)
+			continue
+		}
+
+		for _, value := range cmd.Matrix {
+			instance := cmd
+			instance.Name = cmd.Name + "-" + value
+			instance.Concurrent = true
+			instance.Matrix = nil
+			instance.MatrixOf = cmd.Name
+			instanceNames[cmd.Name] = append(instanceNames[cmd.Name], instance.Name)
+
+			// Instances share nothing that expanding variables changes in place
+			instance.Args = slices.Clone(cmd.Args)
+			instance.Env = maps.Clone(cmd.Env)
+			instance.EnvFromFile = maps.Clone(cmd.EnvFromFile)
+			instance.Filter = slices.Clone(cmd.Filter)
+			if cmd.ExternalReady != nil {
+				external := *cmd.ExternalReady
+				external.Command = slices.Clone(external.Command)
+				instance.ExternalReady = &external
+			}
+
+			instance.expandVariables(func(name string) (string, bool) {
+				return value, name == "MATRIX_VALUE"
+			})
+			expanded = append(expanded, instance)
+		}
+	}
+	config.Commands = expanded
+
+	if len(instanceNames) == 0 {
+		return
+	}
+	for i := range config.Commands {
+		config.Commands[i].AfterReady = expandMatrixReferences(config.Commands[i].AfterReady, instanceNames)
+	}
+	for signal, names := range config.SignalForwarding {
+		config.SignalForwarding[signal] = expandMatrixReferences(names, instanceNames)
+	}
+}
+
+// expandMatrixReferences replaces each name of a matrix command in names by the names of its
+// instances
+func expandMatrixReferences(names []string, instanceNames map[string][]string) []string {
+	if !slices.ContainsFunc(names, func(name string) bool { return instanceNames[name] != nil }) {
+		return names
+	}
+
+	expanded := make([]string, 0, len(names))
+	for _, name := range names {
+		if instances, isMatrix := instanceNames[name]; isMatrix {
+			expanded = append(expanded, instances...)
+		} else {
+			expanded = append(expanded, name)
+		}
+	}
+	return expanded
+}
+
+// extractIntField extracts an optional whole-number field, zero when absent
+func (n *Normalizer) extractIntField(cmdMap map[string]interface{}, fieldName string, index int) (int, error) {
+	fieldInterface, hasField := cmdMap[fieldName]
+	if !hasField {
+		return 0, nil
+	}
+
+	value, ok := fieldInterface.(float64)
+	if !ok || value != float64(int(value)) {
+		return 0, ConfigNormalizationError{
+			Message:      fmt.Sprintf("%s must be a whole number, got %v", fieldName, fieldInterface),
+			CommandIndex: index,
+			Field:        fieldName,
+			Value:        fieldInterface,
+			Suggestion:   fmt.Sprintf("Use a number without quotes: \"%s\": 10", fieldName),
+		}
+	}
+	return int(value), nil
+}
+
+// extractPortsField parses an array of port numbers
+func (n *Normalizer) extractPortsField(cmdMap map[string]interface{}, fieldName string, index int) ([]int, error) {
+	fieldInterface, hasField := cmdMap[fieldName]
+	if !hasField {
+		return nil, nil
+	}
+
+	fieldList, ok := fieldInterface.([]interface{})
+	if !ok {
+		return nil, ConfigNormalizationError{
+			Message:      fmt.Sprintf("%s must be an array of port numbers, got %T", fieldName, fieldInterface),
+			CommandIndex: index,
+			Field:        fieldName,
+			Value:        fieldInterface,
+			Suggestion:   fmt.Sprintf("List the ports the command binds: \"%s\": [3000, 9229]", fieldName),
+		}
+	}
+
+	ports := make([]int, len(fieldList))
+	for i, item := range fieldList {
+		port, ok := item.(float64)
+		if !ok || port != float64(int(port)) {
+			return nil, ConfigNormalizationError{
+				Message:      fmt.Sprintf("%s element %d must be a whole number, got %v", fieldName, i, item),
+				CommandIndex: index,
+				Field:        fmt.Sprintf("%s[%d]", fieldName, i),
+				Value:        item,
+				Suggestion:   fmt.Sprintf("All %s entries must be port numbers", fieldName),
+			}
+		}
+		ports[i] = int(port)
+	}
+	return ports, nil
+}
+
+// extractFilterField parses a filter command given as a string, split like a string command, or
+// as an array of the executable and its arguments
+func (n *Normalizer) extractFilterField(cmdMap map[string]interface{}, fieldName string, index int) ([]string, error) {
+	fieldInterface, hasField := cmdMap[fieldName]
+	if !hasField {
+		return nil, nil
+	}
+
+	var filter Command
+	var err error
+	switch value := fieldInterface.(type) {
+	case string:
+		err = n.normalizeStringCommand(value, &filter)
+	case []interface{}:
+		err = n.normalizeArrayCommand(value, &filter)
+	default:
+		err = fmt.Errorf("must be a string or an array of strings, got %T", fieldInterface)
+	}
+	if err != nil {
+		return nil, ConfigNormalizationError{
+			Message:      fmt.Sprintf("invalid %s: %v", fieldName, err),
+			CommandIndex: index,
+			Field:        fieldName,
+			Value:        fieldInterface,
+			Suggestion:   fmt.Sprintf("Give the filter command as a string or an array: \"%s\": \"grep -v DEBUG\"", fieldName),
+		}
+	}
+
+	return append([]string{filter.Command}, filter.Args...), nil
+}
+
+// extractExternalReadyField parses an externalReady block: a readyTCP address, a readyFile, a
+// check command given like a filter, and a timeout duration string
+func (n *Normalizer) extractExternalReadyField(cmdMap map[string]interface{}, fieldName string, index int) (*ExternalReady, error) {
+	fieldInterface, hasField := cmdMap[fieldName]
+	if !hasField {
+		return nil, nil
+	}
+
+	block, ok := fieldInterface.(map[string]interface{})
+	if !ok {
+		return nil, ConfigNormalizationError{
+			Message:      fmt.Sprintf("%s must be an object, got %T", fieldName, fieldInterface),
+			CommandIndex: index,
+			Field:        fieldName,
+			Value:        fieldInterface,
+			Suggestion:   fmt.Sprintf("Describe how to tell the service is ready: \"%s\": {\"readyTCP\": \"db.internal:5432\"}", fieldName),
+		}
+	}
+
+	var external ExternalReady
+	for _, key := range slices.Sorted(maps.Keys(block)) {
+		value := block[key]
+		var err error
+		switch key {
+		case "readyTCP", "readyFile":
+			str, ok := value.(string)
+			if !ok {
+				err = fmt.Errorf("must be a string, got %T", value)
+			} else if key == "readyTCP" {
+				external.ReadyTCP = str
+			} else {
+				external.ReadyFile = str
+			}
+		case "command":
+			var check Command
+			switch command := value.(type) {
+			case string:
+				err = n.normalizeStringCommand(command, &check)
+			case []interface{}:
+				err = n.normalizeArrayCommand(command, &check)
+			default:
+				err = fmt.Errorf("must be a string or an array of strings, got %T", value)
+			}
+			if err == nil {
+				external.Command = append([]string{check.Command}, check.Args...)
+			}
+		case "timeout":
+			str, ok := value.(string)
+			if !ok {
+				err = fmt.Errorf("must be a duration string, got %T", value)
+			} else {
+				external.Timeout, err = time.ParseDuration(str)
+			}
+		default:
+			err = fmt.Errorf("is not a known field, use readyTCP, readyFile, command or timeout")
+		}
+		if err != nil {
+			return nil, ConfigNormalizationError{
+				Message:      fmt.Sprintf("invalid %s.%s: %v", fieldName, key, err),
+				CommandIndex: index,
+				Field:        fieldName + "." + key,
+				Value:        value,
+				Suggestion:   fmt.Sprintf("For example: \"%s\": {\"readyTCP\": \"db.internal:5432\", \"timeout\": \"1m\"}", fieldName),
+			}
+		}
+	}
+
+	return &external, nil
+}
+
+// extractDurationField parses a duration string such as "30s" or "5m"
+func (n *Normalizer) extractDurationField(cmdMap map[string]interface{}, fieldName string, index int) (time.Duration, error) {
+	fieldInterface, hasField := cmdMap[fieldName]
+	if !hasField {
+		return 0, nil
+	}
+
+	durationStr, ok := fieldInterface.(string)
+	if !ok {
+		return 0, ConfigNormalizationError{
+			Message:      fmt.Sprintf("%s must be a duration string, got %T", fieldName, fieldInterface),
+			CommandIndex: index,
+			Field:        fieldName,
+			Value:        fieldInterface,
+			Suggestion:   fmt.Sprintf("Use a duration string: \"%s\": \"30s\"", fieldName),
+		}
+	}
+
+	duration, err := time.ParseDuration(durationStr)
+	if err != nil {
+		return 0, ConfigNormalizationError{
+			Message:      fmt.Sprintf("%s has invalid duration: %v", fieldName, err),
+			CommandIndex: index,
+			Field:        fieldName,
+			Value:        fieldInterface,
+			Suggestion:   "Durations combine a number and a unit, such as \"500ms\", \"30s\" or \"5m\"",
+		}
+	}
+
+	return duration, nil
+}
+
+func (n *Normalizer) extractSignalForwarding(forwardingInterface interface{}) (map[string][]string, error) {
+	forwardingMap, ok := forwardingInterface.(map[string]interface{})
+	if !ok {
+		return nil, ConfigNormalizationError{
+			Message:      fmt.Sprintf("signalForwarding must be an object, got %T", forwardingInterface),
+			CommandIndex: -1,
+			Field:        "signalForwarding",
+			Value:        forwardingInterface,
+			Suggestion:   "Map signal names to command names: \"signalForwarding\": {\"SIGUSR1\": [\"api\"]}",
+		}
+	}
+
+	forwarding := make(map[string][]string)
+	for signalName, targetsInterface := range forwardingMap {
+		targetsList, ok := targetsInterface.([]interface{})
+		if !ok {
+			return nil, ConfigNormalizationError{
+				Message:      fmt.Sprintf("signalForwarding targets for '%s' must be an array, got %T", signalName, targetsInterface),
+				CommandIndex: -1,
+				Field:        fmt.Sprintf("signalForwarding.%s", signalName),
+				Value:        targetsInterface,
+				Suggestion:   "List target command names in an array: [\"api\", \"worker\"]",
+			}
+		}
+
+		targets := make([]string, len(targetsList))
+		for i, target := range targetsList {
+			targetStr, ok := target.(string)
+			if !ok {
+				return nil, ConfigNormalizationError{
+					Message:      fmt.Sprintf("signalForwarding target %d for '%s' must be a string, got %T", i, signalName, target),
+					CommandIndex: -1,
+					Field:        fmt.Sprintf("signalForwarding.%s[%d]", signalName, i),
+					Value:        target,
+					Suggestion:   "Target entries must be command names",
+				}
+			}
+			targets[i] = targetStr
+		}
+		forwarding[signalName] = targets
+	}
+
+	return forwarding, nil
+}
+
+// extractStopSignalsField parses a stopSignals escalation, where each step is either a signal
+// name or an object with "signal" and an optional "timeout" duration string
+func (n *Normalizer) extractStopSignalsField(cmdMap map[string]interface{}, fieldName string, index int) ([]StopSignal, error) {
+	stepsInterface, hasSteps := cmdMap[fieldName]
+	if !hasSteps {
+		return nil, nil
+	}
+
+	stepsList, ok := stepsInterface.([]interface{})
+	if !ok {
+		return nil, ConfigNormalizationError{
+			Message:      fmt.Sprintf("stopSignals must be an array, got %T", stepsInterface),
+			CommandIndex: index,
+			Field:        fieldName,
+			Value:        stepsInterface,
+			Suggestion:   "List signals in escalation order: \"stopSignals\": [\"SIGTERM\", {\"signal\": \"SIGINT\", \"timeout\": \"2s\"}]",
+		}
+	}
+
+	steps := make([]StopSignal, len(stepsList))
+	for i, stepInterface := range stepsList {
+		switch step := stepInterface.(type) {
+		case string:
+			steps[i] = StopSignal{Signal: step}
+		case map[string]interface{}:
+			signalName, ok := step["signal"].(string)
+			if !ok {
+				return nil, ConfigNormalizationError{
+					Message:      fmt.Sprintf("stopSignals element %d must have a string 'signal' field", i),
+					CommandIndex: index,
+					Field:        fmt.Sprintf("%s[%d].signal", fieldName, i),
+					Value:        step["signal"],
+					Suggestion:   "Name the signal to send: {\"signal\": \"SIGINT\", \"timeout\": \"2s\"}",
+				}
+			}
+			steps[i] = StopSignal{Signal: signalName}
+
+			if timeoutInterface, hasTimeout := step["timeout"]; hasTimeout {
+				timeoutStr, ok := timeoutInterface.(string)
+				if !ok {
+					return nil, ConfigNormalizationError{
+						Message:      fmt.Sprintf("stopSignals element %d timeout must be a string, got %T", i, timeoutInterface),
+						CommandIndex: index,
+						Field:        fmt.Sprintf("%s[%d].timeout", fieldName, i),
+						Value:        timeoutInterface,
+						Suggestion:   "Use a duration string such as \"500ms\" or \"2s\"",
+					}
+				}
+				timeout, err := time.ParseDuration(timeoutStr)
+				if err != nil {
+					return nil, ConfigNormalizationError{
+						Message:      fmt.Sprintf("stopSignals element %d has invalid timeout: %v", i, err),
+						CommandIndex: index,
+						Field:        fmt.Sprintf("%s[%d].timeout", fieldName, i),
+						Value:        timeoutInterface,
+						Suggestion:   "Use a duration string such as \"500ms\" or \"2s\"",
+					}
+				}
+				steps[i].Timeout = timeout
+			}
+		default:
+			return nil, ConfigNormalizationError{
+				Message:      fmt.Sprintf("stopSignals element %d must be a string or object, got %T", i, stepInterface),
+				CommandIndex: index,
+				Field:        fmt.Sprintf("%s[%d]", fieldName, i),
+				Value:        stepInterface,
+				Suggestion:   "Use a signal name or {\"signal\": \"SIGINT\", \"timeout\": \"2s\"}",
+			}
+		}
+	}
+
+	return steps, nil
+}
+
 // aggregateConfigErrors combines multiple configuration errors into a single comprehensive error
 func (n *Normalizer) aggregateConfigErrors(errors []error) error {
 	if len(errors) == 1 {
@@ -1,8 +1,10 @@
 package config
 
 import (
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestNormalizer_NormalizeCommand(t *testing.T) {
@@ -358,272 +360,1191 @@ func TestNormalizer_NormalizeConfig(t *testing.T) {
 			},
 		},
 		{
-			name: "auto-generated command names",
+			name: "config with env values read from files",
 			input: map[string]interface{}{
 				"version": "1.0",
 				"commands": []interface{}{
 					map[string]interface{}{
-						"command": "npm start",
-					},
-					map[string]interface{}{
-						"command": "ls",
+						"command": "deploy",
+						"env": map[string]interface{}{
+							"STAGE":     "prod",
+							"API_TOKEN": map[string]interface{}{"fromFile": "/run/secrets/token"},
+						},
 					},
 				},
 			},
 			wantErr: false,
 			validate: func(t *testing.T, config *Config) {
-				if config.Commands[0].Name != "npm-start" {
-					t.Errorf("Expected auto-generated name 'npm-start', got '%s'", config.Commands[0].Name)
+				cmd := config.Commands[0]
+				if cmd.Env["STAGE"] != "prod" {
+					t.Errorf("Expected STAGE='prod', got '%s'", cmd.Env["STAGE"])
 				}
-				if config.Commands[1].Name != "ls" {
-					t.Errorf("Expected auto-generated name 'ls', got '%s'", config.Commands[1].Name)
+				if _, ok := cmd.Env["API_TOKEN"]; ok {
+					t.Errorf("Expected API_TOKEN to be read from its file, got literal value '%s'", cmd.Env["API_TOKEN"])
+				}
+				if cmd.EnvFromFile["API_TOKEN"] != "/run/secrets/token" {
+					t.Errorf("Expected API_TOKEN from '/run/secrets/token', got %v", cmd.EnvFromFile)
 				}
 			},
 		},
 		{
-			name:        "nil input",
-			input:       nil,
-			wantErr:     true,
-			errorSubstr: "config input cannot be nil",
+			name: "config with output filters",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"command": "make test", "filter": "grep -v 'DEBUG: '"},
+					map[string]interface{}{"command": "make lint", "filter": []interface{}{"tr", "a-z", "A-Z"}},
+				},
+			},
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				if got := config.Commands[0].Filter; !reflect.DeepEqual(got, []string{"grep", "-v", "DEBUG: "}) {
+					t.Errorf("Expected string filter to be split like a command, got %q", got)
+				}
+				if got := config.Commands[1].Filter; !reflect.DeepEqual(got, []string{"tr", "a-z", "A-Z"}) {
+					t.Errorf("Expected array filter to be kept, got %q", got)
+				}
+			},
 		},
 		{
-			name:        "non-object input",
-			input:       "invalid",
-			wantErr:     true,
-			errorSubstr: "config must be an object",
+			name: "config with process groups disabled",
+			input: map[string]interface{}{
+				"version":        "1.0",
+				"noProcessGroup": true,
+				"commands": []interface{}{
+					map[string]interface{}{"command": "make test", "noProcessGroup": true},
+					map[string]interface{}{"command": "make lint"},
+				},
+			},
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				if !config.NoProcessGroup {
+					t.Error("Expected noProcessGroup to be set for the whole config")
+				}
+				if !config.Commands[0].NoProcessGroup || config.Commands[1].NoProcessGroup {
+					t.Errorf("Expected noProcessGroup only on the first command, got %v and %v", config.Commands[0].NoProcessGroup, config.Commands[1].NoProcessGroup)
+				}
+			},
 		},
 		{
-			name: "missing version",
+			name: "config with output prefixed",
 			input: map[string]interface{}{
-				"commands": []interface{}{},
+				"version":      "1.0",
+				"prefixOutput": true,
+				"commands": []interface{}{
+					map[string]interface{}{"command": "make test", "prefixOutput": true},
+					map[string]interface{}{"command": "make lint"},
+				},
+			},
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				if !config.PrefixOutput {
+					t.Error("Expected prefixOutput to be set for the whole config")
+				}
+				if !config.Commands[0].PrefixOutput || config.Commands[1].PrefixOutput {
+					t.Errorf("Expected prefixOutput only on the first command, got %v and %v", config.Commands[0].PrefixOutput, config.Commands[1].PrefixOutput)
+				}
 			},
-			wantErr:     true,
-			errorSubstr: "config must have a 'version' field",
 		},
 		{
-			name: "non-string version",
+			name: "config with command stages",
 			input: map[string]interface{}{
-				"version":  123,
-				"commands": []interface{}{},
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"command": "make build", "stage": "build"},
+					map[string]interface{}{"command": "make test"},
+				},
+			},
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				if config.Commands[0].Stage != "build" || config.Commands[1].Stage != "" {
+					t.Errorf("Expected stage only on the first command, got %q and %q", config.Commands[0].Stage, config.Commands[1].Stage)
+				}
 			},
-			wantErr:     true,
-			errorSubstr: "version must be a string",
 		},
 		{
-			name: "missing commands",
+			name: "config with declared ports",
 			input: map[string]interface{}{
 				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"command": "npm start", "ports": []interface{}{float64(3000), float64(9229)}},
+				},
+			},
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				if got := config.Commands[0].Ports; !reflect.DeepEqual(got, []int{3000, 9229}) {
+					t.Errorf("Expected ports [3000 9229], got %v", got)
+				}
 			},
-			wantErr:     true,
-			errorSubstr: "config must have a 'commands' field",
 		},
 		{
-			name: "non-array commands",
+			name: "config with an args file",
 			input: map[string]interface{}{
-				"version":  "1.0",
-				"commands": "invalid",
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"command": "eslint", "args": []interface{}{"--fix"}, "argsFile": "files.txt"},
+				},
+			},
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				if got := config.Commands[0].ArgsFile; got != "files.txt" {
+					t.Errorf("Expected argsFile 'files.txt', got %q", got)
+				}
+			},
+		},
+		{
+			name: "config with a ready file",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"command": "npm start", "mode": "keepAlive", "readyFile": "tmp/ready", "readyTimeout": "10s"},
+				},
+			},
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				cmd := config.Commands[0]
+				if cmd.ReadyFile != "tmp/ready" || cmd.ReadyTimeout != 10*time.Second {
+					t.Errorf("Expected readyFile 'tmp/ready' within 10s, got %q within %v", cmd.ReadyFile, cmd.ReadyTimeout)
+				}
+			},
+		},
+		{
+			name: "config with a ready TCP address",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"command": "npm start", "mode": "keepAlive", "readyTCP": "localhost:3000", "readyTimeout": "1m"},
+				},
+			},
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				cmd := config.Commands[0]
+				if cmd.ReadyTCP != "localhost:3000" || cmd.ReadyTimeout != time.Minute {
+					t.Errorf("Expected readyTCP 'localhost:3000' within 1m, got %q within %v", cmd.ReadyTCP, cmd.ReadyTimeout)
+				}
+			},
+		},
+		{
+			name: "config with log rotation",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"command": "npm start", "mode": "keepAlive", "logMaxSize": float64(10485760), "logMaxFiles": float64(3)},
+				},
+			},
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				cmd := config.Commands[0]
+				if cmd.LogMaxSize != 10485760 || cmd.LogMaxFiles != 3 {
+					t.Errorf("Expected logs rotated at 10485760 bytes keeping 3, got %d keeping %d", cmd.LogMaxSize, cmd.LogMaxFiles)
+				}
+			},
+		},
+		{
+			name: "config with an output encoding",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"command": "tool.exe", "encoding": "utf-16le"},
+				},
+			},
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				if got := config.Commands[0].Encoding; got != "utf-16le" {
+					t.Errorf("Expected encoding 'utf-16le', got %q", got)
+				}
+			},
+		},
+		{
+			name: "config with afterReady dependencies",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"name": "db", "command": "postgres", "mode": "keepAlive"},
+					map[string]interface{}{"name": "migrate", "command": "make migrate", "afterReady": []interface{}{"db"}},
+				},
+			},
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				if got := config.Commands[1].AfterReady; len(got) != 1 || got[0] != "db" {
+					t.Errorf("Expected afterReady [db], got %v", got)
+				}
+			},
+		},
+		{
+			name: "config with expected output",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"command": "curl localhost/health", "expectOutput": "ok"},
+					map[string]interface{}{"command": "app --version", "expectOutput": map[string]interface{}{"regex": "^v[0-9]+"}},
+				},
+			},
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				if cmd := config.Commands[0]; cmd.ExpectOutput != "ok" || cmd.ExpectOutputRegex {
+					t.Errorf("Expected substring 'ok', got %q (regex %v)", cmd.ExpectOutput, cmd.ExpectOutputRegex)
+				}
+				if cmd := config.Commands[1]; cmd.ExpectOutput != "^v[0-9]+" || !cmd.ExpectOutputRegex {
+					t.Errorf("Expected pattern '^v[0-9]+', got %q (regex %v)", cmd.ExpectOutput, cmd.ExpectOutputRegex)
+				}
+			},
+		},
+		{
+			name: "config with invalid expected output",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"command": "app --version", "expectOutput": map[string]interface{}{"pattern": "v1"}},
+				},
 			},
 			wantErr:     true,
-			errorSubstr: "'commands' field must be an array",
+			errorSubstr: "expectOutput must be a string or a {\"regex\": \"pattern\"} object",
 		},
 		{
-			name: "empty commands array",
+			name: "config with command aliases",
 			input: map[string]interface{}{
-				"version":  "1.0",
-				"commands": []interface{}{},
+				"version": "1.0",
+				"aliases": map[string]interface{}{
+					"drun":   "docker run --rm -v $PWD:/w",
+					"node20": []interface{}{"@drun", "node:20"},
+				},
+				"commands": []interface{}{
+					map[string]interface{}{"command": "@drun alpine echo hi"},
+					map[string]interface{}{"name": "test", "command": "@node20", "args": []interface{}{"npm", "test"}},
+				},
+			},
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				first := config.Commands[0]
+				if first.Name != "drun-alpine" || first.Command != "docker" || strings.Join(first.Args, " ") != "run --rm -v $PWD:/w alpine echo hi" {
+					t.Errorf("Expected @drun to expand into the docker prefix, got %+v", first)
+				}
+				second := config.Commands[1]
+				if second.Name != "test" || second.Command != "docker" || strings.Join(second.Args, " ") != "run --rm -v $PWD:/w node:20 npm test" {
+					t.Errorf("Expected @node20 to expand through @drun, got %+v", second)
+				}
+			},
+		},
+		{
+			name: "config with aliases in a cycle",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"aliases": map[string]interface{}{
+					"a": "@b --x",
+					"b": "@a --y",
+				},
+				"commands": []interface{}{
+					map[string]interface{}{"command": "@a"},
+				},
 			},
 			wantErr:     true,
-			errorSubstr: "config must have at least one command",
+			errorSubstr: "aliases refer to each other in a cycle: @a -> @b -> @a",
 		},
 		{
-			name: "non-object command",
+			name: "config with an unknown alias",
 			input: map[string]interface{}{
 				"version": "1.0",
+				"aliases": map[string]interface{}{"drun": "docker run --rm"},
 				"commands": []interface{}{
-					"invalid",
+					map[string]interface{}{"command": "@dbuild ."},
 				},
 			},
 			wantErr:     true,
-			errorSubstr: "command must be an object",
+			errorSubstr: "command refers to unknown alias '@dbuild'",
 		},
 		{
-			name: "command with invalid name type",
+			name: "config with a memory limit",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"command": "make build", "memoryLimit": float64(2147483648)},
+				},
+			},
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				if got := config.Commands[0].MemoryLimit; got != 2147483648 {
+					t.Errorf("Expected memoryLimit 2147483648, got %d", got)
+				}
+			},
+		},
+		{
+			name: "config with a golden file",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"command": "./report", "goldenFile": "testdata/report.golden"},
+				},
+			},
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				if got := config.Commands[0].GoldenFile; got != "testdata/report.golden" {
+					t.Errorf("Expected goldenFile 'testdata/report.golden', got %q", got)
+				}
+			},
+		},
+		{
+			name: "config with priorities",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"command": "make test", "concurrent": true, "priority": float64(10)},
+					map[string]interface{}{"command": "make lint", "concurrent": true, "priority": float64(-1)},
+				},
+			},
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				if config.Commands[0].Priority != 10 || config.Commands[1].Priority != -1 {
+					t.Errorf("Expected priorities 10 and -1, got %d and %d", config.Commands[0].Priority, config.Commands[1].Priority)
+				}
+			},
+		},
+		{
+			name: "config with a matrix",
 			input: map[string]interface{}{
 				"version": "1.0",
 				"commands": []interface{}{
+					map[string]interface{}{"command": "make lint"},
 					map[string]interface{}{
-						"name":    123,
-						"command": "echo",
+						"name":    "test",
+						"command": "docker",
+						"args":    []interface{}{"run", "node:${MATRIX_VALUE}", "npm", "test"},
+						"env":     map[string]interface{}{"NODE_VERSION": "${MATRIX_VALUE}", "CI": "true"},
+						"matrix":  []interface{}{"18", "20", "22"},
 					},
 				},
 			},
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				if len(config.Commands) != 4 {
+					t.Fatalf("Expected the matrix to expand into 3 commands after make-lint, got %d commands", len(config.Commands))
+				}
+				for i, version := range []string{"18", "20", "22"} {
+					cmd := config.Commands[i+1]
+					if want := "test-" + version; cmd.Name != want {
+						t.Errorf("Expected command name %q, got %q", want, cmd.Name)
+					}
+					if want := "node:" + version; len(cmd.Args) != 4 || cmd.Args[1] != want {
+						t.Errorf("Expected args with image %q, got %v", want, cmd.Args)
+					}
+					if cmd.Env["NODE_VERSION"] != version || cmd.Env["CI"] != "true" {
+						t.Errorf("Expected NODE_VERSION=%s and CI=true, got %v", version, cmd.Env)
+					}
+					if !cmd.Concurrent || cmd.Matrix != nil {
+						t.Errorf("Expected a concurrent instance without a matrix, got concurrent=%v matrix=%v", cmd.Concurrent, cmd.Matrix)
+					}
+				}
+				if config.Commands[0].Concurrent {
+					t.Error("Expected the command without a matrix to stay sequential")
+				}
+			},
+		},
+		{
+			name: "config referring to a matrix by its name",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"name": "lint", "command": "make lint", "concurrent": true},
+					map[string]interface{}{"name": "db", "command": "postgres", "mode": "keepAlive", "matrix": []interface{}{"14", "16"}},
+					map[string]interface{}{"name": "test", "command": "make test", "afterReady": []interface{}{"db"}},
+				},
+				"signalForwarding": map[string]interface{}{"SIGUSR1": []interface{}{"db"}},
+			},
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				instances := []string{"db-14", "db-16"}
+				if !reflect.DeepEqual(config.Commands[3].AfterReady, instances) {
+					t.Errorf("Expected afterReady to wait for every instance, got %v", config.Commands[3].AfterReady)
+				}
+				if !reflect.DeepEqual(config.SignalForwarding["SIGUSR1"], instances) {
+					t.Errorf("Expected signalForwarding to relay to every instance, got %v", config.SignalForwarding["SIGUSR1"])
+				}
+				if ConcurrentWith(config.Commands[0], config.Commands[1]) || !ConcurrentWith(config.Commands[1], config.Commands[2]) {
+					t.Error("Expected the instances to form a concurrent group of their own")
+				}
+			},
+		},
+		{
+			name: "config with an empty matrix",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"command": "npm test", "matrix": []interface{}{}},
+				},
+			},
 			wantErr:     true,
-			errorSubstr: "name must be a string",
+			errorSubstr: "matrix must have at least one value",
+		},
+		{
+			name: "config with an empty matrix value",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"command": "npm test", "matrix": []interface{}{"18", ""}},
+				},
+			},
+			wantErr:     true,
+			errorSubstr: "matrix element 1 cannot be empty",
+		},
+		{
+			name: "config with a repeated matrix value",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"name": "test", "command": "npm test", "matrix": []interface{}{"18", "18"}},
+				},
+			},
+			wantErr:     true,
+			errorSubstr: "duplicate command name 'test-18'",
+		},
+		{
+			name: "config with an allowed failure",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"command": "npm run lint", "allowFailure": true},
+					map[string]interface{}{"command": "npm test"},
+				},
+			},
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				if !config.Commands[0].AllowFailure || config.Commands[1].AllowFailure {
+					t.Errorf("Expected only the first command to allow failure, got %v and %v", config.Commands[0].AllowFailure, config.Commands[1].AllowFailure)
+				}
+			},
+		},
+		{
+			name: "config with a non-boolean allowFailure",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"command": "npm run lint", "allowFailure": "yes"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "config with deduplicated output",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"command": "npm start", "mode": "keepAlive", "dedupeOutput": true},
+				},
+			},
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				if !config.Commands[0].DedupeOutput {
+					t.Error("Expected DedupeOutput to be set")
+				}
+			},
+		},
+		{
+			name: "config with a non-boolean dedupeOutput",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"command": "npm start", "dedupeOutput": 1},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "config with a created workDir",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"command": "make dist", "workDir": "out", "createWorkDir": true},
+				},
+			},
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				if !config.Commands[0].CreateWorkDir {
+					t.Error("Expected CreateWorkDir to be set")
+				}
+			},
+		},
+		{
+			name: "config with a warmup command",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"command": "make prime-cache", "warmup": true},
+					map[string]interface{}{"command": "make bench"},
+				},
+			},
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				if !config.Commands[0].Warmup || config.Commands[1].Warmup {
+					t.Errorf("Expected only the first command to be a warmup, got %v and %v", config.Commands[0].Warmup, config.Commands[1].Warmup)
+				}
+			},
+		},
+		{
+			name: "config with an output FIFO",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"command": "npm start", "mode": "keepAlive", "outputFifo": "tmp/api.fifo"},
+				},
+			},
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				if config.Commands[0].OutputFifo != "tmp/api.fifo" {
+					t.Errorf("Expected outputFifo 'tmp/api.fifo', got %q", config.Commands[0].OutputFifo)
+				}
+			},
+		},
+		{
+			name: "config with line patterns",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"command": "npm start", "mode": "keepAlive", "showLines": "^(WARN|ERROR)", "hideLines": "healthcheck", "filterCapturedLines": true},
+				},
+			},
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				cmd := config.Commands[0]
+				if cmd.ShowLines != "^(WARN|ERROR)" || cmd.HideLines != "healthcheck" || !cmd.FilterCapturedLines {
+					t.Errorf("Expected the line patterns to be kept, got %q, %q and %v", cmd.ShowLines, cmd.HideLines, cmd.FilterCapturedLines)
+				}
+			},
+		},
+		{
+			name: "config with meta",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"command": "make deploy", "meta": map[string]interface{}{"owner": "platform-team", "ticket": "OPS-1234"}},
+				},
+			},
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				meta := config.Commands[0].Meta
+				if len(meta) != 2 || meta["owner"] != "platform-team" || meta["ticket"] != "OPS-1234" {
+					t.Errorf("Expected meta to be kept, got %v", meta)
+				}
+			},
+		},
+		{
+			name: "config with a non-string meta value",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"command": "make deploy", "meta": map[string]interface{}{"retries": float64(3)}},
+				},
+			},
+			wantErr:     true,
+			errorSubstr: "meta value for 'retries' must be a string",
+		},
+		{
+			name: "config with an external service",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"command": "make migrate", "externalReady": map[string]interface{}{"readyTCP": "db.internal:5432", "command": "pg_isready -h db.internal", "timeout": "1m"}},
+				},
+			},
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				external := config.Commands[0].ExternalReady
+				if external == nil || external.ReadyTCP != "db.internal:5432" || external.Timeout != time.Minute {
+					t.Fatalf("Expected externalReady on db.internal:5432 within 1m, got %+v", external)
+				}
+				if !reflect.DeepEqual(external.Command, []string{"pg_isready", "-h", "db.internal"}) {
+					t.Errorf("Expected the check command to be split into words, got %q", external.Command)
+				}
+			},
+		},
+		{
+			name: "config with an unknown externalReady field",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"command": "make migrate", "externalReady": map[string]interface{}{"readyUDP": "db.internal:5432"}},
+				},
+			},
+			wantErr:     true,
+			errorSubstr: "invalid externalReady.readyUDP: is not a known field",
+		},
+		{
+			name: "config with a fractional log size",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"command": "npm start", "logMaxSize": 1.5},
+				},
+			},
+			wantErr:     true,
+			errorSubstr: "logMaxSize must be a whole number",
+		},
+		{
+			name: "config with a fractional port",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"command": "npm start", "ports": []interface{}{float64(30.5)}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "auto-generated command names",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{
+						"command": "npm start",
+					},
+					map[string]interface{}{
+						"command": "ls",
+					},
+				},
+			},
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				if config.Commands[0].Name != "npm-start" {
+					t.Errorf("Expected auto-generated name 'npm-start', got '%s'", config.Commands[0].Name)
+				}
+				if config.Commands[1].Name != "ls" {
+					t.Errorf("Expected auto-generated name 'ls', got '%s'", config.Commands[1].Name)
+				}
+			},
+		},
+		{
+			name:        "nil input",
+			input:       nil,
+			wantErr:     true,
+			errorSubstr: "config input cannot be nil",
+		},
+		{
+			name:        "non-object input",
+			input:       "invalid",
+			wantErr:     true,
+			errorSubstr: "config must be an object",
+		},
+		{
+			name: "missing version",
+			input: map[string]interface{}{
+				"commands": []interface{}{},
+			},
+			wantErr:     true,
+			errorSubstr: "config must have a 'version' field",
+		},
+		{
+			name: "non-string version",
+			input: map[string]interface{}{
+				"version":  123,
+				"commands": []interface{}{},
+			},
+			wantErr:     true,
+			errorSubstr: "version must be a string",
+		},
+		{
+			name: "missing commands",
+			input: map[string]interface{}{
+				"version": "1.0",
+			},
+			wantErr:     true,
+			errorSubstr: "config must have a 'commands' field",
+		},
+		{
+			name: "non-array commands",
+			input: map[string]interface{}{
+				"version":  "1.0",
+				"commands": "invalid",
+			},
+			wantErr:     true,
+			errorSubstr: "'commands' field must be an array",
+		},
+		{
+			name: "empty commands array",
+			input: map[string]interface{}{
+				"version":  "1.0",
+				"commands": []interface{}{},
+			},
+			wantErr:     true,
+			errorSubstr: "config must have at least one command",
+		},
+		{
+			name: "non-object command",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					"invalid",
+				},
+			},
+			wantErr:     true,
+			errorSubstr: "command must be an object",
+		},
+		{
+			name: "command with invalid name type",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{
+						"name":    123,
+						"command": "echo",
+					},
+				},
+			},
+			wantErr:     true,
+			errorSubstr: "name must be a string",
+		},
+		{
+			name: "command with invalid mode type",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{
+						"command": "echo",
+						"mode":    123,
+					},
+				},
+			},
+			wantErr:     true,
+			errorSubstr: "mode must be a string",
+		},
+		{
+			name: "command with invalid workDir type",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{
+						"command": "echo",
+						"workDir": 123,
+					},
+				},
+			},
+			wantErr:     true,
+			errorSubstr: "workDir must be a string",
+		},
+		{
+			name: "command with invalid env type",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{
+						"command": "echo",
+						"env":     "invalid",
+					},
+				},
+			},
+			wantErr:     true,
+			errorSubstr: "env must be an object",
+		},
+		{
+			name: "command with invalid env value type",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{
+						"command": "echo",
+						"env": map[string]interface{}{
+							"KEY": 123,
+						},
+					},
+				},
+			},
+			wantErr:     true,
+			errorSubstr: "env value for key 'KEY' must be a string",
+		},
+		{
+			name: "command with invalid env file object",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{
+						"command": "echo",
+						"env": map[string]interface{}{
+							"KEY": map[string]interface{}{"fromFile": "/run/secrets/key", "default": "x"},
+						},
+					},
+				},
+			},
+			wantErr:     true,
+			errorSubstr: "env value for key 'KEY' must be a string or a {\"fromFile\": \"path\"} object",
+		},
+		{
+			name: "command with invalid filter",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{"command": "echo", "filter": 42},
+				},
+			},
+			wantErr:     true,
+			errorSubstr: "invalid filter: must be a string or an array of strings",
+		},
+		{
+			name: "command missing command field",
+			input: map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{
+						"name": "test",
+					},
+				},
+			},
+			wantErr:     true,
+			errorSubstr: "must have a 'command' field",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := normalizer.NormalizeConfig(tt.input)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NormalizeConfig() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr && tt.errorSubstr != "" {
+				if err == nil || !contains(err.Error(), tt.errorSubstr) {
+					t.Errorf("NormalizeConfig() error = %v, expected to contain %q", err, tt.errorSubstr)
+				}
+			}
+
+			if !tt.wantErr {
+				if config == nil {
+					t.Error("NormalizeConfig() returned nil config without error")
+				} else if tt.validate != nil {
+					tt.validate(t, config)
+				}
+			}
+		})
+	}
+}
+
+func TestNormalizer_NormalizeFromJSON(t *testing.T) {
+	normalizer := NewNormalizer()
+
+	tests := []struct {
+		name        string
+		json        string
+		wantErr     bool
+		errorSubstr string
+		validate    func(*testing.T, *Config)
+	}{
+		{
+			name: "valid JSON with mixed formats",
+			json: `{
+				"version": "1.0",
+				"commands": [
+					{
+						"name": "build",
+						"command": "npm run build",
+						"mode": "once"
+					},
+					{
+						"name": "start",
+						"command": ["node", "server.js"],
+						"mode": "keepAlive"
+					},
+					{
+						"name": "docker",
+						"command": {
+							"command": "docker",
+							"args": ["run", "nginx"]
+						},
+						"mode": "keepAlive"
+					}
+				]
+			}`,
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				if len(config.Commands) != 3 {
+					t.Errorf("Expected 3 commands, got %d", len(config.Commands))
+				}
+				if config.Version != "1.0" {
+					t.Errorf("Expected version '1.0', got '%s'", config.Version)
+				}
+			},
+		},
+		{
+			name:        "empty JSON data",
+			json:        "",
+			wantErr:     true,
+			errorSubstr: "JSON data cannot be empty",
+		},
+		{
+			name:        "invalid JSON syntax",
+			json:        `{"version": "1.0"`,
+			wantErr:     true,
+			errorSubstr: "failed to parse JSON",
+		},
+		{
+			name: "valid JSON but invalid config",
+			json: `{
+				"version": "1.0",
+				"commands": []
+			}`,
+			wantErr:     true,
+			errorSubstr: "failed to normalize config",
+		},
+		{
+			name: "max concurrency",
+			json: `{
+				"version": "1.0",
+				"maxConcurrency": 2,
+				"commands": [
+					{"name": "lint", "command": "npm run lint", "concurrent": true}
+				]
+			}`,
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				if config.MaxConcurrency != 2 {
+					t.Errorf("Expected MaxConcurrency 2, got %d", config.MaxConcurrency)
+				}
+			},
+		},
+		{
+			name: "fractional max concurrency",
+			json: `{
+				"version": "1.0",
+				"maxConcurrency": 1.5,
+				"commands": [
+					{"name": "lint", "command": "npm run lint"}
+				]
+			}`,
+			wantErr:     true,
+			errorSubstr: "maxConcurrency must be a whole number",
+		},
+		{
+			name: "negative max concurrency",
+			json: `{
+				"version": "1.0",
+				"maxConcurrency": -1,
+				"commands": [
+					{"name": "lint", "command": "npm run lint"}
+				]
+			}`,
+			wantErr:     true,
+			errorSubstr: "maxConcurrency cannot be negative",
+		},
+		{
+			name: "min version that is not a string",
+			json: `{
+				"version": "1.0",
+				"minVersion": 1.4,
+				"commands": [
+					{"name": "lint", "command": "npm run lint"}
+				]
+			}`,
+			wantErr:     true,
+			errorSubstr: "minVersion must be a string",
+		},
+		{
+			name: "min version that is not a release",
+			json: `{
+				"version": "1.0",
+				"minVersion": "latest",
+				"commands": [
+					{"name": "lint", "command": "npm run lint"}
+				]
+			}`,
+			wantErr:     true,
+			errorSubstr: "minVersion 'latest' is not a valid seqr version",
+		},
+		{
+			name: "banner and footer",
+			json: `{
+				"version": "1.0",
+				"banner": "Starting staging deploy",
+				"footer": "Staging is up",
+				"commands": [
+					{"name": "deploy", "command": "make deploy"}
+				]
+			}`,
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				if config.Banner != "Starting staging deploy" || config.Footer != "Staging is up" {
+					t.Errorf("Expected the banner and footer to be kept, got %q and %q", config.Banner, config.Footer)
+				}
+			},
+		},
+		{
+			name: "banner that is not a string",
+			json: `{
+				"version": "1.0",
+				"banner": ["Starting"],
+				"commands": [
+					{"name": "deploy", "command": "make deploy"}
+				]
+			}`,
+			wantErr:     true,
+			errorSubstr: "banner must be a string",
+		},
+		{
+			name: "default mode keepAlive",
+			json: `{
+				"version": "1.0",
+				"defaultMode": "keepAlive",
+				"commands": [
+					{"name": "api", "command": "npm start"},
+					{"name": "web", "command": "npm", "args": ["run", "dev"]},
+					{"name": "migrate", "command": "make migrate", "mode": "once"}
+				]
+			}`,
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				for i, want := range []Mode{ModeKeepAlive, ModeKeepAlive, ModeOnce} {
+					if got := config.Commands[i].Mode; got != want {
+						t.Errorf("Expected command %d to have mode %s, got %s", i, want, got)
+					}
+				}
+				if config.DefaultMode != ModeKeepAlive {
+					t.Errorf("Expected DefaultMode keepAlive, got %q", config.DefaultMode)
+				}
+			},
+		},
+		{
+			name: "default mode omitted",
+			json: `{
+				"version": "1.0",
+				"commands": [
+					{"name": "build", "command": "make build"},
+					{"name": "serve", "command": "make serve", "mode": "keepAlive"}
+				]
+			}`,
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				if got := config.Commands[0].Mode; got != ModeOnce {
+					t.Errorf("Expected a command without a mode to run once, got %s", got)
+				}
+				if got := config.Commands[1].Mode; got != ModeKeepAlive {
+					t.Errorf("Expected an explicit mode to be kept, got %s", got)
+				}
+			},
 		},
 		{
-			name: "command with invalid mode type",
-			input: map[string]interface{}{
+			name: "unknown default mode",
+			json: `{
 				"version": "1.0",
-				"commands": []interface{}{
-					map[string]interface{}{
-						"command": "echo",
-						"mode":    123,
-					},
-				},
-			},
+				"defaultMode": "forever",
+				"commands": [
+					{"name": "build", "command": "make build"}
+				]
+			}`,
 			wantErr:     true,
-			errorSubstr: "mode must be a string",
+			errorSubstr: "invalid defaultMode value: forever",
 		},
 		{
-			name: "command with invalid workDir type",
-			input: map[string]interface{}{
+			name: "stop signals escalation",
+			json: `{
 				"version": "1.0",
-				"commands": []interface{}{
-					map[string]interface{}{
-						"command": "echo",
-						"workDir": 123,
-					},
-				},
+				"commands": [
+					{
+						"name": "api",
+						"command": "node server.js",
+						"mode": "keepAlive",
+						"stopSignals": ["SIGTERM", {"signal": "SIGINT", "timeout": "2s"}]
+					}
+				]
+			}`,
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				steps := config.Commands[0].StopSignals
+				if len(steps) != 2 {
+					t.Fatalf("Expected 2 stop signals, got %d", len(steps))
+				}
+				if steps[0].Signal != "SIGTERM" || steps[0].Timeout != 0 {
+					t.Errorf("Expected first step SIGTERM with default timeout, got %+v", steps[0])
+				}
+				if steps[1].Signal != "SIGINT" || steps[1].Timeout != 2*time.Second {
+					t.Errorf("Expected second step SIGINT with 2s timeout, got %+v", steps[1])
+				}
 			},
-			wantErr:     true,
-			errorSubstr: "workDir must be a string",
 		},
 		{
-			name: "command with invalid env type",
-			input: map[string]interface{}{
+			name: "stop signals with invalid timeout",
+			json: `{
 				"version": "1.0",
-				"commands": []interface{}{
-					map[string]interface{}{
-						"command": "echo",
-						"env":     "invalid",
-					},
-				},
-			},
+				"commands": [
+					{
+						"name": "api",
+						"command": "node server.js",
+						"stopSignals": [{"signal": "SIGINT", "timeout": "soon"}]
+					}
+				]
+			}`,
 			wantErr:     true,
-			errorSubstr: "env must be an object",
+			errorSubstr: "invalid timeout",
 		},
 		{
-			name: "command with invalid env value type",
-			input: map[string]interface{}{
+			name: "keepAlive max lifetime",
+			json: `{
 				"version": "1.0",
-				"commands": []interface{}{
-					map[string]interface{}{
-						"command": "echo",
-						"env": map[string]interface{}{
-							"KEY": 123,
-						},
-					},
-				},
+				"commands": [
+					{"name": "api", "command": "node server.js", "mode": "keepAlive", "maxLifetime": "10m"}
+				]
+			}`,
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				if config.Commands[0].MaxLifetime != 10*time.Minute {
+					t.Errorf("Expected maxLifetime of 10m, got %v", config.Commands[0].MaxLifetime)
+				}
 			},
-			wantErr:     true,
-			errorSubstr: "env value for key 'KEY' must be a string",
 		},
 		{
-			name: "command missing command field",
-			input: map[string]interface{}{
+			name: "max lifetime with invalid duration",
+			json: `{
 				"version": "1.0",
-				"commands": []interface{}{
-					map[string]interface{}{
-						"name": "test",
-					},
-				},
-			},
+				"commands": [
+					{"name": "api", "command": "node server.js", "mode": "keepAlive", "maxLifetime": 600}
+				]
+			}`,
 			wantErr:     true,
-			errorSubstr: "must have a 'command' field",
+			errorSubstr: "maxLifetime must be a duration string",
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			config, err := normalizer.NormalizeConfig(tt.input)
-
-			if (err != nil) != tt.wantErr {
-				t.Errorf("NormalizeConfig() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-
-			if tt.wantErr && tt.errorSubstr != "" {
-				if err == nil || !contains(err.Error(), tt.errorSubstr) {
-					t.Errorf("NormalizeConfig() error = %v, expected to contain %q", err, tt.errorSubstr)
-				}
-			}
-
-			if !tt.wantErr {
-				if config == nil {
-					t.Error("NormalizeConfig() returned nil config without error")
-				} else if tt.validate != nil {
-					tt.validate(t, config)
-				}
-			}
-		})
-	}
-}
-
-func TestNormalizer_NormalizeFromJSON(t *testing.T) {
-	normalizer := NewNormalizer()
-
-	tests := []struct {
-		name        string
-		json        string
-		wantErr     bool
-		errorSubstr string
-		validate    func(*testing.T, *Config)
-	}{
 		{
-			name: "valid JSON with mixed formats",
+			name: "cache key as string and array",
 			json: `{
 				"version": "1.0",
 				"commands": [
-					{
-						"name": "build",
-						"command": "npm run build",
-						"mode": "once"
-					},
-					{
-						"name": "start",
-						"command": ["node", "server.js"],
-						"mode": "keepAlive"
-					},
-					{
-						"name": "docker",
-						"command": {
-							"command": "docker",
-							"args": ["run", "nginx"]
-						},
-						"mode": "keepAlive"
-					}
+					{"name": "build", "command": "go build ./...", "cacheKey": "go.sum"},
+					{"name": "test", "command": "go test ./...", "cacheKey": ["*.go", "go.mod"]}
 				]
 			}`,
 			wantErr: false,
 			validate: func(t *testing.T, config *Config) {
-				if len(config.Commands) != 3 {
-					t.Errorf("Expected 3 commands, got %d", len(config.Commands))
+				if got := config.Commands[0].CacheKey; len(got) != 1 || got[0] != "go.sum" {
+					t.Errorf("Expected cacheKey [go.sum], got %v", got)
 				}
-				if config.Version != "1.0" {
-					t.Errorf("Expected version '1.0', got '%s'", config.Version)
+				if got := config.Commands[1].CacheKey; len(got) != 2 || got[0] != "*.go" || got[1] != "go.mod" {
+					t.Errorf("Expected cacheKey [*.go go.mod], got %v", got)
 				}
 			},
 		},
 		{
-			name:        "empty JSON data",
-			json:        "",
-			wantErr:     true,
-			errorSubstr: "JSON data cannot be empty",
-		},
-		{
-			name:        "invalid JSON syntax",
-			json:        `{"version": "1.0"`,
-			wantErr:     true,
-			errorSubstr: "failed to parse JSON",
+			name: "signal forwarding",
+			json: `{
+				"version": "1.0",
+				"commands": [
+					{"name": "api", "command": "node server.js", "mode": "keepAlive"}
+				],
+				"signalForwarding": {"SIGUSR1": ["api"]}
+			}`,
+			wantErr: false,
+			validate: func(t *testing.T, config *Config) {
+				targets := config.SignalForwarding["SIGUSR1"]
+				if len(targets) != 1 || targets[0] != "api" {
+					t.Errorf("Expected SIGUSR1 to forward to [api], got %v", targets)
+				}
+			},
 		},
 		{
-			name: "valid JSON but invalid config",
+			name: "signal forwarding with non-array targets",
 			json: `{
 				"version": "1.0",
-				"commands": []
+				"commands": [
+					{"name": "api", "command": "node server.js", "mode": "keepAlive"}
+				],
+				"signalForwarding": {"SIGUSR1": "api"}
 			}`,
 			wantErr:     true,
-			errorSubstr: "failed to normalize config",
+			errorSubstr: "must be an array",
 		},
 	}
 
@@ -717,6 +1638,90 @@ func TestNormalizer_StrictMode(t *testing.T) {
 	}
 }
 
+func TestNormalizer_OSKeyedEnv(t *testing.T) {
+	tests := []struct {
+		name        string
+		goos        string
+		value       interface{}
+		want        string
+		errorSubstr string
+	}{
+		{
+			name:  "picks the linux value",
+			goos:  "linux",
+			value: map[string]interface{}{"linux": ":", "windows": ";"},
+			want:  ":",
+		},
+		{
+			name:  "picks the windows value",
+			goos:  "windows",
+			value: map[string]interface{}{"linux": ":", "windows": ";"},
+			want:  ";",
+		},
+		{
+			name:  "falls back to the default",
+			goos:  "darwin",
+			value: map[string]interface{}{"windows": ";", "default": ":"},
+			want:  ":",
+		},
+		{
+			name:  "plain string values are unchanged",
+			goos:  "windows",
+			value: ":",
+			want:  ":",
+		},
+		{
+			name:        "no entry for the OS and no default",
+			goos:        "darwin",
+			value:       map[string]interface{}{"linux": ":", "windows": ";"},
+			errorSubstr: "env value for key 'PATH_SEP' has no entry for darwin and no default",
+		},
+		{
+			name:        "unknown OS key",
+			goos:        "linux",
+			value:       map[string]interface{}{"linux": ":", "macos": ":"},
+			errorSubstr: "env value for key 'PATH_SEP' has unknown OS 'macos'",
+		},
+		{
+			name:        "non-string value for an OS",
+			goos:        "linux",
+			value:       map[string]interface{}{"linux": 1},
+			errorSubstr: "env value for key 'PATH_SEP' on linux must be a string",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			normalizer := NewNormalizer()
+			normalizer.GOOS = tt.goos
+
+			input := map[string]interface{}{
+				"version": "1.0",
+				"commands": []interface{}{
+					map[string]interface{}{
+						"command": "echo",
+						"env":     map[string]interface{}{"PATH_SEP": tt.value},
+					},
+				},
+			}
+			config, err := normalizer.NormalizeConfig(input)
+
+			if tt.errorSubstr != "" {
+				if err == nil || !contains(err.Error(), tt.errorSubstr) {
+					t.Errorf("NormalizeConfig() error = %v, expected to contain %q", err, tt.errorSubstr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeConfig() unexpected error: %v", err)
+			}
+			if cmd := config.Commands[0]; cmd.Env["PATH_SEP"] != tt.want {
+				t.Errorf("Expected PATH_SEP=%q on %s, got %q", tt.want, tt.goos, cmd.Env["PATH_SEP"])
+			}
+		})
+	}
+}
+
 func TestNormalizer_generateCommandName(t *testing.T) {
 	normalizer := NewNormalizer()
 
@@ -755,3 +1760,102 @@ func TestNormalizer_generateCommandName(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitShellWords(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    []string
+		errorSubstr string
+	}{
+		{
+			name:     "plain words",
+			input:    "npm  run\tbuild",
+			expected: []string{"npm", "run", "build"},
+		},
+		{
+			name:     "quoted substrings",
+			input:    `echo "hello world" 'single quotes'`,
+			expected: []string{"echo", "hello world", "single quotes"},
+		},
+		{
+			name:     "escaped spaces",
+			input:    `ls my\ project\ dir`,
+			expected: []string{"ls", "my project dir"},
+		},
+		{
+			name:     "mixed quotes",
+			input:    `sh -c 'echo "nested $HOME"' "it's"`,
+			expected: []string{"sh", "-c", `echo "nested $HOME"`, "it's"},
+		},
+		{
+			name:     "quotes join with adjacent text",
+			input:    `node --title="my app" --dir=a'b c'd`,
+			expected: []string{"node", "--title=my app", "--dir=ab cd"},
+		},
+		{
+			name:     "escapes inside double quotes",
+			input:    `printf "a \"b\" \\ \$x \n"`,
+			expected: []string{"printf", `a "b" \ $x \n`},
+		},
+		{
+			name:     "backslash is literal inside single quotes",
+			input:    `grep 'a\sb'`,
+			expected: []string{"grep", `a\sb`},
+		},
+		{
+			name:     "empty quoted argument",
+			input:    `git commit -m ""`,
+			expected: []string{"git", "commit", "-m", ""},
+		},
+		{
+			name:        "unterminated double quote",
+			input:       `echo "oops`,
+			errorSubstr: "unterminated double quote",
+		},
+		{
+			name:        "unterminated single quote",
+			input:       `echo 'oops`,
+			errorSubstr: "unterminated single quote",
+		},
+		{
+			name:        "trailing backslash",
+			input:       `echo oops\`,
+			errorSubstr: "unescaped backslash",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			words, err := splitShellWords(tt.input)
+			if tt.errorSubstr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errorSubstr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.errorSubstr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(words, tt.expected) {
+				t.Errorf("splitShellWords(%q) = %q, want %q", tt.input, words, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNormalizer_StringCommandWithQuotes(t *testing.T) {
+	normalizer := NewNormalizer()
+
+	cmd, err := normalizer.NormalizeCommand(`echo "hello world" 'single quotes'`, "greet", ModeOnce, "", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cmd.Command != "echo" || !reflect.DeepEqual(cmd.Args, []string{"hello world", "single quotes"}) {
+		t.Errorf("Expected echo with two quoted args, got %q %q", cmd.Command, cmd.Args)
+	}
+
+	if _, err := normalizer.NormalizeCommand(`echo "unbalanced`, "broken", ModeOnce, "", nil); err == nil {
+		t.Error("Expected an error for an unterminated quote")
+	}
+}
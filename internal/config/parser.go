@@ -1,12 +1,17 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
+// maxConfigSize bounds the size of a configuration file, local or fetched from a URL
+const maxConfigSize = 1024 * 1024
+
 // LoadFromFile loads and parses a configuration file
 func LoadFromFile(filename string) (*Config, error) {
 	if filename == "" {
@@ -34,10 +39,9 @@ func LoadFromFile(filename string) (*Config, error) {
 		return nil, fmt.Errorf("config file '%s' is empty", cleanPath)
 	}
 
-	const maxFileSize = 1024 * 1024
-	if fileInfo.Size() > maxFileSize {
+	if fileInfo.Size() > maxConfigSize {
 		return nil, fmt.Errorf("config file '%s' is too large (%d bytes), maximum allowed is %d bytes",
-			cleanPath, fileInfo.Size(), maxFileSize)
+			cleanPath, fileInfo.Size(), maxConfigSize)
 	}
 
 	data, err := os.ReadFile(cleanPath)
@@ -50,9 +54,111 @@ func LoadFromFile(filename string) (*Config, error) {
 		return nil, fmt.Errorf("error parsing config file '%s': %w", cleanPath, err)
 	}
 
+	// Golden files belong with the config, wherever seqr is run from
+	for i := range config.Commands {
+		if goldenFile := config.Commands[i].GoldenFile; goldenFile != "" && !filepath.IsAbs(goldenFile) {
+			config.Commands[i].GoldenFile = filepath.Join(filepath.Dir(cleanPath), goldenFile)
+		}
+	}
+
 	return config, nil
 }
 
+// DirConfigPattern matches the configuration files LoadFromDir loads from a directory
+const DirConfigPattern = "*.queue.json"
+
+// LoadFromDir loads every configuration file in dir matching DirConfigPattern, in name order, and
+// merges their commands into one configuration. Relative workDirs are resolved against the
+// directory holding their file. Command names must be unique across all files.
+func LoadFromDir(dir string) (*Config, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("config directory cannot be empty")
+	}
+
+	cleanDir := filepath.Clean(dir)
+
+	dirInfo, err := os.Stat(cleanDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("config directory '%s' does not exist", cleanDir)
+		}
+		return nil, fmt.Errorf("failed to access config directory '%s': %w", cleanDir, err)
+	}
+	if !dirInfo.IsDir() {
+		return nil, fmt.Errorf("'%s' is not a directory", cleanDir)
+	}
+
+	files, err := filepath.Glob(filepath.Join(cleanDir, DirConfigPattern))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list config files in '%s': %w", cleanDir, err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no config files matching '%s' found in '%s'", DirConfigPattern, cleanDir)
+	}
+	sort.Strings(files)
+
+	merged := &Config{}
+	definedIn := make(map[string]string)
+
+	for _, file := range files {
+		cfg, err := LoadFromFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		if merged.Version == "" {
+			merged.Version = cfg.Version
+		}
+		if merged.Banner == "" {
+			merged.Banner = cfg.Banner
+		}
+		if merged.Footer == "" {
+			merged.Footer = cfg.Footer
+		}
+		if cfg.MinVersion != "" {
+			merged.MinVersion = newerVersion(merged.MinVersion, cfg.MinVersion)
+		}
+
+		// The most restrictive bound of all files applies to the merged run
+		if cfg.MaxConcurrency > 0 && (merged.MaxConcurrency == 0 || cfg.MaxConcurrency < merged.MaxConcurrency) {
+			merged.MaxConcurrency = cfg.MaxConcurrency
+		}
+
+		for signalName, targets := range cfg.SignalForwarding {
+			if merged.SignalForwarding == nil {
+				merged.SignalForwarding = make(map[string][]string)
+			}
+			merged.SignalForwarding[signalName] = append(merged.SignalForwarding[signalName], targets...)
+		}
+
+		for _, cmd := range cfg.Commands {
+			// A file's noProcessGroup and prefixOutput only cover its own commands
+			if cfg.NoProcessGroup {
+				cmd.NoProcessGroup = true
+			}
+			if cfg.PrefixOutput {
+				cmd.PrefixOutput = true
+			}
+
+			if previous, exists := definedIn[cmd.Name]; exists {
+				return nil, fmt.Errorf("command name '%s' is defined in both '%s' and '%s'", cmd.Name, previous, file)
+			}
+			definedIn[cmd.Name] = file
+
+			if cmd.WorkDir != "" && !filepath.IsAbs(cmd.WorkDir) {
+				cmd.WorkDir = filepath.Join(filepath.Dir(file), cmd.WorkDir)
+			}
+			merged.Commands = append(merged.Commands, cmd)
+		}
+	}
+
+	if err := merged.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid merged configuration from '%s': %w", cleanDir, err)
+	}
+
+	return merged, nil
+}
+
 // ParseJSON parses JSON data into a Config struct, supporting multiple command formats
 func ParseJSON(data []byte) (*Config, error) {
 	if len(data) == 0 {
@@ -74,6 +180,11 @@ func ParseJSON(data []byte) (*Config, error) {
 	normalizer := NewNormalizer()
 	config, err := normalizer.NormalizeFromJSON(data)
 	if err != nil {
+		// Nothing is wrong with the JSON when seqr is too old for it
+		var tooOld *MinVersionError
+		if errors.As(err, &tooOld) {
+			return nil, tooOld
+		}
 		return nil, enhanceParseError("normalization failed", err, data)
 	}
 
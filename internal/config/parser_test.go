@@ -337,6 +337,28 @@ func TestLoadFromFile(t *testing.T) {
 		t.Fatalf("Failed to create test file: %v", err)
 	}
 
+	// Test config file where a later command has an unsupported mode
+	badModeConfig := `{
+		"version": "1.0",
+		"commands": [
+			{
+				"name": "setup",
+				"command": "echo",
+				"mode": "once"
+			},
+			{
+				"name": "deploy",
+				"command": "echo",
+				"mode": "invalid"
+			}
+		]
+	}`
+
+	badModeFile := filepath.Join(tmpDir, "bad-mode.json")
+	if err := os.WriteFile(badModeFile, []byte(badModeConfig), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
 	// Test empty config file
 	emptyFile := filepath.Join(tmpDir, "empty.json")
 	if err := os.WriteFile(emptyFile, []byte(""), 0644); err != nil {
@@ -366,6 +388,12 @@ func TestLoadFromFile(t *testing.T) {
 			wantErr:     true,
 			errorSubstr: "format detection failed",
 		},
+		{
+			name:        "unsupported mode is rejected at load time",
+			filename:    badModeFile,
+			wantErr:     true,
+			errorSubstr: "invalid mode value: invalid in command 'deploy'",
+		},
 		{
 			name:        "non-existent file",
 			filename:    filepath.Join(tmpDir, "nonexistent.json"),
@@ -410,10 +438,42 @@ func TestLoadFromFile(t *testing.T) {
 			if !tt.wantErr && config == nil {
 				t.Error("LoadFromFile() returned nil config without error")
 			}
+
+			if tt.wantErr && config != nil {
+				t.Error("LoadFromFile() returned a config alongside an error")
+			}
 		})
 	}
 }
 
+func TestLoadFromFile_GoldenFileRelativeToConfig(t *testing.T) {
+	dir := t.TempDir()
+	absGolden := filepath.Join(t.TempDir(), "abs.golden")
+	configFile := filepath.Join(dir, "seqr.queue.json")
+	content := `{
+		"version": "1.0",
+		"commands": [
+			{"name": "relative", "command": "./report", "goldenFile": "testdata/report.golden"},
+			{"name": "absolute", "command": "./report", "goldenFile": "` + filepath.ToSlash(absGolden) + `"}
+		]
+	}`
+	if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := LoadFromFile(configFile)
+	if err != nil {
+		t.Fatalf("LoadFromFile failed: %v", err)
+	}
+
+	if want := filepath.Join(dir, "testdata", "report.golden"); cfg.Commands[0].GoldenFile != want {
+		t.Errorf("Expected relative goldenFile to resolve to %q, got %q", want, cfg.Commands[0].GoldenFile)
+	}
+	if cfg.Commands[1].GoldenFile != filepath.ToSlash(absGolden) {
+		t.Errorf("Expected absolute goldenFile to be kept, got %q", cfg.Commands[1].GoldenFile)
+	}
+}
+
 // Helper function to check if a string contains a substring
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(substr) == 0 ||
@@ -429,6 +489,84 @@ func findSubstring(s, substr string) bool {
 	return false
 }
 
+func TestLoadFromDir(t *testing.T) {
+	writeQueue := func(t *testing.T, dir, name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	t.Run("merges files in name order", func(t *testing.T) {
+		dir := t.TempDir()
+		absWorkDir := filepath.ToSlash(t.TempDir())
+		writeQueue(t, dir, "b-web.queue.json", `{
+			"version": "1.0",
+			"maxConcurrency": 4,
+			"commands": [
+				{"name": "web", "command": "npm", "args": ["start"], "mode": "keepAlive", "workDir": "web"}
+			]
+		}`)
+		writeQueue(t, dir, "a-db.queue.json", `{
+			"version": "1.0",
+			"maxConcurrency": 2,
+			"commands": [
+				{"name": "migrate", "command": "make", "args": ["migrate"], "workDir": "`+absWorkDir+`"},
+				{"name": "seed", "command": "make", "args": ["seed"]}
+			]
+		}`)
+		writeQueue(t, dir, "notes.json", `{"version": "1.0", "commands": [{"name": "ignored", "command": "true"}]}`)
+
+		cfg, err := LoadFromDir(dir)
+		if err != nil {
+			t.Fatalf("LoadFromDir failed: %v", err)
+		}
+
+		var names []string
+		for _, cmd := range cfg.Commands {
+			names = append(names, cmd.Name)
+		}
+		if len(names) != 3 || names[0] != "migrate" || names[1] != "seed" || names[2] != "web" {
+			t.Errorf("Expected commands [migrate seed web], got %v", names)
+		}
+
+		if cfg.Commands[0].WorkDir != absWorkDir {
+			t.Errorf("Expected absolute workDir to be kept, got %q", cfg.Commands[0].WorkDir)
+		}
+		if cfg.Commands[1].WorkDir != "" {
+			t.Errorf("Expected empty workDir to stay empty, got %q", cfg.Commands[1].WorkDir)
+		}
+		if want := filepath.Join(dir, "web"); cfg.Commands[2].WorkDir != want {
+			t.Errorf("Expected relative workDir to resolve to %q, got %q", want, cfg.Commands[2].WorkDir)
+		}
+
+		if cfg.MaxConcurrency != 2 {
+			t.Errorf("Expected the most restrictive maxConcurrency of 2, got %d", cfg.MaxConcurrency)
+		}
+	})
+
+	t.Run("duplicate names across files", func(t *testing.T) {
+		dir := t.TempDir()
+		writeQueue(t, dir, "api.queue.json", `{"version": "1.0", "commands": [{"name": "build", "command": "make"}]}`)
+		writeQueue(t, dir, "web.queue.json", `{"version": "1.0", "commands": [{"name": "build", "command": "npm"}]}`)
+
+		_, err := LoadFromDir(dir)
+		if err == nil {
+			t.Fatal("Expected duplicate command names to be rejected")
+		}
+		if !findSubstring(err.Error(), "command name 'build' is defined in both") {
+			t.Errorf("Expected error to name the duplicate, got: %v", err)
+		}
+	})
+
+	t.Run("no matching files", func(t *testing.T) {
+		_, err := LoadFromDir(t.TempDir())
+		if err == nil || !findSubstring(err.Error(), "no config files matching") {
+			t.Errorf("Expected an error for an empty directory, got: %v", err)
+		}
+	})
+}
+
 func TestDefaultConfigFile(t *testing.T) {
 	expected := ".queue.json"
 	if got := DefaultConfigFile(); got != expected {
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// RemoteConfigTimeout bounds fetching a configuration from a URL, response body included
+const RemoteConfigTimeout = 30 * time.Second
+
+// IsURL reports whether a config location is an http:// or https:// URL rather than a file path
+func IsURL(location string) bool {
+	lower := strings.ToLower(location)
+	return strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "http://")
+}
+
+// LoadFromURL fetches a configuration over HTTPS and parses it like a configuration file. Plain
+// HTTP is refused unless allowInsecure is set, since anyone on the path could change the
+// commands seqr runs. The configuration has no local directory, so relative paths in it, such
+// as workDirs, resolve against the current directory.
+func LoadFromURL(rawURL string, allowInsecure bool) (*Config, error) {
+	return loadFromURL(rawURL, allowInsecure, nil)
+}
+
+// maxConfigRedirects is how many redirects fetching a configuration follows, as many as
+// net/http does by default
+const maxConfigRedirects = 10
+
+// loadFromURL is LoadFromURL with the transport to fetch over, nil for the default one
+func loadFromURL(rawURL string, allowInsecure bool, transport http.RoundTripper) (*Config, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config URL '%s': %w", rawURL, err)
+	}
+	if parsed.Scheme != "https" && !allowInsecure {
+		return nil, fmt.Errorf("refusing to fetch config '%s' over %s, use --insecure to allow it", rawURL, parsed.Scheme)
+	}
+
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   RemoteConfigTimeout,
+		// A redirect must not downgrade the fetch to plain HTTP behind the user's back
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if req.URL.Scheme != "https" && !allowInsecure {
+				return fmt.Errorf("refusing to follow redirect to '%s' over %s, use --insecure to allow it", req.URL, req.URL.Scheme)
+			}
+			if len(via) >= maxConfigRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxConfigRedirects)
+			}
+			return nil
+		},
+	}
+	resp, err := client.Get(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch config '%s': %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch config '%s': %s", rawURL, resp.Status)
+	}
+
+	// Read one byte past the limit to tell a config of exactly the maximum size from a larger one
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxConfigSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config '%s': %w", rawURL, err)
+	}
+	if len(data) > maxConfigSize {
+		return nil, fmt.Errorf("config '%s' is too large, maximum allowed is %d bytes", rawURL, maxConfigSize)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("config '%s' is empty", rawURL)
+	}
+
+	config, err := ParseJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing config '%s': %w", rawURL, err)
+	}
+	return config, nil
+}
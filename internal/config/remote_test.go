@@ -0,0 +1,149 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLoadFromURL(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		status        int
+		allowInsecure bool
+		errorSubstr   string
+	}{
+		{
+			name:          "JSON config",
+			body:          `{"version": "1.0", "commands": [{"name": "build", "command": "go", "args": ["build"]}]}`,
+			allowInsecure: true,
+		},
+		{
+			name:          "string command format",
+			body:          `{"version": "1.0", "commands": [{"name": "build", "command": "go build"}]}`,
+			allowInsecure: true,
+		},
+		{
+			name:        "plain http without insecure",
+			body:        `{"version": "1.0", "commands": [{"name": "build", "command": "go"}]}`,
+			errorSubstr: "use --insecure",
+		},
+		{
+			name:          "not found",
+			status:        http.StatusNotFound,
+			allowInsecure: true,
+			errorSubstr:   "404 Not Found",
+		},
+		{
+			name:          "too large",
+			body:          strings.Repeat(" ", maxConfigSize+1),
+			allowInsecure: true,
+			errorSubstr:   "too large",
+		},
+		{
+			name:          "empty",
+			allowInsecure: true,
+			errorSubstr:   "is empty",
+		},
+		{
+			name:          "malformed",
+			body:          `{"version": "1.0", "commands": [`,
+			allowInsecure: true,
+			errorSubstr:   "error parsing config",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+				}
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			cfg, err := LoadFromURL(server.URL+"/ci.queue.json", tt.allowInsecure)
+			if tt.errorSubstr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errorSubstr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.errorSubstr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Failed to load config from URL: %v", err)
+			}
+			if len(cfg.Commands) != 1 || cfg.Commands[0].Name != "build" {
+				t.Errorf("Expected the served build command, got %+v", cfg.Commands)
+			}
+		})
+	}
+}
+
+func TestIsURL(t *testing.T) {
+	tests := map[string]bool{
+		"https://example.com/ci.queue.json": true,
+		"HTTP://example.com/ci.queue.json":  true,
+		".queue.json":                       false,
+		"configs/https.queue.json":          false,
+		"file:///tmp/.queue.json":           false,
+	}
+	for location, want := range tests {
+		if got := IsURL(location); got != want {
+			t.Errorf("IsURL(%q) = %v, want %v", location, got, want)
+		}
+	}
+}
+
+func TestLoadFromURLRedirects(t *testing.T) {
+	body := `{"version": "1.0", "commands": [{"name": "build", "command": "make"}]}`
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer plain.Close()
+
+	secure := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/to-http":
+			http.Redirect(w, r, plain.URL+"/ci.queue.json", http.StatusFound)
+		case "/to-https":
+			http.Redirect(w, r, "/ci.queue.json", http.StatusFound)
+		default:
+			w.Write([]byte(body))
+		}
+	}))
+	defer secure.Close()
+
+	tests := []struct {
+		name          string
+		path          string
+		allowInsecure bool
+		errorSubstr   string
+	}{
+		{name: "https to https", path: "/to-https"},
+		{name: "https to http refused", path: "/to-http", errorSubstr: "refusing to follow redirect to '" + plain.URL + "/ci.queue.json' over http"},
+		{name: "https to http with insecure", path: "/to-http", allowInsecure: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := loadFromURL(secure.URL+tt.path, tt.allowInsecure, secure.Client().Transport)
+			if tt.errorSubstr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errorSubstr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.errorSubstr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Failed to load config from URL: %v", err)
+			}
+			if len(cfg.Commands) != 1 || cfg.Commands[0].Name != "build" {
+				t.Errorf("Expected the served build command, got %+v", cfg.Commands)
+			}
+		})
+	}
+}
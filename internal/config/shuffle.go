@@ -0,0 +1,95 @@
+package config
+
+import (
+	"math/rand/v2"
+	"slices"
+)
+
+// maxShuffleAttempts bounds the random orders Shuffle draws before keeping the queue's own
+const maxShuffleAttempts = 100
+
+// shuffleUnit is a part of the queue that moves as a whole: a sequential command, or a run of
+// concurrent commands
+type shuffleUnit struct {
+	commands   []Command
+	concurrent bool
+	dependsOn  []int // Units holding keepAlive commands this one waits for with afterReady
+}
+
+// Shuffle returns the commands in a random order drawn from seed, the same for the same seed,
+// to expose commands that only work when run after others without saying so. A run of
+// concurrent commands moves as one group, with its commands shuffled within it, and every
+// position keeps its kind, so groups never merge with each other. Commands stay after the
+// keepAlive commands they wait for with afterReady.
+func Shuffle(commands []Command, seed int64) []Command {
+	rng := rand.New(rand.NewPCG(uint64(seed), 0))
+	units := shuffleUnits(commands)
+
+	for range maxShuffleAttempts {
+		if order, ok := drawOrder(units, rng); ok {
+			shuffled := make([]Command, 0, len(commands))
+			for _, i := range order {
+				group := slices.Clone(units[i].commands)
+				rng.Shuffle(len(group), func(a, b int) { group[a], group[b] = group[b], group[a] })
+				shuffled = append(shuffled, group...)
+			}
+			return shuffled
+		}
+	}
+	return slices.Clone(commands)
+}
+
+// shuffleUnits splits the queue into the units Shuffle moves, noting which units wait for others
+func shuffleUnits(commands []Command) []shuffleUnit {
+	var units []shuffleUnit
+	unitOf := make(map[string]int)
+	for start := 0; start < len(commands); {
+		end := start + 1
+		for end < len(commands) && ConcurrentWith(commands[end-1], commands[end]) {
+			end++
+		}
+		for _, cmd := range commands[start:end] {
+			unitOf[cmd.Name] = len(units)
+		}
+		units = append(units, shuffleUnit{commands: commands[start:end], concurrent: commands[start].Concurrent})
+		start = end
+	}
+
+	for i := range units {
+		for _, cmd := range units[i].commands {
+			for _, name := range cmd.AfterReady {
+				if dependency, ok := unitOf[name]; ok && dependency != i && !slices.Contains(units[i].dependsOn, dependency) {
+					units[i].dependsOn = append(units[i].dependsOn, dependency)
+				}
+			}
+		}
+	}
+	return units
+}
+
+// drawOrder fills the queue's positions, each with a random unit of the same kind whose
+// dependencies are already placed. It fails when some position has no such unit left.
+func drawOrder(units []shuffleUnit, rng *rand.Rand) ([]int, bool) {
+	placed := make([]bool, len(units))
+	order := make([]int, 0, len(units))
+
+	for _, slot := range units {
+		var candidates []int
+		for i, unit := range units {
+			if placed[i] || unit.concurrent != slot.concurrent {
+				continue
+			}
+			if !slices.ContainsFunc(unit.dependsOn, func(dependency int) bool { return !placed[dependency] }) {
+				candidates = append(candidates, i)
+			}
+		}
+		if len(candidates) == 0 {
+			return nil, false
+		}
+
+		next := candidates[rng.IntN(len(candidates))]
+		placed[next] = true
+		order = append(order, next)
+	}
+	return order, true
+}
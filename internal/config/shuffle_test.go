@@ -0,0 +1,80 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+// shuffleQueue is a queue with a sequential keepAlive database, a concurrent group that waits
+// for it, and sequential and concurrent commands around them
+func shuffleQueue() []Command {
+	return []Command{
+		{Name: "db", Command: "postgres", Mode: ModeKeepAlive},
+		{Name: "migrate", Command: "make", Mode: ModeOnce},
+		{Name: "api", Command: "api", Mode: ModeKeepAlive, Concurrent: true, AfterReady: []string{"db"}},
+		{Name: "worker", Command: "worker", Mode: ModeKeepAlive, Concurrent: true},
+		{Name: "seed", Command: "make", Mode: ModeOnce},
+		{Name: "lint", Command: "make", Mode: ModeOnce, Concurrent: true},
+		{Name: "test", Command: "make", Mode: ModeOnce, Concurrent: true},
+		{Name: "report", Command: "make", Mode: ModeOnce},
+	}
+}
+
+func commandNames(commands []Command) []string {
+	names := make([]string, len(commands))
+	for i, cmd := range commands {
+		names[i] = cmd.Name
+	}
+	return names
+}
+
+func TestShuffle_FixedSeedIsDeterministic(t *testing.T) {
+	first := commandNames(Shuffle(shuffleQueue(), 42))
+	second := commandNames(Shuffle(shuffleQueue(), 42))
+	if !slices.Equal(first, second) {
+		t.Errorf("Expected the same order for the same seed, got %v and %v", first, second)
+	}
+
+	original := commandNames(shuffleQueue())
+	sorted := slices.Sorted(slices.Values(first))
+	if !slices.Equal(sorted, slices.Sorted(slices.Values(original))) {
+		t.Fatalf("Expected a permutation of the queue, got %v", first)
+	}
+
+	differs := false
+	for seed := range int64(20) {
+		if !slices.Equal(commandNames(Shuffle(shuffleQueue(), seed)), original) {
+			differs = true
+			break
+		}
+	}
+	if !differs {
+		t.Error("Expected some seed to change the order")
+	}
+}
+
+func TestShuffle_KeepsDependenciesAndGroups(t *testing.T) {
+	original := shuffleQueue()
+
+	for seed := range int64(200) {
+		shuffled := Shuffle(shuffleQueue(), seed)
+		names := commandNames(shuffled)
+
+		if slices.Index(names, "db") > slices.Index(names, "api") {
+			t.Fatalf("Seed %d: expected api after db, which it waits for, got %v", seed, names)
+		}
+
+		// Every position keeps its kind, so concurrent groups neither split nor merge
+		for i := range shuffled {
+			if shuffled[i].Concurrent != original[i].Concurrent {
+				t.Fatalf("Seed %d: expected position %d to stay concurrent %v, got %v", seed, i, original[i].Concurrent, names)
+			}
+		}
+		for _, group := range [][]string{{"api", "worker"}, {"lint", "test"}} {
+			a, b := slices.Index(names, group[0]), slices.Index(names, group[1])
+			if a-b != 1 && b-a != 1 {
+				t.Fatalf("Seed %d: expected %v to stay together, got %v", seed, group, names)
+			}
+		}
+	}
+}
@@ -208,7 +208,7 @@ func (tg *TemplateGenerator) getStringFormatTemplate() string {
 				"Good for simple commands without complex arguments",
 			},
 			"cons": []string{
-				"Shell parsing can be tricky with quotes and spaces",
+				"Only quoting is shell-like: no variables, globs or pipes without \"sh -c\"",
 				"Less structured than object format",
 			},
 		},
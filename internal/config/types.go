@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 )
 
 type Mode string
@@ -21,8 +22,167 @@ type Command struct {
 	WorkDir    string            `json:"workDir,omitempty"`
 	Env        map[string]string `json:"env,omitempty"`
 	Concurrent bool              `json:"concurrent,omitempty"` // Allow concurrent execution with other concurrent commands
+
+	StopSignals []StopSignal  `json:"stopSignals,omitempty"` // Shutdown escalation walked before the final SIGKILL
+	MaxLifetime time.Duration `json:"maxLifetime,omitempty"` // KeepAlive only: stop the process gracefully once it has run this long
+	CacheKey    []string      `json:"cacheKey,omitempty"`    // Once only: input files or globs; the command is skipped while they are unchanged since its last success
+	Filter      []string      `json:"filter,omitempty"`      // Once only: command and args whose stdin receives the command's stdout; its output is what is streamed and captured
+
+	EnvFromFile map[string]string `json:"envFromFile,omitempty"` // Env var name -> file whose trimmed content becomes its value when the command starts, from "env": {"KEY": {"fromFile": "path"}}
+
+	// NoProcessGroup starts the command in seqr's process group instead of its own, for sandboxes
+	// that forbid setpgid. Stopping it then signals only the process, not its children.
+	NoProcessGroup bool `json:"noProcessGroup,omitempty"`
+
+	// PrefixOutput prefixes each line of the captured output, ExecutionResult.Output, with
+	// "[name] " so lines stay attributable once aggregated with other commands' output
+	PrefixOutput bool `json:"prefixOutput,omitempty"`
+
+	// Stage names the phase the command belongs to, such as "build" or "test". The console
+	// prints a "=== Stage: name ===" header whenever the stage changes; it has no other effect.
+	Stage string `json:"stage,omitempty"`
+
+	// PTY connects a once command's stdin, stdout and stderr to a pseudo-terminal instead of
+	// pipes, for tools that only behave interactively on a terminal. Unix only.
+	PTY bool `json:"pty,omitempty"`
+
+	// Ports lists the TCP ports the command binds. Validation rejects two commands of the same
+	// concurrent group declaring the same port, since they would race for it.
+	Ports []int `json:"ports,omitempty"`
+
+	// ArgsFile names a file listing more arguments, one per line, appended after Args when the
+	// command starts. Blank lines and lines starting with # are skipped; a relative path is
+	// resolved against WorkDir.
+	ArgsFile string `json:"argsFile,omitempty"`
+
+	// ReadyFile names a file a keepAlive command creates once it is ready. After starting the
+	// process the executor waits for the file, up to ReadyTimeout, before moving on; a stale copy
+	// is removed first. A relative path is resolved against WorkDir.
+	ReadyFile string `json:"readyFile,omitempty"`
+	// ReadyTCP is a host:port a keepAlive command accepts connections on once it is ready. After
+	// starting the process, and after its ReadyFile if it sets both, the executor dials it until a
+	// connection succeeds, up to ReadyTimeout.
+	ReadyTCP     string        `json:"readyTCP,omitempty"`
+	ReadyTimeout time.Duration `json:"readyTimeout,omitempty"` // Covers ReadyFile and ReadyTCP together; zero uses DefaultReadyTimeout
+
+	// LogMaxSize rotates the command's background log, kept when running verbosely, once it would
+	// grow past this many bytes: name.log moves to name.log.1, name.log.1 to name.log.2 and so on,
+	// keeping at most LogMaxFiles rotated files. Zero lets the log grow without bound.
+	LogMaxSize  int64 `json:"logMaxSize,omitempty"`
+	LogMaxFiles int   `json:"logMaxFiles,omitempty"` // Zero uses DefaultLogMaxFiles
+
+	// Encoding names the character set the command writes its output in, such as "utf-16le" or
+	// "windows-1252". Its output is decoded to UTF-8 before it is streamed and captured. Empty
+	// passes output through as UTF-8.
+	Encoding string `json:"encoding,omitempty"`
+
+	// AfterReady names keepAlive commands the command waits for until they are ready: started,
+	// and past their readyFile and readyTCP if they set them. Each must start earlier in the queue
+	// or in the same concurrent group, where the command holds off until they are ready.
+	AfterReady []string `json:"afterReady,omitempty"`
+
+	// ExpectOutput fails a once command that exits 0 unless its captured output contains this
+	// substring, or matches it as a regular expression with ExpectOutputRegex, as set by
+	// "expectOutput": {"regex": "pattern"}
+	ExpectOutput      string `json:"expectOutput,omitempty"`
+	ExpectOutputRegex bool   `json:"expectOutputRegex,omitempty"`
+
+	// MemoryLimit caps the virtual memory of the command's process, in bytes, through
+	// RLIMIT_AS, so a runaway build fails instead of exhausting the host. Allocations past it
+	// fail. It is not applied on Windows and macOS. Zero means unlimited.
+	MemoryLimit int64 `json:"memoryLimit,omitempty"`
+
+	// GoldenFile fails a once command that exits 0 unless its captured output is exactly the
+	// content of this file, with a diff of the two in the error. Relative paths resolve against
+	// the directory of the config file. seqr --update-golden rewrites it with the output instead.
+	GoldenFile string `json:"goldenFile,omitempty"`
+
+	// Priority orders the commands of a concurrent group that the concurrency limit holds back:
+	// when a slot frees up, the waiting command with the highest priority starts, the earliest
+	// declared among equals. It has no effect without a limit. Zero is the default.
+	Priority int `json:"priority,omitempty"`
+
+	// Matrix runs the command once per value, as concurrent commands named name-<value> with
+	// ${MATRIX_VALUE} in their command, args, workDir and env values replaced by the value. The
+	// normalizer expands it, so loaded configurations hold the instances rather than the matrix.
+	Matrix []string `json:"matrix,omitempty"`
+
+	// MatrixOf is the name of the matrix command an instance was expanded from, empty for other
+	// commands. The instances of a matrix form a concurrent group of their own.
+	MatrixOf string `json:"-"`
+
+	// AllowFailure records the command's failure without failing the run: the commands after it
+	// still start and the run can still succeed, while other commands stay fail-fast.
+	AllowFailure bool `json:"allowFailure,omitempty"`
+
+	// DedupeOutput collapses runs of identical consecutive lines in the streamed output into the
+	// first of them and a "(last line repeated N times)" note. Captured output keeps every line.
+	DedupeOutput bool `json:"dedupeOutput,omitempty"`
+
+	// CreateWorkDir creates WorkDir, along with any missing parents, before the command starts,
+	// rather than failing it when the directory does not exist
+	CreateWorkDir bool `json:"createWorkDir,omitempty"`
+
+	// Warmup runs the command like any other, failing the run if it fails, but leaves it out of
+	// the run's statistics: the total and completed counts and the timing summary
+	Warmup bool `json:"warmup,omitempty"`
+
+	// OutputFifo names an existing FIFO the command's output is also written to, line by line, for
+	// an external log processor reading from it. A relative path is resolved against WorkDir. It
+	// is only supported on Unix.
+	OutputFifo string `json:"outputFifo,omitempty"`
+
+	// ShowLines and HideLines are regular expressions that thin out the streamed output: only
+	// lines matching ShowLines are shown, and lines matching HideLines are not. The captured
+	// output keeps every line unless FilterCapturedLines is set.
+	ShowLines           string `json:"showLines,omitempty"`
+	HideLines           string `json:"hideLines,omitempty"`
+	FilterCapturedLines bool   `json:"filterCapturedLines,omitempty"`
+
+	// Meta is free-form metadata such as an owner or a ticket ID. seqr does not act on it, only
+	// carries it into the command's results and the reports made from them.
+	Meta map[string]string `json:"meta,omitempty"`
+
+	// ExternalReady is a service seqr does not start, such as a shared database, that the command
+	// waits for before it runs
+	ExternalReady *ExternalReady `json:"externalReady,omitempty"`
+}
+
+// ConcurrentWith reports whether cmd runs in the same concurrent group as prev, the command before
+// it in the queue. A group is a run of consecutive concurrent commands, except that the instances
+// of a matrix form a group of their own.
+func ConcurrentWith(prev, cmd Command) bool {
+	return prev.Concurrent && cmd.Concurrent && prev.MatrixOf == cmd.MatrixOf
 }
 
+// StopSignal is one step of a command's shutdown escalation
+type StopSignal struct {
+	Signal  string        `json:"signal"`
+	Timeout time.Duration `json:"timeout,omitempty"` // How long to wait for exit before escalating; zero uses DefaultStopTimeout
+}
+
+// ExternalReady tells when a service that seqr does not manage is ready. Every check that is set
+// must pass, and they are retried until Timeout.
+type ExternalReady struct {
+	ReadyTCP  string        `json:"readyTCP,omitempty"`  // A host:port that must accept a TCP connection
+	ReadyFile string        `json:"readyFile,omitempty"` // A file that must exist, resolved against the command's WorkDir
+	Command   []string      `json:"command,omitempty"`   // A check, run without a shell, that must exit 0
+	Timeout   time.Duration `json:"timeout,omitempty"`   // Zero uses DefaultReadyTimeout
+}
+
+// DefaultStopTimeout is how long a stop signal is given to take effect before escalating
+const DefaultStopTimeout = 5 * time.Second
+
+// DefaultReadyTimeout is how long a keepAlive command is given to create its readyFile or accept
+// connections on its readyTCP address
+const DefaultReadyTimeout = 30 * time.Second
+
+// DefaultLogMaxFiles is how many rotated background logs are kept for a command with a logMaxSize
+const DefaultLogMaxFiles = 5
+
+// StopSignalNames lists the signals that may be used in a stopSignals escalation
+var StopSignalNames = []string{"SIGTERM", "SIGINT", "SIGHUP", "SIGQUIT", "SIGUSR1", "SIGUSR2"}
+
 // FlexibleCommand represents a command that can be parsed from multiple formats
 type FlexibleCommand struct {
 	Name       string            `json:"name,omitempty"`
@@ -40,10 +200,21 @@ type FlexibleConfig struct {
 }
 
 type Config struct {
-	Version  string    `json:"version"`
-	Commands []Command `json:"commands"`
+	Version          string              `json:"version"`              // Schema version of the configuration format
+	MinVersion       string              `json:"minVersion,omitempty"` // Oldest seqr release that can run the configuration
+	Commands         []Command           `json:"commands"`
+	SignalForwarding map[string][]string `json:"signalForwarding,omitempty"` // Parent signal name -> keepAlive command names to relay it to
+	MaxConcurrency   int                 `json:"maxConcurrency,omitempty"`   // Upper bound on commands running at once within a concurrent group; zero means unbounded
+	NoProcessGroup   bool                `json:"noProcessGroup,omitempty"`   // Start every command without its own process group, as if each set noProcessGroup
+	PrefixOutput     bool                `json:"prefixOutput,omitempty"`     // Prefix every command's captured output lines with its name, as if each set prefixOutput
+	DefaultMode      Mode                `json:"defaultMode,omitempty"`      // Mode of commands that do not set one; empty means ModeOnce
+	Banner           string              `json:"banner,omitempty"`           // Message printed when the run starts
+	Footer           string              `json:"footer,omitempty"`           // Message printed when the run succeeds
 }
 
+// ForwardableSignals lists the parent signal names that may be relayed to keepAlive processes
+var ForwardableSignals = []string{"SIGUSR1", "SIGUSR2"}
+
 func (c *Config) Validate() error {
 	validator := NewValidator()
 	return validator.ValidateConfig(c)
@@ -59,7 +230,7 @@ func (c *Command) Validate() error {
 	}
 
 	if c.Mode != ModeOnce && c.Mode != ModeKeepAlive {
-		return fmt.Errorf("mode must be either 'once' or 'keepAlive', got '%s'", c.Mode)
+		return fmt.Errorf("command '%s': mode must be either 'once' or 'keepAlive', got '%s'", c.Name, c.Mode)
 	}
 
 	return nil
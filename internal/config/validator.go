@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -45,6 +46,10 @@ type Validator struct {
 	StrictMode       bool
 	ValidateWorkDirs bool
 	ValidateCommands bool
+
+	// BaseDir is the directory relative workDirs are resolved against when ValidateWorkDirs
+	// is set, normally the directory containing the config file. Empty means the current directory.
+	BaseDir string
 }
 
 func NewValidator() *Validator {
@@ -74,6 +79,12 @@ func (v *Validator) ValidateConfig(config *Config) error {
 		errors = append(errors, ValidationError{Field: "version", Value: config.Version, Message: err.Error()})
 	}
 
+	if config.MinVersion != "" {
+		if _, ok := parseReleaseVersion(config.MinVersion); !ok {
+			errors = append(errors, ValidationError{Field: "minVersion", Value: config.MinVersion, Message: fmt.Sprintf("minVersion '%s' is not a valid seqr version (expected format: X.Y or X.Y.Z)", config.MinVersion)})
+		}
+	}
+
 	if err := v.validateCommandsArray(config.Commands); err != nil {
 		errors = append(errors, ValidationError{Field: "commands", Message: err.Error()})
 	}
@@ -91,6 +102,20 @@ func (v *Validator) ValidateConfig(config *Config) error {
 		errors = append(errors, ValidationError{Field: "commands", Message: err.Error()})
 	}
 
+	if config.DefaultMode != "" {
+		if err := v.validateMode(config.DefaultMode); err != nil {
+			errors = append(errors, ValidationError{Field: "defaultMode", Value: config.DefaultMode, Message: fmt.Sprintf("invalid defaultMode: %v", err)})
+		}
+	}
+
+	if config.MaxConcurrency < 0 {
+		errors = append(errors, ValidationError{Field: "maxConcurrency", Value: config.MaxConcurrency, Message: "maxConcurrency cannot be negative"})
+	}
+
+	errors = append(errors, v.validateSignalForwarding(config.SignalForwarding, config.Commands)...)
+	errors = append(errors, v.validatePortConflicts(config.Commands)...)
+	errors = append(errors, v.validateAfterReady(config.Commands)...)
+
 	if len(errors) > 0 {
 		return errors
 	}
@@ -152,7 +177,7 @@ func (v *Validator) validateCommand(cmd *Command) ValidationErrors {
 	}
 
 	if err := v.validateMode(cmd.Mode); err != nil {
-		errors = append(errors, ValidationError{Field: "mode", Value: cmd.Mode, Message: err.Error()})
+		errors = append(errors, ValidationError{Field: "mode", Value: cmd.Mode, Message: fmt.Sprintf("command '%s': %s", cmd.Name, err.Error())})
 	}
 
 	if err := v.validateArgs(cmd.Args); err != nil {
@@ -160,15 +185,134 @@ func (v *Validator) validateCommand(cmd *Command) ValidationErrors {
 	}
 
 	if cmd.WorkDir != "" {
-		if err := v.validateWorkDir(cmd.WorkDir); err != nil {
+		// A workDir the command creates need not exist beforehand
+		workDirValidator := v
+		if cmd.CreateWorkDir && v.ValidateWorkDirs {
+			relaxed := *v
+			relaxed.ValidateWorkDirs = false
+			workDirValidator = &relaxed
+		}
+		if err := workDirValidator.validateWorkDir(cmd.WorkDir); err != nil {
 			errors = append(errors, ValidationError{Field: "workDir", Value: cmd.WorkDir, Message: err.Error()})
 		}
+	} else if cmd.CreateWorkDir {
+		errors = append(errors, ValidationError{Field: "createWorkDir", Value: true, Message: "createWorkDir requires a workDir"})
 	}
 
 	if err := v.validateEnv(cmd.Env); err != nil {
 		errors = append(errors, ValidationError{Field: "env", Message: err.Error()})
 	}
 
+	if cmd.MaxLifetime < 0 {
+		errors = append(errors, ValidationError{Field: "maxLifetime", Value: cmd.MaxLifetime, Message: "maxLifetime cannot be negative"})
+	} else if cmd.MaxLifetime > 0 && cmd.Mode != ModeKeepAlive {
+		errors = append(errors, ValidationError{Field: "maxLifetime", Value: cmd.MaxLifetime, Message: fmt.Sprintf("command '%s': maxLifetime only applies to keepAlive commands", cmd.Name)})
+	}
+
+	if len(cmd.CacheKey) > 0 && cmd.Mode != ModeOnce {
+		errors = append(errors, ValidationError{Field: "cacheKey", Message: fmt.Sprintf("command '%s': cacheKey only applies to once commands", cmd.Name)})
+	}
+	if len(cmd.Filter) > 0 && cmd.Mode != ModeOnce {
+		errors = append(errors, ValidationError{Field: "filter", Message: fmt.Sprintf("command '%s': filter only applies to once commands", cmd.Name)})
+	}
+	if cmd.PTY && cmd.Mode != ModeOnce {
+		errors = append(errors, ValidationError{Field: "pty", Message: fmt.Sprintf("command '%s': pty only applies to once commands", cmd.Name)})
+	} else if cmd.PTY && len(cmd.Filter) > 0 {
+		errors = append(errors, ValidationError{Field: "pty", Message: fmt.Sprintf("command '%s': pty cannot be combined with filter", cmd.Name)})
+	}
+
+	if cmd.ReadyFile != "" && cmd.Mode != ModeKeepAlive {
+		errors = append(errors, ValidationError{Field: "readyFile", Message: fmt.Sprintf("command '%s': readyFile only applies to keepAlive commands", cmd.Name)})
+	}
+	if cmd.ReadyTCP != "" {
+		if cmd.Mode != ModeKeepAlive {
+			errors = append(errors, ValidationError{Field: "readyTCP", Message: fmt.Sprintf("command '%s': readyTCP only applies to keepAlive commands", cmd.Name)})
+		} else if _, port, err := net.SplitHostPort(cmd.ReadyTCP); err != nil || port == "" {
+			errors = append(errors, ValidationError{Field: "readyTCP", Value: cmd.ReadyTCP, Message: fmt.Sprintf("command '%s': readyTCP must be a host:port address", cmd.Name)})
+		}
+	}
+	if cmd.ReadyTimeout < 0 {
+		errors = append(errors, ValidationError{Field: "readyTimeout", Value: cmd.ReadyTimeout, Message: "readyTimeout cannot be negative"})
+	} else if cmd.ReadyTimeout > 0 && cmd.ReadyFile == "" && cmd.ReadyTCP == "" {
+		errors = append(errors, ValidationError{Field: "readyTimeout", Value: cmd.ReadyTimeout, Message: fmt.Sprintf("command '%s': readyTimeout requires readyFile or readyTCP", cmd.Name)})
+	}
+	if external := cmd.ExternalReady; external != nil {
+		if external.ReadyTCP == "" && external.ReadyFile == "" && len(external.Command) == 0 {
+			errors = append(errors, ValidationError{Field: "externalReady", Message: fmt.Sprintf("command '%s': externalReady needs readyTCP, readyFile or command", cmd.Name)})
+		}
+		if external.ReadyTCP != "" {
+			if _, port, err := net.SplitHostPort(external.ReadyTCP); err != nil || port == "" {
+				errors = append(errors, ValidationError{Field: "externalReady.readyTCP", Value: external.ReadyTCP, Message: fmt.Sprintf("command '%s': externalReady readyTCP must be a host:port address", cmd.Name)})
+			}
+		}
+		if external.Timeout < 0 {
+			errors = append(errors, ValidationError{Field: "externalReady.timeout", Value: external.Timeout, Message: "externalReady timeout cannot be negative"})
+		}
+	}
+
+	if cmd.Encoding != "" {
+		if _, err := LookupEncoding(cmd.Encoding); err != nil {
+			errors = append(errors, ValidationError{Field: "encoding", Value: cmd.Encoding, Message: fmt.Sprintf("command '%s': %v", cmd.Name, err)})
+		} else if len(cmd.Filter) > 0 {
+			errors = append(errors, ValidationError{Field: "encoding", Message: fmt.Sprintf("command '%s': encoding cannot be combined with filter, whose output is what is shown", cmd.Name)})
+		}
+	}
+
+	if cmd.ExpectOutput != "" && cmd.Mode != ModeOnce {
+		errors = append(errors, ValidationError{Field: "expectOutput", Message: fmt.Sprintf("command '%s': expectOutput only applies to once commands", cmd.Name)})
+	} else if cmd.ExpectOutputRegex {
+		if _, err := regexp.Compile(cmd.ExpectOutput); err != nil {
+			errors = append(errors, ValidationError{Field: "expectOutput", Value: cmd.ExpectOutput, Message: fmt.Sprintf("command '%s': invalid expectOutput pattern: %v", cmd.Name, err)})
+		}
+	}
+
+	if _, err := regexp.Compile(cmd.ShowLines); err != nil {
+		errors = append(errors, ValidationError{Field: "showLines", Value: cmd.ShowLines, Message: fmt.Sprintf("command '%s': invalid showLines pattern: %v", cmd.Name, err)})
+	}
+	if _, err := regexp.Compile(cmd.HideLines); err != nil {
+		errors = append(errors, ValidationError{Field: "hideLines", Value: cmd.HideLines, Message: fmt.Sprintf("command '%s': invalid hideLines pattern: %v", cmd.Name, err)})
+	}
+	if cmd.FilterCapturedLines && cmd.ShowLines == "" && cmd.HideLines == "" {
+		errors = append(errors, ValidationError{Field: "filterCapturedLines", Message: fmt.Sprintf("command '%s': filterCapturedLines requires showLines or hideLines", cmd.Name)})
+	}
+
+	if cmd.MemoryLimit < 0 {
+		errors = append(errors, ValidationError{Field: "memoryLimit", Value: cmd.MemoryLimit, Message: "memoryLimit cannot be negative"})
+	}
+
+	if cmd.GoldenFile != "" && cmd.Mode != ModeOnce {
+		errors = append(errors, ValidationError{Field: "goldenFile", Message: fmt.Sprintf("command '%s': goldenFile only applies to once commands", cmd.Name)})
+	}
+
+	if cmd.LogMaxSize < 0 {
+		errors = append(errors, ValidationError{Field: "logMaxSize", Value: cmd.LogMaxSize, Message: "logMaxSize cannot be negative"})
+	}
+	if cmd.LogMaxFiles < 0 {
+		errors = append(errors, ValidationError{Field: "logMaxFiles", Value: cmd.LogMaxFiles, Message: "logMaxFiles cannot be negative"})
+	} else if cmd.LogMaxFiles > 0 && cmd.LogMaxSize == 0 {
+		errors = append(errors, ValidationError{Field: "logMaxFiles", Value: cmd.LogMaxFiles, Message: fmt.Sprintf("command '%s': logMaxFiles requires logMaxSize", cmd.Name)})
+	}
+
+	for i, port := range cmd.Ports {
+		if port < 1 || port > 65535 {
+			errors = append(errors, ValidationError{Field: fmt.Sprintf("ports[%d]", i), Value: port, Message: fmt.Sprintf("port %d is out of range 1-65535", port)})
+		}
+	}
+
+	for i, pattern := range cmd.CacheKey {
+		if strings.TrimSpace(pattern) == "" {
+			errors = append(errors, ValidationError{Field: fmt.Sprintf("cacheKey[%d]", i), Value: pattern, Message: "cacheKey entries cannot be empty"})
+		} else if _, err := filepath.Match(pattern, ""); err != nil {
+			errors = append(errors, ValidationError{Field: fmt.Sprintf("cacheKey[%d]", i), Value: pattern, Message: fmt.Sprintf("invalid glob pattern '%s': %v", pattern, err)})
+		}
+	}
+
+	for i, step := range cmd.StopSignals {
+		if err := v.validateStopSignal(step); err != nil {
+			errors = append(errors, ValidationError{Field: fmt.Sprintf("stopSignals[%d]", i), Value: step.Signal, Message: err.Error()})
+		}
+	}
+
 	return errors
 }
 
@@ -221,6 +365,16 @@ func (v *Validator) validateMode(mode Mode) error {
 	return nil
 }
 
+func (v *Validator) validateStopSignal(step StopSignal) error {
+	if !slices.Contains(StopSignalNames, step.Signal) {
+		return fmt.Errorf("signal '%s' cannot be used as a stop signal, supported signals are: %s", step.Signal, strings.Join(StopSignalNames, ", "))
+	}
+	if step.Timeout < 0 {
+		return fmt.Errorf("stop signal timeout cannot be negative, got %s", step.Timeout)
+	}
+	return nil
+}
+
 func (v *Validator) validateArgs(args []string) error {
 	if v.StrictMode {
 		const maxArgs = 50
@@ -255,11 +409,20 @@ func (v *Validator) validateWorkDir(workDir string) error {
 	}
 
 	if v.ValidateWorkDirs {
-		if _, err := os.Stat(cleanPath); err != nil {
+		resolvedPath := cleanPath
+		if v.BaseDir != "" && !filepath.IsAbs(resolvedPath) {
+			resolvedPath = filepath.Join(v.BaseDir, resolvedPath)
+		}
+
+		info, err := os.Stat(resolvedPath)
+		if err != nil {
 			if os.IsNotExist(err) {
-				return fmt.Errorf("workDir '%s' does not exist", cleanPath)
+				return fmt.Errorf("workDir '%s' does not exist", resolvedPath)
 			}
-			return fmt.Errorf("cannot access workDir '%s': %v", cleanPath, err)
+			return fmt.Errorf("cannot access workDir '%s': %v", resolvedPath, err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("workDir '%s' is not a directory", resolvedPath)
 		}
 	}
 
@@ -308,3 +471,133 @@ func (v *Validator) validateCommandNameUniqueness(commands []Command) error {
 
 	return nil
 }
+
+func (v *Validator) validateSignalForwarding(forwarding map[string][]string, commands []Command) ValidationErrors {
+	var errors ValidationErrors
+
+	modes := make(map[string]Mode)
+	for _, cmd := range commands {
+		modes[cmd.Name] = cmd.Mode
+	}
+
+	for signalName, targets := range forwarding {
+		field := fmt.Sprintf("signalForwarding.%s", signalName)
+
+		if !slices.Contains(ForwardableSignals, signalName) {
+			errors = append(errors, ValidationError{
+				Field:   field,
+				Value:   signalName,
+				Message: fmt.Sprintf("signal '%s' cannot be forwarded, supported signals are: %s", signalName, strings.Join(ForwardableSignals, ", ")),
+			})
+			continue
+		}
+
+		for _, target := range targets {
+			mode, exists := modes[target]
+			if !exists {
+				errors = append(errors, ValidationError{Field: field, Value: target, Message: fmt.Sprintf("unknown target command '%s'", target)})
+			} else if mode != ModeKeepAlive {
+				errors = append(errors, ValidationError{Field: field, Value: target, Message: fmt.Sprintf("target command '%s' must use keepAlive mode", target)})
+			}
+		}
+	}
+
+	return errors
+}
+
+// validatePortConflicts rejects two commands of the same concurrent group, a run of consecutive
+// concurrent commands, that declare the same port
+func (v *Validator) validatePortConflicts(commands []Command) ValidationErrors {
+	var errors ValidationErrors
+
+	owners := make(map[int]string) // Port -> command of the current group declaring it
+	for i, cmd := range commands {
+		if i == 0 || !ConcurrentWith(commands[i-1], cmd) {
+			clear(owners)
+		}
+		if !cmd.Concurrent {
+			continue
+		}
+
+		for _, port := range cmd.Ports {
+			if owner, taken := owners[port]; taken && owner != cmd.Name {
+				errors = append(errors, ValidationError{
+					Field:   fmt.Sprintf("commands[%d].ports", i),
+					Value:   port,
+					Message: fmt.Sprintf("commands '%s' and '%s' run concurrently and both declare port %d", owner, cmd.Name, port),
+				})
+			} else {
+				owners[port] = cmd.Name
+			}
+		}
+	}
+
+	return errors
+}
+
+// validateAfterReady checks that each afterReady dependency is a keepAlive command starting
+// earlier in the queue or in the same concurrent group, and that no commands of a group wait for
+// each other in a cycle
+func (v *Validator) validateAfterReady(commands []Command) ValidationErrors {
+	var errors ValidationErrors
+
+	// Groups match the executor's: each sequential command, or run of consecutive concurrent ones
+	groups := make([]int, len(commands))
+	indexes := make(map[string]int, len(commands))
+	group := -1
+	for i, cmd := range commands {
+		if i == 0 || !ConcurrentWith(commands[i-1], cmd) {
+			group++
+		}
+		groups[i] = group
+		indexes[cmd.Name] = i
+	}
+
+	// Dependencies within each command's own group, the only ones that can form a cycle
+	sameGroup := make([][]int, len(commands))
+	for i, cmd := range commands {
+		field := fmt.Sprintf("commands[%d].afterReady", i)
+		for _, name := range cmd.AfterReady {
+			j, exists := indexes[name]
+			switch {
+			case !exists:
+				errors = append(errors, ValidationError{Field: field, Value: name, Message: fmt.Sprintf("command '%s': unknown afterReady command '%s'", cmd.Name, name)})
+			case j == i:
+				errors = append(errors, ValidationError{Field: field, Value: name, Message: fmt.Sprintf("command '%s': cannot wait for its own readiness", cmd.Name)})
+			case commands[j].Mode != ModeKeepAlive:
+				errors = append(errors, ValidationError{Field: field, Value: name, Message: fmt.Sprintf("command '%s': afterReady command '%s' must use keepAlive mode", cmd.Name, name)})
+			case groups[j] > groups[i]:
+				errors = append(errors, ValidationError{Field: field, Value: name, Message: fmt.Sprintf("command '%s': afterReady command '%s' starts later in the queue", cmd.Name, name)})
+			case groups[j] == groups[i]:
+				sameGroup[i] = append(sameGroup[i], j)
+			}
+		}
+	}
+
+	for i, cmd := range commands {
+		if waitsFor(sameGroup, i, i, make([]bool, len(commands))) {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("commands[%d].afterReady", i),
+				Message: fmt.Sprintf("command '%s': afterReady dependencies wait for each other in a cycle", cmd.Name),
+			})
+		}
+	}
+
+	return errors
+}
+
+// waitsFor reports whether command from waits, directly or through other dependencies, for target
+func waitsFor(dependencies [][]int, from, target int, visited []bool) bool {
+	for _, dep := range dependencies[from] {
+		if dep == target {
+			return true
+		}
+		if !visited[dep] {
+			visited[dep] = true
+			if waitsFor(dependencies, dep, target, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -2,9 +2,11 @@ package config
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestValidator_ValidateConfig(t *testing.T) {
@@ -98,6 +100,469 @@ func TestValidator_ValidateConfig(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:      "invalid mode names the command",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "setup", Command: "echo", Mode: ModeOnce},
+					{Name: "deploy", Command: "echo", Mode: Mode("invalid")},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "command 'deploy': mode must be either 'once' or 'keepAlive'",
+		},
+		{
+			name:      "negative max concurrency",
+			validator: NewValidator(),
+			config: &Config{
+				Version:        "1.0",
+				Commands:       []Command{{Name: "lint", Command: "npm", Mode: ModeOnce}},
+				MaxConcurrency: -2,
+			},
+			wantErr:   true,
+			errSubstr: "maxConcurrency cannot be negative",
+		},
+		{
+			name:      "unknown default mode",
+			validator: NewValidator(),
+			config: &Config{
+				Version:     "1.0",
+				Commands:    []Command{{Name: "lint", Command: "npm", Mode: ModeOnce}},
+				DefaultMode: "daemon",
+			},
+			wantErr:   true,
+			errSubstr: "invalid defaultMode: mode must be either 'once' or 'keepAlive', got 'daemon'",
+		},
+		{
+			name:      "unsupported stop signal",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "api", Command: "node", Mode: ModeKeepAlive, StopSignals: []StopSignal{{Signal: "SIGKILL"}}},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "cannot be used as a stop signal",
+		},
+		{
+			name:      "max lifetime on once command",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "build", Command: "make", Mode: ModeOnce, MaxLifetime: time.Minute},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "maxLifetime only applies to keepAlive commands",
+		},
+		{
+			name:      "ready file on once command",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "build", Command: "make", Mode: ModeOnce, ReadyFile: "ready"},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "readyFile only applies to keepAlive commands",
+		},
+		{
+			name:      "ready timeout without ready file",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "server", Command: "npm", Mode: ModeKeepAlive, ReadyTimeout: time.Second},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "readyTimeout requires readyFile",
+		},
+		{
+			name:      "ready TCP on once command",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "build", Command: "make", Mode: ModeOnce, ReadyTCP: "localhost:8080"},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "readyTCP only applies to keepAlive commands",
+		},
+		{
+			name:      "ready TCP without a port",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "server", Command: "npm", Mode: ModeKeepAlive, ReadyTCP: "localhost"},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "readyTCP must be a host:port address",
+		},
+		{
+			name:      "external ready without a check",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "migrate", Command: "make", Mode: ModeOnce, ExternalReady: &ExternalReady{Timeout: time.Minute}},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "externalReady needs readyTCP, readyFile or command",
+		},
+		{
+			name:      "external ready TCP without a port",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "migrate", Command: "make", Mode: ModeOnce, ExternalReady: &ExternalReady{ReadyTCP: "db.internal"}},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "externalReady readyTCP must be a host:port address",
+		},
+		{
+			name:      "negative log max size",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "server", Command: "npm", Mode: ModeKeepAlive, LogMaxSize: -1},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "logMaxSize cannot be negative",
+		},
+		{
+			name:      "log max files without log max size",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "server", Command: "npm", Mode: ModeKeepAlive, LogMaxFiles: 3},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "logMaxFiles requires logMaxSize",
+		},
+		{
+			name:      "known output encoding",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "legacy", Command: "tool.exe", Mode: ModeOnce, Encoding: "windows-1252"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:      "unknown output encoding",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "legacy", Command: "tool.exe", Mode: ModeOnce, Encoding: "klingon"},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "command 'legacy': unknown encoding 'klingon'",
+		},
+		{
+			name:      "output encoding with filter",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "legacy", Command: "tool.exe", Mode: ModeOnce, Encoding: "utf-16le", Filter: []string{"grep", "error"}},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "encoding cannot be combined with filter",
+		},
+		{
+			name:      "after ready on an earlier keepAlive command",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "db", Command: "postgres", Mode: ModeKeepAlive},
+					{Name: "api", Command: "node", Mode: ModeKeepAlive, Concurrent: true},
+					{Name: "worker", Command: "node", Mode: ModeKeepAlive, Concurrent: true, AfterReady: []string{"db", "api"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:      "after ready on an unknown command",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "migrate", Command: "make", Mode: ModeOnce, AfterReady: []string{"db"}},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "command 'migrate': unknown afterReady command 'db'",
+		},
+		{
+			name:      "after ready on a once command",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "build", Command: "make", Mode: ModeOnce},
+					{Name: "test", Command: "make", Mode: ModeOnce, AfterReady: []string{"build"}},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "afterReady command 'build' must use keepAlive mode",
+		},
+		{
+			name:      "after ready on a later command",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "migrate", Command: "make", Mode: ModeOnce, AfterReady: []string{"db"}},
+					{Name: "db", Command: "postgres", Mode: ModeKeepAlive},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "afterReady command 'db' starts later in the queue",
+		},
+		{
+			name:      "after ready cycle within a concurrent group",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "api", Command: "node", Mode: ModeKeepAlive, Concurrent: true, AfterReady: []string{"worker"}},
+					{Name: "worker", Command: "node", Mode: ModeKeepAlive, Concurrent: true, AfterReady: []string{"api"}},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "afterReady dependencies wait for each other in a cycle",
+		},
+		{
+			name:      "expected output on keepAlive command",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "server", Command: "node", Mode: ModeKeepAlive, ExpectOutput: "listening"},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "expectOutput only applies to once commands",
+		},
+		{
+			name:      "invalid expected output pattern",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "smoke", Command: "curl", Mode: ModeOnce, ExpectOutput: "v[0-9", ExpectOutputRegex: true},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "command 'smoke': invalid expectOutput pattern",
+		},
+		{
+			name:      "negative memory limit",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "build", Command: "make", Mode: ModeOnce, MemoryLimit: -1},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "memoryLimit cannot be negative",
+		},
+		{
+			name:      "golden file on keepAlive command",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "api", Command: "node", Mode: ModeKeepAlive, GoldenFile: "api.golden"},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "goldenFile only applies to once commands",
+		},
+		{
+			name:      "cache key on keepAlive command",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "api", Command: "node", Mode: ModeKeepAlive, CacheKey: []string{"*.js"}},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "cacheKey only applies to once commands",
+		},
+		{
+			name:      "filter on keepAlive command",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "api", Command: "node", Mode: ModeKeepAlive, Filter: []string{"grep", "-v", "DEBUG"}},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "filter only applies to once commands",
+		},
+		{
+			name:      "overlapping ports in a concurrent group",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "api", Command: "node", Mode: ModeKeepAlive, Concurrent: true, Ports: []int{3000, 9229}},
+					{Name: "web", Command: "vite", Mode: ModeKeepAlive, Concurrent: true, Ports: []int{3000}},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "commands 'api' and 'web' run concurrently and both declare port 3000",
+		},
+		{
+			name:      "distinct ports in a concurrent group",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "api", Command: "node", Mode: ModeKeepAlive, Concurrent: true, Ports: []int{3000, 9229}},
+					{Name: "web", Command: "vite", Mode: ModeKeepAlive, Concurrent: true, Ports: []int{5173}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:      "same port in separate concurrent groups",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "first", Command: "node", Mode: ModeOnce, Concurrent: true, Ports: []int{3000}},
+					{Name: "migrate", Command: "make", Mode: ModeOnce},
+					{Name: "second", Command: "node", Mode: ModeOnce, Concurrent: true, Ports: []int{3000}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:      "port out of range",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "api", Command: "node", Mode: ModeKeepAlive, Ports: []int{70000}},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "port 70000 is out of range 1-65535",
+		},
+		{
+			name:      "pty on keepAlive command",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "api", Command: "node", Mode: ModeKeepAlive, PTY: true},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "pty only applies to once commands",
+		},
+		{
+			name:      "pty with filter",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "build", Command: "make", Mode: ModeOnce, PTY: true, Filter: []string{"grep", "-v", "DEBUG"}},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "pty cannot be combined with filter",
+		},
+		{
+			name:      "malformed cache key pattern",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "build", Command: "make", Mode: ModeOnce, CacheKey: []string{"src/[a-"}},
+				},
+			},
+			wantErr:   true,
+			errSubstr: "invalid glob pattern",
+		},
+		{
+			name:      "valid signal forwarding",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "api", Command: "node", Mode: ModeKeepAlive},
+				},
+				SignalForwarding: map[string][]string{"SIGUSR1": {"api"}},
+			},
+			wantErr: false,
+		},
+		{
+			name:      "signal forwarding to unknown command",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "api", Command: "node", Mode: ModeKeepAlive},
+				},
+				SignalForwarding: map[string][]string{"SIGUSR1": {"worker"}},
+			},
+			wantErr:   true,
+			errSubstr: "unknown target command 'worker'",
+		},
+		{
+			name:      "signal forwarding to once command",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "build", Command: "make", Mode: ModeOnce},
+				},
+				SignalForwarding: map[string][]string{"SIGUSR2": {"build"}},
+			},
+			wantErr:   true,
+			errSubstr: "must use keepAlive mode",
+		},
+		{
+			name:      "signal forwarding of unsupported signal",
+			validator: NewValidator(),
+			config: &Config{
+				Version: "1.0",
+				Commands: []Command{
+					{Name: "api", Command: "node", Mode: ModeKeepAlive},
+				},
+				SignalForwarding: map[string][]string{"SIGKILL": {"api"}},
+			},
+			wantErr:   true,
+			errSubstr: "cannot be forwarded",
+		},
 	}
 
 	for _, tt := range tests {
@@ -388,6 +853,13 @@ func TestValidator_validateArgs(t *testing.T) {
 func TestValidator_validateWorkDir(t *testing.T) {
 	// Create a temporary directory for testing
 	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, "sub"), 0755); err != nil {
+		t.Fatalf("Failed to create sub directory: %v", err)
+	}
+	regularFile := filepath.Join(tmpDir, "file.txt")
+	if err := os.WriteFile(regularFile, []byte("x"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
 
 	tests := []struct {
 		name      string
@@ -436,6 +908,26 @@ func TestValidator_validateWorkDir(t *testing.T) {
 			wantErr:   true,
 			errSubstr: "does not exist",
 		},
+		{
+			name:      "file instead of directory with validation enabled",
+			validator: &Validator{ValidateWorkDirs: true},
+			workDir:   regularFile,
+			wantErr:   true,
+			errSubstr: "is not a directory",
+		},
+		{
+			name:      "relative path resolved against base dir",
+			validator: &Validator{ValidateWorkDirs: true, BaseDir: tmpDir},
+			workDir:   "sub",
+			wantErr:   false,
+		},
+		{
+			name:      "missing relative path resolved against base dir",
+			validator: &Validator{ValidateWorkDirs: true, BaseDir: tmpDir},
+			workDir:   "missing",
+			wantErr:   true,
+			errSubstr: filepath.Join(tmpDir, "missing"),
+		},
 	}
 
 	for _, tt := range tests {
@@ -456,6 +948,48 @@ func TestValidator_validateWorkDir(t *testing.T) {
 	}
 }
 
+func TestValidator_CreateWorkDir(t *testing.T) {
+	validator := &Validator{ValidateWorkDirs: true}
+	missing := filepath.Join(t.TempDir(), "out")
+
+	cmd := Command{Name: "build", Command: "make", Mode: ModeOnce, WorkDir: missing, CreateWorkDir: true}
+	if errs := validator.validateCommand(&cmd); len(errs) > 0 {
+		t.Errorf("Expected a missing workDir the command creates to pass, got %v", errs)
+	}
+
+	cmd.CreateWorkDir = false
+	if errs := validator.validateCommand(&cmd); len(errs) == 0 {
+		t.Error("Expected a missing workDir to fail without createWorkDir")
+	}
+
+	cmd = Command{Name: "build", Command: "make", Mode: ModeOnce, CreateWorkDir: true}
+	errs := validator.validateCommand(&cmd)
+	if len(errs) != 1 || !strings.Contains(errs[0].Message, "createWorkDir requires a workDir") {
+		t.Errorf("Expected createWorkDir without a workDir to fail, got %v", errs)
+	}
+}
+
+func TestValidator_LinePatterns(t *testing.T) {
+	validator := NewValidator()
+
+	cmd := Command{Name: "api", Command: "npm", Mode: ModeKeepAlive, ShowLines: `^(WARN|ERROR)`, HideLines: "healthcheck", FilterCapturedLines: true}
+	if errs := validator.validateCommand(&cmd); len(errs) > 0 {
+		t.Errorf("Expected valid line patterns to pass, got %v", errs)
+	}
+
+	cmd.HideLines = "([unclosed"
+	errs := validator.validateCommand(&cmd)
+	if len(errs) != 1 || errs[0].Field != "hideLines" || !strings.Contains(errs[0].Message, "invalid hideLines pattern") {
+		t.Errorf("Expected an invalid hideLines pattern to fail, got %v", errs)
+	}
+
+	cmd = Command{Name: "api", Command: "npm", Mode: ModeKeepAlive, FilterCapturedLines: true}
+	errs = validator.validateCommand(&cmd)
+	if len(errs) != 1 || !strings.Contains(errs[0].Message, "filterCapturedLines requires showLines or hideLines") {
+		t.Errorf("Expected filterCapturedLines without patterns to fail, got %v", errs)
+	}
+}
+
 func TestValidator_validateEnv(t *testing.T) {
 	tests := []struct {
 		name      string
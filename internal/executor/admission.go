@@ -0,0 +1,93 @@
+package executor
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// admission bounds how many commands of a concurrent group run at once. Commands queue for a
+// slot, and each slot that frees up goes to the waiting command with the highest priority, the
+// earliest declared among equals.
+type admission struct {
+	mu      sync.Mutex
+	free    int
+	waiting []*admissionTicket
+}
+
+// admissionTicket is a command's place in the admission queue
+type admissionTicket struct {
+	priority int
+	order    int           // Position of the command in its group
+	granted  chan struct{} // Closed once the command holds a slot
+}
+
+// newAdmission creates an admission queue with slots commands allowed to run at once
+func newAdmission(slots int) *admission {
+	return &admission{free: slots}
+}
+
+// enqueue queues a command for a slot without granting any, so that a batch of commands
+// queued together is admitted by priority rather than by who asked first. Call dispatch to
+// grant the free slots.
+func (a *admission) enqueue(priority, order int) *admissionTicket {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	ticket := &admissionTicket{priority: priority, order: order, granted: make(chan struct{})}
+	a.waiting = append(a.waiting, ticket)
+	return ticket
+}
+
+// dispatch grants the free slots to the waiting commands that come first
+func (a *admission) dispatch() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.dispatchLocked()
+}
+
+func (a *admission) dispatchLocked() {
+	for a.free > 0 && len(a.waiting) > 0 {
+		next := 0
+		for i, ticket := range a.waiting {
+			if ticket.priority > a.waiting[next].priority ||
+				(ticket.priority == a.waiting[next].priority && ticket.order < a.waiting[next].order) {
+				next = i
+			}
+		}
+
+		close(a.waiting[next].granted)
+		a.waiting = slices.Delete(a.waiting, next, next+1)
+		a.free--
+	}
+}
+
+// wait blocks until the ticket is granted a slot, or gives up its place when ctx ends first
+func (a *admission) wait(ctx context.Context, ticket *admissionTicket) error {
+	select {
+	case <-ticket.granted:
+		return nil
+	case <-ctx.Done():
+	}
+
+	a.mu.Lock()
+	if i := slices.Index(a.waiting, ticket); i >= 0 {
+		a.waiting = slices.Delete(a.waiting, i, i+1)
+		a.mu.Unlock()
+		return ctx.Err()
+	}
+	a.mu.Unlock()
+
+	// The slot was granted as ctx ended, pass it on
+	a.release()
+	return ctx.Err()
+}
+
+// release frees a granted slot for the next waiting command
+func (a *admission) release() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.free++
+	a.dispatchLocked()
+}
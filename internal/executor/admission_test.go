@@ -0,0 +1,38 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestAdmission_CancelledWaitGivesUpItsPlace(t *testing.T) {
+	slots := newAdmission(1)
+	running := slots.enqueue(10, 0)
+	cancelled := slots.enqueue(5, 1)
+	next := slots.enqueue(0, 2)
+	slots.dispatch()
+
+	if err := slots.wait(context.Background(), running); err != nil {
+		t.Fatalf("Expected the first ticket to get the free slot, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := slots.wait(ctx, cancelled); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the cancelled wait to fail, got %v", err)
+	}
+
+	// The slot freed by the running command goes past the cancelled ticket
+	slots.release()
+	select {
+	case <-next.granted:
+	default:
+		t.Fatal("Expected the released slot to go to the next waiting ticket")
+	}
+	select {
+	case <-cancelled.granted:
+		t.Error("Expected the cancelled ticket not to be granted a slot")
+	default:
+	}
+}
@@ -0,0 +1,88 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+// readySignal is closed once a command of the current run has finished starting, noting whether
+// it became ready
+type readySignal struct {
+	done  chan struct{}
+	ready bool
+}
+
+// resetReadySignals prepares a signal for each command of a run. Called with e.mu held.
+func (e *Executor) resetReadySignals(commands []config.Command) {
+	e.readySignals = make(map[string]*readySignal, len(commands))
+	for _, cmd := range commands {
+		e.readySignals[cmd.Name] = &readySignal{done: make(chan struct{})}
+	}
+}
+
+// signalReady records whether a command became ready, releasing the commands waiting for it.
+// Only the first call for a command counts.
+func (e *Executor) signalReady(name string, ready bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	signal, exists := e.readySignals[name]
+	if !exists {
+		return
+	}
+	select {
+	case <-signal.done:
+	default:
+		signal.ready = ready
+		close(signal.done)
+	}
+}
+
+// waitForAfterReady blocks until every keepAlive command a command names in afterReady is ready,
+// failing if one of them failed instead or ctx ends first
+func (e *Executor) waitForAfterReady(ctx context.Context, cmd config.Command) error {
+	for _, name := range cmd.AfterReady {
+		e.mu.RLock()
+		signal, exists := e.readySignals[name]
+		e.mu.RUnlock()
+		if !exists {
+			return fmt.Errorf("command '%s' waits for unknown command '%s'", cmd.Name, name)
+		}
+
+		select {
+		case <-signal.done:
+		default:
+			if e.verbose {
+				timestamp := time.Now().Format("15:04:05.000")
+				fmt.Printf("[%s] [%s] [process] Waiting for %s to be ready\n", timestamp, cmd.Name, name)
+			}
+		}
+
+		select {
+		case <-signal.done:
+		case <-ctx.Done():
+			return fmt.Errorf("stopped waiting for '%s' to be ready: %w", name, ctx.Err())
+		}
+		if !signal.ready {
+			return fmt.Errorf("command '%s' waits for '%s', which did not become ready", cmd.Name, name)
+		}
+	}
+	return nil
+}
+
+// afterReadyFailure is the result of a command that never ran because a command or external
+// service it waits for did not become ready
+func afterReadyFailure(cmd config.Command, err error) ExecutionResult {
+	now := time.Now()
+	return ExecutionResult{
+		Command:   cmd,
+		StartTime: now,
+		EndTime:   now,
+		Success:   false,
+		Error:     err.Error(),
+		ExitCode:  -1,
+	}
+}
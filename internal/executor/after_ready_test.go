@@ -0,0 +1,103 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestExecutor_AfterReady(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sh")
+	}
+
+	tests := []struct {
+		name           string
+		producer       string
+		maxConcurrency int
+		errSubstr      string
+	}{
+		{
+			name:     "consumer waits for the producer to be ready",
+			producer: "sleep 0.3; touch ready; sleep 30",
+		},
+		{
+			name:           "waiting does not hold the only concurrency slot",
+			producer:       "sleep 0.3; touch ready; sleep 30",
+			maxConcurrency: 1,
+		},
+		{
+			name:      "consumer fails when the producer never becomes ready",
+			producer:  "exit 0",
+			errSubstr: "command 'consumer' waits for 'producer', which did not become ready",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TMPDIR", t.TempDir())
+			dir := t.TempDir()
+
+			// The consumer is listed first and only succeeds once the ready file exists
+			cfg := &config.Config{
+				Version:        "1.0",
+				MaxConcurrency: tt.maxConcurrency,
+				Commands: []config.Command{
+					{Name: "consumer", Command: "sh", Args: []string{"-c", "test -f ready && touch consumed"}, Mode: config.ModeOnce, WorkDir: dir, Concurrent: true, AfterReady: []string{"producer"}},
+					{Name: "producer", Command: "sh", Args: []string{"-c", tt.producer}, Mode: config.ModeKeepAlive, WorkDir: dir, Concurrent: true, ReadyFile: "ready"},
+				},
+			}
+
+			executor := NewExecutor(false)
+			var err error
+			captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+			defer stopAndUntrack(executor)
+
+			_, consumedErr := os.Stat(filepath.Join(dir, "consumed"))
+			if tt.errSubstr != "" {
+				if err == nil {
+					t.Fatal("Expected execution to fail")
+				}
+				var consumer *ExecutionResult
+				results := executor.GetStatus().Results
+				for i := range results {
+					if results[i].Command.Name == "consumer" {
+						consumer = &results[i]
+					}
+				}
+				if consumer == nil || consumer.Success || !strings.Contains(consumer.Error, tt.errSubstr) {
+					t.Fatalf("Expected the consumer to fail with %q, got %+v", tt.errSubstr, consumer)
+				}
+				if !errors.Is(consumedErr, os.ErrNotExist) {
+					t.Error("Expected the consumer not to run")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Execute returned error: %v", err)
+			}
+			if consumedErr != nil {
+				t.Errorf("Expected the consumer to run once the producer was ready: %v", consumedErr)
+			}
+		})
+	}
+}
+
+// stopAndUntrack stops the executor's processes and waits for them to be untracked. The tracker
+// rewrites its state file once a stopped process exits, after Stop returns, which would race
+// the removal of the test's TMPDIR.
+func stopAndUntrack(executor *Executor) {
+	captureOutput(executor.Stop)
+	deadline := time.Now().Add(5 * time.Second)
+	for executor.tracker.GetRunningProcessCount() > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,134 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestExecutor_AllowFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires the false command")
+	}
+
+	tests := []struct {
+		name       string
+		concurrent bool
+	}{
+		{name: "sequential"},
+		{name: "concurrent", concurrent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Version: "1.0",
+				Commands: []config.Command{
+					{Name: "flaky", Command: "false", Mode: config.ModeOnce, AllowFailure: true, Concurrent: tt.concurrent},
+					{Name: "build", Command: "echo", Args: []string{"built"}, Mode: config.ModeOnce, Concurrent: tt.concurrent},
+					{Name: "after", Command: "echo", Args: []string{"still runs"}, Mode: config.ModeOnce},
+				},
+			}
+
+			var out bytes.Buffer
+			executor := NewExecutorWithOptions(ExecutorOptions{Reporter: NewConsoleReporterWithWriters(&out, &out, false)})
+			if err := executor.Execute(context.Background(), cfg); err != nil {
+				t.Fatalf("Expected the allowed failure not to fail the run, got %v", err)
+			}
+
+			status := executor.GetStatus()
+			if status.State != StateSuccess {
+				t.Errorf("Expected final state Success, got %v", status.State)
+			}
+			if len(status.Results) != 3 {
+				t.Fatalf("Expected all 3 commands to run, got %d results", len(status.Results))
+			}
+
+			for _, result := range status.Results {
+				switch result.Command.Name {
+				case "flaky":
+					if result.Success || !result.AllowedFailure || result.ErrorDetail == nil {
+						t.Errorf("Expected the failure to be recorded as allowed, got %+v", result)
+					}
+				default:
+					if !result.Success || result.AllowedFailure {
+						t.Errorf("Expected %s to succeed, got %+v", result.Command.Name, result)
+					}
+				}
+			}
+
+			if !strings.Contains(out.String(), "✗ flaky failed (allowed):") {
+				t.Errorf("Expected the failure to be reported as allowed, got:\n%s", out.String())
+			}
+		})
+	}
+}
+
+func TestExecutor_FailureWithoutAllowFailureStillStops(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires the false command")
+	}
+
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "flaky", Command: "false", Mode: config.ModeOnce, AllowFailure: true},
+			{Name: "broken", Command: "false", Mode: config.ModeOnce},
+			{Name: "after", Command: "echo", Args: []string{"never runs"}, Mode: config.ModeOnce},
+		},
+	}
+
+	executor := NewExecutorWithOptions(ExecutorOptions{Reporter: NewConsoleReporterWithWriters(&bytes.Buffer{}, nil, false)})
+	if err := executor.Execute(context.Background(), cfg); err == nil {
+		t.Fatal("Expected the failure of a command without allowFailure to fail the run")
+	}
+
+	status := executor.GetStatus()
+	if status.State != StateFailed {
+		t.Errorf("Expected final state Failed, got %v", status.State)
+	}
+	if len(status.Results) != 2 || status.Results[1].AllowedFailure {
+		t.Errorf("Expected the run to stop at the unallowed failure, got %+v", status.Results)
+	}
+}
+
+func TestExecutor_AllowFailureDoesNotOutliveCancellation(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sleep")
+	}
+
+	for _, concurrent := range []bool{false, true} {
+		cfg := &config.Config{
+			Version: "1.0",
+			Commands: []config.Command{
+				{Name: "upload", Command: "sleep", Args: []string{"30"}, Mode: config.ModeOnce, AllowFailure: true, Concurrent: concurrent},
+				{Name: "lint", Command: "sleep", Args: []string{"30"}, Mode: config.ModeOnce, AllowFailure: true, Concurrent: concurrent},
+				{Name: "after", Command: "echo", Args: []string{"must not run"}, Mode: config.ModeOnce},
+			},
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(200*time.Millisecond, cancel)
+
+		var out bytes.Buffer
+		executor := NewExecutorWithOptions(ExecutorOptions{Reporter: NewConsoleReporterWithWriters(&out, &out, false)})
+		if err := executor.Execute(ctx, cfg); err == nil {
+			t.Fatalf("concurrent=%v: expected the cancelled run to fail", concurrent)
+		}
+		cancel()
+
+		for _, result := range executor.GetStatus().Results {
+			if result.Command.Name == "after" {
+				t.Errorf("concurrent=%v: expected the queue to stop at the cancelled command", concurrent)
+			}
+			if result.AllowedFailure {
+				t.Errorf("concurrent=%v: expected the cancelled %s not to count as an allowed failure", concurrent, result.Command.Name)
+			}
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+// commandArgs returns the arguments a command runs with: its Args followed by the lines of its
+// argsFile, which is read now so edits to it apply on the next run
+func commandArgs(cmd config.Command) ([]string, error) {
+	if cmd.ArgsFile == "" {
+		return cmd.Args, nil
+	}
+
+	data, err := os.ReadFile(commandRelativePath(cmd, cmd.ArgsFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read argsFile '%s': %w", cmd.ArgsFile, err)
+	}
+
+	args := slices.Clip(cmd.Args)
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		args = append(args, line)
+	}
+	return args, nil
+}
@@ -0,0 +1,60 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestExecutor_BannerAndFooter(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires the true and false commands")
+	}
+
+	tests := []struct {
+		name       string
+		command    string
+		wantFooter bool
+	}{
+		{name: "success", command: "true", wantFooter: true},
+		{name: "failure", command: "false"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Version: "1.0",
+				Banner:  "Starting staging deploy",
+				Footer:  "Staging is up",
+				Commands: []config.Command{
+					{Name: "deploy", Command: tt.command, Mode: config.ModeOnce},
+				},
+			}
+
+			var out bytes.Buffer
+			executor := NewExecutorWithOptions(ExecutorOptions{Reporter: NewConsoleReporterWithWriters(&out, &out, false)})
+			executor.Execute(context.Background(), cfg)
+
+			output := out.String()
+			banner := strings.Index(output, "Starting staging deploy\n")
+			if banner < 0 || banner > strings.Index(output, "Starting: deploy") {
+				t.Errorf("Expected the banner before the first command, got %q", output)
+			}
+
+			footer := strings.Index(output, "Staging is up\n")
+			if !tt.wantFooter {
+				if footer >= 0 {
+					t.Errorf("Expected no footer after a failed run, got %q", output)
+				}
+				return
+			}
+			if footer < strings.Index(output, "All commands completed successfully") {
+				t.Errorf("Expected the footer after the summary, got %q", output)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package executor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+// cacheFileName is the name of the file holding the input hashes of successful cached commands
+const cacheFileName = "seqr-cache.json"
+
+// CacheFile returns the path of the command cache file within a state directory
+func CacheFile(stateDir string) string {
+	return filepath.Join(stateDir, cacheFileName)
+}
+
+// computeCacheHash hashes a command's definition together with the names and contents of the
+// files its cacheKey patterns match, so any change to either invalidates the cache. Relative
+// patterns are resolved against the command's working directory.
+func computeCacheHash(cmd config.Command) (string, error) {
+	hash := sha256.New()
+
+	// The command line, working directory and env overrides are inputs too, as the command runs
+	// with them, so including the lines of its argsFile and the values of its env files
+	resolved, err := resolveCommandInputs(cmd)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(hash, "command\x00%s\x00workdir\x00%s\x00", buildCommandLine(resolved, true), cmd.WorkDir)
+
+	var files []string
+	for _, pattern := range cmd.CacheKey {
+		if !filepath.IsAbs(pattern) && cmd.WorkDir != "" {
+			pattern = filepath.Join(cmd.WorkDir, pattern)
+		}
+
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return "", fmt.Errorf("invalid cacheKey pattern '%s': %w", pattern, err)
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+
+	seen := make(map[string]bool)
+	for _, file := range files {
+		if seen[file] {
+			continue
+		}
+		seen[file] = true
+
+		info, err := os.Stat(file)
+		if err != nil {
+			return "", fmt.Errorf("failed to stat cache input '%s': %w", file, err)
+		}
+
+		// Directories contribute their name, a file appearing or disappearing changes the match list
+		fmt.Fprintf(hash, "file\x00%s\x00", file)
+		if info.IsDir() {
+			continue
+		}
+
+		f, err := os.Open(file)
+		if err != nil {
+			return "", fmt.Errorf("failed to read cache input '%s': %w", file, err)
+		}
+		_, err = io.Copy(hash, f)
+		f.Close()
+		if err != nil {
+			return "", fmt.Errorf("failed to read cache input '%s': %w", file, err)
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// resolveCommandInputs returns cmd with the lines of its argsFile appended to its Args and the
+// values of its env files read into its Env, as they are when the command starts
+func resolveCommandInputs(cmd config.Command) (config.Command, error) {
+	args, err := commandArgs(cmd)
+	if err != nil {
+		return cmd, err
+	}
+	cmd.Args = args
+	cmd.ArgsFile = ""
+
+	if len(cmd.EnvFromFile) > 0 {
+		env := make(map[string]string, len(cmd.Env)+len(cmd.EnvFromFile))
+		for key, value := range cmd.Env {
+			env[key] = value
+		}
+		for key, path := range cmd.EnvFromFile {
+			value, err := readEnvFile(cmd, path)
+			if err != nil {
+				return cmd, &EnvFileError{Key: key, Path: path, OriginalError: err}
+			}
+			env[key] = value
+		}
+		cmd.Env = env
+		cmd.EnvFromFile = nil
+	}
+	return cmd, nil
+}
+
+// loadCacheEntries reads the command name to input hash map, treating a missing file as empty
+func loadCacheEntries(stateDir string) (map[string]string, error) {
+	entries := make(map[string]string)
+
+	data, err := os.ReadFile(CacheFile(stateDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return entries, nil
+		}
+		return nil, fmt.Errorf("failed to read cache file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cache file: %w", err)
+	}
+	return entries, nil
+}
+
+// saveCacheEntries persists the command name to input hash map
+func saveCacheEntries(stateDir string, entries map[string]string) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entries: %w", err)
+	}
+
+	filePath := CacheFile(stateDir)
+
+	// Write to a temporary file first, then rename for atomic operation
+	tempFile := filePath + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+
+	if err := os.Rename(tempFile, filePath); err != nil {
+		os.Remove(tempFile) // Clean up temp file on error
+		return fmt.Errorf("failed to rename cache file: %w", err)
+	}
+
+	return nil
+}
+
+// IsCached reports whether a run would skip a once command because the inputs its cacheKey
+// names are unchanged since its last successful run, with the cache kept in stateDir
+func IsCached(cmd config.Command, stateDir string) (bool, error) {
+	if cmd.Mode != config.ModeOnce || len(cmd.CacheKey) == 0 {
+		return false, nil
+	}
+
+	hash, err := computeCacheHash(cmd)
+	if err != nil {
+		return false, err
+	}
+	entries, err := loadCacheEntries(stateDir)
+	if err != nil {
+		return false, err
+	}
+	return entries[cmd.Name] == hash, nil
+}
+
+// cacheHit reports whether a command's inputs hash matches its last successful run
+func (e *Executor) cacheHit(name, hash string) bool {
+	e.cacheMu.Lock()
+	defer e.cacheMu.Unlock()
+
+	entries, err := loadCacheEntries(e.tracker.StateDir())
+	if err != nil {
+		e.logCacheWarning(name, err)
+		return false
+	}
+	return entries[name] == hash
+}
+
+// updateCache records a command's inputs hash after a successful run, and forgets it after a
+// failure so the command runs again next time
+func (e *Executor) updateCache(name, hash string, success bool) {
+	e.cacheMu.Lock()
+	defer e.cacheMu.Unlock()
+
+	stateDir := e.tracker.StateDir()
+	entries, err := loadCacheEntries(stateDir)
+	if err != nil {
+		e.logCacheWarning(name, err)
+		return
+	}
+
+	if success {
+		entries[name] = hash
+	} else {
+		delete(entries, name)
+	}
+
+	if err := saveCacheEntries(stateDir, entries); err != nil {
+		e.logCacheWarning(name, err)
+	}
+}
+
+// cachedResult builds the result of a command skipped because its inputs are unchanged
+func cachedResult(cmd config.Command) ExecutionResult {
+	now := time.Now()
+	return ExecutionResult{
+		Command:   cmd,
+		Success:   true,
+		Cached:    true,
+		Output:    "skipped, cacheKey inputs unchanged since the last successful run",
+		StartTime: now,
+		EndTime:   now,
+	}
+}
+
+// logCacheWarning reports a cache problem in verbose mode; the command simply runs uncached
+func (e *Executor) logCacheWarning(name string, err error) {
+	if e.verbose {
+		timestamp := time.Now().Format("15:04:05.000")
+		fmt.Printf("[%s] [%s] [system] Warning: Command cache unavailable: %v\n", timestamp, name, err)
+	}
+}
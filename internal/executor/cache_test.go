@@ -0,0 +1,180 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestCacheKeySkipsUnchangedCommands(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sh")
+	}
+	t.Setenv("TMPDIR", t.TempDir())
+
+	workDir := t.TempDir()
+	inputFile := filepath.Join(workDir, "input.txt")
+	runsFile := filepath.Join(workDir, "runs.log")
+	if err := os.WriteFile(inputFile, []byte("v1"), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{
+				Name:     "build",
+				Command:  "sh",
+				Args:     []string{"-c", "echo run >> runs.log"},
+				Mode:     config.ModeOnce,
+				WorkDir:  workDir,
+				CacheKey: []string{"*.txt"},
+			},
+		},
+	}
+
+	run := func() ExecutionResult {
+		t.Helper()
+		executor := NewExecutor(false)
+		if err := executor.Execute(context.Background(), cfg); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		return executor.GetStatus().Results[0]
+	}
+
+	runCount := func() int {
+		t.Helper()
+		data, err := os.ReadFile(runsFile)
+		if err != nil {
+			t.Fatalf("Failed to read runs: %v", err)
+		}
+		return strings.Count(string(data), "run")
+	}
+
+	if result := run(); result.Cached {
+		t.Error("Expected the first run not to be cached")
+	}
+
+	if result := run(); !result.Cached || !result.Success {
+		t.Errorf("Expected the second run to be skipped as cached, got %+v", result)
+	}
+	if got := runCount(); got != 1 {
+		t.Errorf("Expected the command to have run once, ran %d times", got)
+	}
+
+	// Changing a tracked input invalidates the cache
+	if err := os.WriteFile(inputFile, []byte("v2"), 0644); err != nil {
+		t.Fatalf("Failed to update input: %v", err)
+	}
+	if result := run(); result.Cached {
+		t.Error("Expected a run after the input changed not to be cached")
+	}
+	if got := runCount(); got != 2 {
+		t.Errorf("Expected the command to have run twice, ran %d times", got)
+	}
+
+	// A new file matching the pattern is an input change too
+	if err := os.WriteFile(filepath.Join(workDir, "extra.txt"), []byte("x"), 0644); err != nil {
+		t.Fatalf("Failed to add input: %v", err)
+	}
+	if result := run(); result.Cached {
+		t.Error("Expected a run after a new input appeared not to be cached")
+	}
+
+	if result := run(); !result.Cached {
+		t.Error("Expected the run after that to be cached again")
+	}
+}
+
+func TestCacheKeyForgetsFailedRuns(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sh")
+	}
+	t.Setenv("TMPDIR", t.TempDir())
+
+	workDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(workDir, "input.txt"), []byte("v1"), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+
+	// Fails while the marker file exists, so the same inputs run once failing and once passing
+	marker := filepath.Join(workDir, "fail.marker")
+	if err := os.WriteFile(marker, nil, 0644); err != nil {
+		t.Fatalf("Failed to write marker: %v", err)
+	}
+
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{
+				Name:     "test",
+				Command:  "sh",
+				Args:     []string{"-c", "test ! -e fail.marker"},
+				Mode:     config.ModeOnce,
+				WorkDir:  workDir,
+				CacheKey: []string{"input.txt"},
+			},
+		},
+	}
+
+	if err := NewExecutor(false).Execute(context.Background(), cfg); err == nil {
+		t.Fatal("Expected the first run to fail")
+	}
+	os.Remove(marker)
+
+	executor := NewExecutor(false)
+	if err := executor.Execute(context.Background(), cfg); err != nil {
+		t.Fatalf("Expected the second run to succeed: %v", err)
+	}
+	if executor.GetStatus().Results[0].Cached {
+		t.Error("Expected a failed run never to be cached")
+	}
+}
+
+func TestComputeCacheHashReadsArgsAndEnvFiles(t *testing.T) {
+	workDir := t.TempDir()
+	argsFile := filepath.Join(workDir, "files.txt")
+	tokenFile := filepath.Join(workDir, "token")
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+	write(argsFile, "a.go\n")
+	write(tokenFile, "secret-1\n")
+
+	cmd := config.Command{
+		Name:        "lint",
+		Command:     "golint",
+		Mode:        config.ModeOnce,
+		WorkDir:     workDir,
+		ArgsFile:    "files.txt",
+		EnvFromFile: map[string]string{"TOKEN": "token"},
+		CacheKey:    []string{"*.go"},
+	}
+	hash := func() string {
+		t.Helper()
+		h, err := computeCacheHash(cmd)
+		if err != nil {
+			t.Fatalf("computeCacheHash failed: %v", err)
+		}
+		return h
+	}
+
+	initial := hash()
+	write(argsFile, "a.go\nb.go\n")
+	afterArgs := hash()
+	if afterArgs == initial {
+		t.Error("Expected a change to the argsFile to change the cache hash")
+	}
+	write(tokenFile, "secret-2\n")
+	if hash() == afterArgs {
+		t.Error("Expected a change to an env file to change the cache hash")
+	}
+}
@@ -3,8 +3,17 @@
 package executor
 
 import (
+	"context"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
 	"testing"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
 )
 
 func TestProcessGroupConfigurationUnix(t *testing.T) {
@@ -31,3 +40,126 @@ func TestProcessGroupConfigurationUnix(t *testing.T) {
 		t.Error("Pgid should be 0 to use process PID as group ID")
 	}
 }
+
+// isProcessGone reports whether a process has exited, treating zombies awaiting reaping as gone
+func isProcessGone(pid int) bool {
+	out, err := exec.Command("ps", "-o", "stat=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return true
+	}
+	return strings.HasPrefix(strings.TrimSpace(string(out)), "Z")
+}
+
+func TestContextDeadlineKillsProcessGroup(t *testing.T) {
+	if _, err := exec.LookPath("ps"); err != nil {
+		t.Skip("Test requires ps")
+	}
+
+	pidFile := filepath.Join(t.TempDir(), "grandchild.pid")
+
+	// The shell backgrounds a sleep and waits on it, so the sleep is a grandchild of seqr
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{
+				Name:    "spawner",
+				Command: "sh",
+				Args:    []string{"-c", `sleep 30 & echo $! > "$PID_FILE"; wait`},
+				Mode:    config.ModeOnce,
+				Env:     map[string]string{"PID_FILE": pidFile},
+			},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	executor := NewExecutor(false)
+	start := time.Now()
+	if err := executor.Execute(ctx, cfg); err == nil {
+		t.Fatal("Expected the command to fail when the deadline passes")
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("Execute took %v, expected it to return shortly after the deadline", elapsed)
+	}
+
+	data, err := os.ReadFile(pidFile)
+	if err != nil {
+		t.Fatalf("Failed to read grandchild PID: %v", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		t.Fatalf("Invalid grandchild PID %q: %v", data, err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !isProcessGone(pid) {
+		if time.Now().After(deadline) {
+			syscall.Kill(pid, syscall.SIGKILL)
+			t.Fatalf("Grandchild process %d survived the deadline", pid)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestNoProcessGroupRunsInParentGroup(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *config.Config
+	}{
+		{
+			name: "per command",
+			cfg: &config.Config{
+				Version: "1.0",
+				Commands: []config.Command{
+					{Name: "server", Command: "sleep", Args: []string{"30"}, Mode: config.ModeKeepAlive, NoProcessGroup: true},
+				},
+			},
+		},
+		{
+			name: "global",
+			cfg: &config.Config{
+				Version:        "1.0",
+				NoProcessGroup: true,
+				Commands: []config.Command{
+					{Name: "server", Command: "sleep", Args: []string{"30"}, Mode: config.ModeKeepAlive},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := NewExecutor(false)
+			if err := executor.Execute(context.Background(), tt.cfg); err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+
+			executor.mu.RLock()
+			server := executor.processes["server"]
+			executor.mu.RUnlock()
+			if server == nil {
+				t.Fatal("Expected the keepAlive process to be running")
+			}
+			pid := server.Process.Pid
+
+			pgid, err := syscall.Getpgid(pid)
+			if err != nil {
+				t.Fatalf("Failed to get process group: %v", err)
+			}
+			if pgid != syscall.Getpgrp() {
+				t.Errorf("Expected the process to stay in the parent's process group %d, got %d", syscall.Getpgrp(), pgid)
+			}
+
+			// Without a group to signal, stopping falls back to signalling the process itself
+			executor.Stop()
+			deadline := time.Now().Add(5 * time.Second)
+			for syscall.Kill(pid, 0) == nil {
+				if time.Now().After(deadline) {
+					t.Fatal("Expected the process to be stopped")
+				}
+				time.Sleep(20 * time.Millisecond)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+// concurrentSleepConfig returns a config with count concurrent commands that each sleep briefly
+func concurrentSleepConfig(count, maxConcurrency int) *config.Config {
+	cfg := &config.Config{Version: "1.0", MaxConcurrency: maxConcurrency}
+	for i := 0; i < count; i++ {
+		cfg.Commands = append(cfg.Commands, config.Command{
+			Name:       fmt.Sprintf("sleep-%d", i),
+			Command:    "sleep",
+			Args:       []string{"0.3"},
+			Mode:       config.ModeOnce,
+			Concurrent: true,
+		})
+	}
+	return cfg
+}
+
+// maxOverlap returns the largest number of results whose run intervals overlap at any moment
+func maxOverlap(results []ExecutionResult) int {
+	type event struct {
+		at    time.Time
+		delta int
+	}
+
+	var events []event
+	for _, result := range results {
+		events = append(events, event{result.StartTime, 1}, event{result.EndTime, -1})
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].at.Equal(events[j].at) {
+			return events[i].delta < events[j].delta // Process ends before starts at the same instant
+		}
+		return events[i].at.Before(events[j].at)
+	})
+
+	running, peak := 0, 0
+	for _, ev := range events {
+		running += ev.delta
+		if running > peak {
+			peak = running
+		}
+	}
+	return peak
+}
+
+func TestExecuteConcurrentCommandsRespectsMaxConcurrency(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires the sleep command")
+	}
+
+	tests := []struct {
+		name           string
+		configLimit    int
+		optionLimit    int
+		expectedPeak   int
+		expectExactMax bool
+	}{
+		{name: "config limit", configLimit: 1, expectedPeak: 1},
+		{name: "option overrides config", configLimit: 1, optionLimit: 3, expectedPeak: 3, expectExactMax: true},
+		{name: "option limit without config", optionLimit: 2, expectedPeak: 2},
+		{name: "unbounded", expectedPeak: 3, expectExactMax: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := NewExecutorWithOptions(ExecutorOptions{MaxConcurrency: tt.optionLimit})
+
+			if err := executor.Execute(context.Background(), concurrentSleepConfig(3, tt.configLimit)); err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+
+			results := executor.GetStatus().Results
+			if len(results) != 3 {
+				t.Fatalf("Expected 3 results, got %d", len(results))
+			}
+
+			peak := maxOverlap(results)
+			if peak > tt.expectedPeak {
+				t.Errorf("Expected at most %d commands running at once, got %d", tt.expectedPeak, peak)
+			}
+			if tt.expectExactMax && peak != tt.expectedPeak {
+				t.Errorf("Expected %d commands running at once, got %d", tt.expectedPeak, peak)
+			}
+		})
+	}
+}
+
+func TestExecuteRecordsQueueWaitSeparatelyFromRunTime(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires the sleep command")
+	}
+
+	// With room for one command at a time, the second one has to wait for the first
+	executor := NewExecutor(false)
+	if err := executor.Execute(context.Background(), concurrentSleepConfig(2, 1)); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	results := executor.GetStatus().Results
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].WaitDuration < results[j].WaitDuration })
+	first, second := results[0], results[1]
+
+	for _, result := range results {
+		if result.QueuedAt.IsZero() || result.StartedAt.IsZero() {
+			t.Errorf("%s: expected QueuedAt and StartedAt to be set", result.Command.Name)
+		}
+		if got := result.StartedAt.Sub(result.QueuedAt); got != result.WaitDuration {
+			t.Errorf("%s: WaitDuration %v does not match StartedAt-QueuedAt %v", result.Command.Name, result.WaitDuration, got)
+		}
+		if got := result.EndTime.Sub(result.StartedAt); got != result.RunDuration {
+			t.Errorf("%s: RunDuration %v does not match EndTime-StartedAt %v", result.Command.Name, result.RunDuration, got)
+		}
+		if result.RunDuration < 250*time.Millisecond {
+			t.Errorf("%s: expected RunDuration of about 300ms, got %v", result.Command.Name, result.RunDuration)
+		}
+	}
+
+	if second.WaitDuration < 250*time.Millisecond {
+		t.Errorf("Expected the held-back command to wait for the first to finish, waited %v", second.WaitDuration)
+	}
+	if first.WaitDuration >= second.WaitDuration {
+		t.Errorf("Expected first command to wait less than the second, got %v and %v", first.WaitDuration, second.WaitDuration)
+	}
+}
+
+func TestExecuteConcurrentCommandsStartsHigherPriorityFirst(t *testing.T) {
+	processes := make(map[string]fakeProcess)
+	cfg := &config.Config{Version: "1.0", MaxConcurrency: 1}
+	for _, cmd := range []struct {
+		name     string
+		priority int
+	}{
+		{"lint", 0},
+		{"unit", 5},
+		{"docs", -1},
+		{"e2e", 10},
+		{"vet", 0},
+		{"integration", 5},
+	} {
+		processes[cmd.name] = fakeProcess{runFor: 20 * time.Millisecond}
+		cfg.Commands = append(cfg.Commands, config.Command{
+			Name:       cmd.name,
+			Command:    cmd.name,
+			Mode:       config.ModeOnce,
+			Concurrent: true,
+			Priority:   cmd.priority,
+		})
+	}
+
+	runner := newFakeRunner(processes)
+	executor := NewExecutorWithOptions(ExecutorOptions{Runner: runner})
+	var err error
+	captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	// One slot at a time, so commands start strictly by priority, then declaration order
+	runner.mu.Lock()
+	started := strings.Join(runner.started, " ")
+	runner.mu.Unlock()
+	if want := "e2e unit integration lint vet docs"; started != want {
+		t.Errorf("Expected start order %q, got %q", want, started)
+	}
+}
+
+func TestGroupCommandsByConcurrencyKeepsMatrixApart(t *testing.T) {
+	commands := []config.Command{
+		{Name: "lint", Concurrent: true},
+		{Name: "test-18", Concurrent: true, MatrixOf: "test"},
+		{Name: "test-20", Concurrent: true, MatrixOf: "test"},
+		{Name: "docs", Concurrent: true},
+		{Name: "deploy"},
+	}
+
+	var groups []string
+	for _, group := range NewExecutor(false).groupCommandsByConcurrency(commands) {
+		var names []string
+		for _, cmd := range group {
+			names = append(names, cmd.Name)
+		}
+		groups = append(groups, strings.Join(names, ","))
+	}
+	if want := "lint|test-18,test-20|docs|deploy"; strings.Join(groups, "|") != want {
+		t.Errorf("Expected groups %q, got %q", want, strings.Join(groups, "|"))
+	}
+}
@@ -0,0 +1,217 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+// failingCommands returns count sequential commands that always fail
+func failingCommands(count int) []config.Command {
+	var commands []config.Command
+	for i := 0; i < count; i++ {
+		commands = append(commands, config.Command{
+			Name:    fmt.Sprintf("fail-%d", i+1),
+			Command: "false",
+			Mode:    config.ModeOnce,
+		})
+	}
+	return commands
+}
+
+func TestContinueOnErrorRunsRemainingCommands(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires the false command")
+	}
+
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: append(failingCommands(2),
+			config.Command{Name: "after", Command: "echo", Args: []string{"still runs"}, Mode: config.ModeOnce}),
+	}
+
+	executor := NewExecutorWithOptions(ExecutorOptions{ContinueOnError: true})
+	err := executor.Execute(context.Background(), cfg)
+
+	var failuresErr *CommandFailuresError
+	if !errors.As(err, &failuresErr) {
+		t.Fatalf("Expected CommandFailuresError, got %v", err)
+	}
+	if len(failuresErr.Failures) != 2 || failuresErr.TotalCount != 3 || failuresErr.LimitReached {
+		t.Errorf("Unexpected aggregated error: %+v", failuresErr)
+	}
+
+	status := executor.GetStatus()
+	if len(status.Results) != 3 {
+		t.Fatalf("Expected all 3 commands to run, got %d results", len(status.Results))
+	}
+	if !status.Results[2].Success {
+		t.Error("Expected the command after the failures to run successfully")
+	}
+	if status.State != StateFailed {
+		t.Errorf("Expected final state Failed, got %v", status.State)
+	}
+}
+
+func TestMaxFailuresStopsLaunchingCommands(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires the false command")
+	}
+
+	cfg := &config.Config{Version: "1.0", Commands: failingCommands(3)}
+
+	executor := NewExecutorWithOptions(ExecutorOptions{ContinueOnError: true, MaxFailures: 2})
+	err := executor.Execute(context.Background(), cfg)
+
+	var failuresErr *CommandFailuresError
+	if !errors.As(err, &failuresErr) {
+		t.Fatalf("Expected CommandFailuresError, got %v", err)
+	}
+	if !failuresErr.LimitReached {
+		t.Error("Expected the aggregated error to report that the failure limit was reached")
+	}
+
+	results := executor.GetStatus().Results
+	if len(results) != 2 {
+		t.Fatalf("Expected only 2 commands to run, got %d results", len(results))
+	}
+	for _, result := range results {
+		if result.Command.Name == "fail-3" {
+			t.Error("Expected the third command not to run after reaching MaxFailures")
+		}
+	}
+}
+
+func TestMaxFailuresHoldsBackConcurrentCommands(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires the false command")
+	}
+
+	// With one slot, concurrent commands launch one by one so the limit can stop the rest
+	commands := failingCommands(3)
+	for i := range commands {
+		commands[i].Concurrent = true
+	}
+	cfg := &config.Config{Version: "1.0", Commands: commands, MaxConcurrency: 1}
+
+	executor := NewExecutorWithOptions(ExecutorOptions{ContinueOnError: true, MaxFailures: 2})
+	if err := executor.Execute(context.Background(), cfg); err == nil {
+		t.Fatal("Expected execution to fail")
+	}
+
+	if results := executor.GetStatus().Results; len(results) != 2 {
+		t.Errorf("Expected only 2 concurrent commands to launch, got %d results", len(results))
+	}
+}
+
+func TestCancelCommandLetsRunContinue(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires the sleep command")
+	}
+
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "stuck", Command: "sleep", Args: []string{"30"}, Mode: config.ModeOnce},
+			{Name: "after", Command: "echo", Args: []string{"still runs"}, Mode: config.ModeOnce},
+		},
+	}
+
+	executor := NewExecutorWithOptions(ExecutorOptions{ContinueOnError: true})
+
+	var notFound *ProcessNotFoundError
+	if err := executor.CancelCommand("stuck"); !errors.As(err, &notFound) {
+		t.Fatalf("Expected ProcessNotFoundError before the command starts, got %v", err)
+	}
+
+	// Cancel the stuck command by name as soon as it is running
+	go func() {
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			if executor.CancelCommand("stuck") == nil {
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}()
+
+	start := time.Now()
+	if err := executor.Execute(context.Background(), cfg); err == nil {
+		t.Fatal("Expected the cancelled command to be reported as a failure")
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("Execute took %v, expected the stuck command to be cancelled", elapsed)
+	}
+
+	results := executor.GetStatus().Results
+	if len(results) != 2 {
+		t.Fatalf("Expected both commands to run, got %d results", len(results))
+	}
+
+	stuck := results[0]
+	if stuck.ErrorDetail == nil || stuck.ErrorDetail.Type != ErrorTypeContextCancelled {
+		t.Errorf("Expected the cancelled command to have error type %q, got %+v", ErrorTypeContextCancelled, stuck.ErrorDetail)
+	}
+	if !results[1].Success {
+		t.Error("Expected the command after the cancelled one to run successfully")
+	}
+}
+
+func TestCommandFailuresErrorUnwrapsIntoFailures(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires the false command")
+	}
+
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "lint", Command: "false", Mode: config.ModeOnce},
+			{Name: "build", Command: "echo", Args: []string{"ok"}, Mode: config.ModeOnce},
+			{Name: "deploy", Command: "seqr-missing-command", Mode: config.ModeOnce},
+		},
+	}
+
+	executor := NewExecutorWithOptions(ExecutorOptions{ContinueOnError: true})
+	err := executor.Execute(context.Background(), cfg)
+
+	// errors.As finds the first command to fail
+	var first *CommandFailure
+	if !errors.As(err, &first) {
+		t.Fatalf("Expected the error to unwrap into a CommandFailure, got %v", err)
+	}
+	if first.CommandName != "lint" || first.Detail == nil || first.Detail.Type != ErrorTypeExitCode || first.Detail.ExitCode != 1 {
+		t.Errorf("Expected lint's exit code failure first, got %s with %+v", first.CommandName, first.Detail)
+	}
+
+	var failuresErr *CommandFailuresError
+	if !errors.As(err, &failuresErr) {
+		t.Fatalf("Expected CommandFailuresError, got %v", err)
+	}
+	unwrapped := failuresErr.Unwrap()
+	if len(unwrapped) != 2 {
+		t.Fatalf("Expected one unwrapped error per failed command, got %d", len(unwrapped))
+	}
+
+	var second *CommandFailure
+	if !errors.As(unwrapped[1], &second) {
+		t.Fatalf("Expected the second unwrapped error to be a CommandFailure, got %v", unwrapped[1])
+	}
+	if second.CommandName != "deploy" || second.Detail == nil || second.Detail.Type != ErrorTypeCommandNotFound {
+		t.Errorf("Expected deploy's command not found failure second, got %s with %+v", second.CommandName, second.Detail)
+	}
+
+	// The underlying errors stay reachable through the chain
+	var exitErr *exec.ExitError
+	if !errors.As(unwrapped[0], &exitErr) {
+		t.Errorf("Expected lint's failure to unwrap to its exit error, got %v", unwrapped[0])
+	}
+	if !errors.Is(err, exec.ErrNotFound) {
+		t.Errorf("Expected the aggregated error to match exec.ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestExecutor_CreateWorkDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires touch")
+	}
+
+	workDir := filepath.Join(t.TempDir(), "dist", "assets")
+	cmd := config.Command{Name: "build", Command: "touch", Args: []string{"bundle.js"}, Mode: config.ModeOnce, WorkDir: workDir}
+	cfg := &config.Config{Version: "1.0", Commands: []config.Command{cmd}}
+
+	// A missing workDir still fails the command by default
+	executor := NewExecutor(false)
+	var err error
+	captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+	if err == nil {
+		t.Fatal("Expected a missing workDir to fail the command")
+	}
+	if detail := executor.GetStatus().Results[0].ErrorDetail; detail == nil || detail.Type != ErrorTypeWorkDir {
+		t.Errorf("Expected error type %q, got %+v", ErrorTypeWorkDir, detail)
+	}
+
+	cfg.Commands[0].CreateWorkDir = true
+	executor = NewExecutor(false)
+	captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+	if err != nil {
+		t.Fatalf("Expected the command to run in the created workDir, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(workDir, "bundle.js")); err != nil {
+		t.Errorf("Expected the command to run in %s: %v", workDir, err)
+	}
+}
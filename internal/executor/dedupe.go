@@ -0,0 +1,77 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// lineDeduper collapses a run of identical consecutive lines of a stream into its first line and,
+// once a different line arrives or the stream ends, a note of how many times it was repeated,
+// as journald does
+type lineDeduper struct {
+	enabled bool
+	last    string
+	started bool
+	repeats int
+}
+
+// add returns whether line is to be shown, and the number of repeats of the previous line still
+// to be noted before it
+func (d *lineDeduper) add(line string) (show bool, repeats int) {
+	if !d.enabled {
+		return true, 0
+	}
+	if d.started && line == d.last {
+		d.repeats++
+		return false, 0
+	}
+
+	repeats = d.repeats
+	d.last, d.started, d.repeats = line, true, 0
+	return true, repeats
+}
+
+// flush returns the number of repeats of the last line not noted yet, at the end of the stream
+func (d *lineDeduper) flush() int {
+	repeats := d.repeats
+	d.repeats = 0
+	return repeats
+}
+
+// repeatNote is the line standing in for the repeats of the line before it
+func repeatNote(repeats int) string {
+	if repeats == 1 {
+		return "(last line repeated 1 time)"
+	}
+	return fmt.Sprintf("(last line repeated %d times)", repeats)
+}
+
+// printRepeatNote writes the note for a collapsed run of repeated lines to the console and the
+// background log
+func (e *Executor) printRepeatNote(cmdType, commandName string, repeats int) {
+	if repeats == 0 {
+		return
+	}
+	coloredTimestamp := colorize(time.Now().Format("15:04:05.000"), colorGray)
+	coloredType := e.colorizeCommandType(cmdType)
+	coloredName := colorize(commandName, colorCyan)
+	fmt.Printf("%s %s\n", e.streamPrefix(coloredTimestamp, coloredType, coloredName), repeatNote(repeats))
+	os.Stdout.Sync()
+
+	e.logger.WriteLog(commandName, fmt.Sprintf("[%s] [%s] %s", coloredTimestamp, coloredType, repeatNote(repeats)))
+}
+
+// noteRepeats notes a collapsed run of repeated output lines to sink unless it is nil,
+// and to the console and background log with verbose output
+func (e *Executor) noteRepeats(cmdType, commandName string, repeats int, sink syslogSink) {
+	if repeats == 0 {
+		return
+	}
+	if sink != nil {
+		sink.Info(repeatNote(repeats))
+	}
+	if e.verbose {
+		e.printRepeatNote(cmdType, commandName, repeats)
+	}
+}
@@ -0,0 +1,51 @@
+package executor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStreamOutput_Dedupe(t *testing.T) {
+	input := "connecting\nretrying\nretrying\nretrying\nretrying\nconnected\ndone\ndone\n"
+
+	tests := []struct {
+		name   string
+		dedupe bool
+		want   []string
+	}{
+		{
+			name:   "collapsed",
+			dedupe: true,
+			want:   []string{"connecting", "retrying", "(last line repeated 3 times)", "connected", "done", "(last line repeated 1 time)"},
+		},
+		{
+			name: "not collapsed",
+			want: []string{"connecting", "retrying", "retrying", "retrying", "retrying", "connected", "done", "done"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := NewExecutorWithOptions(ExecutorOptions{Verbose: true, NoTimestamps: true})
+
+			var outputBuilder strings.Builder
+			out := captureOutput(func() {
+				executor.streamOutput(&testReadCloser{strings.NewReader(input)}, &outputBuilder, "api", "stdout", "./client", nil, nil, tt.dedupe)
+			})
+
+			lines := strings.Split(strings.TrimSpace(out), "\n")
+			if len(lines) != len(tt.want) {
+				t.Fatalf("Expected %d streamed lines, got %d:\n%s", len(tt.want), len(lines), out)
+			}
+			for i, want := range tt.want {
+				if !strings.HasSuffix(lines[i], " "+want) || !strings.Contains(lines[i], "api") {
+					t.Errorf("Expected line %d to show %q for api, got %q", i, want, lines[i])
+				}
+			}
+
+			if outputBuilder.String() != input {
+				t.Errorf("Expected every line to be captured, got %q", outputBuilder.String())
+			}
+		})
+	}
+}
@@ -39,18 +39,33 @@
 //   - Detailed error information for failed commands
 //   - Execution summary with statistics (in verbose mode)
 //
+// SetReporter swaps the reporter at any time, including mid-run; a report already in
+// progress finishes on the reporter that was current when it started.
+//
+// # Running Processes
+//
+// Once and keepAlive commands are started and waited on through a CommandRunner. The
+// default, ExecRunner, runs them as real processes with os/exec; ExecutorOptions.Runner
+// swaps in another, such as a fake in tests that simulates exit codes, output and timing.
+//
+// # Running a Single Command
+//
+// RunCommand runs one normalized config.Command without building a Config around it, and
+// returns its ExecutionResult. It leaves the executor's status alone.
+//
 // # Usage Example
 //
 //	// Create custom reporter or use default
 //	reporter := NewConsoleReporter(os.Stdout, true) // verbose mode
+//	// or NewConsoleReporterWithWriters(os.Stdout, os.Stderr, true) to send failures to stderr
 //
 //	opts := ExecutorOptions{
-//		Verbose:  true,
-//		Timeout:  30 * time.Second,
-//		Reporter: reporter, // optional, defaults to console reporter
+//		Verbose:        true,
+//		Reporter:       reporter, // optional, defaults to console reporter
+//		MaxConcurrency: 4,        // optional, overrides the config's maxConcurrency
 //	}
 //
-//	executor := NewExecutor(opts)
+//	executor := NewExecutorWithOptions(opts)
 //
 //	ctx := context.Background()
 //	err := executor.Execute(ctx, config)
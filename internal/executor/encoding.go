@@ -0,0 +1,45 @@
+package executor
+
+import (
+	"io"
+
+	"golang.org/x/text/transform"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+// decodedReader reads a command's output through a decoder, closing the underlying pipe
+type decodedReader struct {
+	io.Reader
+	io.Closer
+}
+
+// decodeOutputStream wraps an output stream so it reads as UTF-8 when the command sets an
+// encoding, and returns it unchanged otherwise
+func decodeOutputStream(cmd config.Command, pipe io.ReadCloser) io.ReadCloser {
+	if cmd.Encoding == "" {
+		return pipe
+	}
+	enc, err := config.LookupEncoding(cmd.Encoding)
+	if err != nil || enc == nil {
+		return pipe
+	}
+	return decodedReader{Reader: transform.NewReader(pipe, enc.NewDecoder()), Closer: pipe}
+}
+
+// decodeOutput converts captured output to UTF-8 when the command sets an encoding. Bytes that
+// do not decode are kept as they are.
+func decodeOutput(cmd config.Command, output []byte) []byte {
+	if cmd.Encoding == "" {
+		return output
+	}
+	enc, err := config.LookupEncoding(cmd.Encoding)
+	if err != nil || enc == nil {
+		return output
+	}
+	decoded, err := enc.NewDecoder().Bytes(output)
+	if err != nil {
+		return output
+	}
+	return decoded
+}
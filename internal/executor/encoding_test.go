@@ -0,0 +1,58 @@
+package executor
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestExecutor_OutputEncoding(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires printf")
+	}
+
+	tests := []struct {
+		name     string
+		encoding string
+		output   string // printf format writing the command's raw bytes
+		want     string
+	}{
+		{name: "windows-1252", encoding: "windows-1252", output: `caf\351 \200 5\n`, want: "café € 5"},
+		{name: "utf-16le", encoding: "utf-16le", output: `h\000\351\000\n\000`, want: "hé"},
+		{name: "utf-8 passthrough", encoding: "", output: `caf\303\251\n`, want: "café"},
+	}
+
+	for _, tt := range tests {
+		for _, verbose := range []bool{false, true} {
+			name := tt.name
+			if verbose {
+				name += " verbose"
+			}
+			t.Run(name, func(t *testing.T) {
+				cfg := &config.Config{
+					Version: "1.0",
+					Commands: []config.Command{
+						{Name: "legacy", Command: "printf", Args: []string{tt.output}, Mode: config.ModeOnce, Encoding: tt.encoding},
+					},
+				}
+
+				executor := NewExecutor(verbose)
+				var err error
+				streamed := captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+				if err != nil {
+					t.Fatalf("Execute returned error: %v", err)
+				}
+
+				if got := strings.TrimSpace(executor.GetStatus().Results[0].Output); got != tt.want {
+					t.Errorf("Expected captured output %q, got %q", tt.want, got)
+				}
+				if verbose && !strings.Contains(streamed, tt.want) {
+					t.Errorf("Expected %q to be streamed, got:\n%s", tt.want, streamed)
+				}
+			})
+		}
+	}
+}
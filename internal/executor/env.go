@@ -0,0 +1,113 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+// secretEnvMarkers are name fragments that mark an environment variable as holding a secret
+var secretEnvMarkers = []string{"SECRET", "TOKEN", "PASSWORD", "PASSWD", "API_KEY", "APIKEY", "PRIVATE_KEY", "CREDENTIAL", "AUTH"}
+
+// maskedEnvValue replaces the value of secret environment variables in diagnostic output
+const maskedEnvValue = "********"
+
+// EnvFileError reports an env value that could not be read from the file it references
+type EnvFileError struct {
+	Key           string
+	Path          string
+	OriginalError error
+}
+
+// Error implements the error interface
+func (e *EnvFileError) Error() string {
+	return fmt.Sprintf("failed to read env value for '%s' from file '%s': %v", e.Key, e.Path, e.OriginalError)
+}
+
+// Unwrap returns the original error for error unwrapping
+func (e *EnvFileError) Unwrap() error {
+	return e.OriginalError
+}
+
+// BuildCommandEnv returns the environment a command runs with, as KEY=value entries: the
+// current process environment with the command's env overrides applied on top. Values given
+// as {"fromFile": "path"} are read now, so a rotated secret is picked up on the next run.
+func BuildCommandEnv(cmd config.Command) ([]string, error) {
+	overrides := make(map[string]string, len(cmd.Env)+len(cmd.EnvFromFile))
+	for key, value := range cmd.Env {
+		overrides[key] = value
+	}
+	for key, path := range cmd.EnvFromFile {
+		value, err := readEnvFile(cmd, path)
+		if err != nil {
+			return nil, &EnvFileError{Key: key, Path: path, OriginalError: err}
+		}
+		overrides[key] = value
+	}
+
+	base := os.Environ()
+	env := make([]string, 0, len(base)+len(overrides))
+
+	for _, entry := range base {
+		key, _, _ := strings.Cut(entry, "=")
+		if _, overridden := overrides[key]; overridden {
+			continue
+		}
+		env = append(env, entry)
+	}
+
+	// Overrides are appended in a stable order so the environment is reproducible
+	keys := make([]string, 0, len(overrides))
+	for key := range overrides {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		env = append(env, key+"="+overrides[key])
+	}
+
+	return env, nil
+}
+
+// readEnvFile returns the content of an env value file without surrounding whitespace, such as
+// the trailing newline most secret files end with. Relative paths are resolved against the
+// command's working directory.
+func readEnvFile(cmd config.Command, path string) (string, error) {
+	data, err := os.ReadFile(commandRelativePath(cmd, path))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// commandRelativePath resolves a relative path against the command's working directory
+func commandRelativePath(cmd config.Command, path string) string {
+	if !filepath.IsAbs(path) && cmd.WorkDir != "" {
+		return filepath.Join(cmd.WorkDir, path)
+	}
+	return path
+}
+
+// IsSecretEnvKey reports whether an environment variable name looks like it holds a secret
+func IsSecretEnvKey(key string) bool {
+	upper := strings.ToUpper(key)
+	for _, marker := range secretEnvMarkers {
+		if strings.Contains(upper, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaskEnvEntry masks the value of a KEY=value entry when the key looks like it holds a secret
+func MaskEnvEntry(entry string) string {
+	key, value, found := strings.Cut(entry, "=")
+	if !found || value == "" || !IsSecretEnvKey(key) {
+		return entry
+	}
+	return key + "=" + maskedEnvValue
+}
@@ -1,6 +1,16 @@
 package executor
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
 
 // CommandExecutionError represents an error that occurred during command execution
 type CommandExecutionError struct {
@@ -68,3 +78,155 @@ func (e *ProcessTerminationError) Error() string {
 func (e *ProcessTerminationError) Unwrap() error {
 	return e.OriginalError
 }
+
+// KeepAliveExitError reports a keepAlive process that exited while the rest of the run was still
+// in progress, which fails the run when FailOnKeepAliveExit is set
+type KeepAliveExitError struct {
+	CommandName string
+	PID         int
+	ExitCode    int
+	Reason      string // How the process ended, e.g. "exit status 1" or "signal: killed"
+}
+
+// Error implements the error interface
+func (e *KeepAliveExitError) Error() string {
+	msg := fmt.Sprintf("keepAlive command '%s' (PID %d) exited unexpectedly with exit code %d", e.CommandName, e.PID, e.ExitCode)
+	if e.Reason != "" {
+		msg += ": " + e.Reason
+	}
+	return msg
+}
+
+// CommandFailure is one failed command of a run that continued past failures
+type CommandFailure struct {
+	CommandName string
+	Detail      *ErrorDetail // Why the command failed, as recorded in its result
+	Err         error
+}
+
+// Error implements the error interface
+func (f *CommandFailure) Error() string {
+	return fmt.Sprintf("%s: %v", f.CommandName, f.Err)
+}
+
+// Unwrap returns the command's error for error unwrapping
+func (f *CommandFailure) Unwrap() error {
+	return f.Err
+}
+
+// CommandFailuresError aggregates the failed commands of a run that continued past failures.
+// It unwraps into a *CommandFailure per command, so errors.As finds the first one to fail.
+type CommandFailuresError struct {
+	Failures     []*CommandFailure // Each failed command with its error details, in the order they failed
+	TotalCount   int               // Number of commands in the run
+	LimitReached bool              // Remaining commands were not started because the failure limit was reached
+}
+
+// Error implements the error interface
+func (e *CommandFailuresError) Error() string {
+	details := make([]string, len(e.Failures))
+	for i, failure := range e.Failures {
+		details[i] = failure.Error()
+	}
+
+	msg := fmt.Sprintf("%d of %d commands failed: %s", len(e.Failures), e.TotalCount, strings.Join(details, "; "))
+	if e.LimitReached {
+		msg += " (stopped after reaching the failure limit)"
+	}
+	return msg
+}
+
+// Unwrap returns the individual command failures for error unwrapping
+func (e *CommandFailuresError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, failure := range e.Failures {
+		errs[i] = failure
+	}
+	return errs
+}
+
+// ErrorType categorizes why a command failed
+type ErrorType string
+
+const (
+	ErrorTypeCommandNotFound  ErrorType = "command_not_found" // The executable could not be found
+	ErrorTypePermissionDenied ErrorType = "permission_denied" // The executable or working directory is not accessible
+	ErrorTypeWorkDir          ErrorType = "work_dir"          // The working directory does not exist
+	ErrorTypeContextCancelled ErrorType = "context_cancelled" // The run or the command was cancelled, or a deadline passed
+	ErrorTypeSignal           ErrorType = "signal"            // The process was terminated by a signal
+	ErrorTypeExitCode         ErrorType = "exit_code"         // The process exited with a non-zero status
+	ErrorTypeEnvFile          ErrorType = "env_file"          // An env value file could not be read
+	ErrorTypeOutputMismatch   ErrorType = "output_mismatch"   // The process exited 0 without the output its expectOutput or goldenFile asks for
+	ErrorTypeMemoryLimit      ErrorType = "memory_limit"      // The process appears to have run out of memory under its memoryLimit
+	ErrorTypeExternalReady    ErrorType = "external_ready"    // An external service the command waits for did not become ready in time
+	ErrorTypeUnknown          ErrorType = "unknown"
+)
+
+// ErrorDetail describes why a command failed
+type ErrorDetail struct {
+	Type     ErrorType `json:"type"`
+	Message  string    `json:"message"`
+	ExitCode int       `json:"exitCode"`
+}
+
+// ErrorClassifier maps a failed command to an ErrorType. Returning an empty ErrorType defers to
+// the built-in categorization, so a classifier only needs to handle the cases it cares about.
+type ErrorClassifier func(cmd config.Command, err error, exitCode int) ErrorType
+
+// categorizeError is the built-in categorization of a command failure
+func categorizeError(ctx context.Context, cmd config.Command, err error, exitCode int) ErrorType {
+	// A command killed because the run ended fails with a signal, report the cause instead
+	if ctx.Err() != nil {
+		return ErrorTypeContextCancelled
+	}
+
+	// Checked first, reading the file fails with the same fs errors as starting the process
+	var envFileErr *EnvFileError
+	if errors.As(err, &envFileErr) {
+		return ErrorTypeEnvFile
+	}
+
+	var mismatchErr *OutputMismatchError
+	var goldenErr *GoldenFileMismatchError
+	if errors.As(err, &mismatchErr) || errors.As(err, &goldenErr) {
+		return ErrorTypeOutputMismatch
+	}
+
+	var memoryErr *MemoryLimitError
+	if errors.As(err, &memoryErr) {
+		return ErrorTypeMemoryLimit
+	}
+
+	var externalErr *ExternalReadyError
+	if errors.As(err, &externalErr) {
+		return ErrorTypeExternalReady
+	}
+
+	if errors.Is(err, exec.ErrNotFound) {
+		return ErrorTypeCommandNotFound
+	}
+	if errors.Is(err, fs.ErrPermission) {
+		return ErrorTypePermissionDenied
+	}
+
+	// A missing working directory surfaces as the fork/exec of the executable failing with ENOENT
+	if cmd.WorkDir != "" && errors.Is(err, fs.ErrNotExist) {
+		if _, statErr := os.Stat(cmd.WorkDir); statErr != nil {
+			return ErrorTypeWorkDir
+		}
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		// ExitCode is -1 when the process was terminated by a signal
+		if exitErr.ExitCode() == -1 {
+			return ErrorTypeSignal
+		}
+		return ErrorTypeExitCode
+	}
+
+	if exitCode > 0 {
+		return ErrorTypeExitCode
+	}
+	return ErrorTypeUnknown
+}
@@ -0,0 +1,116 @@
+package executor
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestErrorClassifierOverridesBuiltInCategorization(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sh")
+	}
+
+	const lintErrors ErrorType = "lint_errors"
+
+	var classified []string
+	classifier := func(cmd config.Command, err error, exitCode int) ErrorType {
+		classified = append(classified, cmd.Name)
+		if cmd.Name == "lint" && exitCode == 2 {
+			return lintErrors
+		}
+		return ""
+	}
+
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "lint", Command: "sh", Args: []string{"-c", "exit 2"}, Mode: config.ModeOnce},
+			{Name: "test", Command: "sh", Args: []string{"-c", "exit 1"}, Mode: config.ModeOnce},
+		},
+	}
+
+	executor := NewExecutorWithOptions(ExecutorOptions{ContinueOnError: true, ErrorClassifier: classifier})
+	if err := executor.Execute(context.Background(), cfg); err == nil {
+		t.Fatal("Expected execution to fail")
+	}
+
+	results := executor.GetStatus().Results
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+
+	lint := results[0].ErrorDetail
+	if lint == nil || lint.Type != lintErrors || lint.ExitCode != 2 {
+		t.Errorf("Expected lint failure to be classified as %q with exit code 2, got %+v", lintErrors, lint)
+	}
+
+	// The classifier returned no type for this failure, so the built-in categorization applies
+	test := results[1].ErrorDetail
+	if test == nil || test.Type != ErrorTypeExitCode {
+		t.Errorf("Expected test failure to fall back to %q, got %+v", ErrorTypeExitCode, test)
+	}
+
+	if len(classified) != 2 {
+		t.Errorf("Expected the classifier to be consulted for both failures, got %v", classified)
+	}
+}
+
+func TestBuiltInErrorCategorization(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sh")
+	}
+
+	tests := []struct {
+		name     string
+		cmd      config.Command
+		expected ErrorType
+	}{
+		{
+			name:     "missing executable",
+			cmd:      config.Command{Name: "missing", Command: "seqr-definitely-not-a-command", Mode: config.ModeOnce},
+			expected: ErrorTypeCommandNotFound,
+		},
+		{
+			name:     "missing working directory",
+			cmd:      config.Command{Name: "nowhere", Command: "sh", Args: []string{"-c", "true"}, Mode: config.ModeOnce, WorkDir: filepath.Join(t.TempDir(), "missing")},
+			expected: ErrorTypeWorkDir,
+		},
+		{
+			name:     "non-zero exit",
+			cmd:      config.Command{Name: "exit", Command: "sh", Args: []string{"-c", "exit 3"}, Mode: config.ModeOnce},
+			expected: ErrorTypeExitCode,
+		},
+		{
+			name:     "terminated by signal",
+			cmd:      config.Command{Name: "killed", Command: "sh", Args: []string{"-c", "kill -9 $$"}, Mode: config.ModeOnce},
+			expected: ErrorTypeSignal,
+		},
+		{
+			name:     "missing env value file",
+			cmd:      config.Command{Name: "secret", Command: "sh", Args: []string{"-c", "true"}, Mode: config.ModeOnce, EnvFromFile: map[string]string{"API_TOKEN": filepath.Join(t.TempDir(), "missing")}},
+			expected: ErrorTypeEnvFile,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := NewExecutor(false)
+			cfg := &config.Config{Version: "1.0", Commands: []config.Command{tt.cmd}}
+			if err := executor.Execute(context.Background(), cfg); err == nil {
+				t.Fatal("Expected execution to fail")
+			}
+
+			results := executor.GetStatus().Results
+			if len(results) != 1 || results[0].ErrorDetail == nil {
+				t.Fatalf("Expected one result with error detail, got %+v", results)
+			}
+			if got := results[0].ErrorDetail.Type; got != tt.expected {
+				t.Errorf("Expected error type %q, got %q (%s)", tt.expected, got, results[0].ErrorDetail.Message)
+			}
+		})
+	}
+}
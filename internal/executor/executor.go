@@ -2,15 +2,19 @@ package executor
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -84,6 +88,14 @@ func (e *Executor) colorizeCommandType(cmdType string) string {
 // BackgroundLogger handles logging of background process output
 type BackgroundLogger struct {
 	logDir string
+
+	filePattern string                // Path of each process's log set by SetLogFile, in place of logDir
+	variables   config.VariableLookup // Run variables expanded in filePattern along with ${NAME}
+	dirsMu      sync.Mutex            // Guards dirs
+	dirs        map[string]bool       // Directories of expanded log paths already created
+
+	mu        sync.Mutex              // Serializes writes to rotated logs, which both output streams share
+	rotations map[string]*logRotation // Process name -> size limits of its log, set by SetLogRotation
 }
 
 // NewBackgroundLogger creates a new background logger
@@ -100,9 +112,37 @@ func NewBackgroundLogger() *BackgroundLogger {
 	return &BackgroundLogger{logDir: logDir}
 }
 
-// GetLogFile returns the log file path for a process
+// SetLogFile keeps each process's log at pattern instead of in the log directory. ${NAME} in
+// pattern is the process name, and the names variables defines, such as ${RUN_ID}, are expanded
+// too.
+func (bl *BackgroundLogger) SetLogFile(pattern string, variables config.VariableLookup) {
+	bl.filePattern = pattern
+	bl.variables = variables
+}
+
+// GetLogFile returns the log file path for a process. A path from SetLogFile has its directories
+// created.
 func (bl *BackgroundLogger) GetLogFile(processName string) string {
-	return filepath.Join(bl.logDir, fmt.Sprintf("%s.log", processName))
+	if bl.filePattern == "" {
+		return filepath.Join(bl.logDir, fmt.Sprintf("%s.log", processName))
+	}
+
+	name := func(variable string) (string, bool) {
+		return processName, variable == "NAME"
+	}
+	path := config.ExpandVariables(bl.filePattern, config.ChainLookups(name, bl.variables))
+
+	bl.dirsMu.Lock()
+	defer bl.dirsMu.Unlock()
+	if dir := filepath.Dir(path); !bl.dirs[dir] {
+		if err := os.MkdirAll(dir, 0755); err == nil {
+			if bl.dirs == nil {
+				bl.dirs = make(map[string]bool)
+			}
+			bl.dirs[dir] = true
+		}
+	}
+	return path
 }
 
 // GetLogDir returns the log directory path
@@ -112,6 +152,19 @@ func (bl *BackgroundLogger) GetLogDir() string {
 
 // WriteLog writes output to the log file
 func (bl *BackgroundLogger) WriteLog(processName, output string) error {
+	timestamp := time.Now().Format("2006-01-02 15:04:05")
+	entry := fmt.Sprintf("[%s] %s\n", timestamp, output)
+
+	bl.mu.Lock()
+	if rotation, ok := bl.rotations[processName]; ok {
+		defer bl.mu.Unlock()
+		if err := bl.prepareWrite(processName, rotation, int64(len(entry))); err != nil {
+			return err
+		}
+	} else {
+		bl.mu.Unlock()
+	}
+
 	logFile := bl.GetLogFile(processName)
 	f, err := os.OpenFile(logFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
 	if err != nil {
@@ -119,8 +172,7 @@ func (bl *BackgroundLogger) WriteLog(processName, output string) error {
 	}
 	defer f.Close()
 
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	_, err = fmt.Fprintf(f, "[%s] %s\n", timestamp, output)
+	_, err = f.WriteString(entry)
 	return err
 }
 
@@ -204,83 +256,316 @@ type Executor struct {
 	tracker         *ProcessTracker
 	monitor         *ProcessMonitor
 	streamingActive map[string]context.CancelFunc // Track active streaming sessions
+	streamWaits     map[string]*sync.WaitGroup    // Streaming goroutines of each keepAlive process, until its output is fully read
 	logger          *BackgroundLogger
+
+	maxConcurrency   int                            // Caller-supplied concurrency bound; zero defers to the config
+	continueOnError  bool                           // Keep running after a command fails and report all failures at the end
+	maxFailures      int                            // In continue-on-error mode, stop launching commands after this many failures; zero means unlimited
+	failures         []*CommandFailure              // Failed commands of the current run, in the order they failed
+	readySignals     map[string]*readySignal        // Command name -> whether it became ready, for commands waiting on it with afterReady
+	concurrencyLimit int                            // Effective concurrency bound for the current run; zero means unbounded
+	signalForwarding map[string][]string            // Signal name -> keepAlive command names, from the current config
+	stopSignals      map[string][]config.StopSignal // Command name -> shutdown escalation, from the current config
+	errorClassifier  ErrorClassifier                // Optional, consulted before the built-in error categorization
+	runner           CommandRunner                  // Starts and waits on the processes of once and keepAlive commands
+	echoCommands     bool                           // Report each command line just before it runs, like set -x
+	echoEnv          bool                           // Include env overrides in echoed command lines
+	heartbeatAfter   time.Duration                  // Log a still-running line after this much keepAlive silence; zero disables it
+	commandCancels   map[string]context.CancelFunc  // Command name -> cancel for once commands currently running
+	autoStops        sync.WaitGroup                 // KeepAlive processes waiting to be stopped at their maxLifetime
+	keepAlives       sync.WaitGroup                 // Started keepAlive processes that have not exited yet
+	cacheMu          sync.Mutex                     // Serializes access to the command cache file
+
+	failOnKeepAliveExit bool                    // Fail and cancel the run when a keepAlive process exits unexpectedly
+	cancelRun           context.CancelCauseFunc // Cancels the run in progress with the reason; nil outside Execute
+	timeout             time.Duration           // Bounds once commands and keepAlive startup; zero disables it
+	maxTotalOutputBytes int                     // Bound on the output captured across results; zero disables it
+	noTimestamps        bool                    // Leave the timestamp out of streamed output lines
+	updateGolden        bool                    // Write once commands' output to their goldenFile instead of comparing it
+	syslog              *SyslogOptions          // Where keepAlive output is also logged; nil disables it
+	syslogWarning       sync.Once               // Warn only once that syslog is unavailable
+	tracer              Tracer                  // Optional, traces runs and commands as spans
+	maxHostMemPercent   float64                 // Stop everything once host memory use exceeds this percentage; zero disables it
+	sampleHostMemory    func() (float64, error) // Returns the host's memory use in percent
+	hostMemoryInterval  time.Duration           // How often the host memory watchdog samples
+	watchdogActive      atomic.Bool             // A host memory watchdog is running
+	cancelForMemory     context.CancelCauseFunc // Cancels the run in progress when host memory runs out; nil outside Execute
+	hostMemoryErr       *HostMemoryError        // Why the watchdog stopped the current run, if it did
+	runID               string                  // Recorded in the status of each run; empty if not set
+
+	noProcessGroups     bool        // From the current config: start every command without its own process group
+	prefixOutput        bool        // From the current config: prefix every command's captured output lines with its name
+	processGroupsDenied atomic.Bool // Creating a process group failed, so later commands do not try
+}
+
+// ExecutorOptions configures an Executor created with NewExecutorWithOptions
+type ExecutorOptions struct {
+	Verbose        bool     // Enable verbose output
+	Reporter       Reporter // Optional, defaults to a console reporter on stdout
+	MaxConcurrency int      // Optional bound on concurrently running commands, overrides the config's maxConcurrency
+
+	ContinueOnError bool // Keep running after a command fails, returning all failures at the end
+	MaxFailures     int  // With ContinueOnError, stop launching commands once this many have failed; zero means unlimited
+
+	ErrorClassifier ErrorClassifier // Optional, categorizes failures before the built-in categorization
+	Runner          CommandRunner   // Optional, starts and waits on command processes; defaults to ExecRunner
+
+	EchoCommands bool // Report each command line just before it runs, like set -x
+	EchoEnv      bool // With EchoCommands, prefix echoed command lines with the command's env overrides
+
+	HeartbeatInterval time.Duration // Verbose only: log that a silent keepAlive process is still running after this long; zero disables it
+
+	FailOnKeepAliveExit bool // Fail the run and cancel the remaining commands if a keepAlive process exits unexpectedly while the run is in progress
+
+	// Timeout bounds each once command, and how long a keepAlive process may take to start. A
+	// keepAlive process that started in time is never killed by it. Zero disables it.
+	Timeout time.Duration
+
+	// MaxTotalOutputBytes bounds the output captured in the results of a run. Once exceeded, the
+	// output of the earliest successful results is dropped; failures keep theirs. Streamed and
+	// logged output is unaffected. Zero disables it.
+	MaxTotalOutputBytes int
+
+	// NoTimestamps leaves the timestamp out of streamed output lines, for consumers such as
+	// journald that timestamp lines themselves
+	NoTimestamps bool
+
+	// UpdateGolden writes the output of once commands that succeed to their goldenFile instead
+	// of comparing it
+	UpdateGolden bool
+
+	// Syslog sends the output of keepAlive commands to the system logger, alongside the console
+	// with verbose output and instead of it otherwise. Nil disables it. On Windows it warns and
+	// does nothing.
+	Syslog *SyslogOptions
+
+	// Tracer wraps each run in a span, and each command in a child span named after it. Nil
+	// disables tracing.
+	Tracer Tracer
+
+	// MaxHostMemPercent watches the host's memory use from Execute on, sampling it every few
+	// seconds, and stops every command once it exceeds this percentage, failing a run still in
+	// progress with a HostMemoryError. Zero disables it. Only Linux can be sampled; elsewhere
+	// it warns and does nothing.
+	MaxHostMemPercent float64
+
+	// RunID identifies the run in its status, and so in the last run and reports. Empty leaves
+	// it out.
+	RunID string
+
+	// LogFile keeps each command's background log at this path instead of
+	// ~/.seqr/logs/<name>.log. ${NAME} is the command's name, and ${RUN_ID}, ${DATE} and ${TIME}
+	// are RunID and RunStart as RunVariables expands them. Missing directories are created.
+	LogFile  string
+	RunStart time.Time // When the run started, for ${DATE} and ${TIME} in LogFile
 }
 
 func NewExecutor(verbose bool) *Executor {
+	return NewExecutorWithOptions(ExecutorOptions{Verbose: verbose})
+}
+
+// NewExecutorWithOptions creates an executor configured by opts
+func NewExecutorWithOptions(opts ExecutorOptions) *Executor {
+	verbose := opts.Verbose
 	tracker := NewProcessTracker()
 	monitor := NewProcessMonitor(verbose, tracker)
 
+	reporter := opts.Reporter
+	if reporter == nil {
+		reporter = NewConsoleReporter(os.Stdout, verbose)
+	}
+
+	runner := opts.Runner
+	if runner == nil {
+		runner = ExecRunner{}
+	}
+
+	logger := NewBackgroundLogger()
+	if opts.LogFile != "" {
+		logger.SetLogFile(opts.LogFile, config.RunVariables(opts.RunID, opts.RunStart))
+	}
+
 	return &Executor{
 		verbose:         verbose,
+		maxConcurrency:  opts.MaxConcurrency,
+		continueOnError: opts.ContinueOnError,
+		maxFailures:     opts.MaxFailures,
+		errorClassifier: opts.ErrorClassifier,
+		runner:          runner,
+		echoCommands:    opts.EchoCommands,
+		echoEnv:         opts.EchoEnv,
+		heartbeatAfter:  opts.HeartbeatInterval,
 		processes:       make(map[string]*exec.Cmd),
-		reporter:        NewConsoleReporter(os.Stdout, verbose),
+		reporter:        reporter,
 		tracker:         tracker,
 		monitor:         monitor,
 		streamingActive: make(map[string]context.CancelFunc),
-		logger:          NewBackgroundLogger(),
+		streamWaits:     make(map[string]*sync.WaitGroup),
+		commandCancels:  make(map[string]context.CancelFunc),
+		logger:          logger,
 		status: ExecutionStatus{
 			State:   StateReady,
 			Results: make([]ExecutionResult, 0),
 		},
+		failOnKeepAliveExit: opts.FailOnKeepAliveExit,
+		timeout:             opts.Timeout,
+		maxTotalOutputBytes: opts.MaxTotalOutputBytes,
+		noTimestamps:        opts.NoTimestamps,
+		updateGolden:        opts.UpdateGolden,
+		syslog:              opts.Syslog,
+		tracer:              opts.Tracer,
+		maxHostMemPercent:   opts.MaxHostMemPercent,
+		sampleHostMemory:    hostMemoryPercent,
+		hostMemoryInterval:  hostMemoryInterval,
+		runID:               opts.RunID,
 	}
 }
 
-func (e *Executor) Execute(ctx context.Context, cfg *config.Config) error {
+func (e *Executor) Execute(ctx context.Context, cfg *config.Config) (err error) {
 	if len(cfg.Commands) == 0 {
 		return fmt.Errorf("no commands to execute")
 	}
 
+	// Started first so the span ends last, with the error the run finally returns
+	ctx, endRunSpan := e.traceRun(ctx, cfg)
+	defer func() { endRunSpan(err) }()
+
+	// With FailOnKeepAliveExit, a keepAlive process exiting mid-run cancels the run with the
+	// reason. The context is not cancelled when Execute returns, since started keepAlive
+	// processes stay bound to it.
+	var cancelRun context.CancelCauseFunc
+	if e.failOnKeepAliveExit {
+		ctx, cancelRun = context.WithCancelCause(ctx)
+	}
+
+	// The host memory watchdog cancels the run to kill once commands still running
+	var cancelForMemory context.CancelCauseFunc
+	if e.maxHostMemPercent > 0 {
+		ctx, cancelForMemory = context.WithCancelCause(ctx)
+	}
+
 	e.mu.Lock()
 	e.status = ExecutionStatus{
+		RunID:      e.runID,
 		State:      StateReady,
-		TotalCount: len(cfg.Commands),
+		TotalCount: countedCommands(cfg.Commands),
 		Results:    make([]ExecutionResult, 0, len(cfg.Commands)),
 	}
 	e.stopped = false
+	e.failures = nil
+	e.resetReadySignals(cfg.Commands)
+	e.signalForwarding = cfg.SignalForwarding
+	e.noProcessGroups = cfg.NoProcessGroup
+	e.prefixOutput = cfg.PrefixOutput
+	e.cancelRun = cancelRun
+	e.cancelForMemory = cancelForMemory
+	e.hostMemoryErr = nil
+	e.concurrencyLimit = cfg.MaxConcurrency
+	if e.maxConcurrency > 0 {
+		e.concurrencyLimit = e.maxConcurrency
+	}
+	e.stopSignals = make(map[string][]config.StopSignal)
+	for _, cmd := range cfg.Commands {
+		if len(cmd.StopSignals) > 0 {
+			e.stopSignals[cmd.Name] = cmd.StopSignals
+		}
+	}
 	e.mu.Unlock()
 
+	defer e.recordSkipped(cfg.Commands)
+
+	// Once the queue is done, keepAlive exits no longer fail it. If one cancelled the run, that
+	// is the reason it failed, whatever error the cancellation surfaced as. The same goes for the
+	// host memory watchdog stopping it.
+	defer func() {
+		e.mu.Lock()
+		e.cancelRun = nil
+		e.cancelForMemory = nil
+		memErr := e.hostMemoryErr
+		e.mu.Unlock()
+
+		var exitErr *KeepAliveExitError
+		if errors.As(context.Cause(ctx), &exitErr) {
+			e.updateState(StateFailed, exitErr.Error())
+			err = exitErr
+		} else if memErr != nil {
+			e.updateState(StateFailed, memErr.Error())
+			err = memErr
+		}
+	}()
+
+	// Keeps watching after the run while ctx lasts, since keepAlive processes outlive it
+	e.watchHostMemory(ctx)
+
 	// Start process monitoring
 	e.monitor.StartMonitoring(ctx)
 	defer e.monitor.StopMonitoring()
 
 	// Start monitoring status changes in a separate goroutine
-	go e.handleStatusChanges(ctx)
+	go e.handleStatusChanges(ctx, e.monitor.Subscribe())
 
-	e.reporter.ReportStart(len(cfg.Commands))
+	e.currentReporter().ReportStart(len(cfg.Commands))
+	if cfg.Banner != "" {
+		e.currentReporter().ReportBanner(cfg.Banner)
+	}
 
 	// Group commands by concurrent execution
 	commandGroups := e.groupCommandsByConcurrency(cfg.Commands)
 
 	commandIndex := 0
+	stage := ""
 	for _, group := range commandGroups {
 		if e.isStopped() {
 			return fmt.Errorf("execution stopped")
 		}
 
+		if e.failureLimitReached() {
+			if e.verbose {
+				timestamp := time.Now().Format("15:04:05.000")
+				fmt.Printf("[%s] [seqr] [system] Reached the limit of %d failed commands, not starting remaining commands\n", timestamp, e.maxFailures)
+			}
+			break
+		}
+
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		default:
 		}
 
+		// Concurrent commands start together, so a group's stage headers all come first
+		for _, cmd := range group {
+			if cmd.Stage != stage && cmd.Stage != "" {
+				e.currentReporter().ReportStageStart(cmd.Stage)
+			}
+			stage = cmd.Stage
+		}
+
 		if len(group) == 1 {
 			// Single command - execute sequentially
 			cmd := group[0]
+			queuedAt := time.Now()
 			e.updateCurrentCommand(&cmd)
-			e.reporter.ReportCommandStart(cmd.Name, commandIndex)
+			e.currentReporter().ReportCommandStart(cmd.Name, commandIndex)
 
-			result, err := e.executeCommand(ctx, cmd)
+			result, err := e.executeTracedCommand(ctx, cmd, queuedAt)
 			e.addResult(result)
 
-			if err != nil {
-				e.reporter.ReportCommandFailure(result, commandIndex)
-				e.updateState(StateFailed, err.Error())
-				return err
+			if err != nil && result.AllowedFailure {
+				e.currentReporter().ReportCommandFailure(result, commandIndex)
+			} else if err != nil {
+				e.currentReporter().ReportCommandFailure(result, commandIndex)
+				e.recordFailure(result, err)
+				if !e.continueOnError {
+					e.updateState(StateFailed, err.Error())
+					return err
+				}
+			} else {
+				e.currentReporter().ReportCommandSuccess(result, commandIndex)
 			}
 
-			e.reporter.ReportCommandSuccess(result, commandIndex)
-			e.updateCompletedCount(commandIndex + 1)
+			e.countCompleted(cmd)
 			commandIndex++
 		} else {
 			// Multiple concurrent commands - execute in parallel
@@ -290,39 +575,364 @@ func (e *Executor) Execute(ctx context.Context, cfg *config.Config) error {
 		}
 	}
 
+	// In continue-on-error mode, report every failure together once the queue is done
+	if err := e.failuresError(len(cfg.Commands)); err != nil {
+		e.updateState(StateFailed, err.Error())
+		e.currentReporter().ReportExecutionComplete(e.GetStatus())
+		return err
+	}
+
 	e.updateState(StateSuccess, "")
 	status := e.GetStatus()
-	e.reporter.ReportExecutionComplete(status)
+	e.currentReporter().ReportExecutionComplete(status)
+	if cfg.Footer != "" {
+		e.currentReporter().ReportFooter(cfg.Footer)
+	}
 	return nil
 }
 
+// RunCommand runs a single normalized command without a queue around it, with the same env,
+// working directory, mode handling, timeout and output streaming as a command run by Execute,
+// and returns its result. The executor's status is left as it is. A keepAlive command keeps
+// running after RunCommand returns, until Stop terminates it.
+func (e *Executor) RunCommand(ctx context.Context, cmd config.Command) (ExecutionResult, error) {
+	if len(cmd.StopSignals) > 0 {
+		e.mu.Lock()
+		if e.stopSignals == nil {
+			e.stopSignals = make(map[string][]config.StopSignal)
+		}
+		e.stopSignals[cmd.Name] = cmd.StopSignals
+		e.mu.Unlock()
+	}
+
+	return e.executeTracedCommand(ctx, cmd, time.Now())
+}
+
+// executeQueuedCommand executes a command that became eligible to run at queuedAt, recording
+// how long it waited before exec began alongside how long it ran
+func (e *Executor) executeQueuedCommand(ctx context.Context, cmd config.Command, queuedAt time.Time) (ExecutionResult, error) {
+	err := e.waitForAfterReady(ctx, cmd)
+	if err == nil && cmd.ExternalReady != nil {
+		err = e.waitForExternalReady(ctx, cmd)
+	}
+	if err != nil {
+		e.signalReady(cmd.Name, false)
+		result := afterReadyFailure(cmd, err)
+		result.recordTiming(queuedAt)
+		result.ExitReason = ExitReasonStartFailed
+		result.ErrorDetail = &ErrorDetail{
+			Type:     e.classifyError(ctx, cmd, err, result.ExitCode),
+			Message:  err.Error(),
+			ExitCode: result.ExitCode,
+		}
+		return result, err
+	}
+
+	// Skip once commands whose cacheKey inputs are unchanged since their last successful run
+	var cacheHash string
+	if cmd.Mode == config.ModeOnce && len(cmd.CacheKey) > 0 {
+		hash, err := computeCacheHash(cmd)
+		if err != nil {
+			e.logCacheWarning(cmd.Name, err)
+		} else if e.cacheHit(cmd.Name, hash) {
+			result := cachedResult(cmd)
+			result.recordTiming(queuedAt)
+			e.signalReady(cmd.Name, true)
+			return result, nil
+		} else {
+			cacheHash = hash
+		}
+	}
+
+	// Once commands get their own context so CancelCommand can abort one without stopping the run,
+	// and the timeout can kill it. KeepAlive commands outlive this call, so they stay bound to the
+	// run's context and only their startup is checked against the timeout.
+	runCtx := ctx
+	if cmd.Mode == config.ModeOnce {
+		var cmdCtx context.Context
+		var cancel context.CancelFunc
+		if e.timeout > 0 {
+			cmdCtx, cancel = context.WithTimeout(ctx, e.timeout)
+		} else {
+			cmdCtx, cancel = context.WithCancel(ctx)
+		}
+		defer cancel()
+
+		e.mu.Lock()
+		e.commandCancels[cmd.Name] = cancel
+		e.mu.Unlock()
+		defer func() {
+			e.mu.Lock()
+			delete(e.commandCancels, cmd.Name)
+			e.mu.Unlock()
+		}()
+
+		ctx = cmdCtx
+	}
+
+	// A keepAlive command has to start, and become ready, within the timeout. Its process is
+	// not bound to this deadline, only the wait for it.
+	readyCtx := ctx
+	if cmd.Mode == config.ModeKeepAlive && e.timeout > 0 {
+		var cancel context.CancelFunc
+		readyCtx, cancel = context.WithTimeout(ctx, e.timeout)
+		defer cancel()
+	}
+
+	result, err := e.executeCommand(ctx, cmd)
+	if err != nil && cmd.Mode == config.ModeOnce && errors.Is(ctx.Err(), context.DeadlineExceeded) && runCtx.Err() == nil {
+		err = fmt.Errorf("command '%s' timed out after %v: %w", cmd.Name, e.timeout, err)
+		result.Error = err.Error()
+	}
+	if err != nil && cmd.MemoryLimit > 0 && ctx.Err() == nil && exceededMemoryLimit(err, result.Output) {
+		err = &MemoryLimitError{CommandName: cmd.Name, Limit: cmd.MemoryLimit, Err: err}
+		result.Error = err.Error()
+	}
+	if err == nil && cmd.Mode == config.ModeKeepAlive && e.timeout > 0 && result.Duration > e.timeout {
+		err = e.stopSlowStart(cmd.Name, result.Duration)
+		result.Success = false
+		result.Error = err.Error()
+	}
+	if err == nil && cmd.Mode == config.ModeKeepAlive && (cmd.ReadyFile != "" || cmd.ReadyTCP != "") {
+		// One readyTimeout covers both checks
+		readyBy := time.Now().Add(readyTimeout(cmd))
+		if cmd.ReadyFile != "" {
+			err = e.waitForReadyFile(readyCtx, cmd, readyBy)
+		}
+		if err == nil && cmd.ReadyTCP != "" {
+			err = e.waitForReadyTCP(readyCtx, cmd, readyBy)
+		}
+		if err != nil && errors.Is(readyCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+			e.stopKeepAlive(cmd.Name)
+			err = fmt.Errorf("keepAlive command '%s' was not ready within the %v timeout: %w", cmd.Name, e.timeout, err)
+		}
+		if err != nil {
+			result.Success = false
+			result.Error = err.Error()
+		}
+		result.EndTime = time.Now()
+		result.Duration = result.EndTime.Sub(result.StartTime)
+	}
+	result.recordTiming(queuedAt)
+	result.ExitReason, result.Signal = exitReasonOf(ctx, result, err)
+	if cacheHash != "" {
+		e.updateCache(cmd.Name, cacheHash, err == nil)
+	}
+	if err != nil {
+		result.ErrorDetail = &ErrorDetail{
+			Type:     e.classifyError(ctx, cmd, err, result.ExitCode),
+			Message:  err.Error(),
+			ExitCode: result.ExitCode,
+		}
+		// A command cut short because the run was interrupted or stopped did not fail on its own
+		result.AllowedFailure = cmd.AllowFailure && runCtx.Err() == nil && !e.isStopped()
+	}
+	e.signalReady(cmd.Name, err == nil)
+	return result, err
+}
+
+// classifyError categorizes a command failure, consulting the custom classifier first if one is set
+func (e *Executor) classifyError(ctx context.Context, cmd config.Command, err error, exitCode int) ErrorType {
+	if e.errorClassifier != nil {
+		if errorType := e.errorClassifier(cmd, err, exitCode); errorType != "" {
+			return errorType
+		}
+	}
+	return categorizeError(ctx, cmd, err, exitCode)
+}
+
+// executeCommand runs a command in its own process group unless it or the config opts out. Where
+// the platform refuses to create one, as in sandboxes that forbid setpgid, the command is started
+// again without one, and later commands skip the attempt.
 func (e *Executor) executeCommand(ctx context.Context, cmd config.Command) (ExecutionResult, error) {
+	e.mu.RLock()
+	processGroup := !cmd.NoProcessGroup && !e.noProcessGroups
+	prefixOutput := cmd.PrefixOutput || e.prefixOutput
+	e.mu.RUnlock()
+	processGroup = processGroup && !e.processGroupsDenied.Load()
+
+	if e.echoCommands {
+		// Echo the arguments the command runs with; an unreadable argsFile fails it right after
+		echoed := cmd
+		if args, err := commandArgs(cmd); err == nil {
+			echoed.Args = args
+		}
+		e.currentReporter().ReportCommandLine(cmd.Name, buildCommandLine(echoed, e.echoEnv))
+	}
+
+	// Opened once for both start attempts, so the reader sees one stream. A keepAlive command that
+	// starts keeps its own hold on the FIFO while its output is streamed.
+	fifo, err := openOutputFifo(cmd)
+	if err != nil {
+		now := time.Now()
+		return ExecutionResult{Command: cmd, StartTime: now, EndTime: now, Error: err.Error(), ExitCode: -1}, err
+	}
+	defer fifo.Close()
+
+	result, err := e.startCommand(ctx, cmd, processGroup, fifo)
+	if err != nil && processGroup && isProcessGroupDenied(err) {
+		e.processGroupsDenied.Store(true)
+		if e.verbose {
+			timestamp := time.Now().Format("15:04:05.000")
+			fmt.Printf("[%s] [%s] [system] Warning: Creating a process group was denied (%v), running commands without one\n", timestamp, cmd.Name, err)
+		}
+		result, err = e.startCommand(ctx, cmd, false, fifo)
+	}
+
+	// Checked before filtering and prefixing, so patterns see the output as the command wrote it
+	if err == nil && cmd.Mode == config.ModeOnce {
+		if err = checkExpectedOutput(cmd, result.Output); err != nil {
+			result.Success = false
+			result.Error = err.Error()
+		} else if err = checkGoldenFile(cmd, result.Output, e.updateGolden); err != nil {
+			result.Success = false
+			result.Error = err.Error()
+		}
+	}
+
+	result.Output = filterCaptured(cmd, result.Output)
+
+	// The console already attributes streamed lines, only the captured copy needs the prefix
+	if prefixOutput {
+		result.Output = prefixLines(cmd.Name, result.Output)
+	}
+	return result, err
+}
+
+// prefixLines prefixes each line of output with "[name] "
+func prefixLines(name, output string) string {
+	if output == "" {
+		return output
+	}
+
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		lines[i] = "[" + name + "] " + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// startCommand starts a command, optionally in its own process group, and runs it according to its mode
+func (e *Executor) startCommand(ctx context.Context, cmd config.Command, processGroup bool, fifo *fifoSink) (ExecutionResult, error) {
 	result := ExecutionResult{
 		Command:   cmd,
 		StartTime: time.Now(),
 	}
 
-	execCmd := exec.CommandContext(ctx, cmd.Command, cmd.Args...)
+	args, err := commandArgs(cmd)
+	if err != nil {
+		result.EndTime = time.Now()
+		result.Duration = result.EndTime.Sub(result.StartTime)
+		result.Success = false
+		result.Error = err.Error()
+		result.ExitCode = -1
+		return result, err
+	}
+
+	if cmd.CreateWorkDir {
+		if err := os.MkdirAll(cmd.WorkDir, 0755); err != nil {
+			err = fmt.Errorf("failed to create workDir '%s': %w", cmd.WorkDir, err)
+			result.EndTime = time.Now()
+			result.Duration = result.EndTime.Sub(result.StartTime)
+			result.Success = false
+			result.Error = err.Error()
+			result.ExitCode = -1
+			return result, err
+		}
+	}
+
+	if err := removeStaleReadyFile(cmd); err != nil {
+		result.EndTime = time.Now()
+		result.Duration = result.EndTime.Sub(result.StartTime)
+		result.Success = false
+		result.Error = err.Error()
+		result.ExitCode = -1
+		return result, err
+	}
+
+	e.logger.SetLogRotation(cmd.Name, cmd.LogMaxSize, cmd.LogMaxFiles)
+
+	execCmd := exec.CommandContext(ctx, cmd.Command, args...)
 
 	if cmd.WorkDir != "" {
 		execCmd.Dir = cmd.WorkDir
 	}
 
-	if len(cmd.Env) > 0 {
-		execCmd.Env = os.Environ()
-		for key, value := range cmd.Env {
-			execCmd.Env = append(execCmd.Env, fmt.Sprintf("%s=%s", key, value))
+	if len(cmd.Env) > 0 || len(cmd.EnvFromFile) > 0 {
+		env, err := BuildCommandEnv(cmd)
+		if err != nil {
+			result.EndTime = time.Now()
+			result.Duration = result.EndTime.Sub(result.StartTime)
+			result.Success = false
+			result.Error = err.Error()
+			result.ExitCode = -1
+			return result, err
 		}
+		execCmd.Env = env
 	}
 
 	// Configure process group for proper child process cleanup
-	e.configureProcessGroup(execCmd)
+	if processGroup {
+		e.configureProcessGroup(execCmd)
+	}
+
+	// When the context is cancelled or its deadline passes, kill the whole process group rather
+	// than only the direct child, so grandchildren such as a shell's subprocesses are not orphaned
+	execCmd.Cancel = func() error {
+		if e.verbose {
+			timestamp := time.Now().Format("15:04:05.000")
+			fmt.Printf("[%s] [%s] [process] Context ended (%v), killing process group %d\n", timestamp, cmd.Name, ctx.Err(), execCmd.Process.Pid)
+		}
+		if err := e.killProcessGroup(execCmd.Process.Pid, false); err != nil {
+			return execCmd.Process.Kill()
+		}
+		return nil
+	}
+
+	result.ResolvedPath = resolvedCommandPath(execCmd)
+	if e.verbose && result.ResolvedPath != "" {
+		timestamp := time.Now().Format("15:04:05.000")
+		fmt.Printf("[%s] [%s] [process] Resolved executable: %s\n", timestamp, cmd.Name, result.ResolvedPath)
+	}
+
+	if cmd.MemoryLimit > 0 {
+		// Printed without -v too, since the command then runs without the limit it asked for
+		if err := limitMemory(execCmd, cmd.MemoryLimit); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: command '%s': %v, running without it\n", cmd.Name, err)
+		}
+	}
+
+	// Commands read from the null device, opened here rather than left to os/exec so that every
+	// mode gets a real end of input instead of a pipe. A PTY command reads its terminal instead.
+	if cmd.Mode != config.ModeOnce || !cmd.PTY {
+		stdin, err := os.Open(os.DevNull)
+		if err != nil {
+			result.EndTime = time.Now()
+			result.Duration = result.EndTime.Sub(result.StartTime)
+			result.Success = false
+			result.Error = fmt.Sprintf("failed to open %s for stdin: %v", os.DevNull, err)
+			result.ExitCode = -1
+			return result, err
+		}
+		// The process has its own copy once started
+		defer stdin.Close()
+		execCmd.Stdin = stdin
+	}
 
 	switch cmd.Mode {
 	case config.ModeOnce:
-		return e.executeOnce(execCmd, result)
+		if len(cmd.Filter) > 0 {
+			return e.executeOnceWithFilter(ctx, execCmd, result, fifo)
+		}
+		if cmd.PTY {
+			return e.executeOnceWithPTY(execCmd, result, fifo)
+		}
+		return e.executeOnce(ctx, execCmd, result, fifo)
 	case config.ModeKeepAlive:
-		return e.executeKeepAlive(execCmd, result, cmd.Name)
+		// Its output outlives this call, so its streaming holds the FIFO until the output ends,
+		// or releases it at once if the process does not start
+		fifo.retain()
+		return e.executeKeepAlive(ctx, execCmd, result, cmd.Name, fifo)
 	default:
 		result.EndTime = time.Now()
 		result.Duration = result.EndTime.Sub(result.StartTime)
@@ -332,26 +942,120 @@ func (e *Executor) executeCommand(ctx context.Context, cmd config.Command) (Exec
 	}
 }
 
-func (e *Executor) executeOnce(execCmd *exec.Cmd, result ExecutionResult) (ExecutionResult, error) {
+// CommandLine renders a command as the shell command line it runs, without its env overrides
+func CommandLine(cmd config.Command) string {
+	return buildCommandLine(cmd, false)
+}
+
+// buildCommandLine renders a command as a shell command line, quoting arguments where needed.
+// With includeEnv, the command's env overrides are prefixed as KEY=value assignments; values read
+// from files are rendered as a $(cat file) substitution rather than printed. A filter is rendered
+// as a pipeline stage.
+func buildCommandLine(cmd config.Command, includeEnv bool) string {
+	var parts []string
+
+	if includeEnv {
+		keys := make([]string, 0, len(cmd.Env)+len(cmd.EnvFromFile))
+		for key := range cmd.Env {
+			keys = append(keys, key)
+		}
+		for key := range cmd.EnvFromFile {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if path, fromFile := cmd.EnvFromFile[key]; fromFile {
+				parts = append(parts, key+`="$(cat `+shellQuote(path)+`)"`)
+				continue
+			}
+			parts = append(parts, key+"="+shellQuote(cmd.Env[key]))
+		}
+	}
+
+	parts = append(parts, shellQuote(cmd.Command))
+	for _, arg := range cmd.Args {
+		parts = append(parts, shellQuote(arg))
+	}
+
+	if len(cmd.Filter) > 0 {
+		parts = append(parts, "|")
+		for _, word := range cmd.Filter {
+			parts = append(parts, shellQuote(word))
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// shellQuote single-quotes s unless it consists only of characters a shell leaves alone
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+
+	safe := true
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("_-+=@%:,./", r)) {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return s
+	}
+
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// resolvedCommandPath returns the absolute path of the executable exec resolved for the command,
+// or an empty string when the lookup failed
+func resolvedCommandPath(execCmd *exec.Cmd) string {
+	if execCmd.Err != nil || execCmd.Path == "" {
+		return ""
+	}
+
+	path := execCmd.Path
+	if !filepath.IsAbs(path) {
+		// Relative paths such as ./script.sh are resolved against the working directory
+		path = filepath.Join(execCmd.Dir, path)
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return ""
+	}
+	return absPath
+}
+
+func (e *Executor) executeOnce(ctx context.Context, execCmd *exec.Cmd, result ExecutionResult, fifo *fifoSink) (ExecutionResult, error) {
 	if e.verbose {
-		return e.executeOnceWithRealTimeOutput(execCmd, result)
+		return e.executeOnceWithRealTimeOutput(ctx, execCmd, result, fifo)
 	}
 
-	// Non-verbose mode: use existing behavior
-	output, err := execCmd.CombinedOutput()
+	// Non-verbose mode: capture stdout and stderr together, like CombinedOutput, copying the lines
+	// the command shows to the FIFO as they arrive
+	var output bytes.Buffer
+	var combined io.Writer = &output
+	if fifo != nil {
+		toFifo := newFilteredWriter(fifo, newLineFilter(result.Command))
+		defer toFifo.Close()
+		combined = io.MultiWriter(&output, toFifo)
+	}
+	execCmd.Stdout = combined
+	execCmd.Stderr = combined
+	err := e.runner.Start(ctx, execCmd)
+	if err == nil {
+		err = e.runner.Wait(execCmd)
+	}
 
 	result.EndTime = time.Now()
 	result.Duration = result.EndTime.Sub(result.StartTime)
-	result.Output = strings.TrimSpace(string(output))
+	result.Output = strings.TrimSpace(string(decodeOutput(result.Command, output.Bytes())))
 
 	if err != nil {
 		result.Success = false
 		result.Error = err.Error()
-		if exitError, ok := err.(*exec.ExitError); ok {
-			result.ExitCode = exitError.ExitCode()
-		} else {
-			result.ExitCode = -1
-		}
+		result.ExitCode = exitCodeOf(err)
 		return result, err
 	}
 
@@ -360,9 +1064,10 @@ func (e *Executor) executeOnce(execCmd *exec.Cmd, result ExecutionResult) (Execu
 	return result, nil
 }
 
-func (e *Executor) executeOnceWithRealTimeOutput(execCmd *exec.Cmd, result ExecutionResult) (ExecutionResult, error) {
-	// Create pipes for stdout and stderr
-	stdoutPipe, err := execCmd.StdoutPipe()
+func (e *Executor) executeOnceWithRealTimeOutput(ctx context.Context, execCmd *exec.Cmd, result ExecutionResult, fifo *fifoSink) (ExecutionResult, error) {
+	// Create pipes for stdout and stderr. Unlike StdoutPipe, Wait leaves these open, so output
+	// still buffered when the process exits is not lost.
+	stdoutPipe, stdoutWriter, err := os.Pipe()
 	if err != nil {
 		result.EndTime = time.Now()
 		result.Duration = result.EndTime.Sub(result.StartTime)
@@ -372,8 +1077,10 @@ func (e *Executor) executeOnceWithRealTimeOutput(execCmd *exec.Cmd, result Execu
 		return result, err
 	}
 
-	stderrPipe, err := execCmd.StderrPipe()
+	stderrPipe, stderrWriter, err := os.Pipe()
 	if err != nil {
+		stdoutPipe.Close()
+		stdoutWriter.Close()
 		result.EndTime = time.Now()
 		result.Duration = result.EndTime.Sub(result.StartTime)
 		result.Success = false
@@ -382,8 +1089,17 @@ func (e *Executor) executeOnceWithRealTimeOutput(execCmd *exec.Cmd, result Execu
 		return result, err
 	}
 
-	// Start the command
-	if err := execCmd.Start(); err != nil {
+	execCmd.Stdout = stdoutWriter
+	execCmd.Stderr = stderrWriter
+
+	// Start the command, then close our copies of the write ends so the reads end at EOF once
+	// the process and anything it spawned have closed theirs
+	err = e.runner.Start(ctx, execCmd)
+	stdoutWriter.Close()
+	stderrWriter.Close()
+	if err != nil {
+		stdoutPipe.Close()
+		stderrPipe.Close()
 		result.EndTime = time.Now()
 		result.Duration = result.EndTime.Sub(result.StartTime)
 		result.Success = false
@@ -395,6 +1111,8 @@ func (e *Executor) executeOnceWithRealTimeOutput(execCmd *exec.Cmd, result Execu
 	// Capture output in real-time
 	var outputBuilder strings.Builder
 	var wg sync.WaitGroup
+	sink := withFifo(nil, fifo)
+	lines := newLineFilter(result.Command)
 
 	// Stream stdout with proper error handling
 	wg.Add(1)
@@ -407,7 +1125,7 @@ func (e *Executor) executeOnceWithRealTimeOutput(execCmd *exec.Cmd, result Execu
 				os.Stdout.Sync()
 			}
 		}()
-		e.streamOutput(stdoutPipe, &outputBuilder, result.Command.Name, "stdout", result.Command.Command)
+		e.streamOutput(decodeOutputStream(result.Command, stdoutPipe), &outputBuilder, result.Command.Name, "stdout", result.Command.Command, sink, lines, result.Command.DedupeOutput)
 	}()
 
 	// Stream stderr with proper error handling
@@ -421,14 +1139,21 @@ func (e *Executor) executeOnceWithRealTimeOutput(execCmd *exec.Cmd, result Execu
 				os.Stdout.Sync()
 			}
 		}()
-		e.streamOutput(stderrPipe, &outputBuilder, result.Command.Name, "stderr", result.Command.Command)
+		e.streamOutput(decodeOutputStream(result.Command, stderrPipe), &outputBuilder, result.Command.Name, "stderr", result.Command.Command, sink, lines, result.Command.DedupeOutput)
 	}()
 
 	// Wait for command to complete
-	err = execCmd.Wait()
+	err = e.runner.Wait(execCmd)
 
-	// Wait for all output streaming to complete
-	wg.Wait()
+	// Wait for the remaining output, but not forever: a background child that inherited the
+	// pipes would keep them open after the command itself exited
+	if !waitForStreams(&wg, streamDrainTimeout) {
+		stdoutPipe.Close()
+		stderrPipe.Close()
+		wg.Wait()
+	}
+	stdoutPipe.Close()
+	stderrPipe.Close()
 
 	result.EndTime = time.Now()
 	result.Duration = result.EndTime.Sub(result.StartTime)
@@ -437,11 +1162,7 @@ func (e *Executor) executeOnceWithRealTimeOutput(execCmd *exec.Cmd, result Execu
 	if err != nil {
 		result.Success = false
 		result.Error = err.Error()
-		if exitError, ok := err.(*exec.ExitError); ok {
-			result.ExitCode = exitError.ExitCode()
-		} else {
-			result.ExitCode = -1
-		}
+		result.ExitCode = exitCodeOf(err)
 		return result, err
 	}
 
@@ -475,7 +1196,19 @@ func (e *Executor) detectCommandType(command string) string {
 	return "exec"
 }
 
-func (e *Executor) streamOutput(pipe io.ReadCloser, outputBuilder *strings.Builder, commandName, streamType, command string) {
+// streamPrefix returns the "[timestamp] [type] [name]" prefix of a streamed output line, leaving
+// out the timestamp when timestamps are disabled
+func (e *Executor) streamPrefix(timestamp, cmdType, commandName string) string {
+	if e.noTimestamps {
+		return fmt.Sprintf("[%s] [%s]", cmdType, commandName)
+	}
+	return fmt.Sprintf("[%s] [%s] [%s]", timestamp, cmdType, commandName)
+}
+
+// streamOutput writes a once command's output to the console and background log, and to sink
+// unless it is nil, line by line, leaving out the lines lines does not show and collapsing runs
+// of identical lines with dedupe, and captures all of it into outputBuilder
+func (e *Executor) streamOutput(pipe io.ReadCloser, outputBuilder *strings.Builder, commandName, streamType, command string, sink syslogSink, lines *lineFilter, dedupe bool) {
 	defer func() {
 		if r := recover(); r != nil {
 			fmt.Printf("[%s] [%s] ❌ Streaming panic recovered: %v\n",
@@ -485,9 +1218,32 @@ func (e *Executor) streamOutput(pipe io.ReadCloser, outputBuilder *strings.Build
 	}()
 
 	cmdType := e.detectCommandType(command)
+	deduper := &lineDeduper{enabled: dedupe}
 	scanner := bufio.NewScanner(pipe)
 	for scanner.Scan() {
 		line := scanner.Text()
+
+		// Captured in full, only what is shown is filtered and collapsed
+		outputBuilder.WriteString(line)
+		outputBuilder.WriteString("\n")
+
+		if !lines.shows(line) {
+			continue
+		}
+		show, repeats := deduper.add(line)
+		if !show {
+			continue
+		}
+		e.noteRepeats(cmdType, commandName, repeats, sink)
+
+		if sink != nil {
+			if streamType == "stderr" {
+				sink.Err(line)
+			} else {
+				sink.Info(line)
+			}
+		}
+
 		timestamp := time.Now().Format("15:04:05.000")
 
 		// Colorize based on command type and stream type
@@ -500,10 +1256,10 @@ func (e *Executor) streamOutput(pipe io.ReadCloser, outputBuilder *strings.Build
 		var icon string
 		if streamType == "stderr" {
 			icon = colorize("❌", colorRed)
-			fmt.Printf("[%s] [%s] [%s] %s %s\n", coloredTimestamp, coloredType, coloredName, icon, line)
+			fmt.Printf("%s %s %s\n", e.streamPrefix(coloredTimestamp, coloredType, coloredName), icon, line)
 		} else {
 			icon = colorize("✓", colorGreen)
-			fmt.Printf("[%s] [%s] [%s] %s %s\n", coloredTimestamp, coloredType, coloredName, icon, line)
+			fmt.Printf("%s %s %s\n", e.streamPrefix(coloredTimestamp, coloredType, coloredName), icon, line)
 		}
 
 		// Ensure immediate output by flushing stdout
@@ -512,11 +1268,8 @@ func (e *Executor) streamOutput(pipe io.ReadCloser, outputBuilder *strings.Build
 		// Log to background logger for persistent storage
 		logLine := fmt.Sprintf("[%s] [%s] %s %s", coloredTimestamp, coloredType, icon, line)
 		e.logger.WriteLog(commandName, logLine)
-
-		// Also capture for the result output
-		outputBuilder.WriteString(line)
-		outputBuilder.WriteString("\n")
 	}
+	e.noteRepeats(cmdType, commandName, deduper.flush(), sink)
 
 	if err := scanner.Err(); err != nil && !strings.Contains(err.Error(), "file already closed") {
 		timestamp := time.Now().Format("15:04:05.000")
@@ -524,19 +1277,20 @@ func (e *Executor) streamOutput(pipe io.ReadCloser, outputBuilder *strings.Build
 		coloredType := e.colorizeCommandType(cmdType)
 		coloredName := colorize(commandName, colorCyan)
 		errorIcon := colorize("❌", colorRed)
-		fmt.Printf("[%s] [%s] [%s] %s Error reading %s: %v\n",
-			coloredTimestamp, coloredType, coloredName, errorIcon, streamType, err)
+		fmt.Printf("%s %s Error reading %s: %v\n",
+			e.streamPrefix(coloredTimestamp, coloredType, coloredName), errorIcon, streamType, err)
 		os.Stdout.Sync()
 	}
 }
 
-func (e *Executor) executeKeepAlive(execCmd *exec.Cmd, result ExecutionResult, name string) (ExecutionResult, error) {
-	if e.verbose {
-		return e.executeKeepAliveWithRealTimeOutput(execCmd, result, name)
+func (e *Executor) executeKeepAlive(ctx context.Context, execCmd *exec.Cmd, result ExecutionResult, name string, fifo *fifoSink) (ExecutionResult, error) {
+	if sink := withFifo(e.openSyslog(name), fifo); e.verbose || sink != nil {
+		return e.executeKeepAliveWithRealTimeOutput(ctx, execCmd, result, name, sink)
 	}
 
-	// Non-verbose mode: use existing behavior
-	err := execCmd.Start()
+	// Non-verbose mode: nothing reads the output, so stdout and stderr are left nil, which os/exec
+	// connects to the null device. A pipe here would fill up and block a chatty process.
+	err := e.runner.Start(ctx, execCmd)
 
 	result.EndTime = time.Now()
 	result.Duration = result.EndTime.Sub(result.StartTime)
@@ -568,7 +1322,14 @@ func (e *Executor) executeKeepAlive(execCmd *exec.Cmd, result ExecutionResult, n
 	// Add process to monitoring
 	e.monitor.AddProcess(execCmd.Process.Pid, name)
 
-	go e.monitorProcess(name, execCmd)
+	exited := make(chan struct{})
+	e.keepAlives.Add(1)
+	go func() {
+		defer e.keepAlives.Done()
+		e.monitorProcess(name, execCmd)
+		close(exited)
+	}()
+	e.scheduleAutoStop(name, execCmd, result.Command.MaxLifetime, exited)
 
 	result.Success = true
 	result.ExitCode = 0
@@ -576,9 +1337,23 @@ func (e *Executor) executeKeepAlive(execCmd *exec.Cmd, result ExecutionResult, n
 	return result, nil
 }
 
-func (e *Executor) executeKeepAliveWithRealTimeOutput(execCmd *exec.Cmd, result ExecutionResult, name string) (ExecutionResult, error) {
-	// Create pipes for stdout and stderr
-	stdoutPipe, err := execCmd.StdoutPipe()
+// executeKeepAliveWithRealTimeOutput starts a keepAlive command with its output streamed to the
+// console with verbose output, and to sink unless it is nil. The executor closes sink once the
+// output ends.
+func (e *Executor) executeKeepAliveWithRealTimeOutput(ctx context.Context, execCmd *exec.Cmd, result ExecutionResult, name string, sink syslogSink) (ExecutionResult, error) {
+	if sink != nil {
+		defer func() {
+			// Without a started process, no stream will close it
+			if execCmd.Process == nil {
+				sink.Close()
+			}
+		}()
+	}
+
+	// Create pipes for stdout and stderr. These are plain OS pipes rather than StdoutPipe and
+	// StderrPipe, which Wait closes as soon as the process exits, discarding any output still
+	// buffered in them.
+	stdoutPipe, stdoutWriter, err := os.Pipe()
 	if err != nil {
 		result.EndTime = time.Now()
 		result.Duration = result.EndTime.Sub(result.StartTime)
@@ -588,8 +1363,10 @@ func (e *Executor) executeKeepAliveWithRealTimeOutput(execCmd *exec.Cmd, result
 		return result, err
 	}
 
-	stderrPipe, err := execCmd.StderrPipe()
+	stderrPipe, stderrWriter, err := os.Pipe()
 	if err != nil {
+		stdoutPipe.Close()
+		stdoutWriter.Close()
 		result.EndTime = time.Now()
 		result.Duration = result.EndTime.Sub(result.StartTime)
 		result.Success = false
@@ -598,8 +1375,17 @@ func (e *Executor) executeKeepAliveWithRealTimeOutput(execCmd *exec.Cmd, result
 		return result, err
 	}
 
-	// Start the command
-	if err := execCmd.Start(); err != nil {
+	execCmd.Stdout = stdoutWriter
+	execCmd.Stderr = stderrWriter
+
+	// Start the command, then close our copies of the write ends so the reads end at EOF once
+	// the process and anything it spawned have closed theirs
+	err = e.runner.Start(ctx, execCmd)
+	stdoutWriter.Close()
+	stderrWriter.Close()
+	if err != nil {
+		stdoutPipe.Close()
+		stderrPipe.Close()
 		result.EndTime = time.Now()
 		result.Duration = result.EndTime.Sub(result.StartTime)
 		result.Success = false
@@ -631,29 +1417,53 @@ func (e *Executor) executeKeepAliveWithRealTimeOutput(execCmd *exec.Cmd, result
 	// Create streaming context that can be cancelled independently of process lifecycle
 	streamCtx, streamCancel := context.WithCancel(context.Background())
 
-	// Track the streaming session
+	// Start streaming output in background goroutines with proper lifecycle management
+	var streamWg sync.WaitGroup
+
+	// Track the streaming session. Output going only to syslog or a FIFO has no console to detach
+	// from.
 	e.mu.Lock()
-	e.streamingActive[name] = streamCancel
+	if e.verbose {
+		e.streamingActive[name] = streamCancel
+	}
+	e.streamWaits[name] = &streamWg
 	e.mu.Unlock()
 
-	// Start streaming output in background goroutines with proper lifecycle management
-	var streamWg sync.WaitGroup
+	// Output on either stream resets the heartbeat, which stops along with streaming
+	var heartbeat *outputHeartbeat
+	if e.verbose {
+		heartbeat = newOutputHeartbeat(e.heartbeatAfter)
+	}
+	go heartbeat.run(streamCtx, e, name, result.Command.Command)
 
+	lines := newLineFilter(result.Command)
 	streamWg.Add(2)
 	go func() {
 		defer streamWg.Done()
-		e.streamOutputContinuousWithContext(streamCtx, stdoutPipe, name, "stdout", result.Command.Command)
+		e.streamOutputContinuousWithContext(streamCtx, decodeOutputStream(result.Command, stdoutPipe), name, "stdout", result.Command.Command, heartbeat, sink, lines, result.Command.DedupeOutput)
 	}()
 
 	go func() {
 		defer streamWg.Done()
-		e.streamOutputContinuousWithContext(streamCtx, stderrPipe, name, "stderr", result.Command.Command)
+		e.streamOutputContinuousWithContext(streamCtx, decodeOutputStream(result.Command, stderrPipe), name, "stderr", result.Command.Command, heartbeat, sink, lines, result.Command.DedupeOutput)
 	}()
 
+	if sink != nil {
+		go func() {
+			streamWg.Wait()
+			sink.Close()
+		}()
+	}
+
 	// Monitor the process and streaming lifecycle
+	exited := make(chan struct{})
+	e.keepAlives.Add(1)
 	go func() {
-		e.monitorProcessWithStreaming(name, execCmd, streamCancel, &streamWg)
+		defer e.keepAlives.Done()
+		e.monitorProcessWithStreaming(name, execCmd, streamCancel, &streamWg, stdoutPipe, stderrPipe)
+		close(exited)
 	}()
+	e.scheduleAutoStop(name, execCmd, result.Command.MaxLifetime, exited)
 
 	result.EndTime = time.Now()
 	result.Duration = result.EndTime.Sub(result.StartTime)
@@ -700,10 +1510,10 @@ func (e *Executor) streamOutputContinuous(pipe io.ReadCloser, commandName, strea
 		var icon string
 		if streamType == "stderr" {
 			icon = colorize("❌", colorRed)
-			fmt.Printf("[%s] [%s] [%s] %s %s\n", coloredTimestamp, coloredType, coloredName, icon, line)
+			fmt.Printf("%s %s %s\n", e.streamPrefix(coloredTimestamp, coloredType, coloredName), icon, line)
 		} else {
 			icon = colorize("✓", colorGreen)
-			fmt.Printf("[%s] [%s] [%s] %s %s\n", coloredTimestamp, coloredType, coloredName, icon, line)
+			fmt.Printf("%s %s %s\n", e.streamPrefix(coloredTimestamp, coloredType, coloredName), icon, line)
 		}
 
 		// Ensure immediate output by flushing stdout for real-time streaming
@@ -720,13 +1530,24 @@ func (e *Executor) streamOutputContinuous(pipe io.ReadCloser, commandName, strea
 		coloredType := e.colorizeCommandType(cmdType)
 		coloredName := colorize(commandName, colorCyan)
 		errorIcon := colorize("❌", colorRed)
-		fmt.Printf("[%s] [%s] [%s] %s Error reading %s: %v\n",
-			coloredTimestamp, coloredType, coloredName, errorIcon, streamType, err)
+		fmt.Printf("%s %s Error reading %s: %v\n",
+			e.streamPrefix(coloredTimestamp, coloredType, coloredName), errorIcon, streamType, err)
 		os.Stdout.Sync()
 	}
 }
 
-func (e *Executor) streamOutputContinuousWithContext(ctx context.Context, pipe io.ReadCloser, commandName, streamType, command string) {
+// drainOutput discards whatever a process still writes to an output pipe nobody is displaying
+// any more. Closing the pipe instead would kill the process with SIGPIPE on its next write, and
+// leaving it unread would block the process once the OS pipe buffer fills.
+func drainOutput(pipe io.Reader) {
+	io.Copy(io.Discard, pipe)
+}
+
+// streamOutputContinuousWithContext streams a keepAlive process's output line by line, to the
+// console and background log with verbose output and to sink unless it is nil, until ctx ends.
+// Lines that lines does not show are left out, and with dedupe, runs of identical lines are
+// collapsed.
+func (e *Executor) streamOutputContinuousWithContext(ctx context.Context, pipe io.ReadCloser, commandName, streamType, command string, heartbeat *outputHeartbeat, sink syslogSink, lines *lineFilter, dedupe bool) {
 	defer func() {
 		if r := recover(); r != nil {
 			fmt.Printf("[%s] [%s] ❌ Streaming panic recovered: %v\n",
@@ -738,6 +1559,7 @@ func (e *Executor) streamOutputContinuousWithContext(ctx context.Context, pipe i
 	}()
 
 	cmdType := e.detectCommandType(command)
+	deduper := &lineDeduper{enabled: dedupe}
 	scanner := bufio.NewScanner(pipe)
 
 	// Set a smaller buffer size to reduce latency for real-time streaming
@@ -749,23 +1571,45 @@ func (e *Executor) streamOutputContinuousWithContext(ctx context.Context, pipe i
 		select {
 		case <-ctx.Done():
 			// Streaming has been cancelled, but process continues running
-			timestamp := time.Now().Format("15:04:05.000")
-			coloredTimestamp := colorize(timestamp, colorGray)
-			coloredType := e.colorizeCommandType(cmdType)
-			coloredName := colorize(commandName, colorCyan)
-			streamIcon := colorize("🔄", colorYellow)
-			fmt.Printf("[%s] [%s] [%s] %s Detached from output streaming (process continues in background)\n",
-				coloredTimestamp, coloredType, coloredName, streamIcon)
-			os.Stdout.Sync()
+			e.noteRepeats(cmdType, commandName, deduper.flush(), sink)
+			if e.verbose {
+				timestamp := time.Now().Format("15:04:05.000")
+				coloredTimestamp := colorize(timestamp, colorGray)
+				coloredType := e.colorizeCommandType(cmdType)
+				coloredName := colorize(commandName, colorCyan)
+				streamIcon := colorize("🔄", colorYellow)
+				fmt.Printf("%s %s Detached from output streaming (process continues in background)\n",
+					e.streamPrefix(coloredTimestamp, coloredType, coloredName), streamIcon)
+				os.Stdout.Sync()
+			}
+			drainOutput(pipe)
 			return
 		default:
 		}
 
-		if e.isStopped() {
-			break
+		line := scanner.Text()
+		heartbeat.touch()
+
+		if !lines.shows(line) {
+			continue
+		}
+		show, repeats := deduper.add(line)
+		if !show {
+			continue
+		}
+		e.noteRepeats(cmdType, commandName, repeats, sink)
+
+		if sink != nil {
+			if streamType == "stderr" {
+				sink.Err(line)
+			} else {
+				sink.Info(line)
+			}
+		}
+		if !e.verbose {
+			continue
 		}
 
-		line := scanner.Text()
 		timestamp := time.Now().Format("15:04:05.000")
 
 		// Colorize output
@@ -778,10 +1622,10 @@ func (e *Executor) streamOutputContinuousWithContext(ctx context.Context, pipe i
 		var icon string
 		if streamType == "stderr" {
 			icon = colorize("❌", colorRed)
-			fmt.Printf("[%s] [%s] [%s] %s %s\n", coloredTimestamp, coloredType, coloredName, icon, line)
+			fmt.Printf("%s %s %s\n", e.streamPrefix(coloredTimestamp, coloredType, coloredName), icon, line)
 		} else {
 			icon = colorize("✓", colorGreen)
-			fmt.Printf("[%s] [%s] [%s] %s %s\n", coloredTimestamp, coloredType, coloredName, icon, line)
+			fmt.Printf("%s %s %s\n", e.streamPrefix(coloredTimestamp, coloredType, coloredName), icon, line)
 		}
 
 		// Ensure immediate output by flushing stdout for real-time streaming
@@ -792,7 +1636,9 @@ func (e *Executor) streamOutputContinuousWithContext(ctx context.Context, pipe i
 		e.logger.WriteLog(commandName, logLine)
 	}
 
-	if err := scanner.Err(); err != nil && !e.isStopped() && !strings.Contains(err.Error(), "file already closed") {
+	e.noteRepeats(cmdType, commandName, deduper.flush(), sink)
+
+	if err := scanner.Err(); err != nil && e.verbose && !e.isStopped() && !strings.Contains(err.Error(), "file already closed") {
 		// Only log errors if context hasn't been cancelled (streaming wasn't intentionally stopped)
 		select {
 		case <-ctx.Done():
@@ -803,15 +1649,18 @@ func (e *Executor) streamOutputContinuousWithContext(ctx context.Context, pipe i
 			coloredType := e.colorizeCommandType(cmdType)
 			coloredName := colorize(commandName, colorCyan)
 			errorIcon := colorize("❌", colorRed)
-			fmt.Printf("[%s] [%s] [%s] %s Error reading %s: %v\n",
-				coloredTimestamp, coloredType, coloredName, errorIcon, streamType, err)
+			fmt.Printf("%s %s Error reading %s: %v\n",
+				e.streamPrefix(coloredTimestamp, coloredType, coloredName), errorIcon, streamType, err)
 			os.Stdout.Sync()
 		}
 	}
+
+	// Keep reading after an overlong line or a stop, so the process never blocks on a full pipe
+	drainOutput(pipe)
 }
 
 func (e *Executor) monitorProcess(name string, cmd *exec.Cmd) {
-	err := cmd.Wait()
+	err := e.runner.Wait(cmd)
 
 	e.mu.Lock()
 	delete(e.processes, name)
@@ -821,14 +1670,10 @@ func (e *Executor) monitorProcess(name string, cmd *exec.Cmd) {
 	if cmd.Process != nil {
 		pid := cmd.Process.Pid
 
-		// Check if this was an unexpected termination
-		if err != nil {
-			exitCode := -1
-			if exitError, ok := err.(*exec.ExitError); ok {
-				exitCode = exitError.ExitCode()
-			}
+		// Check if this was an unexpected termination, rather than a stop seqr asked for
+		if err != nil && !e.monitor.IsExpectedExit(pid) {
 			// Notify monitor of unexpected termination
-			e.monitor.NotifyUnexpectedTermination(pid, name, exitCode, err)
+			e.monitor.NotifyUnexpectedTermination(pid, name, exitCodeOf(err), err)
 		}
 
 		// Remove from tracking
@@ -853,8 +1698,16 @@ func (e *Executor) monitorProcess(name string, cmd *exec.Cmd) {
 	}
 }
 
-func (e *Executor) monitorProcessWithStreaming(name string, cmd *exec.Cmd, streamCancel context.CancelFunc, streamWg *sync.WaitGroup) {
-	err := cmd.Wait()
+func (e *Executor) monitorProcessWithStreaming(name string, cmd *exec.Cmd, streamCancel context.CancelFunc, streamWg *sync.WaitGroup, pipes ...*os.File) {
+	err := e.runner.Wait(cmd)
+
+	// Let the streams finish printing what the process wrote before it exited. A child process
+	// left behind may still hold the pipes open, so they are closed after a bounded wait.
+	if !waitForStreams(streamWg, streamDrainTimeout) {
+		for _, pipe := range pipes {
+			pipe.Close()
+		}
+	}
 
 	// Cancel streaming when process ends
 	streamCancel()
@@ -865,20 +1718,17 @@ func (e *Executor) monitorProcessWithStreaming(name string, cmd *exec.Cmd, strea
 	e.mu.Lock()
 	delete(e.processes, name)
 	delete(e.streamingActive, name) // Clean up streaming tracking
+	delete(e.streamWaits, name)
 	e.mu.Unlock()
 
 	// Remove from process tracker and monitoring
 	if cmd.Process != nil {
 		pid := cmd.Process.Pid
 
-		// Check if this was an unexpected termination
-		if err != nil {
-			exitCode := -1
-			if exitError, ok := err.(*exec.ExitError); ok {
-				exitCode = exitError.ExitCode()
-			}
+		// Check if this was an unexpected termination, rather than a stop seqr asked for
+		if err != nil && !e.monitor.IsExpectedExit(pid) {
 			// Notify monitor of unexpected termination
-			e.monitor.NotifyUnexpectedTermination(pid, name, exitCode, err)
+			e.monitor.NotifyUnexpectedTermination(pid, name, exitCodeOf(err), err)
 		}
 
 		// Remove from tracking
@@ -915,7 +1765,6 @@ func (e *Executor) GetStatus() ExecutionStatus {
 
 func (e *Executor) Stop() {
 	e.mu.Lock()
-	defer e.mu.Unlock()
 
 	e.stopped = true
 
@@ -933,6 +1782,178 @@ func (e *Executor) Stop() {
 	}
 
 	e.processes = make(map[string]*exec.Cmd)
+
+	streams := make([]*sync.WaitGroup, 0, len(e.streamWaits))
+	for _, streamWg := range e.streamWaits {
+		streams = append(streams, streamWg)
+	}
+	e.mu.Unlock()
+
+	// The processes are gone, but their last lines may still be buffered in the output pipes.
+	// Wait, within a bound, for the streams to print them before returning.
+	deadline := time.Now().Add(streamDrainTimeout)
+	for _, streamWg := range streams {
+		if !waitForStreams(streamWg, time.Until(deadline)) {
+			return
+		}
+	}
+}
+
+// streamDrainTimeout bounds how long a stopped or exited keepAlive process's output streams are
+// given to print what is still buffered in their pipes
+const streamDrainTimeout = 2 * time.Second
+
+// waitForStreams waits for a process's streaming goroutines to finish, reporting whether they
+// did so within the timeout
+func waitForStreams(streamWg *sync.WaitGroup, timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		streamWg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// scheduleAutoStop gracefully terminates a keepAlive process once it has run for lifetime, unless
+// it exits or is stopped first. A zero lifetime means the process runs until stopped.
+func (e *Executor) scheduleAutoStop(name string, execCmd *exec.Cmd, lifetime time.Duration, exited <-chan struct{}) {
+	if lifetime <= 0 {
+		return
+	}
+
+	e.autoStops.Add(1)
+	go func() {
+		defer e.autoStops.Done()
+
+		timer := time.NewTimer(lifetime)
+		defer timer.Stop()
+
+		select {
+		case <-exited:
+			return
+		case <-timer.C:
+		}
+
+		e.mu.Lock()
+		defer e.mu.Unlock()
+
+		// Stop may have already terminated the process, or a new one taken over the name
+		if e.processes[name] != execCmd {
+			return
+		}
+
+		e.monitor.MarkExpectedExit(execCmd.Process.Pid)
+		e.reporter.ReportCommandAutoStop(name, lifetime) // e.mu is already held
+		if e.verbose {
+			timestamp := time.Now().Format("15:04:05.000")
+			fmt.Printf("[%s] [%s] [process] Reached maxLifetime of %v, gracefully terminating process (PID %d)\n", timestamp, name, lifetime, execCmd.Process.Pid)
+		}
+
+		e.terminateProcessGracefully(execCmd.Process, name)
+		delete(e.processes, name)
+	}()
+}
+
+// stopSlowStart stops a keepAlive process that took longer than the timeout to start, and returns
+// the error its command fails with
+func (e *Executor) stopSlowStart(name string, startup time.Duration) error {
+	e.stopKeepAlive(name)
+	return fmt.Errorf("keepAlive command '%s' took %v to start, longer than the %v timeout", name, startup.Round(time.Millisecond), e.timeout)
+}
+
+// stopKeepAlive gracefully terminates a keepAlive process whose command is failing, so its exit is
+// not reported as unexpected
+func (e *Executor) stopKeepAlive(name string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if execCmd, exists := e.processes[name]; exists {
+		e.monitor.MarkExpectedExit(execCmd.Process.Pid)
+		e.terminateProcessGracefully(execCmd.Process, name)
+		delete(e.processes, name)
+	}
+}
+
+// Wait blocks until every keepAlive process started by Execute has exited, or ctx is cancelled,
+// so a library user can run a queue in the foreground. It returns immediately when no keepAlive
+// process is running. Call it once Execute has returned; processes started during the wait are
+// not guaranteed to be waited for.
+func (e *Executor) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		e.keepAlives.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// WaitForAutoStops blocks until every keepAlive process with a maxLifetime has exited or been
+// stopped, or ctx is cancelled. It returns immediately when no such process is running.
+func (e *Executor) WaitForAutoStops(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		e.autoStops.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// CancelCommand aborts a single running once command by name, killing its process group, without
+// stopping the rest of the run. The command fails with ErrorTypeContextCancelled, so in
+// continue-on-error mode execution proceeds with the remaining commands.
+func (e *Executor) CancelCommand(name string) error {
+	e.mu.RLock()
+	cancel, exists := e.commandCancels[name]
+	e.mu.RUnlock()
+
+	if !exists {
+		return &ProcessNotFoundError{ProcessName: name}
+	}
+
+	if e.verbose {
+		timestamp := time.Now().Format("15:04:05.000")
+		fmt.Printf("[%s] [%s] [system] Cancelling command\n", timestamp, name)
+	}
+
+	cancel()
+	return nil
+}
+
+// SetReporter replaces the reporter, including during a run. Report calls made after it returns
+// use the new reporter; a call already in progress finishes on the reporter that was current
+// when it started. A nil reporter is ignored.
+func (e *Executor) SetReporter(reporter Reporter) {
+	if reporter == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.reporter = reporter
+}
+
+// currentReporter returns the reporter progress should be reported to right now
+func (e *Executor) currentReporter() Reporter {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.reporter
 }
 
 func (e *Executor) isStopped() bool {
@@ -957,15 +1978,57 @@ func (e *Executor) updateState(state ExecutionState, errorMsg string) {
 }
 
 func (e *Executor) addResult(result ExecutionResult) {
+	result.Meta = result.Command.Meta
+
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	e.status.Results = append(e.status.Results, result)
+	e.boundCapturedOutput()
+}
+
+// boundCapturedOutput drops the output of the earliest successful results while the output
+// captured across results exceeds maxTotalOutputBytes. Failures keep their output, since it
+// explains them, so they alone may still exceed the bound. The caller must hold e.mu.
+func (e *Executor) boundCapturedOutput() {
+	if e.maxTotalOutputBytes <= 0 {
+		return
+	}
+
+	total := 0
+	for _, result := range e.status.Results {
+		total += len(result.Output)
+	}
+
+	for i := range e.status.Results {
+		if total <= e.maxTotalOutputBytes {
+			return
+		}
+		result := &e.status.Results[i]
+		if result.Success && result.Output != "" {
+			total -= len(result.Output)
+			result.Output = ""
+			result.OutputDropped = true
+		}
+	}
 }
 
-func (e *Executor) updateCompletedCount(count int) {
+// countCompleted adds commands that are done to the completed count, leaving out warmups
+func (e *Executor) countCompleted(commands ...config.Command) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
-	e.status.CompletedCount = count
+	e.status.CompletedCount += countedCommands(commands)
+}
+
+// countedCommands returns how many of commands count towards the run's statistics, which
+// warmup commands do not
+func countedCommands(commands []config.Command) int {
+	count := 0
+	for _, cmd := range commands {
+		if !cmd.Warmup {
+			count++
+		}
+	}
+	return count
 }
 
 // GetTrackedProcesses returns all currently tracked processes
@@ -1049,49 +2112,102 @@ func (e *Executor) GetActiveStreamingProcesses() []string {
 	return names
 }
 
-// terminateProcessGracefully attempts to terminate a process gracefully with SIGTERM before falling back to SIGKILL
+// terminateProcessGracefully walks the command's stop signal escalation, waiting for the process
+// group to exit after each signal, before falling back to SIGKILL. The caller must hold e.mu.
 func (e *Executor) terminateProcessGracefully(process *os.Process, name string) {
+	steps := e.stopSignals[name]
+	if len(steps) == 0 {
+		steps = []config.StopSignal{{Signal: "SIGTERM"}}
+	}
+
 	if e.verbose {
 		timestamp := time.Now().Format("15:04:05.000")
 		fmt.Printf("[%s] [%s] [process] Terminating process group (PID %d) gracefully...\n", timestamp, name, process.Pid)
 	}
 
-	// Try to kill the entire process group first
-	if err := e.killProcessGroup(process.Pid, true); err != nil {
-		if e.verbose {
-			timestamp := time.Now().Format("15:04:05.000")
-			fmt.Printf("[%s] [%s] [process] Failed to terminate process group (PID %d): %v, falling back to single process termination\n", timestamp, name, process.Pid, err)
+	var done chan error
+	for i, step := range steps {
+		if err := e.sendStopSignal(process.Pid, step.Signal); err != nil {
+			if i == 0 {
+				if e.verbose {
+					timestamp := time.Now().Format("15:04:05.000")
+					fmt.Printf("[%s] [%s] [process] Failed to terminate process group (PID %d): %v, falling back to single process termination\n", timestamp, name, process.Pid, err)
+				}
+				// Fall back to single process termination
+				e.terminateProcessGracefullyFallback(process, name)
+				return
+			}
+			if e.verbose {
+				timestamp := time.Now().Format("15:04:05.000")
+				fmt.Printf("[%s] [%s] [process] Failed to send %s to process group (PID %d): %v\n", timestamp, name, step.Signal, process.Pid, err)
+			}
+			continue
 		}
-		// Fall back to single process termination
-		e.terminateProcessGracefullyFallback(process, name)
-		return
-	}
 
-	// Wait up to 5 seconds for graceful shutdown
-	done := make(chan error, 1)
-	go func() {
-		_, err := process.Wait()
-		done <- err
-	}()
+		if done == nil {
+			done = make(chan error, 1)
+			go func() {
+				_, err := process.Wait()
+				done <- err
+			}()
+		}
+
+		timeout := step.Timeout
+		if timeout == 0 {
+			timeout = config.DefaultStopTimeout
+		}
 
-	select {
-	case err := <-done:
-		// Process exited gracefully
 		if e.verbose {
 			timestamp := time.Now().Format("15:04:05.000")
-			if err != nil {
-				fmt.Printf("[%s] [%s] [process] Process group exited gracefully with error (PID %d): %v\n", timestamp, name, process.Pid, err)
-			} else {
-				fmt.Printf("[%s] [%s] [process] Process group exited gracefully (PID %d)\n", timestamp, name, process.Pid)
+			fmt.Printf("[%s] [%s] [process] Sent %s to process group (PID %d), waiting up to %s...\n", timestamp, name, step.Signal, process.Pid, timeout)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		exited, err := waitForProcessExit(ctx, done)
+		cancel()
+
+		if exited {
+			if e.verbose {
+				timestamp := time.Now().Format("15:04:05.000")
+				if err != nil {
+					fmt.Printf("[%s] [%s] [process] Process group exited gracefully after %s with error (PID %d): %v\n", timestamp, name, step.Signal, process.Pid, err)
+				} else {
+					fmt.Printf("[%s] [%s] [process] Process group exited gracefully after %s (PID %d)\n", timestamp, name, step.Signal, process.Pid)
+				}
 			}
+			return
 		}
-	case <-time.After(5 * time.Second):
-		// Timeout, force kill with SIGKILL
+
 		if e.verbose {
 			timestamp := time.Now().Format("15:04:05.000")
-			fmt.Printf("[%s] [%s] [process] Graceful shutdown timeout (PID %d), using force kill on process group\n", timestamp, name, process.Pid)
+			fmt.Printf("[%s] [%s] [process] Process group still running %s after %s (PID %d), escalating\n", timestamp, name, timeout, step.Signal, process.Pid)
 		}
-		e.forceKillProcessGroupWithTimeout(process, name, done)
+	}
+
+	if done == nil {
+		// No stop signal could be delivered, so nothing is waiting on the process yet
+		done = make(chan error, 1)
+		go func() {
+			_, err := process.Wait()
+			done <- err
+		}()
+	}
+
+	// Escalation exhausted, force kill with SIGKILL
+	if e.verbose {
+		timestamp := time.Now().Format("15:04:05.000")
+		fmt.Printf("[%s] [%s] [process] Graceful shutdown timeout (PID %d), using force kill on process group\n", timestamp, name, process.Pid)
+	}
+	e.forceKillProcessGroupWithTimeout(process, name, done)
+}
+
+// waitForProcessExit waits for a process wait result until the context is done, reporting whether the process exited
+func waitForProcessExit(ctx context.Context, done <-chan error) (bool, error) {
+	select {
+	case err := <-done:
+		return true, err
+	case <-ctx.Done():
+		return false, nil
 	}
 }
 
@@ -1184,6 +2300,12 @@ func (e *Executor) killProcessGroup(pid int, graceful bool) error {
 	return e.killProcessGroupPlatform(pid, graceful)
 }
 
+// sendStopSignal sends a named stop signal to an entire process group using platform-specific methods
+func (e *Executor) sendStopSignal(pid int, signalName string) error {
+	// The actual implementation is in platform-specific files
+	return e.sendStopSignalPlatform(pid, signalName)
+}
+
 // terminateProcessGracefullyFallback falls back to single process termination when process group termination fails
 func (e *Executor) terminateProcessGracefullyFallback(process *os.Process, name string) {
 	if runtime.GOOS == "windows" {
@@ -1278,9 +2400,10 @@ func (e *Executor) forceKillProcessGroupWithTimeout(process *os.Process, name st
 	}
 }
 
-// handleStatusChanges processes status change notifications from the monitor
-func (e *Executor) handleStatusChanges(ctx context.Context) {
-	statusChanges := e.monitor.GetStatusChanges()
+// handleStatusChanges processes status change notifications from the monitor, leaving the
+// monitor's shared channel to library callers
+func (e *Executor) handleStatusChanges(ctx context.Context, statusChanges <-chan ProcessStatusChange) {
+	defer e.monitor.Unsubscribe(statusChanges)
 
 	for {
 		select {
@@ -1288,13 +2411,13 @@ func (e *Executor) handleStatusChanges(ctx context.Context) {
 			return
 		case change := <-statusChanges:
 			// Handle the status change
-			e.processStatusChange(change)
+			e.processStatusChange(ctx, change)
 		}
 	}
 }
 
 // processStatusChange processes a single status change notification
-func (e *Executor) processStatusChange(change ProcessStatusChange) {
+func (e *Executor) processStatusChange(ctx context.Context, change ProcessStatusChange) {
 	// For now, we mainly log unexpected terminations
 	// The monitor already handles logging, but we could add additional logic here
 	// such as restarting processes, sending alerts, etc.
@@ -1315,7 +2438,25 @@ func (e *Executor) processStatusChange(change ProcessStatusChange) {
 				break
 			}
 		}
+		cancelRun := e.cancelRun
 		e.mu.Unlock()
+
+		// A process killed because the run itself was cancelled is not a keepAlive failure
+		if cancelRun != nil && ctx.Err() == nil {
+			exitErr := &KeepAliveExitError{
+				CommandName: change.Name,
+				PID:         change.PID,
+				ExitCode:    change.ExitCode,
+				Reason:      change.Error,
+			}
+			e.updateState(StateFailed, exitErr.Error())
+			cancelRun(exitErr)
+
+			if e.verbose {
+				timestamp := time.Now().Format("15:04:05.000")
+				fmt.Printf("[%s] [%s] [system] KeepAlive process exited during the run, cancelling remaining commands\n", timestamp, change.Name)
+			}
+		}
 	}
 }
 
@@ -1331,6 +2472,11 @@ func (e *Executor) groupCommandsByConcurrency(commands []config.Command) [][]con
 
 	for _, cmd := range commands {
 		if cmd.Concurrent {
+			// A matrix's instances start a group of their own
+			if len(currentConcurrentGroup) > 0 && !config.ConcurrentWith(currentConcurrentGroup[len(currentConcurrentGroup)-1], cmd) {
+				groups = append(groups, currentConcurrentGroup)
+				currentConcurrentGroup = nil
+			}
 			// Add to current concurrent group
 			currentConcurrentGroup = append(currentConcurrentGroup, cmd)
 		} else {
@@ -1369,26 +2515,84 @@ func (e *Executor) executeConcurrentCommands(ctx context.Context, commands []con
 
 	// Channel to collect results from concurrent executions
 	type concurrentResult struct {
-		index  int
-		result ExecutionResult
-		err    error
+		index   int
+		result  ExecutionResult
+		err     error
+		skipped bool // Held back by the concurrency limit until the failure limit was reached
 	}
 
 	resultChan := make(chan concurrentResult, len(commands))
 	var wg sync.WaitGroup
 
+	// Every command in the group becomes eligible at once, even if the concurrency limit holds it back
+	queuedAt := time.Now()
+
+	// Bound how many commands run at once when a concurrency limit is set. Commands that can
+	// start right away queue for a slot together, so the first slots go by priority rather
+	// than to whichever goroutine asks first.
+	var slots *admission
+	tickets := make([]*admissionTicket, len(commands))
+	e.mu.RLock()
+	limit := e.concurrencyLimit
+	e.mu.RUnlock()
+	if limit > 0 && limit < len(commands) {
+		slots = newAdmission(limit)
+		for i, cmd := range commands {
+			if len(cmd.AfterReady) == 0 {
+				tickets[i] = slots.enqueue(cmd.Priority, i)
+			}
+		}
+		if e.verbose {
+			timestamp := time.Now().Format("15:04:05.000")
+			fmt.Printf("[%s] [seqr] [concurrent] Running at most %d commands at once\n", timestamp, limit)
+		}
+	}
+
 	// Start all concurrent commands
 	for i, cmd := range commands {
 		wg.Add(1)
 		go func(cmdIndex int, command config.Command) {
 			defer wg.Done()
+			// A command that never runs does not become ready; one that ran has already said so
+			defer e.signalReady(command.Name, false)
+
+			// Wait for afterReady dependencies before taking a slot, so a command holding one
+			// cannot keep the dependency it waits for from starting. A dependency that fails is
+			// reported when the command is executed below.
+			e.waitForAfterReady(ctx, command)
+
+			if slots != nil {
+				ticket := tickets[cmdIndex]
+				if ticket == nil {
+					ticket = slots.enqueue(command.Priority, cmdIndex)
+					slots.dispatch()
+				}
+				if err := slots.wait(ctx, ticket); err != nil {
+					resultChan <- concurrentResult{
+						index:  cmdIndex,
+						result: ExecutionResult{Command: command, Success: false, Error: err.Error()},
+						err:    err,
+					}
+					return
+				}
+				defer slots.release()
+				if e.failureLimitReached() {
+					resultChan <- concurrentResult{index: cmdIndex, skipped: true}
+					return
+				}
+			}
 
 			// Report command start
 			currentIndex := *commandIndex + cmdIndex
-			e.reporter.ReportCommandStart(command.Name, currentIndex)
+			e.currentReporter().ReportCommandStart(command.Name, currentIndex)
 
 			// Execute the command
-			result, err := e.executeCommand(ctx, command)
+			result, err := e.executeTracedCommand(ctx, command, queuedAt)
+			if err != nil && result.AllowedFailure {
+				err = nil
+			} else if err != nil {
+				e.recordFailure(result, err)
+			}
 
 			// Send result through channel
 			resultChan <- concurrentResult{
@@ -1398,6 +2602,9 @@ func (e *Executor) executeConcurrentCommands(ctx context.Context, commands []con
 			}
 		}(i, cmd)
 	}
+	if slots != nil {
+		slots.dispatch()
+	}
 
 	// Wait for all commands to complete
 	go func() {
@@ -1410,30 +2617,38 @@ func (e *Executor) executeConcurrentCommands(ctx context.Context, commands []con
 	var firstError error
 
 	for result := range resultChan {
+		if result.skipped {
+			continue
+		}
+
 		results[result.index] = result.result
 		e.addResult(result.result)
 
 		currentIndex := *commandIndex + result.index
-		if result.err != nil {
-			e.reporter.ReportCommandFailure(result.result, currentIndex)
-			if firstError == nil {
+		if result.err != nil || result.result.AllowedFailure {
+			e.currentReporter().ReportCommandFailure(result.result, currentIndex)
+			if firstError == nil && result.err != nil {
 				firstError = result.err
 			}
 		} else {
-			e.reporter.ReportCommandSuccess(result.result, currentIndex)
+			e.currentReporter().ReportCommandSuccess(result.result, currentIndex)
 		}
 	}
 
 	// Update command index
 	*commandIndex += len(commands)
-	e.updateCompletedCount(*commandIndex)
+	e.countCompleted(commands...)
 
-	// If any command failed, return the first error
-	if firstError != nil {
+	// If any command failed, return the first error unless failures are collected for the end of the run
+	if firstError != nil && !e.continueOnError {
 		e.updateState(StateFailed, firstError.Error())
 		return firstError
 	}
 
+	if firstError != nil {
+		return nil
+	}
+
 	if e.verbose {
 		timestamp := time.Now().Format("15:04:05.000")
 		fmt.Printf("[%s] [seqr] [concurrent] All %d concurrent commands completed successfully\n", timestamp, len(commands))
@@ -1442,3 +2657,41 @@ func (e *Executor) executeConcurrentCommands(ctx context.Context, commands []con
 
 	return nil
 }
+
+// recordFailure notes a failed command so it counts towards the failure limit and the final report
+func (e *Executor) recordFailure(result ExecutionResult, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.failures = append(e.failures, &CommandFailure{CommandName: result.Command.Name, Detail: result.ErrorDetail, Err: err})
+}
+
+// failureLimitReached reports whether no further commands should be launched because of failures:
+// after the first failure normally, or after maxFailures in continue-on-error mode
+func (e *Executor) failureLimitReached() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	limit := 1
+	if e.continueOnError {
+		limit = e.maxFailures
+	}
+	return limit > 0 && len(e.failures) >= limit
+}
+
+// failuresError aggregates the failures of the current run into a single error, or returns nil if none failed
+func (e *Executor) failuresError(totalCommands int) error {
+	e.mu.RLock()
+	failures := make([]*CommandFailure, len(e.failures))
+	copy(failures, e.failures)
+	e.mu.RUnlock()
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	return &CommandFailuresError{
+		Failures:     failures,
+		TotalCount:   totalCommands,
+		LimitReached: e.failureLimitReached(),
+	}
+}
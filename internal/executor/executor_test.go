@@ -1,7 +1,14 @@
 package executor
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/seqr-cli/seqr/internal/config"
@@ -60,3 +67,397 @@ func TestExecutor_Execute_Success(t *testing.T) {
 		t.Errorf("Expected final state to be Success, got %v", status.State)
 	}
 }
+
+func TestExecutor_ResolvedPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires the echo and sleep binaries")
+	}
+
+	executor := NewExecutor(false)
+	defer executor.Stop()
+
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "once", Command: "echo", Args: []string{"hello"}, Mode: config.ModeOnce},
+			{Name: "keepalive", Command: "sleep", Args: []string{"5"}, Mode: config.ModeKeepAlive},
+		},
+	}
+
+	if err := executor.Execute(context.Background(), cfg); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	results := executor.GetStatus().Results
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+
+	for _, result := range results {
+		if !filepath.IsAbs(result.ResolvedPath) {
+			t.Errorf("%s: expected absolute resolved path, got %q", result.Command.Name, result.ResolvedPath)
+			continue
+		}
+		info, err := os.Stat(result.ResolvedPath)
+		if err != nil {
+			t.Errorf("%s: resolved path %q does not exist: %v", result.Command.Name, result.ResolvedPath, err)
+		} else if info.IsDir() {
+			t.Errorf("%s: resolved path %q is a directory", result.Command.Name, result.ResolvedPath)
+		}
+	}
+}
+
+func TestExecutor_ResolvedPathMissingCommand(t *testing.T) {
+	executor := NewExecutor(false)
+
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "missing", Command: "seqr-definitely-not-a-command", Mode: config.ModeOnce},
+		},
+	}
+
+	if err := executor.Execute(context.Background(), cfg); err == nil {
+		t.Fatal("Expected execution of a missing command to fail")
+	}
+
+	results := executor.GetStatus().Results
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	if results[0].ResolvedPath != "" {
+		t.Errorf("Expected no resolved path for a missing command, got %q", results[0].ResolvedPath)
+	}
+}
+
+func TestBuildCommandLine(t *testing.T) {
+	tests := []struct {
+		name       string
+		cmd        config.Command
+		includeEnv bool
+		expected   string
+	}{
+		{
+			name:     "plain arguments",
+			cmd:      config.Command{Command: "npm", Args: []string{"run", "build"}},
+			expected: "npm run build",
+		},
+		{
+			name:     "arguments needing quotes",
+			cmd:      config.Command{Command: "sh", Args: []string{"-c", "echo 'hi' && exit 1", ""}},
+			expected: `sh -c 'echo '\''hi'\'' && exit 1' ''`,
+		},
+		{
+			name:     "env overrides omitted by default",
+			cmd:      config.Command{Command: "node", Args: []string{"server.js"}, Env: map[string]string{"PORT": "3000"}},
+			expected: "node server.js",
+		},
+		{
+			name:       "env overrides sorted and quoted",
+			cmd:        config.Command{Command: "node", Args: []string{"server.js"}, Env: map[string]string{"PORT": "3000", "GREETING": "hello world"}},
+			includeEnv: true,
+			expected:   "GREETING='hello world' PORT=3000 node server.js",
+		},
+		{
+			name:       "env values from files not printed",
+			cmd:        config.Command{Command: "node", Args: []string{"server.js"}, Env: map[string]string{"PORT": "3000"}, EnvFromFile: map[string]string{"API_TOKEN": "/run/secrets/token"}},
+			includeEnv: true,
+			expected:   `API_TOKEN="$(cat /run/secrets/token)" PORT=3000 node server.js`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildCommandLine(tt.cmd, tt.includeEnv); got != tt.expected {
+				t.Errorf("buildCommandLine() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExecutor_EchoCommands(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires the echo command")
+	}
+
+	cmd := config.Command{
+		Name:    "greet",
+		Command: "echo",
+		Args:    []string{"hello world"},
+		Mode:    config.ModeOnce,
+		Env:     map[string]string{"LANG": "C"},
+	}
+	cfg := &config.Config{Version: "1.0", Commands: []config.Command{cmd}}
+
+	for _, includeEnv := range []bool{false, true} {
+		var out bytes.Buffer
+		executor := NewExecutorWithOptions(ExecutorOptions{
+			Reporter:     NewConsoleReporter(&out, false),
+			EchoCommands: true,
+			EchoEnv:      includeEnv,
+		})
+		if err := executor.Execute(context.Background(), cfg); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+
+		lines := strings.Split(out.String(), "\n")
+		expected := "+ " + buildCommandLine(cmd, includeEnv)
+		if len(lines) < 2 || lines[1] != expected {
+			t.Errorf("Expected %q right after the start line, got:\n%s", expected, out.String())
+		}
+	}
+}
+
+func TestExecutor_EchoCommandsIncludesArgsFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires the echo command")
+	}
+
+	workDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(workDir, "args.txt"), []byte("from file\n"), 0644); err != nil {
+		t.Fatalf("Failed to write argsFile: %v", err)
+	}
+	cfg := &config.Config{Version: "1.0", Commands: []config.Command{
+		{Name: "greet", Command: "echo", Args: []string{"hello"}, ArgsFile: "args.txt", WorkDir: workDir, Mode: config.ModeOnce},
+	}}
+
+	var out bytes.Buffer
+	executor := NewExecutorWithOptions(ExecutorOptions{
+		Reporter:     NewConsoleReporter(&out, false),
+		EchoCommands: true,
+	})
+	if err := executor.Execute(context.Background(), cfg); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if !strings.Contains(out.String(), "+ echo hello 'from file'\n") {
+		t.Errorf("Expected the echoed command line to include the argsFile lines, got:\n%s", out.String())
+	}
+}
+
+func TestBuildCommandEnv(t *testing.T) {
+	t.Setenv("SEQR_TEST_INHERITED", "from-parent")
+	t.Setenv("SEQR_TEST_OVERRIDDEN", "from-parent")
+
+	secretFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(secretFile, []byte("from-file\n"), 0600); err != nil {
+		t.Fatalf("Failed to write secret file: %v", err)
+	}
+
+	env, err := BuildCommandEnv(config.Command{
+		Name:        "api",
+		Env:         map[string]string{"SEQR_TEST_OVERRIDDEN": "from-config", "SEQR_TEST_ADDED": "new"},
+		EnvFromFile: map[string]string{"SEQR_TEST_TOKEN": secretFile},
+	})
+	if err != nil {
+		t.Fatalf("BuildCommandEnv returned error: %v", err)
+	}
+
+	values := make(map[string][]string)
+	for _, entry := range env {
+		key, value, _ := strings.Cut(entry, "=")
+		values[key] = append(values[key], value)
+	}
+
+	expected := map[string]string{
+		"SEQR_TEST_INHERITED":  "from-parent",
+		"SEQR_TEST_OVERRIDDEN": "from-config",
+		"SEQR_TEST_ADDED":      "new",
+		"SEQR_TEST_TOKEN":      "from-file",
+	}
+	for key, value := range expected {
+		// An override must replace the inherited entry, not shadow it
+		if got := values[key]; len(got) != 1 || got[0] != value {
+			t.Errorf("Expected %s=%s exactly once, got %v", key, value, got)
+		}
+	}
+}
+
+func TestMaskEnvEntry(t *testing.T) {
+	tests := map[string]string{
+		"API_TOKEN=abc123":       "API_TOKEN=" + maskedEnvValue,
+		"db_password=hunter2":    "db_password=" + maskedEnvValue,
+		"GITHUB_AUTH=x=y":        "GITHUB_AUTH=" + maskedEnvValue,
+		"EMPTY_SECRET=":          "EMPTY_SECRET=",
+		"PORT=3000":              "PORT=3000",
+		"NODE_ENV=production":    "NODE_ENV=production",
+		"MALFORMED_SECRET_NO_EQ": "MALFORMED_SECRET_NO_EQ",
+	}
+	for entry, expected := range tests {
+		if got := MaskEnvEntry(entry); got != expected {
+			t.Errorf("MaskEnvEntry(%q) = %q, expected %q", entry, got, expected)
+		}
+	}
+}
+
+func TestExecutor_EnvFromFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sh")
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "token"), []byte("s3cret\n"), 0600); err != nil {
+		t.Fatalf("Failed to write secret file: %v", err)
+	}
+
+	// A relative path is resolved against the working directory
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{
+				Name:        "deploy",
+				Command:     "sh",
+				Args:        []string{"-c", `printf '%s' "$API_TOKEN" > out.txt`},
+				Mode:        config.ModeOnce,
+				WorkDir:     dir,
+				EnvFromFile: map[string]string{"API_TOKEN": "token"},
+			},
+		},
+	}
+
+	executor := NewExecutor(false)
+	if err := executor.Execute(context.Background(), cfg); err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+
+	out, err := os.ReadFile(filepath.Join(dir, "out.txt"))
+	if err != nil {
+		t.Fatalf("Failed to read command output: %v", err)
+	}
+	if string(out) != "s3cret" {
+		t.Errorf("Expected API_TOKEN to be the trimmed file content 's3cret', got %q", out)
+	}
+}
+
+func TestExecutor_ArgsFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires echo")
+	}
+
+	dir := t.TempDir()
+	argsFile := "# files to process\nsrc/a.go\n\n  src/b.go  \n# src/skipped.go\nsrc/c file.go\n"
+	if err := os.WriteFile(filepath.Join(dir, "files.txt"), []byte(argsFile), 0644); err != nil {
+		t.Fatalf("Failed to write args file: %v", err)
+	}
+
+	// A relative path is resolved against the working directory
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "lint", Command: "echo", Args: []string{"--fix"}, ArgsFile: "files.txt", Mode: config.ModeOnce, WorkDir: dir},
+		},
+	}
+
+	executor := NewExecutor(false)
+	if err := executor.Execute(context.Background(), cfg); err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if got := executor.GetStatus().Results[0].Output; got != "--fix src/a.go src/b.go src/c file.go" {
+		t.Errorf("Expected the file's arguments appended after Args in order, got %q", got)
+	}
+
+	// A missing file fails the command without running it
+	cfg.Commands[0].ArgsFile = "missing.txt"
+	executor = NewExecutor(false)
+	err := executor.Execute(context.Background(), cfg)
+	if err == nil || !strings.Contains(err.Error(), "failed to read argsFile 'missing.txt'") {
+		t.Errorf("Expected an error reading the missing args file, got %v", err)
+	}
+}
+
+func TestExecutor_MaxTotalOutputBytes(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sh")
+	}
+
+	// Each command prints about 1 KB, the failure in the middle included
+	chatty := `i=0; while [ $i -lt 100 ]; do printf '%s\n' "$1 line"; i=$((i+1)); done`
+	var commands []config.Command
+	for i := 0; i < 10; i++ {
+		name := fmt.Sprintf("step%d", i)
+		script := chatty
+		if i == 2 {
+			script += "; exit 1"
+		}
+		commands = append(commands, config.Command{Name: name, Command: "sh", Args: []string{"-c", script, "sh", name}, Mode: config.ModeOnce})
+	}
+	cfg := &config.Config{Version: "1.0", Commands: commands}
+
+	const limit = 3500
+	executor := NewExecutorWithOptions(ExecutorOptions{ContinueOnError: true, MaxTotalOutputBytes: limit, Reporter: NewConsoleReporter(io.Discard, false)})
+	if err := executor.Execute(context.Background(), cfg); err == nil {
+		t.Fatal("Expected the failed command to fail the run")
+	}
+
+	results := executor.GetStatus().Results
+	if len(results) != 10 {
+		t.Fatalf("Expected 10 results, got %d", len(results))
+	}
+
+	total := 0
+	for _, result := range results {
+		total += len(result.Output)
+	}
+	if total > limit {
+		t.Errorf("Expected at most %d bytes of captured output, got %d", limit, total)
+	}
+
+	if results[2].Success || results[2].OutputDropped || len(results[2].Output) == 0 {
+		t.Errorf("Expected the failed command to keep its output, got %+v", results[2])
+	}
+	if !results[0].OutputDropped || results[0].Output != "" {
+		t.Errorf("Expected the earliest successful output to be dropped first")
+	}
+	if last := results[9]; last.OutputDropped || last.Output == "" {
+		t.Errorf("Expected the latest output to be kept")
+	}
+}
+
+func TestExecutor_PrefixOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sh")
+	}
+
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "build", Command: "sh", Args: []string{"-c", "echo compiling; echo done"}, Mode: config.ModeOnce, PrefixOutput: true},
+			{Name: "plain", Command: "echo", Args: []string{"unchanged"}, Mode: config.ModeOnce},
+		},
+	}
+
+	for _, verbose := range []bool{false, true} {
+		executor := NewExecutor(verbose)
+		captureOutput(func() {
+			if err := executor.Execute(context.Background(), cfg); err != nil {
+				t.Fatalf("Execute returned error: %v", err)
+			}
+		})
+
+		results := executor.GetStatus().Results
+		if got := results[0].Output; got != "[build] compiling\n[build] done" {
+			t.Errorf("verbose=%v: expected prefixed output, got %q", verbose, got)
+		}
+		if got := results[1].Output; got != "unchanged" {
+			t.Errorf("verbose=%v: expected output without prefix by default, got %q", verbose, got)
+		}
+	}
+
+	// The config-wide setting prefixes every command
+	global := &config.Config{
+		Version:      "1.0",
+		PrefixOutput: true,
+		Commands: []config.Command{
+			{Name: "plain", Command: "echo", Args: []string{"tagged"}, Mode: config.ModeOnce},
+		},
+	}
+	executor := NewExecutor(false)
+	captureOutput(func() {
+		if err := executor.Execute(context.Background(), global); err != nil {
+			t.Fatalf("Execute returned error: %v", err)
+		}
+	})
+	if got := executor.GetStatus().Results[0].Output; got != "[plain] tagged" {
+		t.Errorf("Expected prefixed output with the config-wide setting, got %q", got)
+	}
+}
@@ -0,0 +1,49 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+// ExitReason is how a command's process came to an end, which its exit code alone does not tell:
+// the code is -1 both for a process killed by a signal and for one that never started
+type ExitReason string
+
+const (
+	ExitReasonExited      ExitReason = "exited"      // The process exited on its own, with ExitCode
+	ExitReasonSignaled    ExitReason = "signaled"    // The process was terminated by Signal
+	ExitReasonCancelled   ExitReason = "cancelled"   // The run or the command was cancelled, or its timeout passed
+	ExitReasonStartFailed ExitReason = "startFailed" // The process never started
+)
+
+// exitReasonOf works out how a command ended from its error and context, and names the signal
+// that terminated it, if any. A keepAlive command that started is still running, so it has no
+// exit reason.
+func exitReasonOf(ctx context.Context, result ExecutionResult, err error) (ExitReason, string) {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		signal := terminatingSignal(exitErr.ProcessState)
+		switch {
+		case ctx.Err() != nil:
+			// Killed by seqr because the context ended, the signal is only how
+			return ExitReasonCancelled, signal
+		case signal != "":
+			return ExitReasonSignaled, signal
+		}
+		return ExitReasonExited, ""
+	}
+
+	switch {
+	case err != nil && ctx.Err() != nil:
+		return ExitReasonCancelled, ""
+	case err != nil && result.ExitCode == -1:
+		return ExitReasonStartFailed, ""
+	case result.Command.Mode == config.ModeOnce:
+		// Includes once commands that exited 0 without the output they were expected to print
+		return ExitReasonExited, ""
+	}
+	return "", ""
+}
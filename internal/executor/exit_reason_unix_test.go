@@ -0,0 +1,87 @@
+//go:build !windows
+
+package executor
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestExecutor_ExitReason(t *testing.T) {
+	tests := []struct {
+		name       string
+		command    string
+		args       []string
+		timeout    time.Duration
+		wantReason ExitReason
+		wantSignal string
+		wantCode   int
+	}{
+		{name: "exited", command: "sh", args: []string{"-c", "exit 3"}, wantReason: ExitReasonExited, wantCode: 3},
+		{name: "signaled", command: "sh", args: []string{"-c", "kill -KILL $$"}, wantReason: ExitReasonSignaled, wantSignal: "SIGKILL", wantCode: -1},
+		{name: "cancelled", command: "sleep", args: []string{"5"}, timeout: 100 * time.Millisecond, wantReason: ExitReasonCancelled, wantSignal: "SIGKILL", wantCode: -1},
+		{name: "start failed", command: "seqr-no-such-command", wantReason: ExitReasonStartFailed, wantCode: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Version: "1.0",
+				Commands: []config.Command{
+					{Name: "task", Command: tt.command, Args: tt.args, Mode: config.ModeOnce},
+				},
+			}
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			var out bytes.Buffer
+			executor := NewExecutorWithOptions(ExecutorOptions{Reporter: NewConsoleReporterWithWriters(&out, &out, false)})
+			if err := executor.Execute(ctx, cfg); err == nil {
+				t.Fatal("Expected the command to fail")
+			}
+
+			result := executor.GetStatus().Results[0]
+			if result.ExitReason != tt.wantReason {
+				t.Errorf("Expected exit reason %q, got %q", tt.wantReason, result.ExitReason)
+			}
+			if result.Signal != tt.wantSignal {
+				t.Errorf("Expected signal %q, got %q", tt.wantSignal, result.Signal)
+			}
+			if result.ExitCode != tt.wantCode {
+				t.Errorf("Expected exit code %d, got %d", tt.wantCode, result.ExitCode)
+			}
+			if tt.wantReason == ExitReasonSignaled && !strings.Contains(out.String(), "✗ task failed (terminated by SIGKILL):") {
+				t.Errorf("Expected the failure to name the signal, got %q", out.String())
+			}
+		})
+	}
+}
+
+func TestExecutor_ExitReasonSuccess(t *testing.T) {
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "task", Command: "true", Mode: config.ModeOnce},
+		},
+	}
+
+	executor := NewExecutor(false)
+	var err error
+	captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+	if err != nil {
+		t.Fatalf("Expected the command to succeed, got %v", err)
+	}
+	if result := executor.GetStatus().Results[0]; result.ExitReason != ExitReasonExited || result.Signal != "" {
+		t.Errorf("Expected a clean exit, got reason %q and signal %q", result.ExitReason, result.Signal)
+	}
+}
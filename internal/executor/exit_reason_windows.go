@@ -0,0 +1,10 @@
+//go:build windows
+
+package executor
+
+import "os"
+
+// terminatingSignal returns an empty string, Windows processes are not terminated by signals
+func terminatingSignal(state *os.ProcessState) string {
+	return ""
+}
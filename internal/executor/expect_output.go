@@ -0,0 +1,46 @@
+package executor
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+// OutputMismatchError reports a command that exited 0 without producing the output it was
+// expected to
+type OutputMismatchError struct {
+	CommandName string
+	Expected    string
+	Regex       bool // Expected is a regular expression rather than a substring
+}
+
+// Error implements the error interface
+func (e *OutputMismatchError) Error() string {
+	if e.Regex {
+		return fmt.Sprintf("output of command '%s' does not match pattern %q", e.CommandName, e.Expected)
+	}
+	return fmt.Sprintf("output of command '%s' does not contain %q", e.CommandName, e.Expected)
+}
+
+// checkExpectedOutput checks a command's captured output against its expectOutput
+func checkExpectedOutput(cmd config.Command, output string) error {
+	if cmd.ExpectOutput == "" {
+		return nil
+	}
+
+	matched := strings.Contains(output, cmd.ExpectOutput)
+	if cmd.ExpectOutputRegex {
+		pattern, err := regexp.Compile(cmd.ExpectOutput)
+		if err != nil {
+			return fmt.Errorf("invalid expectOutput pattern for command '%s': %w", cmd.Name, err)
+		}
+		matched = pattern.MatchString(output)
+	}
+
+	if !matched {
+		return &OutputMismatchError{CommandName: cmd.Name, Expected: cmd.ExpectOutput, Regex: cmd.ExpectOutputRegex}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package executor
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestExecutor_ExpectOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires echo")
+	}
+
+	tests := []struct {
+		name      string
+		expect    string
+		regex     bool
+		wantError string
+	}{
+		{name: "contains the substring", expect: "healthy"},
+		{name: "matches the pattern", expect: `^status: \w+ \(v\d+\)$`, regex: true},
+		{name: "missing the substring", expect: "degraded", wantError: `output of command 'smoke' does not contain "degraded"`},
+		{name: "not matching the pattern", expect: `^ready$`, regex: true, wantError: `output of command 'smoke' does not match pattern "^ready$"`},
+	}
+
+	for _, tt := range tests {
+		for _, verbose := range []bool{false, true} {
+			name := tt.name
+			if verbose {
+				name += " verbose"
+			}
+			t.Run(name, func(t *testing.T) {
+				cfg := &config.Config{
+					Version: "1.0",
+					Commands: []config.Command{
+						{Name: "smoke", Command: "echo", Args: []string{"status: healthy (v2)"}, Mode: config.ModeOnce, ExpectOutput: tt.expect, ExpectOutputRegex: tt.regex},
+					},
+				}
+
+				executor := NewExecutor(verbose)
+				var err error
+				captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+				result := executor.GetStatus().Results[0]
+
+				if tt.wantError == "" {
+					if err != nil || !result.Success {
+						t.Fatalf("Expected the output to satisfy expectOutput, got %v", err)
+					}
+					return
+				}
+
+				if err == nil || result.Success || result.Error != tt.wantError {
+					t.Fatalf("Expected failure %q, got %v (result error %q)", tt.wantError, err, result.Error)
+				}
+				if result.ExitCode != 0 {
+					t.Errorf("Expected the command's own exit code 0 to be kept, got %d", result.ExitCode)
+				}
+				if result.ErrorDetail == nil || result.ErrorDetail.Type != ErrorTypeOutputMismatch {
+					t.Errorf("Expected error type %q, got %+v", ErrorTypeOutputMismatch, result.ErrorDetail)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+// externalReadyPollInterval is how often the executor repeats the checks of an externalReady block
+const externalReadyPollInterval = 250 * time.Millisecond
+
+// ExternalReadyError reports an external service a command waits for that did not become ready
+// in time. Unlike a failed keepAlive command, there is no process of seqr's behind it.
+type ExternalReadyError struct {
+	CommandName string
+	Timeout     time.Duration
+	Attempts    int
+	Err         error // Why the last check failed
+}
+
+// Error implements the error interface
+func (e *ExternalReadyError) Error() string {
+	return fmt.Sprintf("external service for command '%s' was not ready within %v (attempts: %d): %v", e.CommandName, e.Timeout, e.Attempts, e.Err)
+}
+
+// Unwrap returns why the last check failed
+func (e *ExternalReadyError) Unwrap() error {
+	return e.Err
+}
+
+// waitForExternalReady repeats the checks of a command's externalReady block until they all pass,
+// failing with an *ExternalReadyError once its timeout passes
+func (e *Executor) waitForExternalReady(ctx context.Context, cmd config.Command) error {
+	external := cmd.ExternalReady
+	timeout := external.Timeout
+	if timeout <= 0 {
+		timeout = config.DefaultReadyTimeout
+	}
+
+	if e.verbose {
+		timestamp := time.Now().Format("15:04:05.000")
+		fmt.Printf("[%s] [%s] [process] Waiting up to %v for external service: %s\n", timestamp, cmd.Name, timeout, describeExternalReady(cmd))
+	}
+
+	// Checks run under the deadline too, so a hanging check command cannot outlast it
+	start := time.Now()
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(externalReadyPollInterval)
+	defer ticker.Stop()
+
+	attempts := 0
+	for {
+		attempts++
+		checkErr := e.checkExternalReady(waitCtx, cmd)
+		if checkErr == nil {
+			if e.verbose {
+				timestamp := time.Now().Format("15:04:05.000")
+				fmt.Printf("[%s] [%s] [process] External service ready after %v (attempts: %d)\n", timestamp, cmd.Name, time.Since(start).Round(time.Millisecond), attempts)
+			}
+			return nil
+		}
+
+		select {
+		case <-waitCtx.Done():
+			if ctx.Err() != nil {
+				return fmt.Errorf("stopped waiting for the external service of command '%s': %w", cmd.Name, ctx.Err())
+			}
+			return &ExternalReadyError{CommandName: cmd.Name, Timeout: timeout, Attempts: attempts, Err: checkErr}
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkExternalReady runs each check of a command's externalReady block once, returning the
+// first that fails
+func (e *Executor) checkExternalReady(ctx context.Context, cmd config.Command) error {
+	external := cmd.ExternalReady
+
+	if external.ReadyTCP != "" {
+		dialCtx, cancel := context.WithTimeout(ctx, readyTCPDialTimeout)
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(dialCtx, "tcp", external.ReadyTCP)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("%s does not accept connections: %w", external.ReadyTCP, err)
+		}
+		conn.Close()
+	}
+
+	if external.ReadyFile != "" {
+		if _, err := os.Stat(commandRelativePath(cmd, external.ReadyFile)); err != nil {
+			return fmt.Errorf("readyFile '%s' does not exist", external.ReadyFile)
+		}
+	}
+
+	if len(external.Command) > 0 {
+		e.mu.RLock()
+		processGroup := !cmd.NoProcessGroup && !e.noProcessGroups
+		e.mu.RUnlock()
+		processGroup = processGroup && !e.processGroupsDenied.Load()
+
+		output, err := e.runExternalReadyCommand(ctx, cmd, processGroup)
+		if err != nil && processGroup && isProcessGroupDenied(err) {
+			e.processGroupsDenied.Store(true)
+			output, err = e.runExternalReadyCommand(ctx, cmd, false)
+		}
+		if err != nil {
+			if lastLine := lastNonEmptyLine(output); lastLine != "" {
+				err = fmt.Errorf("%w: %s", err, lastLine)
+			}
+			return fmt.Errorf("check command '%s' failed: %w", strings.Join(external.Command, " "), err)
+		}
+	}
+
+	return nil
+}
+
+// runExternalReadyCommand runs the check command of a command's externalReady block through the
+// executor's runner and returns its combined output. When ctx ends, its whole process group is
+// killed, and a process it left holding its output is given streamDrainTimeout before the pipes
+// are closed, so a hanging check cannot outlast the wait.
+func (e *Executor) runExternalReadyCommand(ctx context.Context, cmd config.Command, processGroup bool) (string, error) {
+	command := cmd.ExternalReady.Command
+	check := exec.CommandContext(ctx, command[0], command[1:]...)
+	check.Dir = cmd.WorkDir
+	if len(cmd.Env) > 0 || len(cmd.EnvFromFile) > 0 {
+		env, err := BuildCommandEnv(cmd)
+		if err != nil {
+			return "", err
+		}
+		check.Env = env
+	}
+
+	// One writer for both streams, so os/exec writes to it from a single goroutine
+	var output bytes.Buffer
+	check.Stdout = &output
+	check.Stderr = &output
+
+	if processGroup {
+		e.configureProcessGroup(check)
+	}
+	check.Cancel = func() error {
+		if err := e.killProcessGroup(check.Process.Pid, false); err != nil {
+			return check.Process.Kill()
+		}
+		return nil
+	}
+	check.WaitDelay = streamDrainTimeout
+
+	if err := e.runner.Start(ctx, check); err != nil {
+		return "", err
+	}
+	err := e.runner.Wait(check)
+	return output.String(), err
+}
+
+// describeExternalReady lists the checks of a command's externalReady block
+func describeExternalReady(cmd config.Command) string {
+	external := cmd.ExternalReady
+	var checks []string
+	if external.ReadyTCP != "" {
+		checks = append(checks, "readyTCP "+external.ReadyTCP)
+	}
+	if external.ReadyFile != "" {
+		checks = append(checks, "readyFile "+external.ReadyFile)
+	}
+	if len(external.Command) > 0 {
+		checks = append(checks, "command "+strings.Join(external.Command, " "))
+	}
+	return strings.Join(checks, ", ")
+}
+
+// lastNonEmptyLine returns the last line of output with any text, which is usually where a
+// failing check says why
+func lastNonEmptyLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,155 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"net"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestExecutor_ExternalReadyTCP(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	// The service comes up on its own, outside seqr, a little after the run starts
+	addr := freeTCPAddress(t)
+	listening := make(chan net.Listener, 1)
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			listening <- nil
+			return
+		}
+		listening <- listener
+	}()
+	defer func() {
+		if listener := <-listening; listener != nil {
+			listener.Close()
+		}
+	}()
+
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "migrate", Command: "echo", Args: []string{"migrated"}, Mode: config.ModeOnce, ExternalReady: &config.ExternalReady{ReadyTCP: addr, Timeout: 10 * time.Second}},
+		},
+	}
+
+	executor := NewExecutor(false)
+	start := time.Now()
+	var err error
+	captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if elapsed < 300*time.Millisecond {
+		t.Errorf("Expected the command to wait for the external service, ran after %v", elapsed)
+	}
+	if results := executor.GetStatus().Results; len(results) != 1 || !results[0].Success || !strings.Contains(results[0].Output, "migrated") {
+		t.Errorf("Expected the command to run once the service was ready, got %+v", results)
+	}
+}
+
+func TestExecutor_ExternalReadyTimeout(t *testing.T) {
+	tests := []struct {
+		name      string
+		external  config.ExternalReady
+		errSubstr string
+		needsSh   bool
+	}{
+		{
+			name:      "port that never opens",
+			external:  config.ExternalReady{Timeout: 300 * time.Millisecond},
+			errSubstr: "does not accept connections",
+		},
+		{
+			name:      "check command that keeps failing",
+			external:  config.ExternalReady{Command: []string{"sh", "-c", "echo not yet; exit 1"}, Timeout: 300 * time.Millisecond},
+			errSubstr: "check command 'sh -c echo not yet; exit 1' failed: exit status 1: not yet",
+			needsSh:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.needsSh && runtime.GOOS == "windows" {
+				t.Skip("Test requires sh")
+			}
+			t.Setenv("TMPDIR", t.TempDir())
+
+			// Without a check command, the check is a port nothing listens on
+			external := tt.external
+			if external.Command == nil {
+				external.ReadyTCP = freeTCPAddress(t)
+			}
+			cfg := &config.Config{
+				Version: "1.0",
+				Commands: []config.Command{
+					{Name: "migrate", Command: "echo", Args: []string{"migrated"}, Mode: config.ModeOnce, ExternalReady: &external},
+				},
+			}
+
+			executor := NewExecutor(false)
+			var err error
+			captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+
+			var externalErr *ExternalReadyError
+			if !errors.As(err, &externalErr) {
+				t.Fatalf("Expected an ExternalReadyError, got %v", err)
+			}
+			if !strings.Contains(err.Error(), "external service for command 'migrate' was not ready within 300ms") || !strings.Contains(err.Error(), tt.errSubstr) {
+				t.Errorf("Expected the error to name the command, the timeout and %q, got %v", tt.errSubstr, err)
+			}
+
+			results := executor.GetStatus().Results
+			if len(results) != 1 {
+				t.Fatalf("Expected 1 result, got %d", len(results))
+			}
+			if results[0].Output != "" {
+				t.Errorf("Expected the command not to run, got output %q", results[0].Output)
+			}
+			if results[0].ErrorDetail == nil || results[0].ErrorDetail.Type != ErrorTypeExternalReady {
+				t.Errorf("Expected error type %q, got %+v", ErrorTypeExternalReady, results[0].ErrorDetail)
+			}
+		})
+	}
+}
+
+func TestExecutor_ExternalReadyHangingCheckCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sh")
+	}
+	t.Setenv("TMPDIR", t.TempDir())
+
+	// The background sleep keeps the check's output pipe open after the shell is killed
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "migrate", Command: "echo", Args: []string{"migrated"}, Mode: config.ModeOnce, ExternalReady: &config.ExternalReady{
+				Command: []string{"sh", "-c", "sleep 30 & sleep 30"},
+				Timeout: 300 * time.Millisecond,
+			}},
+		},
+	}
+
+	executor := NewExecutor(false)
+	start := time.Now()
+	var err error
+	captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+	elapsed := time.Since(start)
+
+	var externalErr *ExternalReadyError
+	if !errors.As(err, &externalErr) {
+		t.Fatalf("Expected an ExternalReadyError, got %v", err)
+	}
+	if elapsed > 5*time.Second {
+		t.Errorf("Expected the hanging check command to be stopped at the deadline, took %v", elapsed)
+	}
+}
@@ -0,0 +1,128 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+// fifoOpenTimeout is how long the executor waits for a reader to open a command's outputFifo
+const fifoOpenTimeout = 5 * time.Second
+
+// fifoPollInterval is how often the executor retries opening an outputFifo nothing reads yet
+const fifoPollInterval = 50 * time.Millisecond
+
+// fifoSink writes a command's output to its outputFifo. Writes are whole lines or chunks under a
+// lock, so stdout and stderr do not interleave within a line. Once the reader goes away, output
+// is dropped rather than failing, or blocking, the command. The FIFO is closed once every holder
+// has closed the sink.
+type fifoSink struct {
+	mu     sync.Mutex
+	file   *os.File
+	broken bool
+	refs   int // Holders that have yet to close the sink
+}
+
+// openOutputFifo opens a command's outputFifo for writing, or returns nil when it has none
+func openOutputFifo(cmd config.Command) (*fifoSink, error) {
+	if cmd.OutputFifo == "" {
+		return nil, nil
+	}
+
+	file, err := openFifo(commandRelativePath(cmd, cmd.OutputFifo), fifoOpenTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open outputFifo '%s': %w", cmd.OutputFifo, err)
+	}
+	return &fifoSink{file: file, refs: 1}, nil
+}
+
+// retain adds a holder, such as a keepAlive command's streaming, which outlives the caller. A nil
+// sink is a no-op.
+func (f *fifoSink) retain() {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.refs++
+}
+
+// Write writes p to the FIFO, reporting success even when the reader has gone away
+func (f *fifoSink) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.broken {
+		if _, err := f.file.Write(p); err != nil {
+			f.broken = true
+		}
+	}
+	return len(p), nil
+}
+
+// Info writes a stdout line
+func (f *fifoSink) Info(line string) error {
+	_, err := f.Write([]byte(line + "\n"))
+	return err
+}
+
+// Err writes a stderr line
+func (f *fifoSink) Err(line string) error {
+	_, err := f.Write([]byte(line + "\n"))
+	return err
+}
+
+// Close releases a holder's use of the sink, closing the FIFO, which the reader sees as the end of
+// the output, once no holder is left. A nil sink is a no-op.
+func (f *fifoSink) Close() error {
+	if f == nil {
+		return nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.refs--
+	if f.refs > 0 {
+		return nil
+	}
+	return f.file.Close()
+}
+
+// teeSink sends output lines to every one of its sinks
+type teeSink []syslogSink
+
+func (t teeSink) Info(line string) error {
+	for _, sink := range t {
+		sink.Info(line)
+	}
+	return nil
+}
+
+func (t teeSink) Err(line string) error {
+	for _, sink := range t {
+		sink.Err(line)
+	}
+	return nil
+}
+
+func (t teeSink) Close() error {
+	for _, sink := range t {
+		sink.Close()
+	}
+	return nil
+}
+
+// withFifo adds fifo to sink, either of which may be nil
+func withFifo(sink syslogSink, fifo *fifoSink) syslogSink {
+	switch {
+	case fifo == nil:
+		return sink
+	case sink == nil:
+		return fifo
+	default:
+		return teeSink{sink, fifo}
+	}
+}
@@ -0,0 +1,43 @@
+//go:build !windows
+
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"syscall"
+	"time"
+)
+
+// openFifo opens the FIFO at path for writing without blocking, retrying until a reader has
+// opened the other end or timeout passes. A path that is missing or not a FIFO fails at once.
+func openFifo(path string, timeout time.Duration) (*os.File, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%s does not exist, create it with mkfifo first", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if info.Mode()&fs.ModeNamedPipe == 0 {
+		return nil, fmt.Errorf("%s is not a FIFO", path)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		// Without a reader, a non-blocking open fails with ENXIO instead of waiting forever
+		file, err := os.OpenFile(path, os.O_WRONLY|syscall.O_NONBLOCK, 0)
+		if err == nil {
+			return file, nil
+		}
+		if !errors.Is(err, syscall.ENXIO) {
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("no reader opened %s within %v", path, timeout)
+		}
+		time.Sleep(fifoPollInterval)
+	}
+}
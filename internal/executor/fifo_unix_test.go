@@ -0,0 +1,171 @@
+//go:build !windows
+
+package executor
+
+import (
+	"bufio"
+	"context"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestExecutor_OutputFifoReceivesLines(t *testing.T) {
+	for _, verbose := range []bool{false, true} {
+		name := "quiet"
+		if verbose {
+			name = "verbose"
+		}
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("TMPDIR", t.TempDir())
+
+			path := filepath.Join(t.TempDir(), "build.fifo")
+			if err := syscall.Mkfifo(path, 0600); err != nil {
+				t.Fatalf("Failed to create FIFO: %v", err)
+			}
+
+			// The reader collects every line until the executor closes the FIFO
+			lines := make(chan []string, 1)
+			go func() {
+				fifo, err := os.Open(path)
+				if err != nil {
+					lines <- nil
+					return
+				}
+				defer fifo.Close()
+
+				var read []string
+				scanner := bufio.NewScanner(fifo)
+				for scanner.Scan() {
+					read = append(read, scanner.Text())
+				}
+				lines <- read
+			}()
+
+			cfg := &config.Config{
+				Version: "1.0",
+				Commands: []config.Command{
+					{Name: "build", Command: "sh", Args: []string{"-c", "echo compiling; echo debug: cache hit; echo done"}, Mode: config.ModeOnce, OutputFifo: path, HideLines: "^debug"},
+				},
+			}
+
+			executor := NewExecutorWithOptions(ExecutorOptions{Verbose: verbose})
+			var err error
+			captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+			if err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+
+			got := <-lines
+			if strings.Join(got, "\n") != "compiling\ndone" {
+				t.Errorf("Expected the FIFO to receive both shown lines, got %q", got)
+			}
+		})
+	}
+}
+
+// setpgidDeniedRunner refuses to start processes in a process group of their own, as sandboxes
+// that forbid setpgid do, and starts the rest with os/exec
+type setpgidDeniedRunner struct {
+	ExecRunner
+}
+
+func (r setpgidDeniedRunner) Start(ctx context.Context, execCmd *exec.Cmd) error {
+	if execCmd.SysProcAttr != nil && execCmd.SysProcAttr.Setpgid {
+		return &fs.PathError{Op: "fork/exec", Path: execCmd.Path, Err: syscall.EPERM}
+	}
+	return r.ExecRunner.Start(ctx, execCmd)
+}
+
+func TestExecutor_OutputFifoSurvivesProcessGroupFallback(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	path := filepath.Join(t.TempDir(), "build.fifo")
+	if err := syscall.Mkfifo(path, 0600); err != nil {
+		t.Fatalf("Failed to create FIFO: %v", err)
+	}
+
+	// Like cat, the reader stops at the first end of the output. Blocking reads see it as soon
+	// as the last writer closes the FIFO.
+	output := make(chan string, 1)
+	go func() {
+		fd, err := syscall.Open(path, syscall.O_RDONLY, 0)
+		if err != nil {
+			output <- ""
+			return
+		}
+		defer syscall.Close(fd)
+
+		var read []byte
+		buf := make([]byte, 4096)
+		for {
+			n, err := syscall.Read(fd, buf)
+			if n <= 0 || err != nil {
+				break
+			}
+			read = append(read, buf[:n]...)
+		}
+		output <- string(read)
+	}()
+
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "build", Command: "sh", Args: []string{"-c", "echo compiling; echo done"}, Mode: config.ModeOnce, OutputFifo: path},
+		},
+	}
+
+	executor := NewExecutorWithOptions(ExecutorOptions{Runner: setpgidDeniedRunner{}})
+	var err error
+	captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+	if err != nil {
+		t.Fatalf("Expected the command to run without a process group, got %v", err)
+	}
+
+	if got := <-output; got != "compiling\ndone\n" {
+		t.Errorf("Expected the FIFO to receive the output of the retried start, got %q", got)
+	}
+}
+
+func TestExecutor_OutputFifoErrors(t *testing.T) {
+	dir := t.TempDir()
+	regular := filepath.Join(dir, "plain.log")
+	if err := os.WriteFile(regular, nil, 0600); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	unread := filepath.Join(dir, "unread.fifo")
+	if err := syscall.Mkfifo(unread, 0600); err != nil {
+		t.Fatalf("Failed to create FIFO: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		wantError string
+	}{
+		{name: "missing", path: filepath.Join(dir, "missing.fifo"), wantError: "does not exist, create it with mkfifo first"},
+		{name: "not a FIFO", path: regular, wantError: "is not a FIFO"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := config.Command{Name: "build", Command: "true", Mode: config.ModeOnce, OutputFifo: tt.path}
+			_, err := openOutputFifo(cmd)
+			if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Errorf("Expected an error containing %q, got %v", tt.wantError, err)
+			}
+		})
+	}
+
+	// Nothing ever reads the FIFO, so opening it gives up after the timeout
+	_, err := openFifo(unread, 2*fifoPollInterval)
+	if err == nil || !strings.Contains(err.Error(), "no reader opened") {
+		t.Errorf("Expected opening a FIFO without a reader to time out, got %v", err)
+	}
+}
@@ -0,0 +1,14 @@
+//go:build windows
+
+package executor
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// openFifo reports that FIFOs are unavailable, since Windows has no named pipes in the file system
+func openFifo(path string, timeout time.Duration) (*os.File, error) {
+	return nil, fmt.Errorf("FIFOs are not supported on Windows")
+}
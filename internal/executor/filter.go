@@ -0,0 +1,150 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// executeOnceWithFilter runs a once command with its stdout piped into its filter command, like
+// `command | filter` in a shell. The filter's output is what is streamed and captured, while the
+// command's stderr bypasses the filter. As with pipefail, a failure of the command itself is
+// reported before a failure of the filter.
+func (e *Executor) executeOnceWithFilter(ctx context.Context, execCmd *exec.Cmd, result ExecutionResult, fifo *fifoSink) (ExecutionResult, error) {
+	filter := result.Command.Filter
+	filterCmd := exec.CommandContext(ctx, filter[0], filter[1:]...)
+	filterCmd.Dir = execCmd.Dir
+	filterCmd.Env = execCmd.Env
+	if execCmd.SysProcAttr != nil {
+		e.configureProcessGroup(filterCmd)
+	}
+
+	fail := func(err error) (ExecutionResult, error) {
+		result.EndTime = time.Now()
+		result.Duration = result.EndTime.Sub(result.StartTime)
+		result.Success = false
+		result.Error = err.Error()
+		result.ExitCode = -1
+		return result, err
+	}
+
+	// The command writes into the filter's stdin
+	pipeReader, pipeWriter, err := os.Pipe()
+	if err != nil {
+		return fail(fmt.Errorf("failed to create filter pipe: %w", err))
+	}
+	execCmd.Stdout = pipeWriter
+	filterCmd.Stdin = pipeReader
+
+	outReader, outWriter, err := os.Pipe()
+	if err != nil {
+		closeFiles(pipeReader, pipeWriter)
+		return fail(fmt.Errorf("failed to create output pipe: %w", err))
+	}
+
+	// Without verbose output nothing tells the streams apart, so they share one pipe as
+	// CombinedOutput does for unfiltered commands
+	errReader, errWriter := outReader, outWriter
+	if e.verbose {
+		errReader, errWriter, err = os.Pipe()
+		if err != nil {
+			closeFiles(pipeReader, pipeWriter, outReader, outWriter)
+			return fail(fmt.Errorf("failed to create stderr pipe: %w", err))
+		}
+	}
+	filterCmd.Stdout = outWriter
+	filterCmd.Stderr = errWriter
+	execCmd.Stderr = errWriter
+
+	if err := e.runner.Start(ctx, filterCmd); err != nil {
+		closeFiles(pipeReader, pipeWriter, outReader, outWriter, errReader, errWriter)
+		return fail(fmt.Errorf("failed to start filter '%s': %w", filter[0], err))
+	}
+
+	if err := e.runner.Start(ctx, execCmd); err != nil {
+		// Closing the pipe gives the filter EOF, so it exits on its own
+		closeFiles(pipeReader, pipeWriter, outWriter, errWriter)
+		e.runner.Wait(filterCmd)
+		closeFiles(outReader, errReader)
+		return fail(err)
+	}
+
+	// Only the child processes hold the write ends now, so the readers see EOF once both exit
+	closeFiles(pipeReader, pipeWriter, outWriter, errWriter)
+
+	var outputBuilder strings.Builder
+	var wg sync.WaitGroup
+	if e.verbose {
+		sink := withFifo(nil, fifo)
+		lines := newLineFilter(result.Command)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			e.streamOutput(outReader, &outputBuilder, result.Command.Name, "stdout", result.Command.Command, sink, lines, result.Command.DedupeOutput)
+		}()
+		go func() {
+			defer wg.Done()
+			e.streamOutput(errReader, &outputBuilder, result.Command.Name, "stderr", result.Command.Command, sink, lines, result.Command.DedupeOutput)
+		}()
+	} else {
+		var combined io.Writer = &outputBuilder
+		if fifo != nil {
+			toFifo := newFilteredWriter(fifo, newLineFilter(result.Command))
+			defer toFifo.Close()
+			combined = io.MultiWriter(&outputBuilder, toFifo)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			io.Copy(combined, outReader)
+		}()
+	}
+
+	err = e.runner.Wait(execCmd)
+	filterErr := e.runner.Wait(filterCmd)
+
+	// A process either of them left behind may still hold the output pipes open, so they are
+	// closed after a bounded wait for the output that is already buffered
+	if !waitForStreams(&wg, streamDrainTimeout) {
+		closeFiles(outReader, errReader)
+		wg.Wait()
+	}
+	closeFiles(outReader, errReader)
+
+	result.EndTime = time.Now()
+	result.Duration = result.EndTime.Sub(result.StartTime)
+	result.Output = strings.TrimSpace(outputBuilder.String())
+
+	if err == nil && filterErr != nil {
+		err = fmt.Errorf("filter '%s' failed: %w", strings.Join(filter, " "), filterErr)
+	}
+
+	if err != nil {
+		result.Success = false
+		result.Error = err.Error()
+		result.ExitCode = exitCodeOf(err)
+		return result, err
+	}
+
+	result.Success = true
+	result.ExitCode = 0
+	return result, nil
+}
+
+// closeFiles closes each file once, ignoring errors; a file may be listed twice when two
+// streams share a pipe
+func closeFiles(files ...*os.File) {
+	closed := make(map[*os.File]bool, len(files))
+	for _, f := range files {
+		if f == nil || closed[f] {
+			continue
+		}
+		closed[f] = true
+		f.Close()
+	}
+}
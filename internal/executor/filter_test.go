@@ -0,0 +1,124 @@
+package executor
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestExecutor_FilterTransformsOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires tr")
+	}
+
+	for _, verbose := range []bool{false, true} {
+		cfg := &config.Config{
+			Version: "1.0",
+			Commands: []config.Command{
+				{Name: "shout", Command: "echo", Args: []string{"hello world"}, Mode: config.ModeOnce, Filter: []string{"tr", "a-z", "A-Z"}},
+			},
+		}
+
+		executor := NewExecutor(verbose)
+		if verbose {
+			captureOutput(func() {
+				if err := executor.Execute(context.Background(), cfg); err != nil {
+					t.Fatalf("Execute returned error: %v", err)
+				}
+			})
+		} else if err := executor.Execute(context.Background(), cfg); err != nil {
+			t.Fatalf("Execute returned error: %v", err)
+		}
+
+		results := executor.GetStatus().Results
+		if len(results) != 1 || results[0].Output != "HELLO WORLD" {
+			t.Errorf("verbose=%v: expected filtered output 'HELLO WORLD', got %+v", verbose, results)
+		}
+	}
+}
+
+func TestExecutor_FilterFailureIsReported(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sh")
+	}
+
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "grep", Command: "echo", Args: []string{"nothing to see"}, Mode: config.ModeOnce, Filter: []string{"sh", "-c", "cat >/dev/null; exit 4"}},
+		},
+	}
+
+	executor := NewExecutor(false)
+	err := executor.Execute(context.Background(), cfg)
+	if err == nil || !strings.Contains(err.Error(), "filter 'sh -c cat >/dev/null; exit 4' failed") {
+		t.Fatalf("Expected the filter failure to be reported, got %v", err)
+	}
+
+	result := executor.GetStatus().Results[0]
+	if result.Success || result.ExitCode != 4 || result.ErrorDetail == nil || result.ErrorDetail.Type != ErrorTypeExitCode {
+		t.Errorf("Expected a failed result with the filter's exit code 4, got %+v", result)
+	}
+}
+
+func TestExecutor_FilterRunsThroughRunner(t *testing.T) {
+	runner := newFakeRunner(map[string]fakeProcess{
+		"fake-gen":    {output: "raw"},
+		"fake-filter": {output: "FILTERED", exitCode: 3},
+	})
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "gen", Command: "fake-gen", Mode: config.ModeOnce, Filter: []string{"fake-filter"}},
+		},
+	}
+
+	executor := NewExecutorWithOptions(ExecutorOptions{Runner: runner})
+	var err error
+	captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+	if err == nil || !strings.Contains(err.Error(), "filter 'fake-filter' failed") {
+		t.Fatalf("Expected the filter failure to be reported, got %v", err)
+	}
+
+	if strings.Join(runner.started, ",") != "fake-filter,fake-gen" {
+		t.Errorf("Expected the filter and the command to start through the runner, got %v", runner.started)
+	}
+	results := executor.GetStatus().Results
+	if len(results) != 1 || results[0].Output != "FILTERED" || results[0].ExitCode != 3 {
+		t.Errorf("Expected the filter's output and exit code, got %+v", results)
+	}
+}
+
+func TestExecutor_FilterLeavingProcessBehind(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sh")
+	}
+
+	// The background sleep inherits the filter's stdout and keeps it open after the filter exits
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "gen", Command: "echo", Args: []string{"hello"}, Mode: config.ModeOnce, Filter: []string{"sh", "-c", "sleep 30 & cat"}},
+		},
+	}
+
+	for _, verbose := range []bool{false, true} {
+		executor := NewExecutor(verbose)
+		start := time.Now()
+		var err error
+		captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+		if err != nil {
+			t.Fatalf("verbose=%v: Execute returned error: %v", verbose, err)
+		}
+		if elapsed := time.Since(start); elapsed > 10*time.Second {
+			t.Errorf("verbose=%v: expected the run to finish once the filter exited, took %v", verbose, elapsed)
+		}
+		if results := executor.GetStatus().Results; len(results) != 1 || !strings.Contains(results[0].Output, "hello") {
+			t.Errorf("verbose=%v: expected the filtered output, got %+v", verbose, results)
+		}
+	}
+}
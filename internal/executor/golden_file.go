@@ -0,0 +1,141 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+// maxGoldenDiffLines bounds the changed lines a golden file mismatch reports
+const maxGoldenDiffLines = 50
+
+// maxGoldenDiffCells bounds the line pairs compared to find the smallest diff; past it, lines are
+// compared by position instead
+const maxGoldenDiffCells = 1024 * 1024
+
+// GoldenFileMismatchError reports a command whose output differs from its golden file
+type GoldenFileMismatchError struct {
+	CommandName string
+	Path        string
+	Diff        string // Lines of the golden file prefixed with "-" and lines of the output prefixed with "+"
+}
+
+// Error implements the error interface
+func (e *GoldenFileMismatchError) Error() string {
+	return fmt.Sprintf("output of command '%s' differs from golden file '%s':\n%s", e.CommandName, e.Path, e.Diff)
+}
+
+// checkGoldenFile compares a command's captured output with its goldenFile, or with update,
+// writes the output to it instead. Like the captured output, the golden file is compared without
+// surrounding whitespace, so the final newline editors add does not count.
+func checkGoldenFile(cmd config.Command, output string, update bool) error {
+	if cmd.GoldenFile == "" {
+		return nil
+	}
+
+	if update {
+		if err := os.MkdirAll(filepath.Dir(cmd.GoldenFile), 0755); err != nil {
+			return fmt.Errorf("failed to update golden file for command '%s': %w", cmd.Name, err)
+		}
+		if err := os.WriteFile(cmd.GoldenFile, []byte(output+"\n"), 0644); err != nil {
+			return fmt.Errorf("failed to update golden file for command '%s': %w", cmd.Name, err)
+		}
+		return nil
+	}
+
+	expected, err := os.ReadFile(cmd.GoldenFile)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("golden file '%s' of command '%s' does not exist, run with --update-golden to create it", cmd.GoldenFile, cmd.Name)
+		}
+		return fmt.Errorf("failed to read golden file of command '%s': %w", cmd.Name, err)
+	}
+
+	if golden := strings.TrimSpace(string(expected)); golden != output {
+		return &GoldenFileMismatchError{CommandName: cmd.Name, Path: cmd.GoldenFile, Diff: diffLines(golden, output)}
+	}
+	return nil
+}
+
+// diffLines returns the lines that differ between expected and actual, expected lines prefixed
+// with "-" and actual lines with "+", in the order they appear
+func diffLines(expected, actual string) string {
+	a := strings.Split(expected, "\n")
+	b := strings.Split(actual, "\n")
+
+	var changes []string
+	if len(a)*len(b) > maxGoldenDiffCells {
+		changes = diffByPosition(a, b)
+	} else {
+		changes = diffByCommonLines(a, b)
+	}
+
+	if len(changes) > maxGoldenDiffLines {
+		omitted := len(changes) - maxGoldenDiffLines
+		changes = append(changes[:maxGoldenDiffLines], fmt.Sprintf("... %d more changed lines", omitted))
+	}
+	return strings.Join(changes, "\n")
+}
+
+// diffByCommonLines diffs a and b around their longest common subsequence of lines
+func diffByCommonLines(a, b []string) []string {
+	// common[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	var changes []string
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			i++
+			j++
+		case common[i+1][j] >= common[i][j+1]:
+			changes = append(changes, "-"+a[i])
+			i++
+		default:
+			changes = append(changes, "+"+b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		changes = append(changes, "-"+a[i])
+	}
+	for ; j < len(b); j++ {
+		changes = append(changes, "+"+b[j])
+	}
+	return changes
+}
+
+// diffByPosition diffs a and b line by line, for outputs too large to align
+func diffByPosition(a, b []string) []string {
+	var changes []string
+	for i := 0; i < max(len(a), len(b)); i++ {
+		if i < len(a) && i < len(b) && a[i] == b[i] {
+			continue
+		}
+		if i < len(a) {
+			changes = append(changes, "-"+a[i])
+		}
+		if i < len(b) {
+			changes = append(changes, "+"+b[i])
+		}
+	}
+	return changes
+}
@@ -0,0 +1,124 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestExecutor_GoldenFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires printf")
+	}
+
+	const output = "alpha\nbeta\ngamma\n"
+
+	tests := []struct {
+		name     string
+		golden   string
+		wantDiff string
+	}{
+		{name: "matching output", golden: output},
+		{name: "changed line", golden: "alpha\nBETA\ngamma\n", wantDiff: "-BETA\n+beta"},
+		{name: "missing and extra lines", golden: "alpha\ngamma\ndelta\n", wantDiff: "+beta\n-delta"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goldenFile := filepath.Join(t.TempDir(), "list.golden")
+			if err := os.WriteFile(goldenFile, []byte(tt.golden), 0644); err != nil {
+				t.Fatalf("Failed to write golden file: %v", err)
+			}
+
+			cfg := &config.Config{
+				Version: "1.0",
+				Commands: []config.Command{
+					{Name: "list", Command: "printf", Args: []string{output}, Mode: config.ModeOnce, GoldenFile: goldenFile},
+				},
+			}
+
+			executor := NewExecutor(false)
+			var err error
+			captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+			result := executor.GetStatus().Results[0]
+
+			if tt.wantDiff == "" {
+				if err != nil || !result.Success {
+					t.Fatalf("Expected the output to match the golden file, got %v", err)
+				}
+				return
+			}
+
+			if err == nil || result.Success {
+				t.Fatal("Expected the command to fail on the golden file mismatch")
+			}
+			if result.ErrorDetail == nil || result.ErrorDetail.Type != ErrorTypeOutputMismatch {
+				t.Fatalf("Expected error type %q, got %+v", ErrorTypeOutputMismatch, result.ErrorDetail)
+			}
+			if !strings.HasSuffix(result.ErrorDetail.Message, ":\n"+tt.wantDiff) {
+				t.Errorf("Expected the error detail to end with the diff %q, got %q", tt.wantDiff, result.ErrorDetail.Message)
+			}
+		})
+	}
+}
+
+func TestExecutor_UpdateGolden(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires printf")
+	}
+
+	goldenFile := filepath.Join(t.TempDir(), "testdata", "list.golden")
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "list", Command: "printf", Args: []string{"alpha\nbeta\n"}, Mode: config.ModeOnce, GoldenFile: goldenFile},
+		},
+	}
+
+	// Without a golden file, the command fails until one is recorded
+	executor := NewExecutor(false)
+	var err error
+	captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+	if err == nil || !strings.Contains(err.Error(), "--update-golden") {
+		t.Fatalf("Expected a missing golden file to fail with a hint, got %v", err)
+	}
+
+	executor = NewExecutorWithOptions(ExecutorOptions{UpdateGolden: true})
+	captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+	if err != nil {
+		t.Fatalf("Expected updating the golden file to succeed, got %v", err)
+	}
+
+	data, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatalf("Expected the golden file to be written: %v", err)
+	}
+	if string(data) != "alpha\nbeta\n" {
+		t.Errorf("Expected the golden file to hold the output, got %q", data)
+	}
+
+	executor = NewExecutor(false)
+	captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+	if err != nil {
+		t.Errorf("Expected the output to match the updated golden file, got %v", err)
+	}
+}
+
+func TestDiffLines_Truncated(t *testing.T) {
+	expected := strings.Repeat("old\n", maxGoldenDiffLines)
+	actual := strings.Repeat("new\n", maxGoldenDiffLines)
+
+	diff := diffLines(expected, actual)
+	lines := strings.Split(diff, "\n")
+	if len(lines) != maxGoldenDiffLines+1 {
+		t.Fatalf("Expected %d diff lines and a summary, got %d", maxGoldenDiffLines, len(lines))
+	}
+	if want := "... 50 more changed lines"; lines[len(lines)-1] != want {
+		t.Errorf("Expected summary %q, got %q", want, lines[len(lines)-1])
+	}
+}
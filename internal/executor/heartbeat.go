@@ -0,0 +1,81 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// outputHeartbeat logs that a streaming keepAlive process is still running when it has been
+// silent for a whole interval, so a quiet process is not mistaken for a hung one
+type outputHeartbeat struct {
+	interval time.Duration
+	activity chan struct{}
+}
+
+// newOutputHeartbeat returns a heartbeat for the given interval, or nil when interval is zero
+func newOutputHeartbeat(interval time.Duration) *outputHeartbeat {
+	if interval <= 0 {
+		return nil
+	}
+	return &outputHeartbeat{
+		interval: interval,
+		activity: make(chan struct{}, 1),
+	}
+}
+
+// touch records that the process produced a line of output, restarting the silence timer
+func (h *outputHeartbeat) touch() {
+	if h == nil {
+		return
+	}
+	select {
+	case h.activity <- struct{}{}:
+	default:
+		// A reset is already pending
+	}
+}
+
+// run logs a heartbeat line each time the process stays silent for the interval, until ctx is done
+func (h *outputHeartbeat) run(ctx context.Context, e *Executor, commandName, command string) {
+	if h == nil {
+		return
+	}
+
+	timer := time.NewTimer(h.interval)
+	defer timer.Stop()
+
+	lastOutput := time.Now()
+	cmdType := e.detectCommandType(command)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-h.activity:
+			lastOutput = time.Now()
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(h.interval)
+		case <-timer.C:
+			if e.isStopped() {
+				return
+			}
+
+			timestamp := time.Now().Format("15:04:05.000")
+			coloredTimestamp := colorize(timestamp, colorGray)
+			coloredType := e.colorizeCommandType(cmdType)
+			coloredName := colorize(commandName, colorCyan)
+			fmt.Printf("%s still running (no output for %v)\n",
+				e.streamPrefix(coloredTimestamp, coloredType, coloredName), time.Since(lastOutput).Round(time.Second))
+			os.Stdout.Sync()
+
+			timer.Reset(h.interval)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package executor
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestHeartbeatForSilentKeepAliveProcess(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires the sleep command")
+	}
+	t.Setenv("NO_COLOR", "1")
+
+	executor := NewExecutorWithOptions(ExecutorOptions{Verbose: true, HeartbeatInterval: 200 * time.Millisecond})
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "silent", Command: "sleep", Args: []string{"30"}, Mode: config.ModeKeepAlive},
+		},
+	}
+
+	output := captureOutput(func() {
+		if err := executor.Execute(context.Background(), cfg); err != nil {
+			t.Errorf("Execute failed: %v", err)
+			return
+		}
+		time.Sleep(700 * time.Millisecond)
+		executor.Stop()
+	})
+
+	if !strings.Contains(output, "[silent] still running (no output for") {
+		t.Errorf("Expected at least one heartbeat for the silent process, got:\n%s", output)
+	}
+}
+
+func TestHeartbeatDisabledByZeroInterval(t *testing.T) {
+	heartbeat := newOutputHeartbeat(0)
+	if heartbeat != nil {
+		t.Fatal("Expected no heartbeat for a zero interval")
+	}
+
+	// A disabled heartbeat is safe to use from the streaming goroutines
+	heartbeat.touch()
+	heartbeat.run(context.Background(), NewExecutor(false), "silent", "sleep")
+}
@@ -0,0 +1,75 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// hostMemoryInterval is how often the host memory watchdog samples memory use
+const hostMemoryInterval = 5 * time.Second
+
+// HostMemoryError reports a run stopped because the host's memory use exceeded
+// ExecutorOptions.MaxHostMemPercent
+type HostMemoryError struct {
+	Percent float64 // Memory use of the host when it was sampled, in percent
+	Limit   float64 // The MaxHostMemPercent it exceeded
+}
+
+// Error implements the error interface
+func (e *HostMemoryError) Error() string {
+	return fmt.Sprintf("host memory use of %.1f%% exceeded the %g%% limit, all commands were stopped", e.Percent, e.Limit)
+}
+
+// watchHostMemory samples the host's memory use until ctx ends or the executor is stopped, and
+// stops everything once it exceeds the limit: the keepAlive processes gracefully, then any once
+// command still running by cancelling the run. Only one watchdog runs per executor. Without a
+// limit, or where memory cannot be sampled, it does nothing beyond a warning.
+func (e *Executor) watchHostMemory(ctx context.Context) {
+	if e.maxHostMemPercent <= 0 || !e.watchdogActive.CompareAndSwap(false, true) {
+		return
+	}
+
+	go func() {
+		defer e.watchdogActive.Store(false)
+
+		ticker := time.NewTicker(e.hostMemoryInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if e.isStopped() {
+				return
+			}
+
+			percent, err := e.sampleHostMemory()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: not watching host memory: %v\n", err)
+				return
+			}
+			if percent <= e.maxHostMemPercent {
+				continue
+			}
+
+			memErr := &HostMemoryError{Percent: percent, Limit: e.maxHostMemPercent}
+			fmt.Fprintf(os.Stderr, "Host memory use of %.1f%% exceeds the %g%% limit, stopping all commands\n", percent, e.maxHostMemPercent)
+
+			// Recorded first, so a run that notices the stop fails with the reason
+			e.mu.Lock()
+			e.hostMemoryErr = memErr
+			cancel := e.cancelForMemory
+			e.mu.Unlock()
+
+			e.Stop()
+			if cancel != nil {
+				cancel(memErr)
+			}
+			return
+		}
+	}()
+}
@@ -0,0 +1,61 @@
+package executor
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// hostMemoryPercent returns the share of the host's memory in use, in percent, from the
+// MemTotal and MemAvailable lines of /proc/meminfo
+func hostMemoryPercent() (float64, error) {
+	file, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	return meminfoPercent(file)
+}
+
+// meminfoPercent computes the share of memory in use from meminfo content. Kernels older than
+// 3.14 do not report MemAvailable, which is an error rather than every byte counting as used.
+func meminfoPercent(meminfo io.Reader) (float64, error) {
+	var total, available float64
+	var hasAvailable bool
+	scanner := bufio.NewScanner(meminfo)
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), ":")
+		if !found {
+			continue
+		}
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			continue
+		}
+		kib, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			continue
+		}
+		switch key {
+		case "MemTotal":
+			total = kib
+		case "MemAvailable":
+			available = kib
+			hasAvailable = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	if total == 0 {
+		return 0, fmt.Errorf("no MemTotal in /proc/meminfo")
+	}
+	if !hasAvailable {
+		return 0, fmt.Errorf("no MemAvailable in /proc/meminfo")
+	}
+	return (total - available) / total * 100, nil
+}
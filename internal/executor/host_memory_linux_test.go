@@ -0,0 +1,31 @@
+package executor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMeminfoPercent(t *testing.T) {
+	tests := []struct {
+		name    string
+		meminfo string
+		want    float64
+		wantErr bool
+	}{
+		{name: "in use", meminfo: "MemTotal:       1000 kB\nMemFree:         100 kB\nMemAvailable:    250 kB\n", want: 75},
+		{name: "no MemAvailable", meminfo: "MemTotal:       1000 kB\nMemFree:         100 kB\n", wantErr: true},
+		{name: "no MemTotal", meminfo: "MemAvailable:    250 kB\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := meminfoPercent(strings.NewReader(tt.meminfo))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("meminfoPercent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("meminfoPercent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
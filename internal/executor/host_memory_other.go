@@ -0,0 +1,13 @@
+//go:build !linux
+
+package executor
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// hostMemoryPercent reports that host memory use cannot be sampled on this platform
+func hostMemoryPercent() (float64, error) {
+	return 0, fmt.Errorf("host memory sampling is not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,96 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestExecutor_HostMemoryWatchdogStopsRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sleep")
+	}
+	t.Setenv("TMPDIR", t.TempDir())
+
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "api", Command: "sleep", Args: []string{"30"}, Mode: config.ModeKeepAlive},
+			{Name: "load-test", Command: "sleep", Args: []string{"30"}, Mode: config.ModeOnce},
+		},
+	}
+
+	executor := NewExecutorWithOptions(ExecutorOptions{MaxHostMemPercent: 90})
+	executor.hostMemoryInterval = 10 * time.Millisecond
+	executor.sampleHostMemory = func() (float64, error) { return 97.5, nil }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	start := time.Now()
+	var err error
+	captureOutput(func() { err = executor.Execute(ctx, cfg) })
+	defer executor.Stop()
+
+	var memErr *HostMemoryError
+	if !errors.As(err, &memErr) {
+		t.Fatalf("Expected the run to fail with a HostMemoryError, got %v", err)
+	}
+	if memErr.Percent != 97.5 || memErr.Limit != 90 {
+		t.Errorf("Expected 97.5%% against a 90%% limit, got %+v", memErr)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Expected the running once command to be killed, the run took %v", elapsed)
+	}
+	if !executor.isStopped() {
+		t.Error("Expected the executor to be stopped")
+	}
+	if executor.HasActiveKeepAliveProcesses() {
+		t.Error("Expected the keepAlive process to be stopped")
+	}
+	if status := executor.GetStatus(); status.State != StateFailed || status.LastError != memErr.Error() {
+		t.Errorf("Expected the run to be failed with the reason, got %s: %q", status.State, status.LastError)
+	}
+}
+
+func TestExecutor_HostMemoryWatchdogBelowLimit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sleep")
+	}
+	t.Setenv("TMPDIR", t.TempDir())
+
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "build", Command: "sleep", Args: []string{"0.2"}, Mode: config.ModeOnce},
+		},
+	}
+
+	executor := NewExecutorWithOptions(ExecutorOptions{MaxHostMemPercent: 90})
+	executor.hostMemoryInterval = 10 * time.Millisecond
+	var samples atomic.Int32
+	executor.sampleHostMemory = func() (float64, error) {
+		samples.Add(1)
+		return 60, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var err error
+	captureOutput(func() { err = executor.Execute(ctx, cfg) })
+	if err != nil {
+		t.Fatalf("Expected the run to succeed below the limit, got %v", err)
+	}
+	if samples.Load() == 0 {
+		t.Error("Expected host memory to be sampled during the run")
+	}
+	if executor.isStopped() {
+		t.Error("Expected the executor not to be stopped below the limit")
+	}
+}
@@ -0,0 +1,177 @@
+package executor
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+// JUnitTestSuites is the root element of a JUnit XML report
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite groups the commands of a single seqr run
+type JUnitTestSuite struct {
+	Name       string           `xml:"name,attr"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	Skipped    int              `xml:"skipped,attr"`
+	Time       string           `xml:"time,attr"`
+	Timestamp  string           `xml:"timestamp,attr,omitempty"`
+	Properties *JUnitProperties `xml:"properties,omitempty"` // The run ID, if the run has one
+	TestCases  []JUnitTestCase  `xml:"testcase"`
+}
+
+// JUnitTestCase represents one command in the report
+type JUnitTestCase struct {
+	Name       string           `xml:"name,attr"`
+	ClassName  string           `xml:"classname,attr"`
+	Time       string           `xml:"time,attr"`
+	Properties *JUnitProperties `xml:"properties,omitempty"`
+	Failure    *JUnitFailure    `xml:"failure,omitempty"`
+	Skipped    *JUnitSkipped    `xml:"skipped,omitempty"`
+	SystemOut  string           `xml:"system-out,omitempty"`
+}
+
+// JUnitProperties lists a command's meta as name/value properties
+type JUnitProperties struct {
+	Properties []JUnitProperty `xml:"property"`
+}
+
+// JUnitProperty is one meta entry of a command
+type JUnitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// JUnitFailure describes why a command failed
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Details string `xml:",chardata"`
+}
+
+// JUnitSkipped marks a command that never ran
+type JUnitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// NewJUnitReport builds a JUnit report from a run's status. Commands from the configuration
+// that have no result, for example because an earlier command failed, are reported as skipped.
+func NewJUnitReport(suiteName string, commands []config.Command, status ExecutionStatus) JUnitTestSuites {
+	suite := JUnitTestSuite{Name: suiteName}
+	if status.RunID != "" {
+		suite.Properties = &JUnitProperties{Properties: []JUnitProperty{{Name: "runId", Value: status.RunID}}}
+	}
+
+	var total time.Duration
+	var earliest time.Time
+	reported := make(map[string]bool)
+
+	for _, result := range status.Results {
+		reported[result.Command.Name] = true
+		total += result.Duration
+		if !result.StartTime.IsZero() && (earliest.IsZero() || result.StartTime.Before(earliest)) {
+			earliest = result.StartTime
+		}
+
+		testCase := JUnitTestCase{
+			Name:       result.Command.Name,
+			ClassName:  suiteName,
+			Time:       junitSeconds(result.Duration),
+			Properties: junitProperties(result.Meta),
+			SystemOut:  result.Output,
+		}
+
+		// A failure allowFailure let through did not fail the run, so it is noted, not counted
+		if !result.Success && result.AllowedFailure {
+			testCase.SystemOut = fmt.Sprintf("Failed with exit code %d, allowed by allowFailure: %s\n", result.ExitCode, result.Error) + result.Output
+		} else if !result.Success {
+			testCase.Failure = &JUnitFailure{
+				Message: result.Error,
+				Type:    fmt.Sprintf("exit code %d", result.ExitCode),
+				Details: result.Output,
+			}
+			suite.Failures++
+		}
+
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	for _, cmd := range commands {
+		if reported[cmd.Name] {
+			continue
+		}
+		suite.TestCases = append(suite.TestCases, JUnitTestCase{
+			Name:       cmd.Name,
+			ClassName:  suiteName,
+			Time:       junitSeconds(0),
+			Properties: junitProperties(cmd.Meta),
+			Skipped:    &JUnitSkipped{Message: "command did not run"},
+		})
+		suite.Skipped++
+	}
+
+	suite.Tests = len(suite.TestCases)
+	suite.Time = junitSeconds(total)
+	if !earliest.IsZero() {
+		suite.Timestamp = earliest.Format(time.RFC3339)
+	}
+
+	return JUnitTestSuites{
+		Name:     "seqr",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+		Time:     suite.Time,
+		Suites:   []JUnitTestSuite{suite},
+	}
+}
+
+// WriteJUnitReport writes a run's status to w as JUnit XML
+func WriteJUnitReport(w io.Writer, suiteName string, commands []config.Command, status ExecutionStatus) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("failed to write JUnit report: %w", err)
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(NewJUnitReport(suiteName, commands, status)); err != nil {
+		return fmt.Errorf("failed to encode JUnit report: %w", err)
+	}
+
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return fmt.Errorf("failed to write JUnit report: %w", err)
+	}
+	return nil
+}
+
+// junitSeconds formats a duration as the fractional seconds JUnit expects in time attributes
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// junitProperties turns a command's meta into properties sorted by name, or nil without meta
+func junitProperties(meta map[string]string) *JUnitProperties {
+	if len(meta) == 0 {
+		return nil
+	}
+
+	properties := &JUnitProperties{}
+	for _, name := range slices.Sorted(maps.Keys(meta)) {
+		properties.Properties = append(properties.Properties, JUnitProperty{Name: name, Value: meta[name]})
+	}
+	return properties
+}
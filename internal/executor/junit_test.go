@@ -0,0 +1,112 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestWriteJUnitReport(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sh")
+	}
+
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "setup", Command: "echo", Args: []string{"ready"}, Mode: config.ModeOnce},
+			{
+				Name:    "check",
+				Command: "sh",
+				Args:    []string{"-c", `printf 'a < b && "c" \033[31mred\033[0m\n'; exit 3`},
+				Mode:    config.ModeOnce,
+			},
+			{Name: "deploy", Command: "echo", Args: []string{"never"}, Mode: config.ModeOnce},
+		},
+	}
+
+	executor := NewExecutor(false)
+	if err := executor.Execute(context.Background(), cfg); err == nil {
+		t.Fatal("Expected execution to fail")
+	}
+
+	var buf bytes.Buffer
+	if err := WriteJUnitReport(&buf, "queue.json", cfg.Commands, executor.GetStatus()); err != nil {
+		t.Fatalf("WriteJUnitReport failed: %v", err)
+	}
+
+	raw := buf.String()
+	if !strings.HasPrefix(raw, xml.Header) {
+		t.Error("Expected report to start with the XML header")
+	}
+	if !strings.Contains(raw, "a &lt; b &amp;&amp; &#34;c&#34;") {
+		t.Errorf("Expected output to be XML-escaped, got:\n%s", raw)
+	}
+	if strings.Contains(raw, "\x1b") {
+		t.Error("Expected control characters to be stripped from the report")
+	}
+
+	var report JUnitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("Report is not valid XML: %v\n%s", err, raw)
+	}
+
+	if report.Tests != 3 || report.Failures != 1 || report.Skipped != 1 {
+		t.Errorf("Expected 3 tests, 1 failure, 1 skipped, got %d, %d, %d", report.Tests, report.Failures, report.Skipped)
+	}
+	if len(report.Suites) != 1 || report.Suites[0].Name != "queue.json" {
+		t.Fatalf("Expected a single suite named queue.json, got %+v", report.Suites)
+	}
+
+	cases := make(map[string]JUnitTestCase)
+	for _, testCase := range report.Suites[0].TestCases {
+		cases[testCase.Name] = testCase
+	}
+
+	if setup := cases["setup"]; setup.Failure != nil || setup.Skipped != nil || setup.Time == "" {
+		t.Errorf("Expected setup to pass with a time attribute, got %+v", setup)
+	}
+
+	check := cases["check"]
+	if check.Failure == nil {
+		t.Fatal("Expected check to be reported as a failure")
+	}
+	if check.Failure.Type != "exit code 3" {
+		t.Errorf("Expected failure type 'exit code 3', got %q", check.Failure.Type)
+	}
+	if !strings.Contains(check.Failure.Details, `a < b && "c"`) {
+		t.Errorf("Expected failure details to round-trip the command output, got %q", check.Failure.Details)
+	}
+
+	if deploy := cases["deploy"]; deploy.Skipped == nil {
+		t.Errorf("Expected deploy to be reported as skipped, got %+v", deploy)
+	}
+}
+
+func TestNewJUnitReportAllowedFailure(t *testing.T) {
+	status := ExecutionStatus{
+		State: StateSuccess,
+		Results: []ExecutionResult{
+			{Command: config.Command{Name: "build"}, Success: true},
+			{Command: config.Command{Name: "lint"}, Success: false, ExitCode: 2, Error: "exit status 2", Output: "style issues\n", AllowedFailure: true},
+		},
+	}
+
+	report := NewJUnitReport("queue.json", nil, status)
+	if report.Failures != 0 || report.Suites[0].Failures != 0 {
+		t.Errorf("Expected an allowed failure not to be counted as a failure, got %d", report.Failures)
+	}
+
+	lint := report.Suites[0].TestCases[1]
+	if lint.Failure != nil {
+		t.Errorf("Expected no failure element for an allowed failure, got %+v", lint.Failure)
+	}
+	if lint.SystemOut != "Failed with exit code 2, allowed by allowFailure: exit status 2\nstyle issues\n" {
+		t.Errorf("Expected a note ahead of the output, got %q", lint.SystemOut)
+	}
+}
@@ -0,0 +1,133 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+// crashingQueue starts a keepAlive that exits with code 3 shortly after starting, followed by a
+// once command that outlives it
+func crashingQueue(onceDuration string) *config.Config {
+	return &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "server", Command: "sh", Args: []string{"-c", "sleep 0.2; exit 3"}, Mode: config.ModeKeepAlive},
+			{Name: "tests", Command: "sleep", Args: []string{onceDuration}, Mode: config.ModeOnce},
+		},
+	}
+}
+
+func TestFailOnKeepAliveExitCancelsRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sh")
+	}
+
+	executor := NewExecutorWithOptions(ExecutorOptions{
+		Reporter:            NewConsoleReporter(&bytes.Buffer{}, false),
+		FailOnKeepAliveExit: true,
+	})
+	defer executor.Stop()
+
+	start := time.Now()
+	err := executor.Execute(context.Background(), crashingQueue("10"))
+	elapsed := time.Since(start)
+
+	var exitErr *KeepAliveExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("Expected a KeepAliveExitError, got %v", err)
+	}
+	if exitErr.CommandName != "server" || exitErr.ExitCode != 3 {
+		t.Errorf("Expected server to be reported with exit code 3, got %+v", exitErr)
+	}
+	if elapsed > 5*time.Second {
+		t.Errorf("Expected the remaining command to be cancelled, run took %v", elapsed)
+	}
+
+	status := executor.GetStatus()
+	if status.State != StateFailed || status.LastError != exitErr.Error() {
+		t.Errorf("Expected failed state with the keepAlive error, got %s: %q", status.State, status.LastError)
+	}
+}
+
+func TestKeepAliveExitDoesNotFailRunByDefault(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sh")
+	}
+
+	executor := NewExecutorWithOptions(ExecutorOptions{Reporter: NewConsoleReporter(&bytes.Buffer{}, false)})
+	defer executor.Stop()
+
+	if err := executor.Execute(context.Background(), crashingQueue("0.6")); err != nil {
+		t.Fatalf("Expected the run to succeed without FailOnKeepAliveExit, got %v", err)
+	}
+	if state := executor.GetStatus().State; state != StateSuccess {
+		t.Errorf("Expected success state, got %s", state)
+	}
+}
+
+func TestWaitReturnsAfterKeepAliveProcessesExit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires the sleep command")
+	}
+
+	executor := NewExecutorWithOptions(ExecutorOptions{Reporter: NewConsoleReporter(&bytes.Buffer{}, false)})
+	defer executor.Stop()
+
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "short", Command: "sleep", Args: []string{"0.2"}, Mode: config.ModeKeepAlive},
+			{Name: "longer", Command: "sleep", Args: []string{"0.5"}, Mode: config.ModeKeepAlive},
+		},
+	}
+
+	start := time.Now()
+	if err := executor.Execute(context.Background(), cfg); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := executor.Wait(ctx); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < 500*time.Millisecond {
+		t.Errorf("Wait returned after %v, before the longer process could have exited", elapsed)
+	}
+	if executor.HasActiveKeepAliveProcesses() {
+		t.Error("Expected no keepAlive processes once Wait returned")
+	}
+}
+
+func TestWaitStopsWhenContextIsCancelled(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires the sleep command")
+	}
+
+	executor := NewExecutorWithOptions(ExecutorOptions{Reporter: NewConsoleReporter(&bytes.Buffer{}, false)})
+	defer executor.Stop()
+
+	cfg := &config.Config{
+		Version:  "1.0",
+		Commands: []config.Command{{Name: "server", Command: "sleep", Args: []string{"30"}, Mode: config.ModeKeepAlive}},
+	}
+	if err := executor.Execute(context.Background(), cfg); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := executor.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected Wait to give up with the context's error, got %v", err)
+	}
+	if !executor.HasActiveKeepAliveProcesses() {
+		t.Error("Expected the keepAlive process to keep running after Wait gave up")
+	}
+}
@@ -0,0 +1,75 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestKeepAliveStopsAtMaxLifetime(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires the sleep command")
+	}
+
+	var out bytes.Buffer
+	executor := NewExecutorWithOptions(ExecutorOptions{Reporter: NewConsoleReporter(&out, false)})
+	defer executor.Stop()
+
+	const lifetime = 300 * time.Millisecond
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{
+				Name:        "ephemeral",
+				Command:     "sleep",
+				Args:        []string{"30"},
+				Mode:        config.ModeKeepAlive,
+				MaxLifetime: lifetime,
+			},
+		},
+	}
+
+	start := time.Now()
+	if err := executor.Execute(context.Background(), cfg); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if !executor.HasActiveKeepAliveProcesses() {
+		t.Fatal("Expected the keepAlive process to be running before its lifetime elapses")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := executor.WaitForAutoStops(ctx); err != nil {
+		t.Fatalf("Timed out waiting for the auto-stop: %v", err)
+	}
+
+	elapsed := time.Since(start)
+	if elapsed < lifetime {
+		t.Errorf("Process was stopped after %v, before its maxLifetime of %v", elapsed, lifetime)
+	}
+	if elapsed > lifetime+5*time.Second {
+		t.Errorf("Process was stopped after %v, long after its maxLifetime of %v", elapsed, lifetime)
+	}
+
+	if executor.HasActiveKeepAliveProcesses() {
+		t.Error("Expected no keepAlive processes after the auto-stop")
+	}
+	if !strings.Contains(out.String(), "ephemeral stopped after reaching its maxLifetime of 300ms") {
+		t.Errorf("Expected the auto-stop to be reported, got: %s", out.String())
+	}
+}
+
+func TestWaitForAutoStopsReturnsWithoutLifetimes(t *testing.T) {
+	executor := NewExecutor(false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := executor.WaitForAutoStops(ctx); err != nil {
+		t.Errorf("Expected WaitForAutoStops to return immediately, got %v", err)
+	}
+}
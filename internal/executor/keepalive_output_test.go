@@ -0,0 +1,125 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+// waitForFile polls until path exists or the timeout elapses
+func waitForFile(path string, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return false
+}
+
+func TestKeepAliveWithHeavyOutputDoesNotStall(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sh")
+	}
+
+	marker := filepath.Join(t.TempDir(), "done")
+
+	// Far more output than an OS pipe buffer holds; the marker is only written if nothing blocks
+	script := "yes seqr-output-line | head -c 8000000 && touch " + marker + " && sleep 30"
+
+	var out bytes.Buffer
+	executor := NewExecutorWithOptions(ExecutorOptions{Reporter: NewConsoleReporter(&out, false)})
+	defer executor.Stop()
+
+	cfg := &config.Config{
+		Version:  "1.0",
+		Commands: []config.Command{{Name: "chatty", Command: "sh", Args: []string{"-c", script}, Mode: config.ModeKeepAlive}},
+	}
+	if err := executor.Execute(context.Background(), cfg); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if !waitForFile(marker, 10*time.Second) {
+		t.Fatal("Expected the keepAlive process to write all of its output without stalling")
+	}
+	if !executor.HasActiveKeepAliveProcesses() {
+		t.Error("Expected the keepAlive process to still be running")
+	}
+}
+
+func TestKeepAliveKeepsRunningAfterDetachFromStreaming(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sh")
+	}
+
+	marker := filepath.Join(t.TempDir(), "done")
+
+	// One line is streamed, then the bulk of the output is written after the detach
+	script := "echo started && sleep 0.3 && yes seqr-output-line | head -c 2000000 && touch " + marker + " && sleep 30"
+
+	var out bytes.Buffer
+	executor := NewExecutorWithOptions(ExecutorOptions{Verbose: true, Reporter: NewConsoleReporter(&out, false)})
+	defer executor.Stop()
+
+	cfg := &config.Config{
+		Version:  "1.0",
+		Commands: []config.Command{{Name: "chatty", Command: "sh", Args: []string{"-c", script}, Mode: config.ModeKeepAlive}},
+	}
+	if err := executor.Execute(context.Background(), cfg); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	executor.DetachFromStreaming()
+
+	// Without anyone displaying the output the process must neither block nor die of SIGPIPE
+	if !waitForFile(marker, 10*time.Second) {
+		t.Fatal("Expected the detached keepAlive process to keep writing output")
+	}
+	if !executor.HasActiveKeepAliveProcesses() {
+		t.Error("Expected the detached keepAlive process to still be running")
+	}
+}
+
+func TestStopWaitsForBufferedStreamingOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sh")
+	}
+
+	// Streamed lines are also written to the background log, which lives under the home directory
+	t.Setenv("HOME", t.TempDir())
+	marker := filepath.Join(t.TempDir(), "done")
+
+	// More output than the pipe buffer holds, so plenty is still unread when the marker appears
+	const lines = 3000
+	script := "seq -f 'line-%g-" + strings.Repeat("x", 60) + "' 1 " + strconv.Itoa(lines) + " && touch " + marker + " && sleep 30"
+
+	executor := NewExecutorWithOptions(ExecutorOptions{Verbose: true, Reporter: NewConsoleReporter(&bytes.Buffer{}, false)})
+	cfg := &config.Config{
+		Version:  "1.0",
+		Commands: []config.Command{{Name: "flush", Command: "sh", Args: []string{"-c", script}, Mode: config.ModeKeepAlive}},
+	}
+	if err := executor.Execute(context.Background(), cfg); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if !waitForFile(marker, 10*time.Second) {
+		t.Fatal("Timed out waiting for the process to write its output")
+	}
+	executor.Stop()
+
+	data, err := os.ReadFile(executor.logger.GetLogFile("flush"))
+	if err != nil {
+		t.Fatalf("Failed to read the process log: %v", err)
+	}
+	if got := strings.Count(string(data), "line-"); got != lines {
+		t.Errorf("Expected all %d lines to be streamed by the time Stop returns, got %d", lines, got)
+	}
+}
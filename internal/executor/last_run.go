@@ -0,0 +1,135 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+// lastRunFileName is the name of the file holding the most recent run's final status
+const lastRunFileName = "seqr-last-run.json"
+
+// LastRunFile returns the path of the last-run status file within a state directory
+func LastRunFile(stateDir string) string {
+	return filepath.Join(stateDir, lastRunFileName)
+}
+
+// LastRun is the record of the most recent run kept in the state directory, for --last and
+// --retry-failed
+type LastRun struct {
+	ExecutionStatus
+	ConfigPath string `json:"configPath,omitempty"` // Configuration the run loaded, an absolute path or a URL
+}
+
+// SaveLastRun persists the run's final status to the state directory. Only what --last and
+// --retry-failed need is kept: captured output, environment, arguments and the rest of each
+// command's configuration are left out, and the file is readable by its owner only.
+func SaveLastRun(stateDir string, run LastRun) error {
+	data, err := json.MarshalIndent(sanitizeLastRun(run), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal run status: %w", err)
+	}
+
+	filePath := LastRunFile(stateDir)
+
+	// Write to a temporary file first, then rename for atomic operation
+	tempFile := filePath + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0600); err != nil {
+		return fmt.Errorf("failed to write run status file: %w", err)
+	}
+
+	if err := os.Rename(tempFile, filePath); err != nil {
+		os.Remove(tempFile) // Clean up temp file on error
+		return fmt.Errorf("failed to rename run status file: %w", err)
+	}
+
+	return nil
+}
+
+// LoadLastRun reads the most recently persisted run from the state directory
+func LoadLastRun(stateDir string) (*LastRun, error) {
+	data, err := os.ReadFile(LastRunFile(stateDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no previous run recorded in %s", stateDir)
+		}
+		return nil, fmt.Errorf("failed to read run status file: %w", err)
+	}
+
+	var run LastRun
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal run status: %w", err)
+	}
+
+	return &run, nil
+}
+
+// sanitizeLastRun keeps the parts of a run worth persisting: each command's name, mode and
+// outcome, and which commands never started
+func sanitizeLastRun(run LastRun) LastRun {
+	status := run.ExecutionStatus
+	sanitized := LastRun{
+		ExecutionStatus: ExecutionStatus{
+			RunID:          status.RunID,
+			State:          status.State,
+			CompletedCount: status.CompletedCount,
+			TotalCount:     status.TotalCount,
+			Results:        make([]ExecutionResult, 0, len(status.Results)),
+			LastError:      status.LastError,
+			Skipped:        status.Skipped,
+		},
+		ConfigPath: run.ConfigPath,
+	}
+
+	for _, result := range status.Results {
+		sanitized.Results = append(sanitized.Results, ExecutionResult{
+			Command: config.Command{
+				Name:   result.Command.Name,
+				Mode:   result.Command.Mode,
+				Warmup: result.Command.Warmup,
+			},
+			Success:        result.Success,
+			ExitCode:       result.ExitCode,
+			Error:          result.Error,
+			StartTime:      result.StartTime,
+			EndTime:        result.EndTime,
+			Duration:       result.Duration,
+			Cached:         result.Cached,
+			ExitReason:     result.ExitReason,
+			Signal:         result.Signal,
+			AllowedFailure: result.AllowedFailure,
+			QueuedAt:       result.QueuedAt,
+			StartedAt:      result.StartedAt,
+			WaitDuration:   result.WaitDuration,
+			RunDuration:    result.RunDuration,
+		})
+	}
+
+	return sanitized
+}
+
+// GetStateDir returns the directory where the executor persists tracking and run state
+func (e *Executor) GetStateDir() string {
+	return e.tracker.StateDir()
+}
+
+// recordSkipped notes the queued commands that have no result, since they never started
+func (e *Executor) recordSkipped(commands []config.Command) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	started := make(map[string]bool, len(e.status.Results))
+	for _, result := range e.status.Results {
+		started[result.Command.Name] = true
+	}
+
+	e.status.Skipped = nil
+	for _, cmd := range commands {
+		if !started[cmd.Name] {
+			e.status.Skipped = append(e.status.Skipped, cmd.Name)
+		}
+	}
+}
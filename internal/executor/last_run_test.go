@@ -0,0 +1,84 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestSaveLastRun(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	executor := NewExecutor(false)
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "ok", Command: "echo", Args: []string{"hi"}, Mode: config.ModeOnce, Env: map[string]string{"API_TOKEN": "hunter2"}},
+			{Name: "bad", Command: "false", Mode: config.ModeOnce},
+			{Name: "never", Command: "echo", Args: []string{"unreached"}, Mode: config.ModeOnce},
+		},
+	}
+
+	captureOutput(func() {
+		if err := executor.Execute(context.Background(), cfg); err == nil {
+			t.Error("Expected execution to fail")
+		}
+	})
+
+	// Persisting the run is up to the caller
+	if _, err := os.Stat(LastRunFile(executor.GetStateDir())); !os.IsNotExist(err) {
+		t.Fatalf("Expected Execute not to persist the run itself, got %v", err)
+	}
+
+	run := LastRun{ExecutionStatus: executor.GetStatus(), ConfigPath: "/work/queue.json"}
+	if err := SaveLastRun(executor.GetStateDir(), run); err != nil {
+		t.Fatalf("Failed to save last run: %v", err)
+	}
+
+	info, err := os.Stat(LastRunFile(executor.GetStateDir()))
+	if err != nil {
+		t.Fatalf("Failed to stat last run file: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("Expected the last run file to be readable by its owner only, got %v", perm)
+	}
+	data, err := os.ReadFile(LastRunFile(executor.GetStateDir()))
+	if err != nil {
+		t.Fatalf("Failed to read last run file: %v", err)
+	}
+	for _, leaked := range []string{"hunter2", "API_TOKEN", "hi\n", "unreached"} {
+		if strings.Contains(string(data), leaked) {
+			t.Errorf("Expected the last run file not to contain %q, got:\n%s", leaked, data)
+		}
+	}
+
+	status, err := LoadLastRun(executor.GetStateDir())
+	if err != nil {
+		t.Fatalf("Failed to load last run: %v", err)
+	}
+
+	if status.ConfigPath != "/work/queue.json" {
+		t.Errorf("Expected the config path to be recorded, got %q", status.ConfigPath)
+	}
+	if status.State != StateFailed {
+		t.Errorf("Expected persisted state %v, got %v", StateFailed, status.State)
+	}
+	if len(status.Results) != 2 {
+		t.Fatalf("Expected 2 persisted results, got %d", len(status.Results))
+	}
+	if status.Results[0].Command.Name != "ok" || !status.Results[0].Success {
+		t.Errorf("Expected first result to be successful 'ok', got %+v", status.Results[0])
+	}
+	if status.Results[0].Output != "" || status.Results[0].Command.Env != nil || status.Results[0].Command.Args != nil {
+		t.Errorf("Expected output, env and args to be left out, got %+v", status.Results[0])
+	}
+	if status.Results[1].Command.Name != "bad" || status.Results[1].Success || status.Results[1].ExitCode != 1 {
+		t.Errorf("Expected second result to be failed 'bad', got %+v", status.Results[1])
+	}
+	if len(status.Skipped) != 1 || status.Skipped[0] != "never" {
+		t.Errorf("Expected the command after the failure to be recorded as skipped, got %v", status.Skipped)
+	}
+}
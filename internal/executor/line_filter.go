@@ -0,0 +1,119 @@
+package executor
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+// lineFilter decides which lines of a command's streamed output are shown, from its showLines
+// and hideLines patterns. A nil filter shows every line.
+type lineFilter struct {
+	show *regexp.Regexp // Only matching lines are shown; nil shows all
+	hide *regexp.Regexp // Matching lines are not shown; nil hides none
+}
+
+// newLineFilter compiles a command's showLines and hideLines, or returns nil when it sets
+// neither. Validation rejects invalid patterns; one that slips through is ignored.
+func newLineFilter(cmd config.Command) *lineFilter {
+	if cmd.ShowLines == "" && cmd.HideLines == "" {
+		return nil
+	}
+
+	filter := &lineFilter{}
+	if cmd.ShowLines != "" {
+		filter.show, _ = regexp.Compile(cmd.ShowLines)
+	}
+	if cmd.HideLines != "" {
+		filter.hide, _ = regexp.Compile(cmd.HideLines)
+	}
+	return filter
+}
+
+// shows reports whether line passes the filter
+func (f *lineFilter) shows(line string) bool {
+	if f == nil {
+		return true
+	}
+	if f.show != nil && !f.show.MatchString(line) {
+		return false
+	}
+	return f.hide == nil || !f.hide.MatchString(line)
+}
+
+// filterCaptured drops the lines of a command's captured output that the filter does not show,
+// if the command asks for that with filterCapturedLines. A final newline is kept when any line
+// is, rather than being filtered as an empty line.
+func filterCaptured(cmd config.Command, output string) string {
+	filter := newLineFilter(cmd)
+	if filter == nil || !cmd.FilterCapturedLines || output == "" {
+		return output
+	}
+
+	body, terminated := strings.CutSuffix(output, "\n")
+	var kept []string
+	for _, line := range strings.Split(body, "\n") {
+		if filter.shows(strings.TrimSuffix(line, "\r")) {
+			kept = append(kept, line)
+		}
+	}
+	if len(kept) > 0 && terminated {
+		return strings.Join(kept, "\n") + "\n"
+	}
+	return strings.Join(kept, "\n")
+}
+
+// filteredWriter passes the lines written to it that a filter shows on to w, holding back the
+// end of a line until its newline arrives. Close passes on a last line without one.
+type filteredWriter struct {
+	w       io.Writer
+	lines   *lineFilter
+	partial []byte
+}
+
+// newFilteredWriter wraps w so it only receives the lines lines shows, passing everything on
+// when lines is nil
+func newFilteredWriter(w io.Writer, lines *lineFilter) io.WriteCloser {
+	if lines == nil {
+		return nopWriteCloser{w}
+	}
+	return &filteredWriter{w: w, lines: lines}
+}
+
+func (f *filteredWriter) Write(p []byte) (int, error) {
+	f.partial = append(f.partial, p...)
+	for {
+		end := bytes.IndexByte(f.partial, '\n')
+		if end < 0 {
+			break
+		}
+		f.writeLine(f.partial[:end+1])
+		f.partial = f.partial[end+1:]
+	}
+	return len(p), nil
+}
+
+// Close writes out a last line the output did not end with a newline
+func (f *filteredWriter) Close() error {
+	if len(f.partial) > 0 {
+		f.writeLine(f.partial)
+		f.partial = nil
+	}
+	return nil
+}
+
+func (f *filteredWriter) writeLine(line []byte) {
+	if f.lines.shows(strings.TrimRight(string(line), "\r\n")) {
+		f.w.Write(line)
+	}
+}
+
+// nopWriteCloser adds a Close that does nothing to a writer
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,155 @@
+package executor
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestStreamOutput_LineFilter(t *testing.T) {
+	input := "INFO starting\nWARN disk almost full\nINFO healthcheck ok\nERROR healthcheck failed\nERROR request failed\n"
+
+	tests := []struct {
+		name      string
+		showLines string
+		hideLines string
+		want      []string
+	}{
+		{
+			name:      "show",
+			showLines: `^(WARN|ERROR)`,
+			want:      []string{"WARN disk almost full", "ERROR healthcheck failed", "ERROR request failed"},
+		},
+		{
+			name:      "hide",
+			hideLines: "healthcheck",
+			want:      []string{"INFO starting", "WARN disk almost full", "ERROR request failed"},
+		},
+		{
+			name:      "show and hide",
+			showLines: "^ERROR",
+			hideLines: "healthcheck",
+			want:      []string{"ERROR request failed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := NewExecutorWithOptions(ExecutorOptions{Verbose: true, NoTimestamps: true})
+			filter := newLineFilter(config.Command{ShowLines: tt.showLines, HideLines: tt.hideLines})
+
+			var outputBuilder strings.Builder
+			out := captureOutput(func() {
+				executor.streamOutput(&testReadCloser{strings.NewReader(input)}, &outputBuilder, "api", "stdout", "./server", nil, filter, false)
+			})
+
+			lines := strings.Split(strings.TrimSpace(out), "\n")
+			if len(lines) != len(tt.want) {
+				t.Fatalf("Expected %d streamed lines, got %d:\n%s", len(tt.want), len(lines), out)
+			}
+			for i, want := range tt.want {
+				if !strings.HasSuffix(lines[i], " "+want) {
+					t.Errorf("Expected line %d to show %q, got %q", i, want, lines[i])
+				}
+			}
+
+			if outputBuilder.String() != input {
+				t.Errorf("Expected every line to be captured, got %q", outputBuilder.String())
+			}
+		})
+	}
+}
+
+func TestStreamOutputContinuous_LineFilter(t *testing.T) {
+	executor := NewExecutorWithOptions(ExecutorOptions{Verbose: true, NoTimestamps: true})
+	filter := newLineFilter(config.Command{HideLines: `^GET /health`})
+
+	input := "listening on :8080\nGET /health 200\nGET /users 200\nGET /health 200\n"
+	out := captureOutput(func() {
+		executor.streamOutputContinuousWithContext(context.Background(), &testReadCloser{strings.NewReader(input)}, "api", "stdout", "./server", nil, nil, filter, false)
+	})
+
+	if strings.Contains(out, "/health") {
+		t.Errorf("Expected health checks to be hidden, got:\n%s", out)
+	}
+	for _, want := range []string{"listening on :8080", "GET /users 200"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q to be shown, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestExecutor_FilterCapturedLines(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sh")
+	}
+	t.Setenv("TMPDIR", t.TempDir())
+
+	script := "echo 'ok 1 parse'; echo 'not ok 2 render'; echo 'ok 3 save'"
+	for _, filterCaptured := range []bool{false, true} {
+		cfg := &config.Config{
+			Version: "1.0",
+			Commands: []config.Command{
+				{Name: "test", Command: "sh", Args: []string{"-c", script}, Mode: config.ModeOnce, ShowLines: "^not ok", FilterCapturedLines: filterCaptured},
+			},
+		}
+
+		executor := NewExecutor(false)
+		var err error
+		captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+		if err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+
+		want := "ok 1 parse\nnot ok 2 render\nok 3 save"
+		if filterCaptured {
+			want = "not ok 2 render"
+		}
+		if got := executor.GetStatus().Results[0].Output; got != want {
+			t.Errorf("With filterCapturedLines %v, expected captured output %q, got %q", filterCaptured, want, got)
+		}
+	}
+}
+
+func TestFilterCaptured(t *testing.T) {
+	tests := []struct {
+		name      string
+		showLines string
+		hideLines string
+		output    string
+		want      string
+	}{
+		{name: "show", showLines: "^not ok", output: "ok 1\nnot ok 2\n", want: "not ok 2\n"},
+		{name: "hide", hideLines: "^ok", output: "ok 1\nnot ok 2\n", want: "not ok 2\n"},
+		{name: "no final newline", showLines: "^not ok", output: "ok 1\nnot ok 2", want: "not ok 2"},
+		{name: "carriage returns", showLines: "2$", output: "ok 1\r\nnot ok 2\r\n", want: "not ok 2\r\n"},
+		{name: "nothing shown", showLines: "^not ok", output: "ok 1\n", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := config.Command{ShowLines: tt.showLines, HideLines: tt.hideLines, FilterCapturedLines: true}
+			if got := filterCaptured(cmd, tt.output); got != tt.want {
+				t.Errorf("filterCaptured(%q) = %q, want %q", tt.output, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilteredWriter(t *testing.T) {
+	var out strings.Builder
+	w := newFilteredWriter(&out, newLineFilter(config.Command{HideLines: "^debug"}))
+
+	// Lines arrive split across writes, and the last one has no newline
+	for _, chunk := range []string{"comp", "iling\ndebug: cache", " hit\ndo", "ne"} {
+		w.Write([]byte(chunk))
+	}
+	w.Close()
+
+	if got := out.String(); got != "compiling\ndone" {
+		t.Errorf("Expected only the shown lines, got %q", got)
+	}
+}
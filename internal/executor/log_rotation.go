@@ -0,0 +1,83 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+// logRotation tracks the size of a process log that is rotated once it reaches maxSize
+type logRotation struct {
+	maxSize  int64
+	maxFiles int
+	size     int64 // Bytes in the current log; -1 until it is first checked
+}
+
+// SetLogRotation rotates a process log to name.log.1, name.log.2 and so on once writing to it
+// would take it past maxSize bytes, keeping at most maxFiles rotated files. A zero maxSize lets the
+// log grow without bound; a zero maxFiles keeps config.DefaultLogMaxFiles.
+func (bl *BackgroundLogger) SetLogRotation(processName string, maxSize int64, maxFiles int) {
+	bl.mu.Lock()
+	defer bl.mu.Unlock()
+
+	if maxSize <= 0 {
+		delete(bl.rotations, processName)
+		return
+	}
+	if maxFiles <= 0 {
+		maxFiles = config.DefaultLogMaxFiles
+	}
+	if bl.rotations == nil {
+		bl.rotations = make(map[string]*logRotation)
+	}
+	bl.rotations[processName] = &logRotation{maxSize: maxSize, maxFiles: maxFiles, size: -1}
+}
+
+// GetRotatedLogFile returns the path of a process log's nth rotated file, 1 being the newest
+func (bl *BackgroundLogger) GetRotatedLogFile(processName string, n int) string {
+	return fmt.Sprintf("%s.%d", bl.GetLogFile(processName), n)
+}
+
+// prepareWrite rotates a process log when writing n more bytes would take it past its maximum
+// size, then counts them. Called with bl.mu held.
+func (bl *BackgroundLogger) prepareWrite(processName string, rotation *logRotation, n int64) error {
+	if rotation.size < 0 {
+		rotation.size = 0
+		if info, err := os.Stat(bl.GetLogFile(processName)); err == nil {
+			rotation.size = info.Size()
+		}
+	}
+
+	// A single write larger than the limit still goes to a file of its own
+	if rotation.size > 0 && rotation.size+n > rotation.maxSize {
+		if err := bl.rotate(processName, rotation.maxFiles); err != nil {
+			return err
+		}
+		rotation.size = 0
+	}
+
+	rotation.size += n
+	return nil
+}
+
+// rotate shifts each rotated file of a process log up by one, dropping the oldest beyond maxFiles,
+// and moves the current log to name.log.1
+func (bl *BackgroundLogger) rotate(processName string, maxFiles int) error {
+	if err := os.Remove(bl.GetRotatedLogFile(processName, maxFiles)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove oldest log of %s: %w", processName, err)
+	}
+
+	for i := maxFiles - 1; i >= 0; i-- {
+		from := bl.GetRotatedLogFile(processName, i)
+		if i == 0 {
+			from = bl.GetLogFile(processName)
+		}
+		if err := os.Rename(from, bl.GetRotatedLogFile(processName, i+1)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to rotate log of %s: %w", processName, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestBackgroundLogger_LogRotation(t *testing.T) {
+	logger := &BackgroundLogger{logDir: t.TempDir()}
+	logger.SetLogRotation("api", 1024, 2)
+
+	// About 60 bytes per entry with the timestamp, so several rotations' worth
+	for i := 0; i < 100; i++ {
+		if err := logger.WriteLog("api", fmt.Sprintf("request %03d handled in 12ms", i)); err != nil {
+			t.Fatalf("WriteLog returned error: %v", err)
+		}
+	}
+
+	for _, path := range []string{logger.GetLogFile("api"), logger.GetRotatedLogFile("api", 1), logger.GetRotatedLogFile("api", 2)} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("Expected %s to be kept: %v", filepath.Base(path), err)
+		}
+		if info.Size() > 1024 {
+			t.Errorf("Expected %s to stay within 1024 bytes, got %d", filepath.Base(path), info.Size())
+		}
+	}
+	if _, err := os.Stat(logger.GetRotatedLogFile("api", 3)); !os.IsNotExist(err) {
+		t.Errorf("Expected at most 2 rotated files, found api.log.3 (%v)", err)
+	}
+
+	// The newest output is in the current log, and rotated files hold older output in order
+	current, _ := os.ReadFile(logger.GetLogFile("api"))
+	newest, _ := os.ReadFile(logger.GetRotatedLogFile("api", 1))
+	older, _ := os.ReadFile(logger.GetRotatedLogFile("api", 2))
+	if !strings.HasSuffix(string(current), "request 099 handled in 12ms\n") {
+		t.Errorf("Expected the current log to end with the last entry, got:\n%s", current)
+	}
+	if lastEntry(t, older) >= firstEntry(t, newest) || lastEntry(t, newest) >= firstEntry(t, current) {
+		t.Errorf("Expected api.log.2, api.log.1 and api.log to hold successively newer output")
+	}
+
+	// Without rotation the log grows past the limit
+	logger.SetLogRotation("api", 0, 0)
+	for i := 0; i < 50; i++ {
+		logger.WriteLog("api", "unbounded")
+	}
+	if info, _ := os.Stat(logger.GetLogFile("api")); info.Size() <= 1024 {
+		t.Errorf("Expected the log to grow without bound once rotation is off, got %d bytes", info.Size())
+	}
+}
+
+// firstEntry and lastEntry return the request number of a log's first and last entries
+func firstEntry(t *testing.T, log []byte) string {
+	t.Helper()
+	lines := strings.Split(strings.TrimSpace(string(log)), "\n")
+	return entryNumber(t, lines[0])
+}
+
+func lastEntry(t *testing.T, log []byte) string {
+	t.Helper()
+	lines := strings.Split(strings.TrimSpace(string(log)), "\n")
+	return entryNumber(t, lines[len(lines)-1])
+}
+
+func entryNumber(t *testing.T, line string) string {
+	t.Helper()
+	_, after, found := strings.Cut(line, "request ")
+	if !found || len(after) < 3 {
+		t.Fatalf("Unexpected log line %q", line)
+	}
+	return after[:3]
+}
+
+func TestExecutor_LogRotation(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sh")
+	}
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("TMPDIR", t.TempDir())
+
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{
+				Name:        "chatty",
+				Command:     "sh",
+				Args:        []string{"-c", `i=0; while [ $i -lt 200 ]; do echo "line $i"; i=$((i+1)); done`},
+				Mode:        config.ModeOnce,
+				LogMaxSize:  2048,
+				LogMaxFiles: 3,
+			},
+		},
+	}
+
+	executor := NewExecutor(true)
+	captureOutput(func() {
+		if err := executor.Execute(context.Background(), cfg); err != nil {
+			t.Fatalf("Execute returned error: %v", err)
+		}
+	})
+
+	logger := executor.logger
+	for n := 1; n <= 3; n++ {
+		if _, err := os.Stat(logger.GetRotatedLogFile("chatty", n)); err != nil {
+			t.Errorf("Expected rotated log %d to be kept: %v", n, err)
+		}
+	}
+	if _, err := os.Stat(logger.GetRotatedLogFile("chatty", 4)); !os.IsNotExist(err) {
+		t.Errorf("Expected at most 3 rotated logs, found chatty.log.4 (%v)", err)
+	}
+	current, err := os.ReadFile(logger.GetLogFile("chatty"))
+	if err != nil {
+		t.Fatalf("Failed to read current log: %v", err)
+	}
+	if len(current) > 2048 || !strings.Contains(string(current), "line 199") {
+		t.Errorf("Expected the current log to hold the latest output within 2048 bytes, got %d bytes:\n%s", len(current), current)
+	}
+}
+
+func TestBackgroundLogger_LogFilePattern(t *testing.T) {
+	dir := t.TempDir()
+	logger := &BackgroundLogger{logDir: t.TempDir()}
+	logger.SetLogFile(filepath.Join(dir, "${RUN_ID}", "${NAME}.log"), config.RunVariables("run-1", time.Now()))
+
+	if err := logger.WriteLog("api", "listening"); err != nil {
+		t.Fatalf("WriteLog returned error: %v", err)
+	}
+
+	want := filepath.Join(dir, "run-1", "api.log")
+	if got := logger.GetLogFile("api"); got != want {
+		t.Errorf("Expected the log at %s, got %s", want, got)
+	}
+	if data, err := os.ReadFile(want); err != nil || !strings.Contains(string(data), "listening") {
+		t.Errorf("Expected the entry to be written to %s, got %q (%v)", want, data, err)
+	}
+}
@@ -0,0 +1,126 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// DefaultLogPollInterval is how often followed log files are checked for new output
+const DefaultLogPollInterval = 250 * time.Millisecond
+
+// followedLog tracks how far a single process log has been read
+type followedLog struct {
+	name    string
+	path    string
+	offset  int64
+	partial []byte // Trailing output not yet terminated by a newline
+}
+
+// FollowLogs multiplexes the background logs of the named processes into w, prefixing each line
+// with the process name, until ctx is cancelled. The last historyLines lines of each log are
+// written first, then new output is streamed as it is appended, like `tail -f`.
+func (bl *BackgroundLogger) FollowLogs(ctx context.Context, w io.Writer, names []string, historyLines int, pollInterval time.Duration) error {
+	if len(names) == 0 {
+		return fmt.Errorf("no processes to follow")
+	}
+	if pollInterval <= 0 {
+		pollInterval = DefaultLogPollInterval
+	}
+
+	logs := make([]*followedLog, 0, len(names))
+	for _, name := range names {
+		log := &followedLog{name: name, path: bl.GetLogFile(name)}
+
+		// Start following from the current end of the file, after replaying recent history
+		if info, err := os.Stat(log.path); err == nil {
+			log.offset = info.Size()
+			if historyLines > 0 {
+				recent, err := bl.ReadRecentLogs(name, historyLines)
+				if err == nil {
+					for _, line := range recent {
+						writeFollowedLine(w, name, line)
+					}
+				}
+			}
+		}
+
+		logs = append(logs, log)
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, log := range logs {
+			if err := log.readNew(w); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// readNew writes any complete lines appended to the log since the last read
+func (l *followedLog) readNew(w io.Writer) error {
+	f, err := os.Open(l.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// The process may not have written any output yet
+			return nil
+		}
+		return fmt.Errorf("failed to open log for %s: %w", l.name, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat log for %s: %w", l.name, err)
+	}
+
+	// The log was truncated or replaced, start again from the beginning
+	if info.Size() < l.offset {
+		l.offset = 0
+		l.partial = nil
+	}
+
+	if info.Size() == l.offset {
+		return nil
+	}
+
+	if _, err := f.Seek(l.offset, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek log for %s: %w", l.name, err)
+	}
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return fmt.Errorf("failed to read log for %s: %w", l.name, err)
+	}
+	l.offset += int64(len(data))
+
+	data = append(l.partial, data...)
+	for {
+		newline := bytes.IndexByte(data, '\n')
+		if newline < 0 {
+			break
+		}
+		writeFollowedLine(w, l.name, string(data[:newline]))
+		data = data[newline+1:]
+	}
+	l.partial = append([]byte(nil), data...)
+
+	return nil
+}
+
+// writeFollowedLine writes a log line prefixed with its process name
+func writeFollowedLine(w io.Writer, name, line string) {
+	fmt.Fprintf(w, "[%s] %s\n", colorize(name, colorCyan), line)
+}
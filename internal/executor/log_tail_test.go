@@ -0,0 +1,98 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes and reads
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// appendToFile appends raw text to a file, creating it if needed
+func appendToFile(t *testing.T, path, text string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", path, err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(text); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func TestBackgroundLogger_FollowLogs(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+
+	logger := &BackgroundLogger{logDir: t.TempDir()}
+	appendToFile(t, logger.GetLogFile("api"), "history line\n")
+
+	var out syncBuffer
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- logger.FollowLogs(ctx, &out, []string{"api", "worker"}, 10, 20*time.Millisecond)
+	}()
+
+	waitForOutput := func(expected string) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			if strings.Contains(out.String(), expected) {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("Timed out waiting for %q, got:\n%s", expected, out.String())
+	}
+
+	waitForOutput("[api] history line\n")
+
+	// The file grows while it is being followed
+	appendToFile(t, logger.GetLogFile("api"), "first new line\n")
+	waitForOutput("[api] first new line\n")
+
+	// A log that did not exist when following started is picked up once written,
+	// and a partially written line is held back until it is complete
+	appendToFile(t, logger.GetLogFile("worker"), "partial ")
+	time.Sleep(100 * time.Millisecond)
+	if strings.Contains(out.String(), "[worker] partial") {
+		t.Errorf("Expected incomplete line to be held back, got:\n%s", out.String())
+	}
+	appendToFile(t, logger.GetLogFile("worker"), "line done\n")
+	waitForOutput("[worker] partial line done\n")
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("FollowLogs returned error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("FollowLogs did not stop after cancellation")
+	}
+
+	if strings.Count(out.String(), "history line") != 1 {
+		t.Errorf("Expected history to be written exactly once, got:\n%s", out.String())
+	}
+}
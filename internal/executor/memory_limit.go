@@ -0,0 +1,65 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// MemoryLimitError reports a command that failed the way running out of memory under its
+// memoryLimit looks
+type MemoryLimitError struct {
+	CommandName string
+	Limit       int64 // The command's memoryLimit in bytes
+	Err         error
+}
+
+// Error implements the error interface
+func (e *MemoryLimitError) Error() string {
+	return fmt.Sprintf("command '%s' ran out of memory under its memoryLimit of %d bytes: %v", e.CommandName, e.Limit, e.Err)
+}
+
+// Unwrap returns the command's own error
+func (e *MemoryLimitError) Unwrap() error {
+	return e.Err
+}
+
+// outOfMemoryMarkers are what programs commonly print when an allocation fails, lowercased
+var outOfMemoryMarkers = []string{
+	"out of memory",
+	"cannot allocate memory",
+	"memoryerror",
+	"bad_alloc",
+	"allocation failed",
+}
+
+// allocationFailureSignals are the signals a program is typically terminated by when an allocation
+// fails: a crash on an allocation it did not check, or an abort from a runtime that does
+var allocationFailureSignals = map[string]bool{
+	"SIGSEGV": true,
+	"SIGABRT": true,
+	"SIGBUS":  true,
+}
+
+// exceededMemoryLimit reports whether a failed command looks like it ran out of memory: it was
+// terminated by a signal an allocation failure causes, or it exited reporting a failed allocation.
+// Other signals, such as the SIGTERM or SIGKILL seqr stops a command with, are not the limit. The
+// limit only makes allocations fail, so this is a best guess.
+func exceededMemoryLimit(err error, output string) bool {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	if signal := terminatingSignal(exitErr.ProcessState); signal != "" {
+		return allocationFailureSignals[signal]
+	}
+
+	output = strings.ToLower(output)
+	for _, marker := range outOfMemoryMarkers {
+		if strings.Contains(output, marker) {
+			return true
+		}
+	}
+	return false
+}
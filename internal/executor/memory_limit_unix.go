@@ -0,0 +1,29 @@
+//go:build !windows
+
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// limitMemory runs execCmd under a shell that lowers RLIMIT_AS to limit bytes with ulimit -v
+// and then execs the command, which keeps the PID and inherits the limit. os/exec cannot set
+// resource limits on the child itself.
+func limitMemory(execCmd *exec.Cmd, limit int64) error {
+	if runtime.GOOS == "darwin" {
+		return errors.New("memoryLimit is not supported on macOS, which does not enforce RLIMIT_AS")
+	}
+	if execCmd.Err != nil {
+		// The executable was not found; Start reports that
+		return nil
+	}
+
+	kib := (limit + 1023) / 1024
+	script := fmt.Sprintf(`ulimit -v %d && exec "$0" "$@"`, kib)
+	execCmd.Args = append([]string{"sh", "-c", script, execCmd.Path}, execCmd.Args[1:]...)
+	execCmd.Path = "/bin/sh"
+	return nil
+}
@@ -0,0 +1,93 @@
+//go:build !windows
+
+package executor
+
+import (
+	"context"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestExecutor_MemoryLimitKillsHungryCommand(t *testing.T) {
+	if runtime.GOOS == "darwin" {
+		t.Skip("macOS does not enforce RLIMIT_AS")
+	}
+	if _, err := exec.LookPath("awk"); err != nil {
+		t.Skip("Test requires awk")
+	}
+
+	const limit = 64 * 1024 * 1024
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			// Doubles a string until an allocation fails
+			{Name: "hungry", Command: "awk", Args: []string{`BEGIN { s = "x"; while (1) s = s s }`}, Mode: config.ModeOnce, MemoryLimit: limit},
+		},
+	}
+
+	executor := NewExecutor(false)
+	var err error
+	captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+	if err == nil {
+		t.Fatal("Expected the command to fail under its memory limit")
+	}
+
+	result := executor.GetStatus().Results[0]
+	if !strings.Contains(result.Error, "command 'hungry' ran out of memory under its memoryLimit of 67108864 bytes") {
+		t.Errorf("Expected a memory limit error, got %q (output %q)", result.Error, result.Output)
+	}
+	if result.ErrorDetail == nil || result.ErrorDetail.Type != ErrorTypeMemoryLimit {
+		t.Errorf("Expected error type %q, got %+v", ErrorTypeMemoryLimit, result.ErrorDetail)
+	}
+}
+
+func TestExecutor_MemoryLimitKeepsCommandAndArgs(t *testing.T) {
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "greet", Command: "echo", Args: []string{"hello", "$HOME", "two words"}, Mode: config.ModeOnce, MemoryLimit: 64 * 1024 * 1024},
+		},
+	}
+
+	executor := NewExecutor(false)
+	var err error
+	captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+	if err != nil {
+		t.Fatalf("Expected the command to run under its memory limit, got %v", err)
+	}
+
+	if result := executor.GetStatus().Results[0]; result.Output != "hello $HOME two words" {
+		t.Errorf("Expected the args to pass through unchanged, got %q", result.Output)
+	}
+}
+
+func TestExceededMemoryLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   string
+		expected bool
+	}{
+		{name: "crash on a failed allocation", script: "kill -SEGV $$", expected: true},
+		{name: "abort on a failed allocation", script: "kill -ABRT $$", expected: true},
+		{name: "stopped by seqr", script: "kill -TERM $$", expected: false},
+		{name: "killed", script: "kill -KILL $$", expected: false},
+		{name: "failed allocation reported", script: "echo 'fatal error: runtime: out of memory'; exit 2", expected: true},
+		{name: "ordinary failure", script: "echo 'test failed'; exit 1", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := exec.Command("sh", "-c", tt.script).CombinedOutput()
+			if err == nil {
+				t.Fatal("Expected the script to fail")
+			}
+			if got := exceededMemoryLimit(err, string(output)); got != tt.expected {
+				t.Errorf("exceededMemoryLimit() = %v, want %v (error %v)", got, tt.expected, err)
+			}
+		})
+	}
+}
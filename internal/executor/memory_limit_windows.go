@@ -0,0 +1,13 @@
+//go:build windows
+
+package executor
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// limitMemory is not supported on Windows, which has no RLIMIT_AS; the command runs unlimited
+func limitMemory(execCmd *exec.Cmd, limit int64) error {
+	return errors.New("memoryLimit is not supported on Windows")
+}
@@ -0,0 +1,68 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"maps"
+	"testing"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestExecutor_MetaReachesReports(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	buildMeta := map[string]string{"owner": "platform-team", "ticket": "OPS-1234"}
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "build", Command: "echo", Args: []string{"built"}, Mode: config.ModeOnce, Meta: buildMeta},
+			{Name: "check", Command: "false", Mode: config.ModeOnce},
+			{Name: "deploy", Command: "echo", Args: []string{"never"}, Mode: config.ModeOnce, Meta: map[string]string{"owner": "release-team"}},
+		},
+	}
+
+	executor := NewExecutor(false)
+	captureOutput(func() { executor.Execute(context.Background(), cfg) })
+
+	status := executor.GetStatus()
+	if len(status.Results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(status.Results))
+	}
+	if !maps.Equal(status.Results[0].Meta, buildMeta) {
+		t.Errorf("Expected the build result to carry its meta, got %v", status.Results[0].Meta)
+	}
+	if status.Results[1].Meta != nil {
+		t.Errorf("Expected no meta on a command without any, got %v", status.Results[1].Meta)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteJUnitReport(&buf, "queue.json", cfg.Commands, status); err != nil {
+		t.Fatalf("WriteJUnitReport failed: %v", err)
+	}
+	var report JUnitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("Report is not valid XML: %v\n%s", err, buf.String())
+	}
+
+	properties := map[string]map[string]string{}
+	for _, testCase := range report.Suites[0].TestCases {
+		if testCase.Properties == nil {
+			continue
+		}
+		properties[testCase.Name] = map[string]string{}
+		for _, property := range testCase.Properties.Properties {
+			properties[testCase.Name][property.Name] = property.Value
+		}
+	}
+	if !maps.Equal(properties["build"], buildMeta) {
+		t.Errorf("Expected the build testcase to list its meta as properties, got %v", properties["build"])
+	}
+	if properties["deploy"]["owner"] != "release-team" {
+		t.Errorf("Expected the skipped deploy testcase to list its meta too, got %v", properties["deploy"])
+	}
+	if _, found := properties["check"]; found {
+		t.Errorf("Expected no properties on a command without meta, got %v", properties["check"])
+	}
+}
@@ -0,0 +1,149 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// processNameLimit is the length Linux truncates process names to
+const processNameLimit = 15
+
+// orphanStopTimeout is how long an orphaned process is given to exit before it is killed
+const orphanStopTimeout = 5 * time.Second
+
+// FindOrphans returns the tracked processes that are still running although the seqr process
+// that started them has exited, such as one that crashed, sorted by PID. A process whose PID now
+// belongs to a different program, because the original exited and the PID was reused, is not
+// returned and is dropped from tracking instead.
+func (pm *ProcessManager) FindOrphans() ([]*ProcessInfo, error) {
+	processes, err := pm.GetAllRunningProcesses()
+	if err != nil {
+		return nil, err
+	}
+
+	var orphans []*ProcessInfo
+	for pid, info := range processes {
+		if ownerRunning(info) {
+			continue
+		}
+
+		name, err := processName(pid)
+		if err != nil {
+			// The process may have exited since it was checked, or cannot be inspected; without
+			// knowing what it is, it is neither reaped nor forgotten
+			continue
+		}
+		if !processNameMatches(name, info.Command) {
+			pm.tracker.RemoveProcess(pid)
+			continue
+		}
+		orphans = append(orphans, info)
+	}
+
+	sort.Slice(orphans, func(i, j int) bool { return orphans[i].PID < orphans[j].PID })
+	return orphans, nil
+}
+
+// AdoptProcess makes the current seqr process the owner of a tracked process, so it is not reported
+// as an orphan while this run lasts
+func (pm *ProcessManager) AdoptProcess(pid int) error {
+	return pm.tracker.SetOwner(pid, os.Getpid())
+}
+
+// TerminateOrphan stops an orphaned process along with the processes it started, killing them if
+// it has not exited within orphanStopTimeout, and stops tracking it
+func (pm *ProcessManager) TerminateOrphan(pid int) error {
+	for _, graceful := range []bool{true, false} {
+		if err := pm.signalOrphan(pid, graceful); err != nil {
+			return fmt.Errorf("failed to terminate process %d: %w", pid, err)
+		}
+		if waitForOrphanExit(pid, orphanStopTimeout) {
+			return pm.tracker.RemoveProcess(pid)
+		}
+	}
+	return fmt.Errorf("process %d did not exit after being killed", pid)
+}
+
+// signalOrphan asks an orphaned process and its process group to exit, or kills them. A process
+// started without a process group of its own is signalled alone.
+func (pm *ProcessManager) signalOrphan(pid int, graceful bool) error {
+	if err := pm.killProcessGroup(pid, graceful); err == nil {
+		return nil
+	}
+
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	if graceful && runtime.GOOS != "windows" {
+		return process.Signal(syscall.SIGTERM)
+	}
+	return process.Kill()
+}
+
+// waitForOrphanExit polls until a process no longer exists, reporting false if it still does
+// after timeout
+func waitForOrphanExit(pid int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for processExists(pid) {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return true
+}
+
+// ownerRunning reports whether the seqr process that started or adopted a tracked process is still
+// running. An owner whose PID now belongs to a process started at a different time has exited, and
+// the PID was reused.
+func ownerRunning(info *ProcessInfo) bool {
+	if info.OwnerPID == 0 {
+		return false
+	}
+	started, err := processStartTime(info.OwnerPID)
+	if err != nil {
+		return false
+	}
+	return info.OwnerStart == "" || started == info.OwnerStart
+}
+
+// currentProcessStart returns when the current seqr process started, as processStartTime reports
+// it, or an empty string if that is unknown
+var currentProcessStart = sync.OnceValue(func() string {
+	started, _ := processStartTime(os.Getpid())
+	return started
+})
+
+// processExists reports whether a process with the PID exists. Unlike isProcessRunning, it is
+// accurate on Windows too.
+func processExists(pid int) bool {
+	_, err := processName(pid)
+	return err == nil
+}
+
+// processNameMatches reports whether the name the system reports for a process is that of the
+// command seqr started it with
+func processNameMatches(name, command string) bool {
+	got := trimExecutableSuffix(filepath.Base(strings.TrimSpace(name)))
+	want := trimExecutableSuffix(filepath.Base(command))
+	if got == want {
+		return true
+	}
+	return len(got) == processNameLimit && strings.HasPrefix(want, got)
+}
+
+// trimExecutableSuffix removes a Windows .exe suffix from an executable name
+func trimExecutableSuffix(name string) string {
+	if strings.HasSuffix(strings.ToLower(name), ".exe") {
+		return name[:len(name)-len(".exe")]
+	}
+	return name
+}
@@ -0,0 +1,25 @@
+package executor
+
+import "testing"
+
+func TestProcessNameMatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		want    bool
+	}{
+		{name: "sleep", command: "sleep", want: true},
+		{name: "sleep", command: "/bin/sleep", want: true},
+		{name: "/usr/bin/node", command: "node", want: true},
+		{name: "node.exe", command: "node", want: true},
+		{name: "very-long-serv", command: "very-long-server-name", want: false},
+		{name: "very-long-serve", command: "very-long-server-name", want: true},
+		{name: "bash", command: "sleep", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := processNameMatches(tt.name, tt.command); got != tt.want {
+			t.Errorf("processNameMatches(%q, %q) = %v, want %v", tt.name, tt.command, got, tt.want)
+		}
+	}
+}
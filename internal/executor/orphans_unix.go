@@ -0,0 +1,39 @@
+//go:build !windows
+
+package executor
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// processName returns the executable name of a running process
+func processName(pid int) (string, error) {
+	output, err := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "comm=").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect process %d: %w", pid, err)
+	}
+
+	name := strings.TrimSpace(string(output))
+	if name == "" {
+		return "", fmt.Errorf("process %d not found", pid)
+	}
+	return name, nil
+}
+
+// processStartTime returns when a running process started, in a form only meant to be compared
+// with another result for the same PID
+func processStartTime(pid int) (string, error) {
+	output, err := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "lstart=").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect process %d: %w", pid, err)
+	}
+
+	started := strings.TrimSpace(string(output))
+	if started == "" {
+		return "", fmt.Errorf("process %d not found", pid)
+	}
+	return started, nil
+}
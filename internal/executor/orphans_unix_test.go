@@ -0,0 +1,118 @@
+//go:build !windows
+
+package executor
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+)
+
+// startOrphan starts a long-running process and tracks it as if a seqr process that has since
+// exited had started it
+func startOrphan(t *testing.T, pm *ProcessManager, command string) int {
+	t.Helper()
+
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Failed to start process: %v", err)
+	}
+	exited := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(exited)
+	}()
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		<-exited
+	})
+
+	// The PID of a process that has already exited stands in for the crashed seqr
+	owner := exec.Command("true")
+	if err := owner.Run(); err != nil {
+		t.Fatalf("Failed to run owner process: %v", err)
+	}
+
+	pid := cmd.Process.Pid
+	if err := pm.tracker.AddProcess(pid, "server", command, []string{"30"}, "", "keepAlive"); err != nil {
+		t.Fatalf("AddProcess failed: %v", err)
+	}
+	if err := pm.tracker.SetOwner(pid, owner.Process.Pid); err != nil {
+		t.Fatalf("SetOwner failed: %v", err)
+	}
+	return pid
+}
+
+func TestFindOrphans(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	pm := NewProcessManager()
+
+	orphan := startOrphan(t, pm, "sleep")
+	reused := startOrphan(t, pm, "/usr/bin/postgres")
+	owned := startOrphan(t, pm, "sleep")
+	if err := pm.tracker.SetOwner(owned, os.Getpid()); err != nil {
+		t.Fatalf("SetOwner failed: %v", err)
+	}
+
+	// An owner PID given to a later process, here this one, does not keep an orphan owned
+	reusedOwner := startOrphan(t, pm, "sleep")
+	if err := pm.tracker.SetOwner(reusedOwner, os.Getpid()); err != nil {
+		t.Fatalf("SetOwner failed: %v", err)
+	}
+	pm.tracker.mu.Lock()
+	pm.tracker.processes[reusedOwner].OwnerStart = "Thu Jan  1 00:00:00 1970"
+	pm.tracker.saveToFile()
+	pm.tracker.mu.Unlock()
+
+	orphans, err := pm.FindOrphans()
+	if err != nil {
+		t.Fatalf("FindOrphans failed: %v", err)
+	}
+	if len(orphans) != 2 || orphans[0].PID != min(orphan, reusedOwner) || orphans[1].PID != max(orphan, reusedOwner) {
+		t.Fatalf("Expected PIDs %d and %d to be orphans, got %+v", orphan, reusedOwner, orphans)
+	}
+
+	if _, tracked := pm.tracker.GetProcess(reused); tracked {
+		t.Error("Expected a PID running a different program to be dropped from tracking")
+	}
+	if _, tracked := pm.tracker.GetProcess(owned); !tracked {
+		t.Error("Expected a process of a running seqr to stay tracked")
+	}
+}
+
+func TestTerminateOrphan(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	pm := NewProcessManager()
+	pid := startOrphan(t, pm, "sleep")
+
+	if err := pm.TerminateOrphan(pid); err != nil {
+		t.Fatalf("TerminateOrphan failed: %v", err)
+	}
+	if processExists(pid) {
+		t.Errorf("Expected process %d to be terminated", pid)
+	}
+	if _, tracked := pm.tracker.GetProcess(pid); tracked {
+		t.Error("Expected the terminated process to be dropped from tracking")
+	}
+}
+
+func TestAdoptProcess(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	pm := NewProcessManager()
+	pid := startOrphan(t, pm, "sleep")
+
+	if err := pm.AdoptProcess(pid); err != nil {
+		t.Fatalf("AdoptProcess failed: %v", err)
+	}
+
+	orphans, err := NewProcessManager().FindOrphans()
+	if err != nil {
+		t.Fatalf("FindOrphans failed: %v", err)
+	}
+	if len(orphans) != 0 {
+		t.Errorf("Expected an adopted process not to be an orphan, got %+v", orphans)
+	}
+	if !processExists(pid) {
+		t.Error("Expected the adopted process to keep running")
+	}
+}
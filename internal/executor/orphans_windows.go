@@ -0,0 +1,41 @@
+//go:build windows
+
+package executor
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// processName returns the executable name of a running process
+func processName(pid int) (string, error) {
+	output, err := exec.Command("tasklist", "/FI", fmt.Sprintf("PID eq %d", pid), "/FO", "CSV", "/NH").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect process %d: %w", pid, err)
+	}
+
+	// Without a match tasklist prints an informational line instead of a CSV record
+	record, err := csv.NewReader(strings.NewReader(string(output))).Read()
+	if err != nil || len(record) < 2 || record[1] != fmt.Sprint(pid) {
+		return "", fmt.Errorf("process %d not found", pid)
+	}
+	return record[0], nil
+}
+
+// processStartTime returns when a running process started, in a form only meant to be compared
+// with another result for the same PID
+func processStartTime(pid int) (string, error) {
+	script := fmt.Sprintf("(Get-Process -Id %d -ErrorAction Stop).StartTime.ToString('o')", pid)
+	output, err := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect process %d: %w", pid, err)
+	}
+
+	started := strings.TrimSpace(string(output))
+	if started == "" {
+		return "", fmt.Errorf("process %d not found", pid)
+	}
+	return started, nil
+}
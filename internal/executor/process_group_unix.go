@@ -3,10 +3,37 @@
 package executor
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
 	"os/exec"
 	"syscall"
 )
 
+// forwardableSignals maps forwardable signal names to their Unix signals
+var forwardableSignals = map[string]os.Signal{
+	"SIGUSR1": syscall.SIGUSR1,
+	"SIGUSR2": syscall.SIGUSR2,
+}
+
+// suspendSignal and resumeSignal pause and continue keepAlive process groups while seqr is
+// suspended. SIGSTOP cannot be caught or ignored, unlike the terminal's SIGTSTP.
+var (
+	suspendSignal os.Signal = syscall.SIGSTOP
+	resumeSignal  os.Signal = syscall.SIGCONT
+)
+
+// stopSignalsByName maps stop signal names to their Unix signals
+var stopSignalsByName = map[string]syscall.Signal{
+	"SIGTERM": syscall.SIGTERM,
+	"SIGINT":  syscall.SIGINT,
+	"SIGHUP":  syscall.SIGHUP,
+	"SIGQUIT": syscall.SIGQUIT,
+	"SIGUSR1": syscall.SIGUSR1,
+	"SIGUSR2": syscall.SIGUSR2,
+}
+
 // configureProcessGroupPlatform sets up process group on Unix-like systems
 func (e *Executor) configureProcessGroupPlatform(cmd *exec.Cmd) {
 	// Set up process group so we can kill the entire process tree
@@ -16,18 +43,53 @@ func (e *Executor) configureProcessGroupPlatform(cmd *exec.Cmd) {
 	}
 }
 
+// isProcessGroupDenied reports whether starting a process failed because creating its process
+// group is not permitted, as in sandboxes that forbid setpgid
+func isProcessGroupDenied(err error) bool {
+	var pathErr *fs.PathError
+	return errors.As(err, &pathErr) && pathErr.Op == "fork/exec" && errors.Is(err, syscall.EPERM)
+}
+
+// killGroupOrProcess signals the process group led by pid, or only the process when it was started
+// without its own group
+func killGroupOrProcess(pid int, sig syscall.Signal) error {
+	err := syscall.Kill(-pid, sig)
+	if errors.Is(err, syscall.ESRCH) {
+		return syscall.Kill(pid, sig)
+	}
+	return err
+}
+
 // killProcessGroupPlatform kills an entire process group on Unix-like systems
 func (e *Executor) killProcessGroupPlatform(pid int, graceful bool) error {
 	if graceful {
 		// Send SIGTERM to the entire process group
-		if err := syscall.Kill(-pid, syscall.SIGTERM); err != nil {
+		if err := killGroupOrProcess(pid, syscall.SIGTERM); err != nil {
 			return err
 		}
 	} else {
 		// Send SIGKILL to the entire process group
-		if err := syscall.Kill(-pid, syscall.SIGKILL); err != nil {
+		if err := killGroupOrProcess(pid, syscall.SIGKILL); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// signalProcessGroupPlatform sends a signal to an entire process group on Unix-like systems
+func (e *Executor) signalProcessGroupPlatform(pid int, sig os.Signal) error {
+	unixSig, ok := sig.(syscall.Signal)
+	if !ok {
+		return fmt.Errorf("unsupported signal type %T", sig)
+	}
+	return killGroupOrProcess(pid, unixSig)
+}
+
+// sendStopSignalPlatform sends a named stop signal to an entire process group on Unix-like systems
+func (e *Executor) sendStopSignalPlatform(pid int, signalName string) error {
+	sig, ok := stopSignalsByName[signalName]
+	if !ok {
+		return fmt.Errorf("unsupported stop signal: %s", signalName)
+	}
+	return killGroupOrProcess(pid, sig)
+}
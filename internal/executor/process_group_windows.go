@@ -4,10 +4,20 @@ package executor
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
 	"syscall"
 )
 
+// forwardableSignals is empty on Windows, which has no user-defined signals
+var forwardableSignals = map[string]os.Signal{}
+
+// suspendSignal and resumeSignal are nil on Windows, which has no job control signals
+var (
+	suspendSignal os.Signal
+	resumeSignal  os.Signal
+)
+
 // configureProcessGroupPlatform sets up process group on Windows
 func (e *Executor) configureProcessGroupPlatform(cmd *exec.Cmd) {
 	// On Windows, we use CREATE_NEW_PROCESS_GROUP to create a new process group
@@ -17,6 +27,12 @@ func (e *Executor) configureProcessGroupPlatform(cmd *exec.Cmd) {
 	}
 }
 
+// isProcessGroupDenied always reports false on Windows, where a new process group is only a
+// creation flag
+func isProcessGroupDenied(err error) bool {
+	return false
+}
+
 // killProcessGroupPlatform kills an entire process group on Windows
 func (e *Executor) killProcessGroupPlatform(pid int, graceful bool) error {
 	// On Windows, we'll use taskkill to kill the process tree
@@ -33,3 +49,14 @@ func (e *Executor) killProcessGroupPlatform(pid int, graceful bool) error {
 
 	return killCmd.Run()
 }
+
+// signalProcessGroupPlatform is not supported on Windows
+func (e *Executor) signalProcessGroupPlatform(pid int, sig os.Signal) error {
+	return fmt.Errorf("signal forwarding is not supported on Windows")
+}
+
+// sendStopSignalPlatform requests graceful termination of the process tree on Windows,
+// which has no equivalent of the named Unix signals
+func (e *Executor) sendStopSignalPlatform(pid int, signalName string) error {
+	return e.killProcessGroupPlatform(pid, true)
+}
@@ -127,6 +127,11 @@ func (pm *ProcessManager) GetProcessCount() (int, error) {
 	return pm.tracker.GetRunningProcessCount(), nil
 }
 
+// GetStateDir returns the directory where tracking and run state are persisted
+func (pm *ProcessManager) GetStateDir() string {
+	return pm.tracker.StateDir()
+}
+
 // killProcessGroup kills an entire process group using platform-specific methods
 func (pm *ProcessManager) killProcessGroup(pid int, graceful bool) error {
 	// The actual implementation is in platform-specific files
@@ -55,8 +55,13 @@ type ProcessMonitor struct {
 	tracker          *ProcessTracker
 	expectedExits    map[int]bool // Track processes that are expected to exit
 	monitoringActive bool
+	subscribers      []chan ProcessStatusChange // Channels from Subscribe, each receiving every status change
 }
 
+// subscriberBufferSize is how many status changes a subscriber can fall behind before further
+// changes are dropped for it
+const subscriberBufferSize = 100
+
 // NewProcessMonitor creates a new process monitor
 func NewProcessMonitor(verbose bool, tracker *ProcessTracker) *ProcessMonitor {
 	return &ProcessMonitor{
@@ -117,6 +122,13 @@ func (pm *ProcessMonitor) MarkExpectedExit(pid int) {
 	pm.expectedExits[pid] = true
 }
 
+// IsExpectedExit reports whether a process was marked as expected to exit
+func (pm *ProcessMonitor) IsExpectedExit(pid int) bool {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	return pm.expectedExits[pid]
+}
+
 // RemoveProcess removes a process from monitoring
 func (pm *ProcessMonitor) RemoveProcess(pid int) {
 	pm.mu.Lock()
@@ -126,11 +138,58 @@ func (pm *ProcessMonitor) RemoveProcess(pid int) {
 	delete(pm.expectedExits, pid)
 }
 
-// GetStatusChanges returns a channel for receiving process status changes
+// GetStatusChanges returns the monitor's shared channel of process status changes. Each change
+// is received by only one reader of it; use Subscribe to observe changes independently.
 func (pm *ProcessMonitor) GetStatusChanges() <-chan ProcessStatusChange {
 	return pm.statusChanges
 }
 
+// Subscribe returns a new channel that receives every subsequent process status change,
+// independently of other subscribers and the shared GetStatusChanges channel. Changes are
+// dropped for a subscriber that falls too far behind. Call Unsubscribe when done with it.
+func (pm *ProcessMonitor) Subscribe() <-chan ProcessStatusChange {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	ch := make(chan ProcessStatusChange, subscriberBufferSize)
+	pm.subscribers = append(pm.subscribers, ch)
+	return ch
+}
+
+// Unsubscribe stops delivering status changes to a channel returned by Subscribe and closes it
+func (pm *ProcessMonitor) Unsubscribe(ch <-chan ProcessStatusChange) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	for i, subscriber := range pm.subscribers {
+		if subscriber == ch {
+			pm.subscribers = append(pm.subscribers[:i], pm.subscribers[i+1:]...)
+			close(subscriber)
+			return
+		}
+	}
+}
+
+// publish delivers a status change to the shared channel and every subscriber without blocking,
+// reporting whether every subscriber had room for it. The shared channel often has no reader at
+// all, so a change that does not fit in it is dropped silently. The caller must hold pm.mu.
+func (pm *ProcessMonitor) publish(change ProcessStatusChange) bool {
+	select {
+	case pm.statusChanges <- change:
+	default:
+	}
+
+	delivered := true
+	for _, ch := range pm.subscribers {
+		select {
+		case ch <- change:
+		default:
+			delivered = false
+		}
+	}
+	return delivered
+}
+
 // GetProcessStatus returns the current status of a process
 func (pm *ProcessMonitor) GetProcessStatus(pid int) (ProcessStatus, bool) {
 	pm.mu.RLock()
@@ -208,15 +267,11 @@ func (pm *ProcessMonitor) checkProcessStatuses() {
 			pm.processStatuses[pid] = newStatus
 
 			// Send notification (non-blocking)
-			select {
-			case pm.statusChanges <- change:
-			default:
-				// Channel is full, skip this notification
-				if pm.verbose {
-					timestamp := time.Now().Format("15:04:05.000")
-					fmt.Printf("[%s] [%s] [monitor] Warning: Status change notification dropped (channel full)\n", timestamp, name)
-					os.Stdout.Sync()
-				}
+			if !pm.publish(change) && pm.verbose {
+				// A subscriber's channel is full, so it misses this notification
+				timestamp := time.Now().Format("15:04:05.000")
+				fmt.Printf("[%s] [%s] [monitor] Warning: Status change notification dropped (channel full)\n", timestamp, name)
+				os.Stdout.Sync()
 			}
 
 			// Log the status change if verbose
@@ -311,15 +366,11 @@ func (pm *ProcessMonitor) NotifyUnexpectedTermination(pid int, name string, exit
 	pm.processStatuses[pid] = newStatus
 
 	// Send notification (non-blocking)
-	select {
-	case pm.statusChanges <- change:
-	default:
-		// Channel is full, skip this notification
-		if pm.verbose {
-			timestamp := time.Now().Format("15:04:05.000")
-			fmt.Printf("[%s] [%s] [monitor] Warning: Unexpected termination notification dropped (channel full)\n", timestamp, name)
-			os.Stdout.Sync()
-		}
+	if !pm.publish(change) && pm.verbose {
+		// A subscriber's channel is full, so it misses this notification
+		timestamp := time.Now().Format("15:04:05.000")
+		fmt.Printf("[%s] [%s] [monitor] Warning: Unexpected termination notification dropped (channel full)\n", timestamp, name)
+		os.Stdout.Sync()
 	}
 
 	// Always log unexpected terminations, even in non-verbose mode
@@ -3,6 +3,7 @@ package executor
 import (
 	"context"
 	"os"
+	"strings"
 	"testing"
 	"time"
 )
@@ -224,3 +225,68 @@ func TestProcessMonitor_Integration(t *testing.T) {
 	_ = cmd
 	_ = args
 }
+
+func TestProcessMonitor_Subscribe(t *testing.T) {
+	tracker := NewProcessTracker()
+	monitor := NewProcessMonitor(false, tracker)
+
+	first := monitor.Subscribe()
+	second := monitor.Subscribe()
+
+	pid := 99998
+	name := "test-process"
+	monitor.AddProcess(pid, name)
+	monitor.NotifyUnexpectedTermination(pid, name, 2, nil)
+
+	// Every subscriber and the shared channel receive the same change
+	for i, ch := range []<-chan ProcessStatusChange{first, second, monitor.GetStatusChanges()} {
+		select {
+		case change := <-ch:
+			if change.PID != pid || change.Name != name || change.ExitCode != 2 || change.NewStatus != ProcessStatusCrashed {
+				t.Errorf("Channel %d: unexpected status change %+v", i, change)
+			}
+		case <-time.After(1 * time.Second):
+			t.Errorf("Channel %d: expected to receive the termination", i)
+		}
+	}
+
+	// An unsubscribed channel is closed and receives nothing more
+	monitor.Unsubscribe(first)
+	monitor.AddProcess(pid+1, name)
+	monitor.NotifyUnexpectedTermination(pid+1, name, 1, nil)
+
+	if change, ok := <-first; ok {
+		t.Errorf("Expected the unsubscribed channel to be closed, got %+v", change)
+	}
+	select {
+	case change := <-second:
+		if change.PID != pid+1 {
+			t.Errorf("Expected the remaining subscriber to receive PID %d, got %d", pid+1, change.PID)
+		}
+	case <-time.After(1 * time.Second):
+		t.Error("Expected the remaining subscriber to keep receiving changes")
+	}
+}
+
+func TestProcessMonitor_DropWarnings(t *testing.T) {
+	monitor := NewProcessMonitor(true, NewProcessTracker())
+	notify := func(count int) string {
+		return captureOutput(func() {
+			for i := 0; i < count; i++ {
+				monitor.AddProcess(90000+i, "worker")
+				monitor.NotifyUnexpectedTermination(90000+i, "worker", 1, nil)
+			}
+		})
+	}
+
+	// Nobody reads the shared channel, so filling it is not worth a warning
+	if output := notify(subscriberBufferSize + 10); strings.Contains(output, "notification dropped") {
+		t.Errorf("Expected no drop warning without subscribers, got:\n%s", output)
+	}
+
+	// A subscriber that falls behind does miss changes
+	monitor.Subscribe()
+	if output := notify(subscriberBufferSize + 1); !strings.Contains(output, "Unexpected termination notification dropped") {
+		t.Errorf("Expected a drop warning for a full subscriber, got:\n%s", output)
+	}
+}
@@ -13,13 +13,15 @@ import (
 
 // ProcessInfo represents information about a tracked process
 type ProcessInfo struct {
-	PID       int       `json:"pid"`
-	Name      string    `json:"name"`
-	Command   string    `json:"command"`
-	Args      []string  `json:"args"`
-	WorkDir   string    `json:"workDir,omitempty"`
-	StartTime time.Time `json:"startTime"`
-	Mode      string    `json:"mode"`
+	PID        int       `json:"pid"`
+	Name       string    `json:"name"`
+	Command    string    `json:"command"`
+	Args       []string  `json:"args"`
+	WorkDir    string    `json:"workDir,omitempty"`
+	StartTime  time.Time `json:"startTime"`
+	Mode       string    `json:"mode"`
+	OwnerPID   int       `json:"ownerPid,omitempty"`   // The seqr process that started or adopted it; zero if not recorded
+	OwnerStart string    `json:"ownerStart,omitempty"` // When the owner started, telling it apart from a later process given its PID
 }
 
 // ProcessTracker manages tracking of running seqr processes
@@ -52,13 +54,15 @@ func (pt *ProcessTracker) AddProcess(pid int, name, command string, args []strin
 	defer pt.mu.Unlock()
 
 	processInfo := &ProcessInfo{
-		PID:       pid,
-		Name:      name,
-		Command:   command,
-		Args:      args,
-		WorkDir:   workDir,
-		StartTime: time.Now(),
-		Mode:      mode,
+		PID:        pid,
+		Name:       name,
+		Command:    command,
+		Args:       args,
+		WorkDir:    workDir,
+		StartTime:  time.Now(),
+		Mode:       mode,
+		OwnerPID:   os.Getpid(),
+		OwnerStart: currentProcessStart(),
 	}
 
 	pt.processes[pid] = processInfo
@@ -74,6 +78,23 @@ func (pt *ProcessTracker) RemoveProcess(pid int) error {
 	return pt.saveToFile()
 }
 
+// SetOwner records owner as the seqr process a tracked process belongs to
+func (pt *ProcessTracker) SetOwner(pid, owner int) error {
+	// Without a start time the owner is taken to be running for as long as its PID exists
+	ownerStart, _ := processStartTime(owner)
+
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
+	info, exists := pt.processes[pid]
+	if !exists {
+		return fmt.Errorf("process with PID %d is not tracked by seqr", pid)
+	}
+	info.OwnerPID = owner
+	info.OwnerStart = ownerStart
+	return pt.saveToFile()
+}
+
 // GetAllProcesses returns all tracked processes
 func (pt *ProcessTracker) GetAllProcesses() map[int]*ProcessInfo {
 	pt.mu.RLock()
@@ -129,6 +150,11 @@ func (pt *ProcessTracker) CleanupDeadProcesses() error {
 	return nil
 }
 
+// StateDir returns the directory where the tracker persists its state
+func (pt *ProcessTracker) StateDir() string {
+	return filepath.Dir(pt.filePath)
+}
+
 // GetRunningProcessCount returns the number of currently tracked processes
 func (pt *ProcessTracker) GetRunningProcessCount() int {
 	pt.mu.RLock()
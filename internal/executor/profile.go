@@ -0,0 +1,110 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+// ProfileTrace is a run's timeline in the Chrome trace event format, which chrome://tracing,
+// Perfetto and speedscope can open
+type ProfileTrace struct {
+	TraceEvents     []ProfileEvent `json:"traceEvents"`
+	DisplayTimeUnit string         `json:"displayTimeUnit"`
+}
+
+// ProfileEvent is one command's span. Spans that overlap, such as concurrent commands, are put
+// on separate threads so the viewer shows them side by side.
+type ProfileEvent struct {
+	Name      string           `json:"name"`
+	Category  string           `json:"cat"` // The command's mode
+	Phase     string           `json:"ph"`  // Always "X", a complete event with a duration
+	Timestamp int64            `json:"ts"`  // Start, in microseconds since the first command started
+	Duration  int64            `json:"dur"` // In microseconds
+	PID       int              `json:"pid"` // Always 1, the run
+	TID       int              `json:"tid"` // Lane, starting at 1, that no other span overlaps
+	Args      ProfileEventArgs `json:"args"`
+}
+
+// ProfileEventArgs are the details shown for a selected span
+type ProfileEventArgs struct {
+	Group      int     `json:"group"`      // Index of the execution group: each sequential command, or run of concurrent ones
+	Concurrent bool    `json:"concurrent"` // Whether the command ran concurrently with the rest of its group
+	Success    bool    `json:"success"`
+	ExitCode   int     `json:"exitCode"`
+	WaitMs     float64 `json:"waitMs"` // Time spent queued before starting, e.g. behind a concurrency limit
+	Cached     bool    `json:"cached,omitempty"`
+}
+
+// NewProfileTrace builds the timeline of a run's commands, ordered by start time
+func NewProfileTrace(commands []config.Command, status ExecutionStatus) ProfileTrace {
+	groups := make(map[string]int, len(commands))
+	group := -1
+	for i, cmd := range commands {
+		if i == 0 || !config.ConcurrentWith(commands[i-1], cmd) {
+			group++
+		}
+		groups[cmd.Name] = group
+	}
+
+	results := make([]ExecutionResult, len(status.Results))
+	copy(results, status.Results)
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].StartTime.Before(results[j].StartTime)
+	})
+
+	trace := ProfileTrace{TraceEvents: []ProfileEvent{}, DisplayTimeUnit: "ms"}
+	if len(results) == 0 {
+		return trace
+	}
+
+	origin := results[0].StartTime
+	var laneEnds []time.Time // When the last span on each lane ended
+	for _, result := range results {
+		end := result.StartTime.Add(result.Duration)
+
+		lane := 0
+		for lane < len(laneEnds) && laneEnds[lane].After(result.StartTime) {
+			lane++
+		}
+		if lane == len(laneEnds) {
+			laneEnds = append(laneEnds, end)
+		} else {
+			laneEnds[lane] = end
+		}
+
+		trace.TraceEvents = append(trace.TraceEvents, ProfileEvent{
+			Name:      result.Command.Name,
+			Category:  string(result.Command.Mode),
+			Phase:     "X",
+			Timestamp: result.StartTime.Sub(origin).Microseconds(),
+			Duration:  result.Duration.Microseconds(),
+			PID:       1,
+			TID:       lane + 1,
+			Args: ProfileEventArgs{
+				Group:      groups[result.Command.Name],
+				Concurrent: result.Command.Concurrent,
+				Success:    result.Success,
+				ExitCode:   result.ExitCode,
+				WaitMs:     float64(result.WaitDuration.Microseconds()) / 1000,
+				Cached:     result.Cached,
+			},
+		})
+	}
+
+	return trace
+}
+
+// WriteProfile writes a run's timeline to w as Chrome trace event JSON
+func WriteProfile(w io.Writer, commands []config.Command, status ExecutionStatus) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(NewProfileTrace(commands, status)); err != nil {
+		return fmt.Errorf("failed to encode profile: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestNewProfileTrace(t *testing.T) {
+	commands := []config.Command{
+		{Name: "install", Mode: config.ModeOnce},
+		{Name: "api", Mode: config.ModeKeepAlive, Concurrent: true},
+		{Name: "web", Mode: config.ModeKeepAlive, Concurrent: true},
+		{Name: "test", Mode: config.ModeOnce},
+	}
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	result := func(cmd config.Command, offset, duration time.Duration) ExecutionResult {
+		return ExecutionResult{Command: cmd, Success: true, StartTime: start.Add(offset), Duration: duration}
+	}
+
+	// Results are recorded in completion order, web finishing before api
+	status := ExecutionStatus{Results: []ExecutionResult{
+		result(commands[0], 0, 2*time.Second),
+		result(commands[2], 2*time.Second, 500*time.Millisecond),
+		result(commands[1], 2*time.Second+time.Millisecond, time.Second),
+		result(commands[3], 4*time.Second, time.Second),
+	}}
+	status.Results[3].Success = false
+	status.Results[3].ExitCode = 1
+
+	trace := NewProfileTrace(commands, status)
+	if len(trace.TraceEvents) != 4 {
+		t.Fatalf("Expected one span per command, got %d", len(trace.TraceEvents))
+	}
+
+	expected := []struct {
+		name      string
+		timestamp int64
+		duration  int64
+		tid       int
+		group     int
+	}{
+		{"install", 0, 2000000, 1, 0},
+		{"web", 2000000, 500000, 1, 1},
+		{"api", 2001000, 1000000, 2, 1},
+		{"test", 4000000, 1000000, 1, 2},
+	}
+	for i, want := range expected {
+		event := trace.TraceEvents[i]
+		if event.Name != want.name || event.Timestamp != want.timestamp || event.Duration != want.duration {
+			t.Errorf("Span %d: expected %s at %dµs for %dµs, got %s at %dµs for %dµs",
+				i, want.name, want.timestamp, want.duration, event.Name, event.Timestamp, event.Duration)
+		}
+		if event.TID != want.tid || event.Args.Group != want.group {
+			t.Errorf("Span %s: expected lane %d in group %d, got lane %d in group %d", event.Name, want.tid, want.group, event.TID, event.Args.Group)
+		}
+		if event.Phase != "X" {
+			t.Errorf("Span %s: expected a complete event, got phase %q", event.Name, event.Phase)
+		}
+	}
+
+	if last := trace.TraceEvents[3]; last.Args.Success || last.Args.ExitCode != 1 {
+		t.Errorf("Expected the failed command's span to record the failure, got %+v", last.Args)
+	}
+	if !trace.TraceEvents[1].Args.Concurrent || trace.TraceEvents[1].Category != "keepAlive" {
+		t.Errorf("Expected web's span to note its concurrency and mode, got %+v", trace.TraceEvents[1])
+	}
+}
+
+func TestWriteProfile(t *testing.T) {
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "first", Command: "echo", Args: []string{"one"}, Mode: config.ModeOnce},
+			{Name: "second", Command: "echo", Args: []string{"two"}, Mode: config.ModeOnce},
+		},
+	}
+
+	executor := NewExecutor(false)
+	captureOutput(func() {
+		if err := executor.Execute(context.Background(), cfg); err != nil {
+			t.Fatalf("Execute returned error: %v", err)
+		}
+	})
+
+	var buf bytes.Buffer
+	if err := WriteProfile(&buf, cfg.Commands, executor.GetStatus()); err != nil {
+		t.Fatalf("WriteProfile returned error: %v", err)
+	}
+
+	var trace struct {
+		TraceEvents []struct {
+			Name string `json:"name"`
+			Ph   string `json:"ph"`
+			Ts   int64  `json:"ts"`
+			Dur  int64  `json:"dur"`
+		} `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &trace); err != nil {
+		t.Fatalf("Expected valid JSON, got %v:\n%s", err, buf.String())
+	}
+
+	if len(trace.TraceEvents) != 2 {
+		t.Fatalf("Expected one span per command, got %d", len(trace.TraceEvents))
+	}
+	first, second := trace.TraceEvents[0], trace.TraceEvents[1]
+	if first.Name != "first" || second.Name != "second" {
+		t.Errorf("Expected spans in execution order, got %s then %s", first.Name, second.Name)
+	}
+	if first.Ts != 0 || second.Ts < first.Ts+first.Dur {
+		t.Errorf("Expected the second span to start after the first ended, got %+v and %+v", first, second)
+	}
+}
@@ -0,0 +1,89 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// executeOnceWithPTY runs a once command with its stdin, stdout and stderr connected to a
+// pseudo-terminal instead of pipes, for tools such as progress bars or `docker run -it` that only
+// behave interactively on a terminal. Nothing is typed into the terminal; everything the command
+// writes to it is streamed and captured as its stdout.
+func (e *Executor) executeOnceWithPTY(execCmd *exec.Cmd, result ExecutionResult, fifo *fifoSink) (ExecutionResult, error) {
+	fail := func(err error) (ExecutionResult, error) {
+		result.EndTime = time.Now()
+		result.Duration = result.EndTime.Sub(result.StartTime)
+		result.Success = false
+		result.Error = err.Error()
+		result.ExitCode = -1
+		return result, err
+	}
+
+	ptmx, tty, err := openPTY()
+	if err != nil {
+		return fail(fmt.Errorf("failed to allocate a pseudo-terminal: %w", err))
+	}
+	execCmd.Stdin = tty
+	execCmd.Stdout = tty
+	execCmd.Stderr = tty
+	configurePTY(execCmd)
+
+	// Only the command holds the terminal once it started, so reading ends when it closes it
+	err = execCmd.Start()
+	tty.Close()
+	if err != nil {
+		ptmx.Close()
+		return fail(err)
+	}
+
+	output := decodeOutputStream(result.Command, newPTYReader(ptmx))
+	var outputBuilder strings.Builder
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if e.verbose {
+			e.streamOutput(output, &outputBuilder, result.Command.Name, "stdout", result.Command.Command, withFifo(nil, fifo), newLineFilter(result.Command), result.Command.DedupeOutput)
+		} else if fifo != nil {
+			toFifo := newFilteredWriter(fifo, newLineFilter(result.Command))
+			io.Copy(io.MultiWriter(&outputBuilder, toFifo), output)
+			toFifo.Close()
+		} else {
+			io.Copy(&outputBuilder, output)
+		}
+	}()
+
+	err = execCmd.Wait()
+
+	// A background child that inherited the terminal would keep it open after the command exited
+	if !waitForStreams(&wg, streamDrainTimeout) {
+		ptmx.Close()
+		wg.Wait()
+	}
+	ptmx.Close()
+
+	result.EndTime = time.Now()
+	result.Duration = result.EndTime.Sub(result.StartTime)
+	result.Output = strings.TrimSpace(outputBuilder.String())
+
+	if err != nil {
+		result.Success = false
+		result.Error = err.Error()
+		var exitError *exec.ExitError
+		if errors.As(err, &exitError) {
+			result.ExitCode = exitError.ExitCode()
+		} else {
+			result.ExitCode = -1
+		}
+		return result, err
+	}
+
+	result.Success = true
+	result.ExitCode = 0
+	return result, nil
+}
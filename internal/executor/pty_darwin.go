@@ -0,0 +1,53 @@
+package executor
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	ioctlGetTermios = syscall.TIOCGETA
+	ioctlSetTermios = syscall.TIOCSETA
+)
+
+// openPTY allocates a pseudo-terminal, returning its controlling side and the terminal a command
+// is connected to
+func openPTY() (ptmx, tty *os.File, err error) {
+	ptmx, err = os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := ptyIoctl(ptmx, syscall.TIOCPTYGRANT, 0); err != nil {
+		ptmx.Close()
+		return nil, nil, fmt.Errorf("failed to grant pseudo-terminal: %w", err)
+	}
+	if err := ptyIoctl(ptmx, syscall.TIOCPTYUNLK, 0); err != nil {
+		ptmx.Close()
+		return nil, nil, fmt.Errorf("failed to unlock pseudo-terminal: %w", err)
+	}
+
+	name := make([]byte, 128)
+	if err := ptyIoctl(ptmx, syscall.TIOCPTYGNAME, uintptr(unsafe.Pointer(&name[0]))); err != nil {
+		ptmx.Close()
+		return nil, nil, fmt.Errorf("failed to get pseudo-terminal name: %w", err)
+	}
+	if end := bytes.IndexByte(name, 0); end >= 0 {
+		name = name[:end]
+	}
+
+	tty, err = os.OpenFile(string(name), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		ptmx.Close()
+		return nil, nil, err
+	}
+
+	if err := disableOutputCRLF(tty); err != nil {
+		closeFiles(ptmx, tty)
+		return nil, nil, fmt.Errorf("failed to configure pseudo-terminal: %w", err)
+	}
+	return ptmx, tty, nil
+}
@@ -0,0 +1,22 @@
+//go:build !linux && !darwin
+
+package executor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// openPTY reports that pseudo-terminals are not supported on this platform
+func openPTY() (ptmx, tty *os.File, err error) {
+	return nil, nil, fmt.Errorf("pty is not supported on %s", runtime.GOOS)
+}
+
+func configurePTY(cmd *exec.Cmd) {}
+
+func newPTYReader(ptmx *os.File) io.ReadCloser {
+	return ptmx
+}
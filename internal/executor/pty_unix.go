@@ -0,0 +1,76 @@
+//go:build linux || darwin
+
+package executor
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"syscall"
+	"unsafe"
+)
+
+// configurePTY makes the command's terminal its controlling terminal. That takes a session of
+// its own, which also makes the command the leader of a new process group, so stopping it still
+// reaches its children.
+func configurePTY(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setsid:  true,
+		Setctty: true,
+		Ctty:    0, // The command's stdin, which is the terminal
+	}
+}
+
+// disableOutputCRLF stops the terminal from turning "\n" into "\r\n", so captured output has the
+// same line endings as when the command writes to a pipe
+func disableOutputCRLF(tty *os.File) error {
+	var termios syscall.Termios
+	if err := ptyIoctl(tty, ioctlGetTermios, uintptr(unsafe.Pointer(&termios))); err != nil {
+		return err
+	}
+	termios.Oflag &^= syscall.ONLCR
+	return ptyIoctl(tty, ioctlSetTermios, uintptr(unsafe.Pointer(&termios)))
+}
+
+// ptyIoctl performs an ioctl on f without switching it to blocking mode, so closing it still
+// interrupts a pending read
+func ptyIoctl(f *os.File, request uint, arg uintptr) error {
+	conn, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var errno syscall.Errno
+	if err := conn.Control(func(fd uintptr) {
+		_, _, errno = syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(request), arg)
+	}); err != nil {
+		return err
+	}
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// ptyReader reads the controlling side of a pseudo-terminal. Once every process has closed the
+// terminal, reads fail with EIO rather than returning EOF.
+type ptyReader struct {
+	file *os.File
+}
+
+func newPTYReader(ptmx *os.File) io.ReadCloser {
+	return ptyReader{file: ptmx}
+}
+
+func (r ptyReader) Read(p []byte) (int, error) {
+	n, err := r.file.Read(p)
+	if errors.Is(err, syscall.EIO) {
+		return n, io.EOF
+	}
+	return n, err
+}
+
+func (r ptyReader) Close() error {
+	return r.file.Close()
+}
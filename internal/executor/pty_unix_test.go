@@ -0,0 +1,60 @@
+//go:build linux || darwin
+
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestExecutor_PTYCommandIsInteractive(t *testing.T) {
+	script := `if [ -t 0 ] && [ -t 1 ] && [ -t 2 ]; then echo interactive; else echo piped; fi; echo second line`
+
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "tty", Command: "sh", Args: []string{"-c", script}, Mode: config.ModeOnce, PTY: true},
+			{Name: "pipes", Command: "sh", Args: []string{"-c", script}, Mode: config.ModeOnce},
+		},
+	}
+
+	for _, verbose := range []bool{false, true} {
+		executor := NewExecutor(verbose)
+		captureOutput(func() {
+			if err := executor.Execute(context.Background(), cfg); err != nil {
+				t.Fatalf("verbose=%v: Execute returned error: %v", verbose, err)
+			}
+		})
+
+		results := executor.GetStatus().Results
+		if got := results[0].Output; got != "interactive\nsecond line" {
+			t.Errorf("verbose=%v: expected the pty command to see a terminal, got %q", verbose, got)
+		}
+		if got := results[1].Output; got != "piped\nsecond line" {
+			t.Errorf("verbose=%v: expected commands without pty to see pipes, got %q", verbose, got)
+		}
+	}
+}
+
+func TestExecutor_PTYCommandExitCode(t *testing.T) {
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "tty", Command: "sh", Args: []string{"-c", "echo failing; exit 3"}, Mode: config.ModeOnce, PTY: true},
+		},
+	}
+
+	executor := NewExecutor(false)
+	captureOutput(func() {
+		if err := executor.Execute(context.Background(), cfg); err == nil {
+			t.Fatal("Expected Execute to fail")
+		}
+	})
+
+	result := executor.GetStatus().Results[0]
+	if result.ExitCode != 3 || result.Output != "failing" {
+		t.Errorf("Expected exit code 3 and the output written before failing, got %d and %q", result.ExitCode, result.Output)
+	}
+}
@@ -0,0 +1,82 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+// readyFilePollInterval is how often the executor checks whether a readyFile has appeared
+const readyFilePollInterval = 50 * time.Millisecond
+
+// removeStaleReadyFile removes a readyFile left behind by an earlier run, so only the process
+// about to start can signal readiness
+func removeStaleReadyFile(cmd config.Command) error {
+	if cmd.ReadyFile == "" {
+		return nil
+	}
+
+	if err := os.Remove(commandRelativePath(cmd, cmd.ReadyFile)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove stale readyFile '%s': %w", cmd.ReadyFile, err)
+	}
+	return nil
+}
+
+// readyTimeout returns how long a keepAlive command is given to become ready, covering both its
+// readyFile and its readyTCP address
+func readyTimeout(cmd config.Command) time.Duration {
+	if cmd.ReadyTimeout <= 0 {
+		return config.DefaultReadyTimeout
+	}
+	return cmd.ReadyTimeout
+}
+
+// waitForReadyFile waits for a started keepAlive command to create its readyFile. A process that
+// does not create it by readyBy, the end of its readyTimeout, is stopped; one that exits first
+// fails.
+func (e *Executor) waitForReadyFile(ctx context.Context, cmd config.Command, readyBy time.Time) error {
+	timeout := readyTimeout(cmd)
+	path := commandRelativePath(cmd, cmd.ReadyFile)
+
+	if e.verbose {
+		timestamp := time.Now().Format("15:04:05.000")
+		fmt.Printf("[%s] [%s] [process] Waiting up to %v for readyFile %s\n", timestamp, cmd.Name, time.Until(readyBy).Round(time.Millisecond), path)
+	}
+
+	start := time.Now()
+	deadline := time.NewTimer(time.Until(readyBy))
+	defer deadline.Stop()
+	ticker := time.NewTicker(readyFilePollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := os.Stat(path); err == nil {
+			if e.verbose {
+				timestamp := time.Now().Format("15:04:05.000")
+				fmt.Printf("[%s] [%s] [process] Ready after %v\n", timestamp, cmd.Name, time.Since(start).Round(time.Millisecond))
+			}
+			return nil
+		}
+
+		e.mu.RLock()
+		_, running := e.processes[cmd.Name]
+		e.mu.RUnlock()
+		if !running {
+			return fmt.Errorf("keepAlive command '%s' exited before creating readyFile '%s'", cmd.Name, cmd.ReadyFile)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("stopped waiting for readyFile '%s' of keepAlive command '%s': %w", cmd.ReadyFile, cmd.Name, ctx.Err())
+		case <-deadline.C:
+			e.stopKeepAlive(cmd.Name)
+			return fmt.Errorf("keepAlive command '%s' did not create readyFile '%s' within %v", cmd.Name, cmd.ReadyFile, timeout)
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestExecutor_ReadyFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sh")
+	}
+
+	tests := []struct {
+		name         string
+		script       string
+		readyTimeout time.Duration
+		errSubstr    string
+	}{
+		{
+			name:   "waits for the file to appear",
+			script: "sleep 0.3; touch ready; sleep 30",
+		},
+		{
+			name:         "stops the process when the file never appears",
+			script:       "sleep 30",
+			readyTimeout: 200 * time.Millisecond,
+			errSubstr:    "keepAlive command 'server' did not create readyFile 'ready' within 200ms",
+		},
+		{
+			name:      "fails when the process exits first",
+			script:    "sleep 0.1",
+			errSubstr: "keepAlive command 'server' exited before creating readyFile 'ready'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TMPDIR", t.TempDir())
+
+			// A file left by an earlier run must not count as ready
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "ready"), nil, 0644); err != nil {
+				t.Fatalf("Failed to create stale ready file: %v", err)
+			}
+
+			cfg := &config.Config{
+				Version: "1.0",
+				Commands: []config.Command{
+					{Name: "server", Command: "sh", Args: []string{"-c", tt.script}, Mode: config.ModeKeepAlive, WorkDir: dir, ReadyFile: "ready", ReadyTimeout: tt.readyTimeout},
+					{Name: "after", Command: "echo", Args: []string{"ran"}, Mode: config.ModeOnce},
+				},
+			}
+
+			executor := NewExecutor(false)
+			start := time.Now()
+			var err error
+			captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+			elapsed := time.Since(start)
+			defer captureOutput(executor.Stop)
+
+			if tt.errSubstr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.errSubstr, err)
+				}
+				if executor.HasActiveKeepAliveProcesses() {
+					t.Error("Expected the failed keepAlive process to be stopped")
+				}
+				if len(executor.GetStatus().Results) != 1 {
+					t.Error("Expected the queue not to advance past the failed command")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Execute returned error: %v", err)
+			}
+			if elapsed < 300*time.Millisecond {
+				t.Errorf("Expected the queue to wait for the ready file, advanced after %v", elapsed)
+			}
+			if results := executor.GetStatus().Results; len(results) != 2 || !results[1].Success {
+				t.Errorf("Expected the next command to run once the server was ready, got %+v", results)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+const (
+	// readyTCPPollInterval is how often the executor tries to connect to a readyTCP address
+	readyTCPPollInterval = 100 * time.Millisecond
+	// readyTCPDialTimeout bounds one attempt, for addresses that drop connections unanswered
+	readyTCPDialTimeout = time.Second
+)
+
+// waitForReadyTCP waits for a started keepAlive command to accept connections on its readyTCP
+// address, dialing it until a connection succeeds. A process that does not accept one by readyBy,
+// the end of its readyTimeout, which a readyFile wait may already have used up part of, is
+// stopped; one that exits first fails.
+func (e *Executor) waitForReadyTCP(ctx context.Context, cmd config.Command, readyBy time.Time) error {
+	timeout := readyTimeout(cmd)
+
+	if e.verbose {
+		timestamp := time.Now().Format("15:04:05.000")
+		fmt.Printf("[%s] [%s] [process] Waiting up to %v for %s to accept connections\n", timestamp, cmd.Name, time.Until(readyBy).Round(time.Millisecond), cmd.ReadyTCP)
+	}
+
+	start := time.Now()
+	deadline := time.NewTimer(time.Until(readyBy))
+	defer deadline.Stop()
+	ticker := time.NewTicker(readyTCPPollInterval)
+	defer ticker.Stop()
+
+	var dialer net.Dialer
+	attempts := 0
+	for {
+		attempts++
+		dialCtx, cancel := context.WithTimeout(ctx, readyTCPDialTimeout)
+		conn, dialErr := dialer.DialContext(dialCtx, "tcp", cmd.ReadyTCP)
+		cancel()
+		if dialErr == nil {
+			conn.Close()
+			if e.verbose {
+				timestamp := time.Now().Format("15:04:05.000")
+				fmt.Printf("[%s] [%s] [process] Ready after %v (attempts: %d)\n", timestamp, cmd.Name, time.Since(start).Round(time.Millisecond), attempts)
+			}
+			return nil
+		}
+
+		e.mu.RLock()
+		_, running := e.processes[cmd.Name]
+		e.mu.RUnlock()
+		if !running {
+			return fmt.Errorf("keepAlive command '%s' exited before accepting connections on %s (attempts: %d)", cmd.Name, cmd.ReadyTCP, attempts)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("stopped waiting for keepAlive command '%s' to accept connections on %s: %w", cmd.Name, cmd.ReadyTCP, ctx.Err())
+		case <-deadline.C:
+			e.stopKeepAlive(cmd.Name)
+			return fmt.Errorf("keepAlive command '%s' did not accept connections on %s within %v (attempts: %d, last error: %v)", cmd.Name, cmd.ReadyTCP, timeout, attempts, dialErr)
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package executor
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+// TestReadyTCPServer is not a test: run as a keepAlive command by TestExecutor_ReadyTCP, it
+// starts listening on SEQR_TEST_LISTEN after SEQR_TEST_LISTEN_DELAY, never if that is "never",
+// and exits at once if it is "exit". With SEQR_TEST_READY_FILE it first creates that file after
+// readyFileDelay.
+func TestReadyTCPServer(t *testing.T) {
+	addr := os.Getenv("SEQR_TEST_LISTEN")
+	if addr == "" {
+		t.Skip("Only runs as a keepAlive command")
+	}
+
+	if path := os.Getenv("SEQR_TEST_READY_FILE"); path != "" {
+		time.Sleep(readyFileDelay)
+		os.WriteFile(path, nil, 0600)
+	}
+
+	switch delay := os.Getenv("SEQR_TEST_LISTEN_DELAY"); delay {
+	case "exit":
+		os.Exit(0)
+	case "never":
+		time.Sleep(time.Minute)
+		os.Exit(0)
+	default:
+		d, _ := time.ParseDuration(delay)
+		time.Sleep(d)
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		os.Exit(1)
+	}
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			os.Exit(1)
+		}
+		conn.Close()
+	}
+}
+
+// readyFileDelay is how long TestReadyTCPServer takes to create its readyFile
+const readyFileDelay = 500 * time.Millisecond
+
+// freeTCPAddress returns a local address nothing is listening on
+func freeTCPAddress(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to find a free port: %v", err)
+	}
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+func TestExecutor_ReadyTCP(t *testing.T) {
+	tests := []struct {
+		name         string
+		delay        string
+		readyTimeout time.Duration
+		errSubstr    string
+	}{
+		{
+			name:  "waits for the port to accept connections",
+			delay: "300ms",
+		},
+		{
+			name:         "stops the process when the port never opens",
+			delay:        "never",
+			readyTimeout: 300 * time.Millisecond,
+			errSubstr:    "did not accept connections on",
+		},
+		{
+			name:      "fails when the process exits first",
+			delay:     "exit",
+			errSubstr: "keepAlive command 'server' exited before accepting connections on",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TMPDIR", t.TempDir())
+
+			addr := freeTCPAddress(t)
+			cfg := &config.Config{
+				Version: "1.0",
+				Commands: []config.Command{
+					{
+						Name:         "server",
+						Command:      os.Args[0],
+						Args:         []string{"-test.run=^TestReadyTCPServer$"},
+						Mode:         config.ModeKeepAlive,
+						Env:          map[string]string{"SEQR_TEST_LISTEN": addr, "SEQR_TEST_LISTEN_DELAY": tt.delay},
+						ReadyTCP:     addr,
+						ReadyTimeout: tt.readyTimeout,
+					},
+					{Name: "after", Command: "echo", Args: []string{"ran"}, Mode: config.ModeOnce},
+				},
+			}
+
+			executor := NewExecutor(false)
+			start := time.Now()
+			var err error
+			captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+			elapsed := time.Since(start)
+			defer captureOutput(executor.Stop)
+
+			if tt.errSubstr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errSubstr) || !strings.Contains(err.Error(), "attempts: ") {
+					t.Fatalf("Expected error containing %q and the number of attempts, got %v", tt.errSubstr, err)
+				}
+				if executor.HasActiveKeepAliveProcesses() {
+					t.Error("Expected the failed keepAlive process to be stopped")
+				}
+				if len(executor.GetStatus().Results) != 1 {
+					t.Error("Expected the queue not to advance past the failed command")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Execute returned error: %v", err)
+			}
+			if elapsed < 300*time.Millisecond {
+				t.Errorf("Expected the queue to wait for the port, advanced after %v", elapsed)
+			}
+			if results := executor.GetStatus().Results; len(results) != 2 || !results[1].Success {
+				t.Errorf("Expected the next command to run once the server was ready, got %+v", results)
+			}
+		})
+	}
+}
+
+func TestExecutor_ReadyTimeoutCoversFileAndTCP(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	addr := freeTCPAddress(t)
+	readyFile := filepath.Join(t.TempDir(), "ready")
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{
+				Name:         "server",
+				Command:      os.Args[0],
+				Args:         []string{"-test.run=^TestReadyTCPServer$"},
+				Mode:         config.ModeKeepAlive,
+				Env:          map[string]string{"SEQR_TEST_LISTEN": addr, "SEQR_TEST_LISTEN_DELAY": "never", "SEQR_TEST_READY_FILE": readyFile},
+				ReadyFile:    readyFile,
+				ReadyTCP:     addr,
+				ReadyTimeout: 700 * time.Millisecond,
+			},
+		},
+	}
+
+	executor := NewExecutor(false)
+	start := time.Now()
+	var err error
+	captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+	elapsed := time.Since(start)
+	defer captureOutput(executor.Stop)
+
+	if err == nil || !strings.Contains(err.Error(), "did not accept connections on") {
+		t.Fatalf("Expected the port wait to time out, got %v", err)
+	}
+	// Waiting for the port with a fresh readyTimeout after the file would take 1.2s
+	if elapsed >= readyFileDelay+500*time.Millisecond {
+		t.Errorf("Expected both waits to share the 700ms readyTimeout, took %v", elapsed)
+	}
+}
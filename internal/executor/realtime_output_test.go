@@ -96,6 +96,25 @@ func TestExecuteOnceWithRealTimeOutput(t *testing.T) {
 	}
 }
 
+func TestExecuteOnceWithRealTimeOutputKeepsShortOutput(t *testing.T) {
+	// A command that exits right after writing must not lose its output to the pipe closing
+	for i := 0; i < 20; i++ {
+		cfg := &config.Config{
+			Version:  "1.0",
+			Commands: []config.Command{{Name: "quick", Command: "echo", Args: []string{"hello"}, Mode: config.ModeOnce}},
+		}
+		executor := NewExecutor(true)
+		var err error
+		captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+		if err != nil {
+			t.Fatalf("Execute returned error: %v", err)
+		}
+		if got := executor.GetStatus().Results[0].Output; got != "hello" {
+			t.Fatalf("Run %d: expected captured output %q, got %q", i, "hello", got)
+		}
+	}
+}
+
 func TestStreamOutput(t *testing.T) {
 	executor := NewExecutor(true)
 
@@ -107,7 +126,7 @@ func TestStreamOutput(t *testing.T) {
 
 	// We can't easily test the streaming directly since it writes to stdout,
 	// but we can test the output building functionality
-	executor.streamOutput(reader, &outputBuilder, "test-command", "stdout")
+	executor.streamOutput(reader, &outputBuilder, "test-command", "stdout", "echo", nil, nil, false)
 
 	capturedOutput := strings.TrimSpace(outputBuilder.String())
 	expectedOutput := strings.ReplaceAll(testContent, "\n", "\n") + "\n"
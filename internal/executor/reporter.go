@@ -3,60 +3,313 @@ package executor
 import (
 	"fmt"
 	"io"
+	"os"
+	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 )
 
 type Reporter interface {
 	ReportStart(totalCommands int)
+	ReportBanner(message string)
+	ReportStageStart(stage string)
 	ReportCommandStart(commandName string, commandIndex int)
+	ReportCommandLine(commandName string, commandLine string)
 	ReportCommandSuccess(result ExecutionResult, commandIndex int)
 	ReportCommandFailure(result ExecutionResult, commandIndex int)
 	ReportExecutionComplete(status ExecutionStatus)
+	ReportFooter(message string)
+	ReportCommandAutoStop(commandName string, lifetime time.Duration)
 }
 
 type ConsoleReporter struct {
-	writer  io.Writer
-	verbose bool
+	writer       io.Writer
+	errWriter    io.Writer // Failures and error details, same as writer unless configured otherwise
+	verbose      bool
+	summaryLines int // Output lines shown for a successful command in verbose mode; 0 shows all, -1 none
+
+	showOutputOnFailure bool // Without verbose output, print a failed command's captured output
+	maxOutputBytes      int  // Bound on the failure output printed, keeping its end
+
+	compact       bool       // Show progress as one status line updated in place
+	totalCommands int        // Size of the queue, for the compact status line
+	statusMu      sync.Mutex // Guards statusShown and the spinner state, as concurrent commands report at once
+	statusShown   bool       // The compact status line is on screen without a newline after it
+
+	spinner      bool             // Animate the status line while commands run
+	running      []runningCommand // Commands the spinner shows, in start order
+	spinnerDone  chan struct{}    // Closed to stop the animation; nil while nothing runs
+	spinnerShown bool             // The status line on screen is a spinner frame
+
+	noSummary bool // Leave out the final result line and the verbose timing summary
+}
+
+// DefaultSummaryOutputLines is how many output lines a verbose success summary shows by default
+const DefaultSummaryOutputLines = 3
+
+// DefaultMaxOutputBytes bounds the failure output printed with ShowOutputOnFailure by default
+const DefaultMaxOutputBytes = 64 * 1024
+
+// ConsoleReporterOptions configures a ConsoleReporter created with NewConsoleReporterWithOptions
+type ConsoleReporterOptions struct {
+	Writer    io.Writer // Progress and successes
+	ErrWriter io.Writer // Failures and error details; nil falls back to Writer
+	Verbose   bool
+
+	// SummaryOutputLines is how many lines of a successful command's output the verbose summary
+	// shows before "... (N more lines)". 0 shows all of it and -1 none; the other constructors
+	// use DefaultSummaryOutputLines.
+	SummaryOutputLines int
+
+	// ShowOutputOnFailure prints a failed command's captured output in non-verbose mode, where
+	// it was not streamed. At most MaxOutputBytes of it are printed, keeping the end, where the
+	// error usually is; zero uses DefaultMaxOutputBytes.
+	ShowOutputOnFailure bool
+	MaxOutputBytes      int
+
+	// Compact replaces the start and success lines of each command with a single status line
+	// updated in place, such as "[3/10] running build...". It only applies without Verbose and
+	// when Writer is a terminal, or AssumeTerminal is set; otherwise output is unchanged.
+	Compact        bool
+	AssumeTerminal bool
+
+	// Spinner animates a status line with the elapsed time while a command runs, such as
+	// "⠹ build (12s)", so a long command without output does not look frozen. Like Compact, it
+	// only applies without Verbose and when Writer is a terminal or AssumeTerminal is set.
+	Spinner bool
+
+	// NoSummary leaves out what ReportExecutionComplete prints: the final "All commands
+	// completed successfully" or "Execution failed" line and the verbose timing summary, for
+	// scripts that parse the last line of output
+	NoSummary bool
 }
 
 func NewConsoleReporter(writer io.Writer, verbose bool) *ConsoleReporter {
+	return NewConsoleReporterWithWriters(writer, writer, verbose)
+}
+
+// NewConsoleReporterWithWriters creates a reporter that writes progress and successes to writer
+// and failures to errWriter, for example stdout and stderr. A nil errWriter falls back to writer.
+func NewConsoleReporterWithWriters(writer, errWriter io.Writer, verbose bool) *ConsoleReporter {
+	return NewConsoleReporterWithOptions(ConsoleReporterOptions{
+		Writer:             writer,
+		ErrWriter:          errWriter,
+		Verbose:            verbose,
+		SummaryOutputLines: DefaultSummaryOutputLines,
+	})
+}
+
+// NewConsoleReporterWithOptions creates a reporter configured by opts
+func NewConsoleReporterWithOptions(opts ConsoleReporterOptions) *ConsoleReporter {
+	errWriter := opts.ErrWriter
+	if errWriter == nil {
+		errWriter = opts.Writer
+	}
+	maxOutputBytes := opts.MaxOutputBytes
+	if maxOutputBytes <= 0 {
+		maxOutputBytes = DefaultMaxOutputBytes
+	}
 	return &ConsoleReporter{
-		writer:  writer,
-		verbose: verbose,
+		writer:       opts.Writer,
+		errWriter:    errWriter,
+		verbose:      opts.Verbose,
+		summaryLines: opts.SummaryOutputLines,
+
+		showOutputOnFailure: opts.ShowOutputOnFailure,
+		maxOutputBytes:      maxOutputBytes,
+
+		compact: opts.Compact && !opts.Verbose && (opts.AssumeTerminal || isTerminal(opts.Writer)),
+		spinner: opts.Spinner && !opts.Verbose && (opts.AssumeTerminal || isTerminal(opts.Writer)),
+
+		noSummary: opts.NoSummary,
+	}
+}
+
+// isTerminal reports whether w writes to a terminal rather than a file or pipe
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// showStatus replaces the compact status line with text
+func (r *ConsoleReporter) showStatus(format string, args ...any) {
+	r.statusMu.Lock()
+	defer r.statusMu.Unlock()
+	fmt.Fprintf(r.writer, "\r"+format+"\033[K", args...)
+	r.statusShown = true
+	r.spinnerShown = false
+}
+
+// eraseStatus erases the status line if one is on screen, so a line of regular output can take
+// its place; statusMu must be held
+func (r *ConsoleReporter) eraseStatus() {
+	if r.statusShown {
+		fmt.Fprintf(r.writer, "\r\033[K")
+		r.statusShown = false
+		r.spinnerShown = false
+	}
+}
+
+// printf writes a line of regular output to w in place of the status line, so that it cannot
+// interleave with a spinner frame
+func (r *ConsoleReporter) printf(w io.Writer, format string, args ...any) {
+	r.statusMu.Lock()
+	defer r.statusMu.Unlock()
+	r.eraseStatus()
+	fmt.Fprintf(w, format, args...)
+}
+
+// finishStatus keeps the compact status line on screen, ending it with a newline
+func (r *ConsoleReporter) finishStatus() {
+	r.statusMu.Lock()
+	defer r.statusMu.Unlock()
+	if r.statusShown {
+		fmt.Fprintf(r.writer, "\n")
+		r.statusShown = false
 	}
 }
 
 func (r *ConsoleReporter) ReportStart(totalCommands int) {
+	r.totalCommands = totalCommands
 	if r.verbose {
 		timestamp := time.Now().Format("15:04:05.000")
 		fmt.Fprintf(r.writer, "[%s] [seqr] [system] Starting execution of %d commands\n", timestamp, totalCommands)
 	}
 }
 
+// ReportBanner prints the config's banner as the run starts
+func (r *ConsoleReporter) ReportBanner(message string) {
+	r.printf(r.writer, "%s\n", message)
+}
+
+// ReportStageStart prints a header before the first command of a stage
+func (r *ConsoleReporter) ReportStageStart(stage string) {
+	r.printf(r.writer, "=== Stage: %s ===\n", stage)
+}
+
 func (r *ConsoleReporter) ReportCommandStart(commandName string, commandIndex int) {
-	fmt.Fprintf(r.writer, "[%d] Starting: %s\n", commandIndex+1, commandName)
+	defer r.startSpinner(commandName, commandIndex)
+	if r.compact {
+		r.showStatus("[%d/%d] running %s...", commandIndex+1, r.totalCommands, commandName)
+		return
+	}
+	r.printf(r.writer, "[%d] Starting: %s\n", commandIndex+1, commandName)
+}
+
+func (r *ConsoleReporter) ReportCommandLine(commandName string, commandLine string) {
+	r.printf(r.writer, "+ %s\n", commandLine)
 }
 
 func (r *ConsoleReporter) ReportCommandSuccess(result ExecutionResult, commandIndex int) {
-	fmt.Fprintf(r.writer, "[%d] ✓ %s (%v)\n", commandIndex+1, result.Command.Name, result.Duration.Round(10))
-	if r.verbose && result.Output != "" {
+	r.stopSpinner(result.Command.Name)
+	if r.compact {
+		if result.Cached {
+			r.showStatus("[%d/%d] ✓ %s (cached)", commandIndex+1, r.totalCommands, result.Command.Name)
+		} else {
+			r.showStatus("[%d/%d] ✓ %s (%v)", commandIndex+1, r.totalCommands, result.Command.Name, result.Duration.Round(10))
+		}
+		return
+	}
+	if result.Cached {
+		r.printf(r.writer, "[%d] ✓ %s (cached)\n", commandIndex+1, result.Command.Name)
+		return
+	}
+	r.printf(r.writer, "[%d] ✓ %s (%v)\n", commandIndex+1, result.Command.Name, result.Duration.Round(10))
+	if r.verbose && result.Output != "" && r.summaryLines >= 0 {
 		timestamp := time.Now().Format("15:04:05.000")
-		fmt.Fprintf(r.writer, "[%s] [%s] [summary] Output: %s\n", timestamp, result.Command.Name, result.Output)
+		fmt.Fprintf(r.writer, "[%s] [%s] [summary] Output: %s\n", timestamp, result.Command.Name, truncateLines(result.Output, r.summaryLines))
+	}
+}
+
+// truncateLines keeps the first limit lines of output, noting how many were left out. A limit of
+// 0 keeps everything.
+func truncateLines(output string, limit int) string {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if limit == 0 || len(lines) <= limit {
+		return strings.Join(lines, "\n")
 	}
+	return fmt.Sprintf("%s\n... (%d more lines)", strings.Join(lines[:limit], "\n"), len(lines)-limit)
 }
 
 func (r *ConsoleReporter) ReportCommandFailure(result ExecutionResult, commandIndex int) {
-	fmt.Fprintf(r.writer, "[%d] ✗ %s failed: %s\n", commandIndex+1, result.Command.Name, result.Error)
+	r.stopSpinner(result.Command.Name)
+
+	// The whole report is written at once, so a spinner frame cannot land inside it
+	var b strings.Builder
+	var notes []string
+	if result.AllowedFailure {
+		notes = append(notes, "allowed")
+	}
+	if result.Signal != "" {
+		notes = append(notes, "terminated by "+result.Signal)
+	}
+	if len(notes) > 0 {
+		fmt.Fprintf(&b, "[%d] ✗ %s failed (%s): %s\n", commandIndex+1, result.Command.Name, strings.Join(notes, ", "), result.Error)
+	} else {
+		fmt.Fprintf(&b, "[%d] ✗ %s failed: %s\n", commandIndex+1, result.Command.Name, result.Error)
+	}
 	if r.verbose && result.Output != "" {
 		timestamp := time.Now().Format("15:04:05.000")
-		fmt.Fprintf(r.writer, "[%s] [%s] [summary] Output: %s\n", timestamp, result.Command.Name, result.Output)
+		fmt.Fprintf(&b, "[%s] [%s] [summary] Output: %s\n", timestamp, result.Command.Name, result.Output)
+	} else if !r.verbose && r.showOutputOnFailure && result.Output != "" {
+		fmt.Fprintf(&b, "--- output of %s ---\n%s\n--- end of output ---\n", result.Command.Name, truncateBytes(result.Output, r.maxOutputBytes))
 	}
+	r.printf(r.errWriter, "%s", b.String())
+}
+
+// truncateBytes keeps at most the last limit bytes of output, starting at a line boundary where
+// there is one and otherwise at a character boundary, and notes how many bytes were left out
+func truncateBytes(output string, limit int) string {
+	if len(output) <= limit {
+		return output
+	}
+
+	start := len(output) - limit
+	for start < len(output) && !utf8.RuneStart(output[start]) {
+		start++
+	}
+	tail := output[start:]
+	if newline := strings.IndexByte(tail, '\n'); newline >= 0 && newline < len(tail)-1 {
+		tail = tail[newline+1:]
+	}
+	return fmt.Sprintf("... (%d bytes omitted)\n%s", len(output)-len(tail), tail)
+}
+
+// ReportFooter prints the config's footer once the run has succeeded
+func (r *ConsoleReporter) ReportFooter(message string) {
+	r.printf(r.writer, "%s\n", message)
+}
+
+func (r *ConsoleReporter) ReportCommandAutoStop(commandName string, lifetime time.Duration) {
+	r.printf(r.writer, "⏱ %s stopped after reaching its maxLifetime of %v\n", commandName, lifetime)
 }
 
 func (r *ConsoleReporter) ReportExecutionComplete(status ExecutionStatus) {
+	r.stopAllSpinners()
+	r.finishStatus()
+	if r.noSummary {
+		return
+	}
+
 	if status.State == StateSuccess {
 		fmt.Fprintf(r.writer, "All commands completed successfully\n")
 	} else {
-		fmt.Fprintf(r.writer, "Execution failed: %s\n", status.LastError)
+		fmt.Fprintf(r.errWriter, "Execution failed: %s\n", status.LastError)
+	}
+
+	if r.verbose {
+		timestamp := time.Now().Format("15:04:05.000")
+		for _, result := range status.Results {
+			if result.Command.Warmup {
+				continue
+			}
+			fmt.Fprintf(r.writer, "[%s] [%s] [summary] Waited %v, ran %v\n",
+				timestamp, result.Command.Name, result.WaitDuration.Round(time.Millisecond), result.RunDuration.Round(time.Millisecond))
+		}
 	}
 }
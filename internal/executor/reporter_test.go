@@ -2,9 +2,15 @@ package executor
 
 import (
 	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"runtime"
 	"strings"
+	"sync"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	"github.com/seqr-cli/seqr/internal/config"
 )
@@ -56,3 +62,471 @@ func TestConsoleReporter_ReportCommandFailure(t *testing.T) {
 		t.Errorf("Expected failure message, got: %s", output)
 	}
 }
+
+func TestConsoleReporter_SeparateErrorWriter(t *testing.T) {
+	var out, errOut bytes.Buffer
+	reporter := NewConsoleReporterWithWriters(&out, &errOut, true)
+
+	reporter.ReportCommandStart("build", 0)
+	reporter.ReportCommandSuccess(ExecutionResult{Command: config.Command{Name: "build"}, Success: true}, 0)
+	reporter.ReportCommandFailure(ExecutionResult{
+		Command: config.Command{Name: "test"},
+		Error:   "exit status 1",
+		Output:  "assertion failed",
+	}, 1)
+	reporter.ReportExecutionComplete(ExecutionStatus{State: StateFailed, LastError: "command test failed"})
+
+	if !strings.Contains(out.String(), "[1] Starting: build") || !strings.Contains(out.String(), "[1] ✓ build") {
+		t.Errorf("Expected progress and successes on the output writer, got: %s", out.String())
+	}
+
+	for _, expected := range []string{"[2] ✗ test failed: exit status 1", "Output: assertion failed", "Execution failed: command test failed"} {
+		if !strings.Contains(errOut.String(), expected) {
+			t.Errorf("Expected %q on the error writer, got: %s", expected, errOut.String())
+		}
+		if strings.Contains(out.String(), expected) {
+			t.Errorf("Expected %q not to be written to the output writer", expected)
+		}
+	}
+}
+
+func TestConsoleReporter_ErrorWriterDefaultsToWriter(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := NewConsoleReporterWithWriters(&buf, nil, false)
+
+	reporter.ReportCommandFailure(ExecutionResult{Command: config.Command{Name: "test"}, Error: "boom"}, 0)
+
+	if !strings.Contains(buf.String(), "[1] ✗ test failed: boom") {
+		t.Errorf("Expected failure on the output writer when no error writer is set, got: %s", buf.String())
+	}
+}
+
+func TestConsoleReporter_ReportExecutionCompleteTiming(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := NewConsoleReporter(&buf, true)
+
+	status := ExecutionStatus{
+		State: StateSuccess,
+		Results: []ExecutionResult{
+			{
+				Command:      config.Command{Name: "test"},
+				Success:      true,
+				WaitDuration: 250 * time.Millisecond,
+				RunDuration:  1500 * time.Millisecond,
+			},
+		},
+	}
+
+	reporter.ReportExecutionComplete(status)
+
+	output := buf.String()
+	if !strings.Contains(output, "[test] [summary] Waited 250ms, ran 1.5s") {
+		t.Errorf("Expected timing breakdown in verbose summary, got: %s", output)
+	}
+}
+
+func TestConsoleReporter_NoSummary(t *testing.T) {
+	status := ExecutionStatus{
+		Results: []ExecutionResult{
+			{Command: config.Command{Name: "test"}, Success: true, RunDuration: time.Second},
+		},
+	}
+
+	for _, state := range []ExecutionState{StateSuccess, StateFailed} {
+		for _, verbose := range []bool{false, true} {
+			var out, errOut bytes.Buffer
+			reporter := NewConsoleReporterWithOptions(ConsoleReporterOptions{
+				Writer:    &out,
+				ErrWriter: &errOut,
+				Verbose:   verbose,
+				NoSummary: true,
+			})
+
+			status.State = state
+			status.LastError = "exit status 1"
+			reporter.ReportExecutionComplete(status)
+
+			if out.Len() != 0 || errOut.Len() != 0 {
+				t.Errorf("Expected no summary for a %s run (verbose=%v), got %q and %q", state, verbose, out.String(), errOut.String())
+			}
+		}
+	}
+}
+
+func TestExecutor_NoSummary(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sh")
+	}
+
+	cfg := &config.Config{
+		Version:  "1.0",
+		Commands: []config.Command{{Name: "emit", Command: "sh", Args: []string{"-c", "echo result=42"}, Mode: config.ModeOnce}},
+	}
+
+	var err error
+	output := captureOutput(func() {
+		executor := NewExecutorWithOptions(ExecutorOptions{
+			Verbose:  true,
+			Reporter: NewConsoleReporterWithOptions(ConsoleReporterOptions{Writer: os.Stdout, Verbose: true, NoSummary: true}),
+		})
+		err = executor.Execute(context.Background(), cfg)
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if !strings.Contains(output, "result=42") {
+		t.Errorf("Expected the command's output to be streamed, got:\n%s", output)
+	}
+	if strings.Contains(output, "All commands completed successfully") || strings.Contains(output, "[summary] Waited") {
+		t.Errorf("Expected no final summary, got:\n%s", output)
+	}
+}
+
+func TestExecutor_SetReporterDuringRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sh")
+	}
+
+	var commands []config.Command
+	for i := 0; i < 8; i++ {
+		commands = append(commands, config.Command{
+			Name:       fmt.Sprintf("job-%d", i),
+			Command:    "sh",
+			Args:       []string{"-c", "sleep 0.02"},
+			Mode:       config.ModeOnce,
+			Concurrent: i%2 == 0,
+		})
+	}
+	cfg := &config.Config{Version: "1.0", Commands: commands}
+
+	first, second := &syncBuffer{}, &syncBuffer{}
+	reporters := []Reporter{NewConsoleReporter(first, false), NewConsoleReporter(second, false)}
+	executor := NewExecutorWithOptions(ExecutorOptions{Reporter: reporters[0]})
+
+	// Keep swapping reporters until the run finishes
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 1; ; i++ {
+			select {
+			case <-done:
+				return
+			case <-time.After(5 * time.Millisecond):
+				executor.SetReporter(reporters[i%2])
+			}
+		}
+	}()
+
+	err := executor.Execute(context.Background(), cfg)
+	close(done)
+	wg.Wait()
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	// Every command is reported exactly once, on whichever reporter was current at the time
+	combined := first.String() + second.String()
+	for _, cmd := range commands {
+		if got := strings.Count(combined, "✓ "+cmd.Name+" "); got != 1 {
+			t.Errorf("Expected one success line for %s across both reporters, got %d:\n%s", cmd.Name, got, combined)
+		}
+	}
+
+	// A nil reporter is ignored rather than breaking later reports
+	third := NewConsoleReporter(&syncBuffer{}, false)
+	executor.SetReporter(third)
+	executor.SetReporter(nil)
+	if executor.currentReporter() != third {
+		t.Error("Expected SetReporter(nil) to keep the current reporter")
+	}
+}
+
+func TestConsoleReporter_SummaryOutputLines(t *testing.T) {
+	result := ExecutionResult{
+		Command: config.Command{Name: "build"},
+		Success: true,
+		Output:  "one\ntwo\nthree\nfour\nfive\n",
+	}
+
+	tests := []struct {
+		name     string
+		reporter func(w *bytes.Buffer) *ConsoleReporter
+		contains []string
+		absent   []string
+	}{
+		{
+			name:     "default shows three lines",
+			reporter: func(w *bytes.Buffer) *ConsoleReporter { return NewConsoleReporter(w, true) },
+			contains: []string{"Output: one\ntwo\nthree\n... (2 more lines)\n"},
+			absent:   []string{"four"},
+		},
+		{
+			name: "zero shows all lines",
+			reporter: func(w *bytes.Buffer) *ConsoleReporter {
+				return NewConsoleReporterWithOptions(ConsoleReporterOptions{Writer: w, Verbose: true, SummaryOutputLines: 0})
+			},
+			contains: []string{"Output: one\ntwo\nthree\nfour\nfive\n"},
+			absent:   []string{"more lines"},
+		},
+		{
+			name: "custom count",
+			reporter: func(w *bytes.Buffer) *ConsoleReporter {
+				return NewConsoleReporterWithOptions(ConsoleReporterOptions{Writer: w, Verbose: true, SummaryOutputLines: 1})
+			},
+			contains: []string{"Output: one\n... (4 more lines)\n"},
+			absent:   []string{"two"},
+		},
+		{
+			name: "count covering all lines",
+			reporter: func(w *bytes.Buffer) *ConsoleReporter {
+				return NewConsoleReporterWithOptions(ConsoleReporterOptions{Writer: w, Verbose: true, SummaryOutputLines: 5})
+			},
+			contains: []string{"Output: one\ntwo\nthree\nfour\nfive\n"},
+			absent:   []string{"more lines"},
+		},
+		{
+			name: "minus one shows none",
+			reporter: func(w *bytes.Buffer) *ConsoleReporter {
+				return NewConsoleReporterWithOptions(ConsoleReporterOptions{Writer: w, Verbose: true, SummaryOutputLines: -1})
+			},
+			contains: []string{"[1] ✓ build"},
+			absent:   []string{"Output:", "one"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.reporter(&buf).ReportCommandSuccess(result, 0)
+
+			output := buf.String()
+			for _, expected := range tt.contains {
+				if !strings.Contains(output, expected) {
+					t.Errorf("Expected %q in output, got:\n%s", expected, output)
+				}
+			}
+			for _, unexpected := range tt.absent {
+				if strings.Contains(output, unexpected) {
+					t.Errorf("Expected %q to be absent, got:\n%s", unexpected, output)
+				}
+			}
+		})
+	}
+}
+
+func TestConsoleReporter_ShowOutputOnFailure(t *testing.T) {
+	result := ExecutionResult{
+		Command: config.Command{Name: "build"},
+		Success: false,
+		Error:   "exit status 2",
+		Output:  "compiling\nmain.go:3: undefined: foo",
+	}
+
+	// Off by default, the output was never streamed without verbose mode
+	var buf bytes.Buffer
+	NewConsoleReporter(&buf, false).ReportCommandFailure(result, 0)
+	if strings.Contains(buf.String(), "undefined: foo") {
+		t.Errorf("Expected no output replay by default, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	reporter := NewConsoleReporterWithOptions(ConsoleReporterOptions{Writer: &buf, ShowOutputOnFailure: true})
+	reporter.ReportCommandFailure(result, 0)
+	expected := "[1] ✗ build failed: exit status 2\n--- output of build ---\ncompiling\nmain.go:3: undefined: foo\n--- end of output ---\n"
+	if buf.String() != expected {
+		t.Errorf("Expected failed output to be replayed, got:\n%s", buf.String())
+	}
+
+	// Long output keeps its end, from the first complete line
+	buf.Reset()
+	reporter = NewConsoleReporterWithOptions(ConsoleReporterOptions{Writer: &buf, ShowOutputOnFailure: true, MaxOutputBytes: 30})
+	reporter.ReportCommandFailure(result, 0)
+	if !strings.Contains(buf.String(), "... (10 bytes omitted)\nmain.go:3: undefined: foo\n") {
+		t.Errorf("Expected output truncated to its last line, got:\n%s", buf.String())
+	}
+}
+
+func TestTruncateBytesKeepsRunesWhole(t *testing.T) {
+	// Each é is two bytes, so the last 5 bytes start in the middle of one
+	got := truncateBytes("ééééé", 5)
+	if !utf8.ValidString(got) || !strings.HasSuffix(got, "\néé") {
+		t.Errorf("Expected the tail to start at a character boundary, got %q", got)
+	}
+	if !strings.HasPrefix(got, "... (6 bytes omitted)") {
+		t.Errorf("Expected the split character to count as omitted, got %q", got)
+	}
+}
+
+func TestConsoleReporter_ReportStageStart(t *testing.T) {
+	var buf bytes.Buffer
+	NewConsoleReporter(&buf, false).ReportStageStart("build")
+
+	if buf.String() != "=== Stage: build ===\n" {
+		t.Errorf("Expected stage header, got: %q", buf.String())
+	}
+}
+
+func TestExecutor_StageHeaders(t *testing.T) {
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "compile", Command: "echo", Args: []string{"compile"}, Mode: config.ModeOnce, Stage: "build"},
+			{Name: "bundle", Command: "echo", Args: []string{"bundle"}, Mode: config.ModeOnce, Stage: "build"},
+			{Name: "unit", Command: "echo", Args: []string{"unit"}, Mode: config.ModeOnce, Stage: "test", Concurrent: true},
+			{Name: "lint", Command: "echo", Args: []string{"lint"}, Mode: config.ModeOnce, Stage: "test", Concurrent: true},
+			{Name: "report", Command: "echo", Args: []string{"report"}, Mode: config.ModeOnce},
+			{Name: "package", Command: "echo", Args: []string{"package"}, Mode: config.ModeOnce, Stage: "build"},
+		},
+	}
+
+	var buf bytes.Buffer
+	executor := NewExecutor(false)
+	executor.SetReporter(NewConsoleReporter(&buf, false))
+	captureOutput(func() {
+		if err := executor.Execute(context.Background(), cfg); err != nil {
+			t.Fatalf("Execute returned error: %v", err)
+		}
+	})
+
+	output := buf.String()
+	if got := strings.Count(output, "=== Stage: build ==="); got != 2 {
+		t.Errorf("Expected the build header once for each of its two runs of commands, got %d:\n%s", got, output)
+	}
+	if got := strings.Count(output, "=== Stage: test ==="); got != 1 {
+		t.Errorf("Expected the test header once for the concurrent group, got %d:\n%s", got, output)
+	}
+
+	first := strings.Index(output, "=== Stage: build ===")
+	if first < 0 || first > strings.Index(output, "Starting: compile") {
+		t.Errorf("Expected the build header before its first command, got:\n%s", output)
+	}
+	if strings.LastIndex(output, "=== Stage: build ===") < strings.Index(output, "Starting: report") {
+		t.Errorf("Expected the build header again after the command without a stage, got:\n%s", output)
+	}
+}
+
+func TestConsoleReporter_Compact(t *testing.T) {
+	build := ExecutionResult{Command: config.Command{Name: "build"}, Success: true, Duration: 1500 * time.Millisecond}
+	test := ExecutionResult{Command: config.Command{Name: "test"}, Success: false, Error: "exit status 1"}
+
+	var buf bytes.Buffer
+	reporter := NewConsoleReporterWithOptions(ConsoleReporterOptions{Writer: &buf, Compact: true, AssumeTerminal: true})
+	reporter.ReportStart(3)
+	reporter.ReportCommandStart("build", 0)
+	reporter.ReportCommandSuccess(build, 0)
+	reporter.ReportCommandStart("test", 1)
+	reporter.ReportCommandFailure(test, 1)
+	reporter.ReportExecutionComplete(ExecutionStatus{State: StateFailed, LastError: "exit status 1"})
+
+	expected := "\r[1/3] running build...\033[K" +
+		"\r[1/3] ✓ build (1.5s)\033[K" +
+		"\r[2/3] running test...\033[K" +
+		"\r\033[K[2] ✗ test failed: exit status 1\n" +
+		"Execution failed: exit status 1\n"
+	if buf.String() != expected {
+		t.Errorf("Expected the status line to be updated in place, got:\n%q\nwant:\n%q", buf.String(), expected)
+	}
+
+	// A successful run keeps the last status line, ending it with a newline
+	buf.Reset()
+	reporter = NewConsoleReporterWithOptions(ConsoleReporterOptions{Writer: &buf, Compact: true, AssumeTerminal: true})
+	reporter.ReportStart(1)
+	reporter.ReportCommandStart("build", 0)
+	reporter.ReportCommandSuccess(build, 0)
+	reporter.ReportExecutionComplete(ExecutionStatus{State: StateSuccess})
+	if !strings.HasSuffix(buf.String(), "\r[1/1] ✓ build (1.5s)\033[K\nAll commands completed successfully\n") {
+		t.Errorf("Expected the final status line to be kept, got: %q", buf.String())
+	}
+}
+
+func TestConsoleReporter_CompactFallsBack(t *testing.T) {
+	build := ExecutionResult{Command: config.Command{Name: "build"}, Success: true, Duration: 1500 * time.Millisecond}
+
+	tests := []struct {
+		name string
+		opts ConsoleReporterOptions
+	}{
+		{name: "not a terminal", opts: ConsoleReporterOptions{Compact: true}},
+		{name: "verbose", opts: ConsoleReporterOptions{Compact: true, AssumeTerminal: true, Verbose: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.opts.Writer = &buf
+			reporter := NewConsoleReporterWithOptions(tt.opts)
+			reporter.ReportStart(1)
+			reporter.ReportCommandStart("build", 0)
+			reporter.ReportCommandSuccess(build, 0)
+
+			if strings.Contains(buf.String(), "\r") {
+				t.Errorf("Expected no in-place updates, got: %q", buf.String())
+			}
+			if !strings.Contains(buf.String(), "[1] Starting: build\n[1] ✓ build (1.5s)\n") {
+				t.Errorf("Expected the regular start and result lines, got: %q", buf.String())
+			}
+		})
+	}
+}
+
+func TestConsoleReporter_Spinner(t *testing.T) {
+	build := ExecutionResult{Command: config.Command{Name: "build"}, Success: true, Duration: 350 * time.Millisecond}
+
+	var buf syncBuffer
+	reporter := NewConsoleReporterWithOptions(ConsoleReporterOptions{Writer: &buf, Spinner: true, AssumeTerminal: true})
+	reporter.ReportStart(1)
+	reporter.ReportCommandStart("build", 0)
+	time.Sleep(3*spinnerInterval + spinnerInterval/2)
+	reporter.ReportCommandSuccess(build, 0)
+	reporter.ReportExecutionComplete(ExecutionStatus{State: StateSuccess})
+
+	output := buf.String()
+	for _, frame := range []string{"\r⠋ build (0s)\033[K", "\r⠙ build (0s)\033[K", "\r⠹ build (0s)\033[K"} {
+		if !strings.Contains(output, frame) {
+			t.Errorf("Expected spinner frame %q, got: %q", frame, output)
+		}
+	}
+	if !strings.HasPrefix(output, "[1] Starting: build\n") {
+		t.Errorf("Expected the start line before the spinner, got: %q", output)
+	}
+	if !strings.HasSuffix(output, "\r\033[K[1] ✓ build (350ms)\nAll commands completed successfully\n") {
+		t.Errorf("Expected the spinner to be erased before the result line, got: %q", output)
+	}
+
+	// No frame is drawn once the command has finished
+	time.Sleep(2 * spinnerInterval)
+	if buf.String() != output {
+		t.Errorf("Expected the spinner to stop with the command, got: %q", buf.String())
+	}
+}
+
+func TestConsoleReporter_SpinnerNeedsTerminal(t *testing.T) {
+	build := ExecutionResult{Command: config.Command{Name: "build"}, Success: true, Duration: 350 * time.Millisecond}
+
+	tests := []struct {
+		name string
+		opts ConsoleReporterOptions
+	}{
+		{name: "not a terminal", opts: ConsoleReporterOptions{Spinner: true}},
+		{name: "verbose", opts: ConsoleReporterOptions{Spinner: true, AssumeTerminal: true, Verbose: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf syncBuffer
+			tt.opts.Writer = &buf
+			reporter := NewConsoleReporterWithOptions(tt.opts)
+			reporter.ReportStart(1)
+			reporter.ReportCommandStart("build", 0)
+			time.Sleep(2 * spinnerInterval)
+			reporter.ReportCommandSuccess(build, 0)
+
+			if strings.Contains(buf.String(), "\r") {
+				t.Errorf("Expected no spinner, got: %q", buf.String())
+			}
+		})
+	}
+}
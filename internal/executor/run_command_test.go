@@ -0,0 +1,81 @@
+package executor
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestExecutor_RunCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sh")
+	}
+
+	t.Run("once success", func(t *testing.T) {
+		executor := NewExecutor(false)
+		cmd := config.Command{
+			Name:    "greet",
+			Command: "sh",
+			Args:    []string{"-c", `echo "hello $NAME from $(basename "$PWD")"`},
+			Mode:    config.ModeOnce,
+			WorkDir: t.TempDir(),
+			Env:     map[string]string{"NAME": "seqr"},
+		}
+
+		result, err := executor.RunCommand(context.Background(), cmd)
+		if err != nil {
+			t.Fatalf("RunCommand failed: %v", err)
+		}
+		if !result.Success || result.ExitCode != 0 {
+			t.Errorf("Expected a successful result, got %+v", result)
+		}
+		if want := "hello seqr from 001"; result.Output != want {
+			t.Errorf("Expected output %q, got %q", want, result.Output)
+		}
+		if len(executor.GetStatus().Results) != 0 {
+			t.Error("Expected the executor's status to be left alone")
+		}
+	})
+
+	t.Run("once failure", func(t *testing.T) {
+		executor := NewExecutor(false)
+		cmd := config.Command{Name: "fail", Command: "sh", Args: []string{"-c", "echo broken; exit 3"}, Mode: config.ModeOnce}
+
+		result, err := executor.RunCommand(context.Background(), cmd)
+		if err == nil {
+			t.Fatal("Expected RunCommand to fail")
+		}
+		if result.Success || result.ExitCode != 3 || result.Output != "broken" {
+			t.Errorf("Expected a failed result with exit code 3 and its output, got %+v", result)
+		}
+		if result.ErrorDetail == nil || result.ErrorDetail.Type != ErrorTypeExitCode {
+			t.Errorf("Expected error type %q, got %+v", ErrorTypeExitCode, result.ErrorDetail)
+		}
+	})
+
+	t.Run("keepAlive start", func(t *testing.T) {
+		executor := NewExecutor(false)
+		cmd := config.Command{Name: "server", Command: "sleep", Args: []string{"30"}, Mode: config.ModeKeepAlive}
+
+		result, err := executor.RunCommand(context.Background(), cmd)
+		if err != nil {
+			t.Fatalf("RunCommand failed: %v", err)
+		}
+		defer executor.Stop()
+
+		if !result.Success || !strings.HasPrefix(result.Output, "started with PID") {
+			t.Errorf("Expected a started keepAlive result, got %+v", result)
+		}
+		if !executor.HasActiveKeepAliveProcesses() {
+			t.Error("Expected the keepAlive process to keep running after RunCommand returns")
+		}
+
+		executor.Stop()
+		if executor.HasActiveKeepAliveProcesses() {
+			t.Error("Expected Stop to terminate the keepAlive process")
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+)
+
+// CommandRunner starts the processes of the queue's commands and waits for them to exit. The
+// executor prepares each *exec.Cmd, its arguments, directory, environment and output writers,
+// and hands it to the runner. ExecRunner, the default, runs it with os/exec; tests can inject a
+// runner that simulates exit codes, output and timing without starting real processes.
+//
+// Commands with a pty always run through os/exec.
+type CommandRunner interface {
+	// Start starts execCmd, which writes its output to execCmd.Stdout and execCmd.Stderr. The
+	// command must be stopped when ctx ends, as exec.CommandContext does. For keepAlive
+	// commands, execCmd.Process must be set once Start succeeds, as they are tracked and
+	// stopped by PID.
+	Start(ctx context.Context, execCmd *exec.Cmd) error
+
+	// Wait waits for a started execCmd to exit. An unsuccessful exit is reported with an error
+	// that has an ExitCode() int method, such as *exec.ExitError.
+	Wait(execCmd *exec.Cmd) error
+}
+
+// ExecRunner is the CommandRunner that runs commands as real processes with os/exec
+type ExecRunner struct{}
+
+// Start starts execCmd with os/exec; the context it was created with stops it
+func (ExecRunner) Start(ctx context.Context, execCmd *exec.Cmd) error {
+	return execCmd.Start()
+}
+
+// Wait waits for execCmd to exit
+func (ExecRunner) Wait(execCmd *exec.Cmd) error {
+	return execCmd.Wait()
+}
+
+// exitCodeOf returns the exit code carried by a command's error, or -1 when the command did not
+// exit with one, e.g. because it failed to start or was killed by a signal
+func exitCodeOf(err error) int {
+	var exitErr interface{ ExitCode() int }
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
@@ -0,0 +1,179 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+// fakeProcess describes how a fake command behaves
+type fakeProcess struct {
+	output   string        // Written to stdout when the command starts
+	exitCode int           // Non-zero makes Wait fail with a fakeExitError
+	runFor   time.Duration // How long the command runs before exiting, unless its context ends first
+	startErr error         // Returned by Start instead of starting the command
+}
+
+// fakeExitError is the error of a fake command that exited unsuccessfully
+type fakeExitError struct {
+	code int
+}
+
+func (e *fakeExitError) Error() string { return fmt.Sprintf("exit status %d", e.code) }
+func (e *fakeExitError) ExitCode() int { return e.code }
+
+// fakeRunner simulates commands by name without starting any process
+type fakeRunner struct {
+	processes map[string]fakeProcess
+
+	mu      sync.Mutex
+	exits   map[*exec.Cmd]chan error
+	started []string
+}
+
+func newFakeRunner(processes map[string]fakeProcess) *fakeRunner {
+	return &fakeRunner{processes: processes, exits: make(map[*exec.Cmd]chan error)}
+}
+
+func (r *fakeRunner) Start(ctx context.Context, execCmd *exec.Cmd) error {
+	name := execCmd.Args[0]
+	process, ok := r.processes[name]
+	if !ok {
+		return fmt.Errorf("fake runner: unknown command %q", name)
+	}
+	if process.startErr != nil {
+		return process.startErr
+	}
+
+	r.mu.Lock()
+	r.started = append(r.started, name)
+	exit := make(chan error, 1)
+	r.exits[execCmd] = exit
+	r.mu.Unlock()
+
+	// Written before Start returns: the executor closes its copy of a pipe's write end after Start
+	if process.output != "" {
+		fmt.Fprintln(execCmd.Stdout, process.output)
+	}
+
+	go func() {
+		select {
+		case <-time.After(process.runFor):
+			if process.exitCode != 0 {
+				exit <- &fakeExitError{code: process.exitCode}
+				return
+			}
+			exit <- nil
+		case <-ctx.Done():
+			exit <- errors.New("signal: killed")
+		}
+	}()
+	return nil
+}
+
+func (r *fakeRunner) Wait(execCmd *exec.Cmd) error {
+	r.mu.Lock()
+	exit := r.exits[execCmd]
+	r.mu.Unlock()
+	return <-exit
+}
+
+func TestExecutor_RunnerFailure(t *testing.T) {
+	for _, verbose := range []bool{false, true} {
+		t.Run(fmt.Sprintf("verbose=%v", verbose), func(t *testing.T) {
+			runner := newFakeRunner(map[string]fakeProcess{
+				"fake-build": {output: "main.go:3: undefined: foo", exitCode: 2},
+				"fake-test":  {},
+			})
+			cfg := &config.Config{
+				Version: "1.0",
+				Commands: []config.Command{
+					{Name: "build", Command: "fake-build", Mode: config.ModeOnce},
+					{Name: "test", Command: "fake-test", Mode: config.ModeOnce},
+				},
+			}
+
+			executor := NewExecutorWithOptions(ExecutorOptions{Verbose: verbose, Runner: runner})
+			var err error
+			captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+			if err == nil {
+				t.Fatal("Expected the run to fail")
+			}
+
+			result := executor.GetStatus().Results[0]
+			if result.Success || result.ExitCode != 2 || result.Error != "exit status 2" {
+				t.Errorf("Expected build to fail with exit code 2, got %+v", result)
+			}
+			if result.Output != "main.go:3: undefined: foo" {
+				t.Errorf("Expected the fake output to be captured, got %q", result.Output)
+			}
+			if result.ErrorDetail == nil || result.ErrorDetail.Type != ErrorTypeExitCode {
+				t.Errorf("Expected error type %q, got %+v", ErrorTypeExitCode, result.ErrorDetail)
+			}
+			if len(runner.started) != 1 {
+				t.Errorf("Expected only build to start, got %v", runner.started)
+			}
+		})
+	}
+}
+
+func TestExecutor_RunnerTimeout(t *testing.T) {
+	runner := newFakeRunner(map[string]fakeProcess{
+		"fake-slow": {runFor: time.Minute},
+	})
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "slow", Command: "fake-slow", Mode: config.ModeOnce},
+		},
+	}
+
+	executor := NewExecutorWithOptions(ExecutorOptions{Runner: runner, Timeout: 50 * time.Millisecond})
+	start := time.Now()
+	var err error
+	captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+
+	if err == nil || !strings.Contains(err.Error(), "command 'slow' timed out after 50ms") {
+		t.Fatalf("Expected the command to time out, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the timeout to end the command early, took %v", elapsed)
+	}
+	if result := executor.GetStatus().Results[0]; result.ExitCode != -1 {
+		t.Errorf("Expected no exit code for a killed command, got %d", result.ExitCode)
+	}
+}
+
+func TestExecutor_RunnerKeepAliveStartFailure(t *testing.T) {
+	runner := newFakeRunner(map[string]fakeProcess{
+		"fake-server": {startErr: exec.ErrNotFound},
+	})
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "server", Command: "fake-server", Mode: config.ModeKeepAlive},
+		},
+	}
+
+	executor := NewExecutorWithOptions(ExecutorOptions{Runner: runner})
+	var err error
+	captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+	if err == nil {
+		t.Fatal("Expected the run to fail")
+	}
+
+	result := executor.GetStatus().Results[0]
+	if result.Success || result.ExitCode != -1 {
+		t.Errorf("Expected the start failure to be reported, got %+v", result)
+	}
+	if result.ErrorDetail == nil || result.ErrorDetail.Type != ErrorTypeCommandNotFound {
+		t.Errorf("Expected error type %q, got %+v", ErrorTypeCommandNotFound, result.ErrorDetail)
+	}
+}
@@ -0,0 +1,83 @@
+package executor
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"time"
+)
+
+// ForwardedSignals returns the parent signals a config's signalForwarding section relays to at
+// least one command, of those this platform can forward
+func ForwardedSignals(forwarding map[string][]string) []os.Signal {
+	var signals []os.Signal
+	for _, name := range slices.Sorted(maps.Keys(forwarding)) {
+		if sig, ok := forwardableSignals[name]; ok && len(forwarding[name]) > 0 {
+			signals = append(signals, sig)
+		}
+	}
+	return signals
+}
+
+// signalName returns the configuration name of a forwardable signal
+func signalName(sig os.Signal) (string, bool) {
+	for name, candidate := range forwardableSignals {
+		if candidate == sig {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// ForwardSignal relays a parent signal to the process groups of the keepAlive commands
+// configured for it in the config's signalForwarding section
+func (e *Executor) ForwardSignal(sig os.Signal) error {
+	name, ok := signalName(sig)
+	if !ok {
+		return fmt.Errorf("signal %v cannot be forwarded", sig)
+	}
+
+	e.mu.RLock()
+	targets := e.signalForwarding[name]
+	pids := make(map[string]int, len(targets))
+	for _, target := range targets {
+		if cmd, exists := e.processes[target]; exists && cmd.Process != nil {
+			pids[target] = cmd.Process.Pid
+		}
+	}
+	e.mu.RUnlock()
+
+	var firstErr error
+	for _, target := range targets {
+		pid, running := pids[target]
+		if !running {
+			if e.verbose {
+				timestamp := time.Now().Format("15:04:05.000")
+				fmt.Printf("[%s] [%s] [signal] Not running, %s not forwarded\n", timestamp, target, name)
+			}
+			continue
+		}
+
+		if err := e.signalProcessGroup(pid, sig); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to forward %s to '%s' (PID %d): %w", name, target, pid, err)
+			}
+			continue
+		}
+
+		if e.verbose {
+			timestamp := time.Now().Format("15:04:05.000")
+			fmt.Printf("[%s] [%s] [signal] Forwarded %s to process group (PID %d)\n", timestamp, target, name, pid)
+			os.Stdout.Sync()
+		}
+	}
+
+	return firstErr
+}
+
+// signalProcessGroup sends a signal to an entire process group using platform-specific methods
+func (e *Executor) signalProcessGroup(pid int, sig os.Signal) error {
+	// The actual implementation is in platform-specific files
+	return e.signalProcessGroupPlatform(pid, sig)
+}
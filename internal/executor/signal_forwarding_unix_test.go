@@ -0,0 +1,91 @@
+//go:build !windows
+
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestForwardSignalReachesKeepAliveChild(t *testing.T) {
+	markerFile := filepath.Join(t.TempDir(), "usr1-received")
+
+	executor := NewExecutor(false)
+	defer executor.Stop()
+
+	script := `trap 'echo received > "$MARKER"; exit 0' USR1; while true; do sleep 0.1; done`
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{
+				Name:    "rotating-service",
+				Command: "sh",
+				Args:    []string{"-c", script},
+				Mode:    config.ModeKeepAlive,
+				Env:     map[string]string{"MARKER": markerFile},
+			},
+		},
+		SignalForwarding: map[string][]string{
+			"SIGUSR1": {"rotating-service"},
+		},
+	}
+
+	if err := executor.Execute(context.Background(), cfg); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	// Give the shell time to install its trap
+	time.Sleep(200 * time.Millisecond)
+
+	if err := executor.ForwardSignal(syscall.SIGUSR1); err != nil {
+		t.Fatalf("ForwardSignal failed: %v", err)
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		if data, err := os.ReadFile(markerFile); err == nil && strings.TrimSpace(string(data)) == "received" {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatal("Forwarded SIGUSR1 did not reach the keepAlive child")
+}
+
+func TestForwardedSignals(t *testing.T) {
+	forwarding := map[string][]string{
+		"SIGUSR2": {"api"},
+		"SIGUSR1": {"worker", "api"},
+		"SIGHUP":  {"api"},
+	}
+	signals := ForwardedSignals(forwarding)
+	if len(signals) != 2 || signals[0] != syscall.SIGUSR1 || signals[1] != syscall.SIGUSR2 {
+		t.Errorf("Expected SIGUSR1 and SIGUSR2, got %v", signals)
+	}
+
+	if signals := ForwardedSignals(map[string][]string{"SIGUSR1": {}}); len(signals) != 0 {
+		t.Errorf("Expected a signal without commands not to be forwarded, got %v", signals)
+	}
+	if signals := ForwardedSignals(nil); len(signals) != 0 {
+		t.Errorf("Expected no signals without signalForwarding, got %v", signals)
+	}
+}
+
+func TestForwardSignalIgnoresUnmappedSignal(t *testing.T) {
+	executor := NewExecutor(false)
+
+	// SIGUSR2 is forwardable but not mapped to any command
+	if err := executor.ForwardSignal(syscall.SIGUSR2); err != nil {
+		t.Errorf("Expected unmapped signal to be a no-op, got: %v", err)
+	}
+
+	if err := executor.ForwardSignal(syscall.SIGHUP); err == nil {
+		t.Error("Expected error for a signal that cannot be forwarded")
+	}
+}
@@ -0,0 +1,116 @@
+package executor
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// spinnerFrames are drawn in turn, one per spinnerInterval
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// spinnerInterval is how often the spinner is redrawn; commands finishing sooner never show it
+const spinnerInterval = 100 * time.Millisecond
+
+// runningCommand is a command the spinner shows while it runs
+type runningCommand struct {
+	name    string
+	index   int
+	started time.Time
+}
+
+// startSpinner adds a command to the spinner, starting the animation if nothing else runs
+func (r *ConsoleReporter) startSpinner(commandName string, commandIndex int) {
+	if !r.spinner {
+		return
+	}
+
+	r.statusMu.Lock()
+	defer r.statusMu.Unlock()
+	r.running = append(r.running, runningCommand{name: commandName, index: commandIndex, started: time.Now()})
+	if r.spinnerDone == nil {
+		r.spinnerDone = make(chan struct{})
+		go r.animateSpinner(r.spinnerDone)
+	}
+}
+
+// stopSpinner removes a finished command from the spinner, stopping the animation and erasing
+// its frame once nothing runs
+func (r *ConsoleReporter) stopSpinner(commandName string) {
+	if !r.spinner {
+		return
+	}
+
+	r.statusMu.Lock()
+	defer r.statusMu.Unlock()
+	r.running = slices.DeleteFunc(r.running, func(cmd runningCommand) bool { return cmd.name == commandName })
+	if len(r.running) == 0 {
+		r.haltSpinner()
+	}
+}
+
+// stopAllSpinners stops the animation at the end of the run, whatever was left running
+func (r *ConsoleReporter) stopAllSpinners() {
+	if !r.spinner {
+		return
+	}
+
+	r.statusMu.Lock()
+	defer r.statusMu.Unlock()
+	r.running = nil
+	r.haltSpinner()
+}
+
+// haltSpinner stops the animation and erases its last frame; statusMu must be held
+func (r *ConsoleReporter) haltSpinner() {
+	if r.spinnerDone != nil {
+		close(r.spinnerDone)
+		r.spinnerDone = nil
+	}
+	if r.spinnerShown {
+		r.eraseStatus()
+	}
+}
+
+// animateSpinner redraws the status line with the next frame until done is closed
+func (r *ConsoleReporter) animateSpinner(done <-chan struct{}) {
+	ticker := time.NewTicker(spinnerInterval)
+	defer ticker.Stop()
+
+	for frame := 0; ; frame++ {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		r.statusMu.Lock()
+		select {
+		case <-done:
+			// Stopped while waiting for the lock; the frame must not outlive the command
+			r.statusMu.Unlock()
+			return
+		default:
+		}
+		fmt.Fprintf(r.writer, "\r%s\033[K", r.spinnerText(spinnerFrames[frame%len(spinnerFrames)]))
+		r.statusShown = true
+		r.spinnerShown = true
+		r.statusMu.Unlock()
+	}
+}
+
+// spinnerText describes the longest-running command, e.g. "⠹ build (12s)", noting how many
+// others run alongside it; statusMu must be held
+func (r *ConsoleReporter) spinnerText(frame string) string {
+	cmd := r.running[0]
+	name := cmd.name
+	if others := len(r.running) - 1; others > 0 {
+		name = fmt.Sprintf("%s and %d more", name, others)
+	}
+	elapsed := time.Since(cmd.started).Truncate(time.Second)
+
+	if r.compact {
+		return fmt.Sprintf("[%d/%d] %s running %s... (%v)", cmd.index+1, r.totalCommands, frame, name, elapsed)
+	}
+	return fmt.Sprintf("%s %s (%v)", frame, name, elapsed)
+}
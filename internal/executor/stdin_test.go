@@ -0,0 +1,49 @@
+package executor
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestExecutor_StdinIsAtEOF(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sh")
+	}
+	t.Setenv("TMPDIR", t.TempDir())
+
+	for _, verbose := range []bool{false, true} {
+		name := "captured"
+		if verbose {
+			name = "streamed"
+		}
+		t.Run(name, func(t *testing.T) {
+			// read fails at end of input; were stdin left open, it would wait for the timeout
+			cfg := &config.Config{
+				Version: "1.0",
+				Commands: []config.Command{
+					{Name: "reader", Command: "sh", Args: []string{"-c", "if read line; then echo got input; else echo eof; fi; cat"}, Mode: config.ModeOnce},
+				},
+			}
+
+			executor := NewExecutorWithOptions(ExecutorOptions{Verbose: verbose, Timeout: 10 * time.Second})
+			start := time.Now()
+			var err error
+			captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+			if err != nil {
+				t.Fatalf("Expected the command to finish at end of input, got %v", err)
+			}
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("Expected end of input promptly, took %v", elapsed)
+			}
+
+			if output := executor.GetStatus().Results[0].Output; !strings.Contains(output, "eof") {
+				t.Errorf("Expected the command to see end of input, got output %q", output)
+			}
+		})
+	}
+}
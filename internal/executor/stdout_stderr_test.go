@@ -53,10 +53,22 @@ func (r *testReporter) ReportStart(totalCommands int) {
 	fmt.Fprintf(r.output, "Starting execution of %d commands\n", totalCommands)
 }
 
+func (r *testReporter) ReportBanner(message string) {
+	fmt.Fprintf(r.output, "Banner: %s\n", message)
+}
+
+func (r *testReporter) ReportStageStart(stage string) {
+	fmt.Fprintf(r.output, "Stage: %s\n", stage)
+}
+
 func (r *testReporter) ReportCommandStart(commandName string, index int) {
 	fmt.Fprintf(r.output, "Starting command %d: %s\n", index+1, commandName)
 }
 
+func (r *testReporter) ReportCommandLine(commandName string, commandLine string) {
+	fmt.Fprintf(r.output, "Command line for %s: %s\n", commandName, commandLine)
+}
+
 func (r *testReporter) ReportCommandSuccess(result ExecutionResult, index int) {
 	fmt.Fprintf(r.output, "Command %d completed successfully\n", index+1)
 }
@@ -68,3 +80,11 @@ func (r *testReporter) ReportCommandFailure(result ExecutionResult, index int) {
 func (r *testReporter) ReportExecutionComplete(status ExecutionStatus) {
 	fmt.Fprintf(r.output, "Execution completed with status: %s\n", status.State)
 }
+
+func (r *testReporter) ReportFooter(message string) {
+	fmt.Fprintf(r.output, "Footer: %s\n", message)
+}
+
+func (r *testReporter) ReportCommandAutoStop(commandName string, lifetime time.Duration) {
+	fmt.Fprintf(r.output, "Command %s auto-stopped after %v\n", commandName, lifetime)
+}
@@ -0,0 +1,63 @@
+//go:build !windows
+
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestStopEscalatesThroughStopSignals(t *testing.T) {
+	markerFile := filepath.Join(t.TempDir(), "stopped-by")
+
+	executor := NewExecutor(false)
+
+	// Ignores SIGTERM and only exits once it receives SIGINT
+	script := `trap '' TERM; trap 'echo SIGINT > "$MARKER"; exit 0' INT; while true; do sleep 0.1; done`
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{
+				Name:    "stubborn-service",
+				Command: "sh",
+				Args:    []string{"-c", script},
+				Mode:    config.ModeKeepAlive,
+				Env:     map[string]string{"MARKER": markerFile},
+				StopSignals: []config.StopSignal{
+					{Signal: "SIGTERM", Timeout: 300 * time.Millisecond},
+					{Signal: "SIGINT", Timeout: 3 * time.Second},
+				},
+			},
+		},
+	}
+
+	if err := executor.Execute(context.Background(), cfg); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	// Give the shell time to install its traps
+	time.Sleep(200 * time.Millisecond)
+
+	start := time.Now()
+	executor.Stop()
+	elapsed := time.Since(start)
+
+	data, err := os.ReadFile(markerFile)
+	if err != nil {
+		t.Fatalf("Process was not stopped by the second signal: %v", err)
+	}
+	if got := strings.TrimSpace(string(data)); got != "SIGINT" {
+		t.Errorf("Expected process to be stopped by SIGINT, got %q", got)
+	}
+
+	// Escalation should stop at SIGINT, well before the default timeout or a SIGKILL
+	if elapsed >= config.DefaultStopTimeout {
+		t.Errorf("Stop took %v, expected escalation to finish after the second signal", elapsed)
+	}
+}
@@ -0,0 +1,54 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// SuspendProcesses stops the process group of every running keepAlive command, so they pause
+// while seqr itself is suspended with Ctrl+Z. Started in groups of their own, they would not
+// get the terminal's SIGTSTP and would keep running. It does nothing on Windows.
+func (e *Executor) SuspendProcesses() error {
+	return e.signalKeepAlives(suspendSignal, "Suspended")
+}
+
+// ResumeProcesses continues the process groups stopped by SuspendProcesses
+func (e *Executor) ResumeProcesses() error {
+	return e.signalKeepAlives(resumeSignal, "Resumed")
+}
+
+// signalKeepAlives sends sig to the process group of every running keepAlive command, returning
+// the first failure
+func (e *Executor) signalKeepAlives(sig os.Signal, action string) error {
+	if sig == nil {
+		return nil
+	}
+
+	e.mu.RLock()
+	pids := make(map[string]int, len(e.processes))
+	for name, cmd := range e.processes {
+		if cmd.Process != nil {
+			pids[name] = cmd.Process.Pid
+		}
+	}
+	e.mu.RUnlock()
+
+	var firstErr error
+	for name, pid := range pids {
+		if err := e.signalProcessGroup(pid, sig); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to send %v to '%s' (PID %d): %w", sig, name, pid, err)
+			}
+			continue
+		}
+
+		if e.verbose {
+			timestamp := time.Now().Format("15:04:05.000")
+			fmt.Printf("[%s] [%s] [signal] %s process group (PID %d)\n", timestamp, name, action, pid)
+			os.Stdout.Sync()
+		}
+	}
+
+	return firstErr
+}
@@ -0,0 +1,92 @@
+//go:build !windows
+
+package executor
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+// processState returns the ps state letter of a process, such as S for sleeping or T for stopped
+func processState(t *testing.T, pid int) string {
+	t.Helper()
+	out, err := exec.Command("ps", "-o", "stat=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		t.Fatalf("Failed to read the state of PID %d: %v", pid, err)
+	}
+	return strings.TrimSpace(string(out))[:1]
+}
+
+// waitForState polls until the process reaches the given ps state letter
+func waitForState(t *testing.T, pid int, state string) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		if processState(t, pid) == state {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("Expected PID %d to reach state %s, it is in %s", pid, state, processState(t, pid))
+}
+
+func TestSuspendAndResumeKeepAliveProcessGroup(t *testing.T) {
+	if _, err := exec.LookPath("ps"); err != nil {
+		t.Skip("Test requires ps")
+	}
+
+	pidFile := filepath.Join(t.TempDir(), "child.pid")
+
+	executor := NewExecutor(false)
+	defer executor.Stop()
+
+	// The shell backgrounds a sleep and waits on it, so the sleep is a grandchild of seqr
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{
+				Name:    "service",
+				Command: "sh",
+				Args:    []string{"-c", `sleep 30 & echo $! > "$PID_FILE"; wait`},
+				Mode:    config.ModeKeepAlive,
+				Env:     map[string]string{"PID_FILE": pidFile},
+			},
+		},
+	}
+
+	var err error
+	captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	var childPID int
+	deadline := time.Now().Add(3 * time.Second)
+	for childPID == 0 && time.Now().Before(deadline) {
+		if data, err := os.ReadFile(pidFile); err == nil {
+			childPID, _ = strconv.Atoi(strings.TrimSpace(string(data)))
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if childPID == 0 {
+		t.Fatal("The keepAlive command did not start its child")
+	}
+
+	if err := executor.SuspendProcesses(); err != nil {
+		t.Fatalf("SuspendProcesses failed: %v", err)
+	}
+	waitForState(t, childPID, "T")
+
+	if err := executor.ResumeProcesses(); err != nil {
+		t.Fatalf("ResumeProcesses failed: %v", err)
+	}
+	waitForState(t, childPID, "S")
+}
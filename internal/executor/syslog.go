@@ -0,0 +1,86 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"slices"
+)
+
+// DefaultSyslogTag is the tag syslog lines are logged under when SyslogOptions.Tag is empty
+const DefaultSyslogTag = "seqr"
+
+// SyslogOptions sends the output of keepAlive commands to the system logger, each command
+// under its own tag
+type SyslogOptions struct {
+	Facility string // Facility name such as "daemon" or "local0"; empty means "user"
+	Tag      string // Each command logs as <Tag>/<name>; empty means DefaultSyslogTag
+
+	// Network and Address reach a remote syslog server, e.g. "udp" and "logs.internal:514".
+	// Both empty use the local system logger.
+	Network string
+	Address string
+}
+
+// syslogFacilities maps facility names to their codes, which are the same on every system
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// SyslogFacilities returns the facility names SyslogOptions.Facility accepts, sorted
+func SyslogFacilities() []string {
+	names := make([]string, 0, len(syslogFacilities))
+	for name := range syslogFacilities {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// syslogSink receives a command's output lines, stdout at informational and stderr at error
+// severity
+type syslogSink interface {
+	Info(line string) error
+	Err(line string) error
+	Close() error
+}
+
+// openSyslog connects a command's output to the system logger, or returns nil when syslog is
+// not configured. A connection that fails, or a platform without syslog, is warned about once
+// and the command runs without it.
+func (e *Executor) openSyslog(commandName string) syslogSink {
+	if e.syslog == nil {
+		return nil
+	}
+
+	facility := e.syslog.Facility
+	if facility == "" {
+		facility = "user"
+	}
+	code, ok := syslogFacilities[facility]
+	if !ok {
+		e.warnSyslog(fmt.Errorf("unknown facility '%s'", facility))
+		return nil
+	}
+
+	tag := e.syslog.Tag
+	if tag == "" {
+		tag = DefaultSyslogTag
+	}
+
+	sink, err := dialSyslog(e.syslog.Network, e.syslog.Address, code, tag+"/"+commandName)
+	if err != nil {
+		e.warnSyslog(err)
+		return nil
+	}
+	return sink
+}
+
+// warnSyslog reports, once per executor, that output is not going to syslog
+func (e *Executor) warnSyslog(err error) {
+	e.syslogWarning.Do(func() {
+		fmt.Fprintf(os.Stderr, "Warning: not sending output to syslog: %v\n", err)
+	})
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+package executor
+
+import "log/syslog"
+
+// dialSyslog connects to the syslog server at network and address, or to the local system
+// logger when both are empty, logging under facility and tag
+func dialSyslog(network, address string, facility int, tag string) (syslogSink, error) {
+	return syslog.Dial(network, address, syslog.Priority(facility<<3)|syslog.LOG_INFO, tag)
+}
@@ -0,0 +1,80 @@
+//go:build !windows
+
+package executor
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestExecutor_SyslogReceivesKeepAliveOutput(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	// A fake syslog server collecting the datagrams it receives
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to start fake syslog server: %v", err)
+	}
+	defer server.Close()
+
+	messages := make(chan string, 16)
+	go func() {
+		buf := make([]byte, 4096)
+		for {
+			n, _, err := server.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			messages <- string(buf[:n])
+		}
+	}()
+
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "web", Command: "sh", Args: []string{"-c", "echo listening; echo degraded >&2; sleep 30"}, Mode: config.ModeKeepAlive},
+		},
+	}
+
+	executor := NewExecutorWithOptions(ExecutorOptions{
+		Syslog: &SyslogOptions{Facility: "local3", Tag: "test", Network: "udp", Address: server.LocalAddr().String()},
+	})
+	var execErr error
+	output := captureOutput(func() { execErr = executor.Execute(context.Background(), cfg) })
+	defer executor.Stop()
+	if execErr != nil {
+		t.Fatalf("Execute failed: %v", execErr)
+	}
+
+	// local3 is facility 19: info is 19*8+6, err is 19*8+3
+	want := map[string]string{"listening": "<158>", "degraded": "<155>"}
+	deadline := time.After(5 * time.Second)
+	for len(want) > 0 {
+		select {
+		case message := <-messages:
+			if !strings.Contains(message, " test/web[") {
+				t.Errorf("Expected the message to be tagged test/web, got %q", message)
+			}
+			for line, priority := range want {
+				if strings.HasSuffix(strings.TrimSpace(message), ": "+line) {
+					if !strings.HasPrefix(message, priority) {
+						t.Errorf("Expected %q to be logged with priority %s, got %q", line, priority, message)
+					}
+					delete(want, line)
+				}
+			}
+		case <-deadline:
+			t.Fatalf("Timed out waiting for syslog lines %v", want)
+		}
+	}
+
+	// Without verbose output, the lines go only to syslog
+	if strings.Contains(output, "listening") || strings.Contains(output, "degraded") {
+		t.Errorf("Expected no streamed output on the console, got:\n%s", output)
+	}
+}
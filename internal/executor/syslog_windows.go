@@ -0,0 +1,10 @@
+//go:build windows
+
+package executor
+
+import "fmt"
+
+// dialSyslog reports that syslog is unavailable, since Windows has no system logger it speaks to
+func dialSyslog(network, address string, facility int, tag string) (syslogSink, error) {
+	return nil, fmt.Errorf("syslog is not supported on Windows")
+}
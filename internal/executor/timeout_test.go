@@ -0,0 +1,109 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestTimeoutDoesNotBoundKeepAliveRuntime(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires the sleep command")
+	}
+
+	var out bytes.Buffer
+	const timeout = 300 * time.Millisecond
+	executor := NewExecutorWithOptions(ExecutorOptions{Reporter: NewConsoleReporter(&out, false), Timeout: timeout})
+	defer executor.Stop()
+
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "server", Command: "sleep", Args: []string{"30"}, Mode: config.ModeKeepAlive},
+			{Name: "setup", Command: "true", Mode: config.ModeOnce},
+		},
+	}
+
+	if err := executor.Execute(context.Background(), cfg); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	time.Sleep(3 * timeout)
+
+	executor.mu.RLock()
+	server, exists := executor.processes["server"]
+	executor.mu.RUnlock()
+	if !exists {
+		t.Fatal("Expected the keepAlive process to still be tracked after the timeout")
+	}
+	if err := server.Process.Signal(syscall.Signal(0)); err != nil {
+		t.Errorf("Expected the keepAlive process to survive past the timeout, got %v", err)
+	}
+}
+
+func TestTimeoutKillsOnceCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires the sleep command")
+	}
+
+	var out bytes.Buffer
+	executor := NewExecutorWithOptions(ExecutorOptions{Reporter: NewConsoleReporter(&out, false), Timeout: 200 * time.Millisecond})
+
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "slow", Command: "sleep", Args: []string{"30"}, Mode: config.ModeOnce},
+		},
+	}
+
+	start := time.Now()
+	err := executor.Execute(context.Background(), cfg)
+	if err == nil || !strings.Contains(err.Error(), "command 'slow' timed out after 200ms") {
+		t.Fatalf("Expected a timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Expected the command to be killed at the timeout, took %v", elapsed)
+	}
+
+	detail := executor.GetStatus().Results[0].ErrorDetail
+	if detail == nil || detail.Type != ErrorTypeContextCancelled {
+		t.Errorf("Expected a %q error, got %+v", ErrorTypeContextCancelled, detail)
+	}
+}
+
+func TestTimeoutBoundsKeepAliveReadiness(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires the sleep command")
+	}
+	t.Setenv("TMPDIR", t.TempDir())
+
+	const timeout = 300 * time.Millisecond
+	executor := NewExecutorWithOptions(ExecutorOptions{Reporter: NewConsoleReporter(&bytes.Buffer{}, false), Timeout: timeout})
+	defer executor.Stop()
+
+	// The readyTimeout alone would wait much longer for a file that never appears
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "server", Command: "sleep", Args: []string{"30"}, Mode: config.ModeKeepAlive, WorkDir: t.TempDir(), ReadyFile: "ready", ReadyTimeout: 30 * time.Second},
+		},
+	}
+
+	start := time.Now()
+	err := executor.Execute(context.Background(), cfg)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the readiness wait to end at the timeout, took %v", elapsed)
+	}
+	if err == nil || !strings.Contains(err.Error(), "keepAlive command 'server' was not ready within the 300ms timeout") {
+		t.Fatalf("Expected a readiness timeout error, got %v", err)
+	}
+	if executor.HasActiveKeepAliveProcesses() {
+		t.Error("Expected the keepAlive process to be stopped after the timeout")
+	}
+}
@@ -0,0 +1,93 @@
+package executor
+
+import (
+	"context"
+	"time"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+// RunSpanName is the name of the span a traced run is wrapped in; each command's span is named
+// after the command
+const RunSpanName = "seqr"
+
+// Tracer starts the spans a run is traced with. It mirrors the part of OpenTelemetry's
+// trace.Tracer the executor needs, so a program can hand it a tracer from its OpenTelemetry
+// TracerProvider through a small adapter, without seqr depending on OpenTelemetry.
+type Tracer interface {
+	// Start starts a span named name as a child of the span in ctx, if any, and returns a
+	// context holding the new span
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is one traced operation, a run or a command
+type Span interface {
+	// SetAttribute records a string, int or bool attribute of the operation
+	SetAttribute(key string, value any)
+
+	// RecordError records err as an event of the operation
+	RecordError(err error)
+
+	// SetStatus marks the operation as succeeded, or as failed with description
+	SetStatus(ok bool, description string)
+
+	// End ends the span
+	End()
+}
+
+// traceRun starts the span of a run, when the executor has a tracer. The returned func ends it
+// with the run's error.
+func (e *Executor) traceRun(ctx context.Context, cfg *config.Config) (context.Context, func(err error)) {
+	if e.tracer == nil {
+		return ctx, func(error) {}
+	}
+
+	ctx, span := e.tracer.Start(ctx, RunSpanName)
+	span.SetAttribute("seqr.commands", len(cfg.Commands))
+	return ctx, func(err error) {
+		endSpan(span, err)
+	}
+}
+
+// executeTracedCommand runs a queued command within a span named after it, when the executor
+// has a tracer, recording how it ended as span attributes
+func (e *Executor) executeTracedCommand(ctx context.Context, cmd config.Command, queuedAt time.Time) (ExecutionResult, error) {
+	if e.tracer == nil {
+		return e.executeQueuedCommand(ctx, cmd, queuedAt)
+	}
+
+	ctx, span := e.tracer.Start(ctx, cmd.Name)
+	result, err := e.executeQueuedCommand(ctx, cmd, queuedAt)
+
+	span.SetAttribute("seqr.command.mode", string(cmd.Mode))
+	span.SetAttribute("seqr.success", result.Success)
+	span.SetAttribute("seqr.exit_code", result.ExitCode)
+	if result.ExitReason != "" {
+		span.SetAttribute("seqr.exit_reason", string(result.ExitReason))
+	}
+	if result.Signal != "" {
+		span.SetAttribute("seqr.signal", result.Signal)
+	}
+	if result.Cached {
+		span.SetAttribute("seqr.cached", true)
+	}
+	if result.ErrorDetail != nil {
+		span.SetAttribute("seqr.error.type", string(result.ErrorDetail.Type))
+	}
+	if result.AllowedFailure {
+		span.SetAttribute("seqr.allowed_failure", true)
+	}
+	endSpan(span, err)
+	return result, err
+}
+
+// endSpan sets the status of a span from the error of its operation and ends it
+func endSpan(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(false, err.Error())
+	} else {
+		span.SetStatus(true, "")
+	}
+	span.End()
+}
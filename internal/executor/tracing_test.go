@@ -0,0 +1,111 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+// memorySpan is a span kept in memory by memoryTracer
+type memorySpan struct {
+	name       string
+	parent     *memorySpan
+	attributes map[string]any
+	errors     []error
+	ok         bool
+	ended      bool
+}
+
+func (s *memorySpan) SetAttribute(key string, value any) { s.attributes[key] = value }
+func (s *memorySpan) RecordError(err error)              { s.errors = append(s.errors, err) }
+func (s *memorySpan) SetStatus(ok bool, _ string)        { s.ok = ok }
+func (s *memorySpan) End()                               { s.ended = true }
+
+type memorySpanKey struct{}
+
+// memoryTracer records the spans it starts, like an in-memory span exporter
+type memoryTracer struct {
+	mu    sync.Mutex
+	spans []*memorySpan
+}
+
+func (t *memoryTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	parent, _ := ctx.Value(memorySpanKey{}).(*memorySpan)
+	span := &memorySpan{name: name, parent: parent, attributes: make(map[string]any)}
+
+	t.mu.Lock()
+	t.spans = append(t.spans, span)
+	t.mu.Unlock()
+	return context.WithValue(ctx, memorySpanKey{}, span), span
+}
+
+func (t *memoryTracer) span(name string) *memorySpan {
+	for _, span := range t.spans {
+		if span.name == name {
+			return span
+		}
+	}
+	return nil
+}
+
+func TestExecutor_Tracer(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires the true and false commands")
+	}
+
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "build", Command: "true", Mode: config.ModeOnce},
+			{Name: "lint", Command: "false", Mode: config.ModeOnce, Concurrent: true},
+			{Name: "test", Command: "true", Mode: config.ModeOnce, Concurrent: true},
+		},
+	}
+
+	tracer := &memoryTracer{}
+	var out bytes.Buffer
+	executor := NewExecutorWithOptions(ExecutorOptions{
+		Reporter:        NewConsoleReporterWithWriters(&out, &out, false),
+		ContinueOnError: true,
+		Tracer:          tracer,
+	})
+	if err := executor.Execute(context.Background(), cfg); err == nil {
+		t.Fatal("Expected the failing command to fail the run")
+	}
+
+	if len(tracer.spans) != 4 {
+		t.Fatalf("Expected a run span and one span per command, got %d spans", len(tracer.spans))
+	}
+
+	run := tracer.span(RunSpanName)
+	if run == nil || run.parent != nil || !run.ended || run.ok || len(run.errors) != 1 {
+		t.Fatalf("Expected an ended, failed run span at the root, got %+v", run)
+	}
+
+	for _, name := range []string{"build", "lint", "test"} {
+		span := tracer.span(name)
+		if span == nil {
+			t.Fatalf("Expected a span for %s", name)
+		}
+		if span.parent != run || !span.ended {
+			t.Errorf("Expected the %s span to be an ended child of the run span, got %+v", name, span)
+		}
+
+		wantOK := name != "lint"
+		if span.ok != wantOK || span.attributes["seqr.success"] != wantOK {
+			t.Errorf("Expected the %s span to record success %v, got %+v", name, wantOK, span)
+		}
+	}
+
+	lint := tracer.span("lint")
+	if lint.attributes["seqr.exit_code"] != 1 || lint.attributes["seqr.exit_reason"] != string(ExitReasonExited) {
+		t.Errorf("Expected the lint span to record exit code 1, got %v", lint.attributes)
+	}
+	if lint.attributes["seqr.error.type"] != string(ErrorTypeExitCode) || len(lint.errors) != 1 {
+		t.Errorf("Expected the lint span to record its error, got %+v", lint)
+	}
+}
@@ -39,13 +39,44 @@ type ExecutionResult struct {
 	StartTime time.Time      `json:"startTime"`
 	EndTime   time.Time      `json:"endTime"`
 	Duration  time.Duration  `json:"duration"`
+
+	ResolvedPath string       `json:"resolvedPath,omitempty"` // Absolute path of the executable that was run
+	ErrorDetail  *ErrorDetail `json:"errorDetail,omitempty"`  // Why the command failed, nil on success
+	Cached       bool         `json:"cached,omitempty"`       // Skipped because its cacheKey inputs were unchanged
+
+	ExitReason ExitReason `json:"exitReason,omitempty"` // How the process ended, empty for a keepAlive command left running
+	Signal     string     `json:"signal,omitempty"`     // Name of the signal that terminated the process, such as SIGKILL
+
+	AllowedFailure bool `json:"allowedFailure,omitempty"` // Failed, but the command's allowFailure kept the run going
+
+	OutputDropped bool `json:"outputDropped,omitempty"` // Output was discarded to keep the run's captured output under MaxTotalOutputBytes
+
+	Meta map[string]string `json:"meta,omitempty"` // The command's meta, for reports and dashboards
+
+	QueuedAt     time.Time     `json:"queuedAt"`     // When the command became eligible to run
+	StartedAt    time.Time     `json:"startedAt"`    // When exec began
+	WaitDuration time.Duration `json:"waitDuration"` // Time spent queued, StartedAt - QueuedAt
+	RunDuration  time.Duration `json:"runDuration"`  // Time spent running, EndTime - StartedAt
+}
+
+// recordTiming fills in the queue wait and run time breakdown for a command queued at queuedAt
+func (r *ExecutionResult) recordTiming(queuedAt time.Time) {
+	r.QueuedAt = queuedAt
+	r.StartedAt = r.StartTime
+	r.WaitDuration = r.StartedAt.Sub(r.QueuedAt)
+	r.RunDuration = r.EndTime.Sub(r.StartedAt)
 }
 
 type ExecutionStatus struct {
+	RunID          string            `json:"runId,omitempty"`
 	State          ExecutionState    `json:"state"`
 	CurrentCommand *config.Command   `json:"currentCommand,omitempty"`
 	CompletedCount int               `json:"completedCount"`
 	TotalCount     int               `json:"totalCount"`
 	Results        []ExecutionResult `json:"results"`
 	LastError      string            `json:"lastError,omitempty"`
+
+	// Skipped names the queued commands that never started, for example after an earlier
+	// failure stopped the run
+	Skipped []string `json:"skipped,omitempty"`
 }
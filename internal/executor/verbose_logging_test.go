@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"regexp"
 	"strings"
 	"sync"
 	"testing"
@@ -479,6 +480,49 @@ func TestVerboseLogging_TimestampFormat(t *testing.T) {
 	}
 }
 
+func TestVerboseLogging_NoTimestamps(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	t.Setenv("NO_COLOR", "1")
+	timestampPattern := regexp.MustCompile(`\d{2}:\d{2}:\d{2}\.\d{3}`)
+	executor := NewExecutorWithOptions(ExecutorOptions{Verbose: true, NoTimestamps: true})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "build", Command: "echo", Args: []string{"once line"}, Mode: config.ModeOnce},
+			{Name: "server", Command: "echo", Args: []string{"keepAlive line"}, Mode: config.ModeKeepAlive},
+		},
+	}
+
+	output := captureOutput(func() {
+		if err := executor.Execute(ctx, cfg); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if err := executor.Wait(ctx); err != nil {
+			t.Errorf("Unexpected error waiting for the keepAlive process: %v", err)
+		}
+	})
+
+	// Streamed lines keep their "[type] [name]" prefix, and only lose the timestamp
+	for name, streamed := range map[string]string{"build": "once line", "server": "keepAlive line"} {
+		var found bool
+		for _, line := range strings.Split(output, "\n") {
+			if !strings.HasSuffix(line, "["+name+"] ✓ "+streamed) {
+				continue
+			}
+			found = true
+			if timestampPattern.MatchString(line) || strings.Count(line, "[") != 2 {
+				t.Errorf("Expected no timestamp in streamed line, got %q", line)
+			}
+		}
+		if !found {
+			t.Errorf("Could not find streamed line %q in: %s", streamed, output)
+		}
+	}
+}
+
 func TestVerboseLogging_CommandIdentification(t *testing.T) {
 	executor := NewExecutor(true)
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -0,0 +1,74 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/seqr-cli/seqr/internal/config"
+)
+
+func TestExecutor_WarmupExcludedFromSummary(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires the true command")
+	}
+
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "prime-cache", Command: "true", Mode: config.ModeOnce, Warmup: true},
+			{Name: "bench-a", Command: "true", Mode: config.ModeOnce, Concurrent: true},
+			{Name: "bench-b", Command: "true", Mode: config.ModeOnce, Concurrent: true},
+		},
+	}
+
+	var out bytes.Buffer
+	executor := NewExecutorWithOptions(ExecutorOptions{Verbose: true, Reporter: NewConsoleReporterWithWriters(&out, &out, true)})
+	var err error
+	captureOutput(func() { err = executor.Execute(context.Background(), cfg) })
+	if err != nil {
+		t.Fatalf("Expected the run to succeed, got %v", err)
+	}
+
+	status := executor.GetStatus()
+	if status.TotalCount != 2 || status.CompletedCount != 2 {
+		t.Errorf("Expected 2 of 2 commands counted without the warmup, got %d of %d", status.CompletedCount, status.TotalCount)
+	}
+	if len(status.Results) != 3 {
+		t.Errorf("Expected the warmup to run and keep its result, got %d results", len(status.Results))
+	}
+
+	if strings.Contains(out.String(), "[prime-cache] [summary]") {
+		t.Errorf("Expected the warmup to be left out of the timing summary, got %q", out.String())
+	}
+	if !strings.Contains(out.String(), "[bench-a] [summary]") {
+		t.Errorf("Expected the other commands in the timing summary, got %q", out.String())
+	}
+}
+
+func TestExecutor_WarmupFailureFailsRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires the false command")
+	}
+
+	cfg := &config.Config{
+		Version: "1.0",
+		Commands: []config.Command{
+			{Name: "prime-cache", Command: "false", Mode: config.ModeOnce, Warmup: true},
+			{Name: "bench", Command: "true", Mode: config.ModeOnce},
+		},
+	}
+
+	var out bytes.Buffer
+	executor := NewExecutorWithOptions(ExecutorOptions{Reporter: NewConsoleReporterWithWriters(&out, &out, false)})
+	if err := executor.Execute(context.Background(), cfg); err == nil {
+		t.Fatal("Expected a failed warmup to fail the run")
+	}
+
+	status := executor.GetStatus()
+	if status.State != StateFailed || status.TotalCount != 1 || status.CompletedCount != 0 {
+		t.Errorf("Expected a failed run with 0 of 1 commands counted, got %v with %d of %d", status.State, status.CompletedCount, status.TotalCount)
+	}
+}